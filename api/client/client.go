@@ -0,0 +1,323 @@
+// Package client is a typed Go client for the ingestion service REST API.
+// Types and methods follow api/openapi.yaml one-to-one (operationId → method).
+// It depends on the standard library only, so callers outside this module can
+// vendor it without the service's internal packages.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ingestion states reported by the service.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusPartial   = "partial"
+)
+
+// Schema Health.
+type Health struct {
+	Status string `json:"status"`
+}
+
+// Schema DataType.
+type DataType struct {
+	DataType       string `json:"dataType"`
+	TableName      string `json:"tableName"`
+	Description    string `json:"description,omitempty"`
+	Enabled        bool   `json:"enabled"`
+	DisabledReason string `json:"disabledReason,omitempty"`
+}
+
+// Schema IngestRequest.
+type IngestRequest struct {
+	DataType string `json:"dataType"`
+	Format   string `json:"format,omitempty"`
+	Tenant   string `json:"tenant,omitempty"`
+}
+
+// Schema Ingestion: one accepted ingestion and, once finished, its result.
+type Ingestion struct {
+	ID          string           `json:"id"`
+	DataType    string           `json:"dataType"`
+	Format      string           `json:"format"`
+	Tenant      string           `json:"tenant,omitempty"`
+	Status      string           `json:"status"`
+	SubmittedAt time.Time        `json:"submittedAt"`
+	StartedAt   *time.Time       `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	Remediation *Remediation     `json:"remediation,omitempty"`
+	Result      *IngestionResult `json:"result,omitempty"`
+}
+
+// Schema Remediation: what to do about a recognized workspace failure.
+type Remediation struct {
+	Code string `json:"code"`
+	Hint string `json:"hint"`
+}
+
+// Schema IngestionResult: one load's outcome as serialized by the service.
+type IngestionResult struct {
+	RowsIngested      int64                  `json:"rowsIngested"`
+	RowsSkipped       int64                  `json:"rowsSkipped,omitempty"`
+	RowsRejected      int64                  `json:"rowsRejected,omitempty"`
+	Rejections        []Rejection            `json:"rejections,omitempty"`
+	Duration          time.Duration          `json:"duration"`
+	TableName         string                 `json:"tableName"`
+	Status            string                 `json:"status"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	Validations       []ValidationResult     `json:"validations,omitempty"`
+	Verification      *SampleVerification    `json:"verification,omitempty"`
+	RowCount          *RowCountCheck         `json:"rowCount,omitempty"`
+	Warnings          []Warning              `json:"warnings,omitempty"`
+	ThrottleTime      time.Duration          `json:"throttleTime,omitempty"`
+	ThrottledRequests int                    `json:"throttledRequests,omitempty"`
+	Manifest          *BatchDecision         `json:"manifest,omitempty"`
+}
+
+// Schema Rejection: a record turned away by a load.
+type Rejection struct {
+	Index   int      `json:"index"`
+	Line    int      `json:"line,omitempty"`
+	Stage   string   `json:"stage"`
+	ItemID  string   `json:"itemId,omitempty"`
+	Reasons []string `json:"reasons"`
+}
+
+// Schema ValidationResult: one post-load validation rule and how it went.
+type ValidationResult struct {
+	Name       string `json:"name"`
+	Severity   string `json:"severity"`
+	Passed     bool   `json:"passed"`
+	Violations int64  `json:"violations"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Schema SampleVerification: records read back after the load and compared field by field.
+type SampleVerification struct {
+	Sampled    int             `json:"sampled"`
+	Missing    []string        `json:"missing,omitempty"`
+	Mismatches []FieldMismatch `json:"mismatches,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// Item of SampleVerification.mismatches.
+type FieldMismatch struct {
+	ItemID string `json:"itemId"`
+	Field  string `json:"field"`
+	Source string `json:"source"`
+	Stored string `json:"stored"`
+}
+
+// Schema RowCountCheck: the table counted before the insert and after the load.
+type RowCountCheck struct {
+	Before      int64  `json:"before"`
+	After       int64  `json:"after"`
+	Inserted    int64  `json:"inserted"`
+	Verified    bool   `json:"verified"`
+	Discrepancy int64  `json:"discrepancy,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Schema Warning: a non-fatal condition of a load.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Schema BatchDecision: what the batch manifest decided for the load.
+type BatchDecision struct {
+	Action          string `json:"action"`
+	SourceHash      string `json:"sourceHash,omitempty"`
+	BatchID         string `json:"batchId"`
+	PreviousStatus  string `json:"previousStatus,omitempty"`
+	PreviousRows    int64  `json:"previousRows,omitempty"`
+	CommittedChunks int    `json:"committedChunks,omitempty"`
+}
+
+// Reports whether the ingestion has reached a terminal state.
+func (i *Ingestion) Done() bool {
+	return i.Status == StatusCompleted || i.Status == StatusFailed || i.Status == StatusPartial
+}
+
+// Schema IngestionList: one page of ingestions.
+type IngestionList struct {
+	Ingestions    []Ingestion `json:"ingestions"`
+	NextPageToken string      `json:"nextPageToken,omitempty"`
+}
+
+// Query parameters of listIngestions; zero values are omitted.
+type ListOptions struct {
+	DataType  string
+	Status    string
+	Tenant    string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+	PageToken string
+}
+
+func (o ListOptions) query() string {
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	set("dataType", o.DataType)
+	set("status", o.Status)
+	set("tenant", o.Tenant)
+	set("pageToken", o.PageToken)
+	if !o.Since.IsZero() {
+		values.Set("since", o.Since.Format(time.RFC3339))
+	}
+	if !o.Until.IsZero() {
+		values.Set("until", o.Until.Format(time.RFC3339))
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+// Returned for non-2xx responses (schema Error).
+type APIError struct {
+	StatusCode  int
+	Message     string       `json:"error"`
+	Remediation *Remediation `json:"remediation,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ingestion service returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Calls the ingestion service at BaseURL (e.g. "http://localhost:8080").
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client // defaults to http.DefaultClient
+}
+
+// Creates a client for the service at baseURL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// GET /healthz (getHealth).
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var health Health
+	return &health, c.do(ctx, http.MethodGet, "/healthz", nil, &health)
+}
+
+// GET /datatypes (listDataTypes).
+func (c *Client) ListDataTypes(ctx context.Context) ([]DataType, error) {
+	var dataTypes []DataType
+	return dataTypes, c.do(ctx, http.MethodGet, "/datatypes", nil, &dataTypes)
+}
+
+// POST /ingest (createIngestion).
+func (c *Client) Ingest(ctx context.Context, req IngestRequest) (*Ingestion, error) {
+	var ingestion Ingestion
+	return &ingestion, c.do(ctx, http.MethodPost, "/ingest", req, &ingestion)
+}
+
+// GET /ingestions (listIngestions).
+func (c *Client) ListIngestions(ctx context.Context, opts ListOptions) (*IngestionList, error) {
+	var list IngestionList
+	return &list, c.do(ctx, http.MethodGet, "/ingestions"+opts.query(), nil, &list)
+}
+
+// GET /ingestions/{id} (getIngestion).
+func (c *Client) GetIngestion(ctx context.Context, id string) (*Ingestion, error) {
+	var ingestion Ingestion
+	return &ingestion, c.do(ctx, http.MethodGet, "/ingestions/"+url.PathEscape(id), nil, &ingestion)
+}
+
+// GET /ingestions/{id}/log (getIngestionLog).
+func (c *Client) GetIngestionLog(ctx context.Context, id string) (string, error) {
+	var log string
+	if err := c.do(ctx, http.MethodGet, "/ingestions/"+url.PathEscape(id)+"/log", nil, &log); err != nil {
+		return "", err
+	}
+	return log, nil
+}
+
+// Polls GetIngestion every interval until the ingestion finishes or ctx is done.
+func (c *Client) WaitForIngestion(ctx context.Context, id string, interval time.Duration) (*Ingestion, error) {
+	for {
+		ingestion, err := c.GetIngestion(ctx, id)
+		if err != nil || ingestion.Done() {
+			return ingestion, err
+		}
+		select {
+		case <-ctx.Done():
+			return ingestion, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if json.NewDecoder(resp.Body).Decode(apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+	// - text/plain responses (the run log) are returned as they are
+	if text, ok := out.(*string); ok {
+		content, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read %s %s response: %w", method, path, err)
+		}
+		*text = string(content)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+// Package api holds the OpenAPI 3 document for the ingestion service's REST mode.
+// The spec is the source of truth: the server serves it and api/client mirrors it.
+package api
+
+import _ "embed"
+
+// The OpenAPI 3 document served at GET /openapi.yaml.
+//
+//go:embed openapi.yaml
+var Spec []byte
@@ -0,0 +1,128 @@
+package blademap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//   Purpose: Advana re-publishes BLADE data as Parquet snapshots. Loading a snapshot into
+//   the same tables as the direct BLADE feed lets the two paths be compared row by row.
+
+//   Snapshot Layout:
+//   - {snapshot}/manifest.json describes the snapshot and each exported table
+//   - Each table's Parquet files sit in their own directory (location), relative to the
+//     snapshot or a /Volumes/... path
+//   - Columns follow Advana conventions: upper snake case (ITEM_ID), a few renamed fields
+//     (EVENT_TS for timestamp) and Advana's own lineage columns prefixed with _ADVANA_
+
+// File name of the manifest at the root of a snapshot directory.
+const AdvanaManifestFile = "manifest.json"
+
+// Prefix of the lineage columns Advana adds to every table (after normalization).
+const AdvanaLineagePrefix = "_advana_"
+
+// Advana column spellings of BLADE fields, keyed by normalized form.
+var AdvanaColumnAliases = map[string]string{
+	"event_ts":                "timestamp",
+	"blade_item_id":           "item_id",
+	"blade_item_type":         "item_type",
+	"classification_mark":     "classification_marking",
+	"security_classification": "classification_marking",
+}
+
+// A snapshot's manifest.json.
+type AdvanaManifest struct {
+	SnapshotID   string        `json:"snapshot_id"`
+	ExportedAt   time.Time     `json:"exported_at"`
+	SourceSystem string        `json:"source_system"`
+	Tables       []AdvanaTable `json:"tables"`
+}
+
+// One exported table of a snapshot.
+//   - DataType: The BLADE data type the table was exported from
+//   - Location: Directory of its Parquet files (relative to the snapshot, or /Volumes/...)
+//   - RowCount: Rows Advana exported (0 = not reported)
+//   - Columns: Parquet column names, as Advana wrote them
+type AdvanaTable struct {
+	Name     string   `json:"name"`
+	DataType string   `json:"data_type"`
+	Location string   `json:"location"`
+	RowCount int64    `json:"row_count"`
+	Columns  []string `json:"columns"`
+}
+
+// Reads and checks the manifest of the snapshot directory dir.
+func ReadAdvanaManifest(dir string) (*AdvanaManifest, error) {
+	path := filepath.Join(dir, AdvanaManifestFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Advana manifest: %w", err)
+	}
+	var manifest AdvanaManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse Advana manifest %s: %w", path, err)
+	}
+	if manifest.SnapshotID == "" {
+		return nil, fmt.Errorf("Advana manifest %s has no snapshot_id", path)
+	}
+	for i, table := range manifest.Tables {
+		if table.DataType == "" || table.Location == "" || len(table.Columns) == 0 {
+			return nil, fmt.Errorf("Advana manifest %s: table %d (%s) needs data_type, location and columns", path, i+1, table.Name)
+		}
+	}
+	return &manifest, nil
+}
+
+// Returns the snapshot's table of a BLADE data type.
+func (m *AdvanaManifest) Table(dataType string) (*AdvanaTable, error) {
+	var available []string
+	for i := range m.Tables {
+		if m.Tables[i].DataType == dataType {
+			return &m.Tables[i], nil
+		}
+		available = append(available, m.Tables[i].DataType)
+	}
+	sort.Strings(available)
+	return nil, fmt.Errorf("Advana snapshot %s has no %s table (has: %s)", m.SnapshotID, dataType, strings.Join(available, ", "))
+}
+
+// Maps BLADE field names to the table's Parquet columns.
+//   - Columns are normalized like CSV headers, resolving aliases (the mapping's), then
+//     AdvanaColumnAliases, then DefaultCSVAliases
+//   - Advana lineage columns (_ADVANA_*) are left out
+//   - Every RequiredCSVColumns field must be present
+func (t *AdvanaTable) FieldColumns(aliases map[string]string) (map[string]string, error) {
+	merged := make(map[string]string, len(AdvanaColumnAliases)+len(aliases))
+	for normalized, field := range AdvanaColumnAliases {
+		merged[normalized] = field
+	}
+	for normalized, field := range aliases {
+		merged[normalized] = field
+	}
+
+	fields := make(map[string]string, len(t.Columns))
+	for _, column := range t.Columns {
+		if strings.ContainsAny(column, "`\n") {
+			return nil, fmt.Errorf("Advana table %s: invalid column name %q", t.Name, column)
+		}
+		field := NormalizeHeader(column, merged)
+		if field == "" || strings.HasPrefix(field, AdvanaLineagePrefix) {
+			continue
+		}
+		if previous, duplicate := fields[field]; duplicate {
+			return nil, fmt.Errorf("Advana table %s: columns %q and %q both map to %s", t.Name, previous, column, field)
+		}
+		fields[field] = column
+	}
+	for _, required := range RequiredCSVColumns {
+		if _, ok := fields[required]; !ok {
+			return nil, fmt.Errorf("Advana table %s has no column for %s", t.Name, required)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,148 @@
+package blademap
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//   Purpose: Real BLADE extracts are delivered compressed. A data file may come gzipped
+//   (maintenance_data.json.gz) or inside a zip archive (extract.zip), and is decompressed
+//   while it is read, so nothing is unpacked to disk first.
+
+//   Zip Archives:
+//   - Directories, hidden files and macOS resource forks (__MACOSX/) are ignored
+//   - With a format, the archive must hold exactly one data file of that format;
+//     without one, exactly one data file of any format
+//   - A gzipped file inside a zip isn't opened (compress the archive or the file, not both)
+
+// Returns the format of a data file from its name, looking through a .gz suffix:
+// "JSON", "CSV" or "NDJSON" (.ndjson, .jsonl); "" for anything else, including .zip.
+func FileFormat(name string) string {
+	name = strings.ToLower(name)
+	name = strings.TrimSuffix(name, ".gz")
+	switch filepath.Ext(name) {
+	case ".json":
+		return "JSON"
+	case ".csv":
+		return "CSV"
+	case ".ndjson", ".jsonl":
+		return "NDJSON"
+	}
+	return ""
+}
+
+// Reports whether a file name is a zip archive.
+func IsZip(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".zip")
+}
+
+// Opens a data file of the given format ("" = any), decompressing .gz and .zip on the fly.
+//   - Returns the decompressed content and the data file's name (the archive entry for
+//     a zip, so callers can take its format from it)
+func OpenDataFile(filePath, format string) (io.ReadCloser, string, error) {
+	if IsZip(filePath) {
+		return openZipEntry(filePath, format)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+	if !strings.EqualFold(filepath.Ext(filePath), ".gz") {
+		return file, filepath.Base(filePath), nil
+	}
+	decompressed, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, "", fmt.Errorf("%s is not a gzip file: %w", filePath, err)
+	}
+	return &stackedReader{Reader: decompressed, closers: []io.Closer{decompressed, file}}, strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)), nil
+}
+
+// Returns the format of the data file a path holds (see FileFormat), opening zip
+// archives to find it.
+func DataFileFormat(filePath string) (string, error) {
+	if !IsZip(filePath) {
+		return FileFormat(filePath), nil
+	}
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open zip archive %s: %w", filePath, err)
+	}
+	defer archive.Close()
+	entry, err := zipDataFile(filePath, archive, "")
+	if err != nil {
+		return "", err
+	}
+	return FileFormat(entry.Name), nil
+}
+
+func openZipEntry(filePath, format string) (io.ReadCloser, string, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open zip archive %s: %w", filePath, err)
+	}
+	entry, err := zipDataFile(filePath, archive, format)
+	if err != nil {
+		archive.Close()
+		return nil, "", err
+	}
+	content, err := entry.Open()
+	if err != nil {
+		archive.Close()
+		return nil, "", fmt.Errorf("failed to open %s in %s: %w", entry.Name, filePath, err)
+	}
+	return &stackedReader{Reader: content, closers: []io.Closer{content, archive}}, path.Base(entry.Name), nil
+}
+
+// Picks the one data file of an archive, of the given format ("" = any).
+func zipDataFile(filePath string, archive *zip.ReadCloser, format string) (*zip.File, error) {
+	var found []*zip.File
+	for _, entry := range archive.File {
+		name := path.Base(entry.Name)
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || strings.HasPrefix(name, ".") {
+			continue
+		}
+		entryFormat := FileFormat(name)
+		if entryFormat == "" || strings.HasSuffix(strings.ToLower(name), ".gz") || (format != "" && entryFormat != format) {
+			continue
+		}
+		found = append(found, entry)
+	}
+	what := "data file"
+	if format != "" {
+		what = format + " file"
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("zip archive %s holds no %s", filePath, what)
+	case 1:
+		return found[0], nil
+	}
+	names := make([]string, len(found))
+	for i, entry := range found {
+		names[i] = entry.Name
+	}
+	return nil, fmt.Errorf("zip archive %s holds %d %ss (%s); deliver one per archive", filePath, len(found), what, strings.Join(names, ", "))
+}
+
+// Reads decompressed content and closes every layer beneath it, innermost first.
+type stackedReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *stackedReader) Close() error {
+	var first error
+	for _, closer := range r.closers {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,361 @@
+// Package blademap parses BLADE extracts into records the same way the ingestion
+// pipeline does: versioned JSON envelopes, CSV preambles, header normalization,
+// long-format pivots and array fields. It has no Databricks dependency, so other tools
+// that consume BLADE extracts can share the exact parsing behavior.
+package blademap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//   Purpose: Turns a BLADE CSV extract into JSON-style records (one map per item).
+
+// Describes a long-format CSV extract where each row carries one field of an item.
+//   - IDColumn: Column identifying the item (e.g. "item_id")
+//   - KeyColumn / ValueColumn: Columns holding the field name and its value
+type CSVPivot struct {
+	IDColumn    string `json:"idColumn"`
+	KeyColumn   string `json:"keyColumn"`
+	ValueColumn string `json:"valueColumn"`
+}
+
+// Returns the pivot with its column names normalized like CSV headers (nil stays nil).
+func (p *CSVPivot) Normalized() *CSVPivot {
+	if p == nil {
+		return nil
+	}
+	return &CSVPivot{
+		IDColumn:    NormalizeHeader(p.IDColumn, nil),
+		KeyColumn:   NormalizeHeader(p.KeyColumn, nil),
+		ValueColumn: NormalizeHeader(p.ValueColumn, nil),
+	}
+}
+
+// Reports whether a CSV header row carries all three pivot columns (otherwise the file is read as wide).
+func (p CSVPivot) Matches(headers []string) bool {
+	found := 0
+	for _, header := range headers {
+		if header == p.IDColumn || header == p.KeyColumn || header == p.ValueColumn {
+			found++
+		}
+	}
+	return found == 3
+}
+
+// How a data type's CSV extracts are read.
+//   - Aliases: Extra header spellings (normalized form → canonical field name)
+//   - Pivot: Long-format layout, used when the file carries its three columns
+//   - Types: Column type hints by field name (see csvtypes.go); ArrayFields are arrays
+//     unless hinted otherwise
+//   - Infer: Infer the type of every other column from its values; otherwise they stay text
+//   - KeepBadRows: Set aside data rows that aren't valid CSV (a stray quote) in
+//     CSVResult.BadRows and read on; otherwise the first one fails the parse
+type CSVOptions struct {
+	Aliases     map[string]string
+	Pivot       *CSVPivot
+	Types       map[string]string
+	Infer       bool
+	KeepBadRows bool
+}
+
+// A parsed CSV extract.
+//   - RaggedRows: Wide rows with non-empty fields past the last header column (ignored)
+//   - SkippedRows: Long-format rows without an id, key and value (skipped)
+//   - Columns: The type each column was converted to, with its invalid values
+//   - BadRows: Data rows that aren't valid CSV (CSVOptions.KeepBadRows)
+type CSVResult struct {
+	Records     []map[string]interface{}
+	Version     SourceVersion
+	Headers     []string
+	RaggedRows  int
+	SkippedRows int
+	Columns     []ColumnType
+	BadRows     []BadRow
+}
+
+// A data row of a CSV extract that couldn't be parsed.
+//   - Line: Line of the file the row starts on (1-based, counting the preamble)
+//   - Row: Data rows read before it, i.e. the position its record would have had
+//   - Text: The row as it appears in the file
+type BadRow struct {
+	Line  int
+	Row   int
+	Text  string
+	Error string
+}
+
+// Columns every wide BLADE CSV must provide (after normalization) for the standardized table schema.
+var RequiredCSVColumns = []string{"item_id", "item_type", "classification_marking", "timestamp"}
+
+// Header spellings seen across BLADE export releases, keyed by normalized form.
+var DefaultCSVAliases = map[string]string{
+	"id":              "item_id",
+	"itemid":          "item_id",
+	"type":            "item_type",
+	"itemtype":        "item_type",
+	"classification":  "classification_marking",
+	"marking":         "classification_marking",
+	"event_time":      "timestamp",
+	"event_timestamp": "timestamp",
+}
+
+// Fields whose CSV values hold ";"-separated lists.
+var ArrayFields = []string{"parts_required", "compliance_refs"}
+
+// Parses a CSV extract: "#" preamble (export version), header row, then one record per
+// row (wide) or per item (long format, see CSVOptions.Pivot).
+func ParseCSV(r io.Reader, opts CSVOptions) (*CSVResult, error) {
+	for field, columnType := range opts.Types {
+		if err := ValidateColumnType(columnType); err != nil {
+			return nil, fmt.Errorf("column %s: %w", field, err)
+		}
+	}
+
+	// - "# blade_export_version: ..." lines ahead of the header declare the export version
+	// - The extract is held whole anyway, so it is read up front: bad rows quote their
+	//   text from it, and line numbers count the preamble
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content := bytes.NewReader(data)
+	buffered := bufio.NewReader(content)
+	version, err := ReadCSVPreamble(buffered)
+	if err != nil {
+		return nil, err
+	}
+	body := data[len(data)-content.Len()-buffered.Buffered():]
+	preambleLines := bytes.Count(data[:len(data)-len(body)], []byte("\n"))
+
+	// - FieldsPerRecord = -1 tolerates ragged rows from hand-edited exports;
+	//   missing trailing fields are left out and surplus ones are ignored (counted)
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+
+	// - Reads every row to [][]string (array of rows, each row is array of fields)
+	// - A data row that isn't valid CSV fails the parse, or is set aside with KeepBadRows
+	//   (a bad header always fails)
+	// - Validates CSV has at least 2 rows (headers + at least 1 data row)
+	var rows [][]string
+	var badRows []BadRow
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if err != nil && opts.KeepBadRows && len(rows) > 0 && errors.As(err, &parseErr) {
+			badRows = append(badRows, BadRow{
+				Line:  preambleLines + parseErr.StartLine,
+				Row:   len(rows) - 1,
+				Text:  fileLines(body, parseErr.StartLine, parseErr.Line),
+				Error: parseErr.Err.Error(),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("no data rows")
+	}
+
+	// - Headers are normalized (trimmed, case-folded, aliases resolved) since field
+	//   names and order drift between BLADE releases
+	headers, err := NormalizeHeaders(rows[0], opts.Aliases)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	// Record Layouts:
+	// - Wide (default): one row per item, one column per field
+	// - Long: one row per item field (id, key, value) when the options have a Pivot
+	//   and the file carries its key/value columns; pivoted back to one record per item
+	// - Either way the fields are read as text, then converted to their column types
+	result := &CSVResult{Version: version, Headers: headers, BadRows: badRows}
+	if pivot := opts.Pivot.Normalized(); pivot != nil && pivot.Matches(headers) {
+		result.Records, result.SkippedRows = pivotLongRows(headers, rows[1:], *pivot, opts.Aliases)
+		result.Columns = typeColumns(result.Records, nil, opts)
+		return result, nil
+	}
+	for _, column := range RequiredCSVColumns {
+		if !containsString(headers, column) {
+			return nil, fmt.Errorf("missing required column %s", column)
+		}
+	}
+	result.Records, result.RaggedRows = wideRows(headers, rows[1:])
+	result.Columns = typeColumns(result.Records, headers, opts)
+	return result, nil
+}
+
+// Returns lines first through last (1-based) of data, without their line endings.
+func fileLines(data []byte, first, last int) string {
+	lines := strings.Split(string(data), "\n")
+	if first < 1 || first > len(lines) {
+		return ""
+	}
+	last = min(max(last, first), len(lines))
+	return strings.TrimRight(strings.Join(lines[first-1:last], "\n"), "\r\n")
+}
+
+// Returns one record per row and the number of rows whose surplus fields were ignored.
+func wideRows(headers []string, rows [][]string) ([]map[string]interface{}, int) {
+	var records []map[string]interface{}
+	ragged := 0
+
+	// 	 Row-by-Row Processing:
+	// 	 - Creates map[string]interface{} for each data row
+	// 	 - Maps CSV columns to JSON fields using headers as keys
+	for _, row := range rows {
+		record := make(map[string]interface{})
+		for j, header := range headers {
+			if j < len(row) {
+				record[header] = row[j]
+			}
+		}
+		// - Only non-empty surplus fields count; a trailing comma drops nothing
+		for _, surplus := range row[min(len(row), len(headers)):] {
+			if strings.TrimSpace(surplus) != "" {
+				ragged++
+				break
+			}
+		}
+		records = append(records, record)
+	}
+	return records, ragged
+}
+
+// Returns one record per item and the number of rows skipped for lacking an id, key or value.
+func pivotLongRows(headers []string, rows [][]string, pivot CSVPivot, aliases map[string]string) ([]map[string]interface{}, int) {
+	// Column Roles:
+	// - IDColumn groups rows into one record per item (first-seen order is kept)
+	// - KeyColumn/ValueColumn become a field name and its value
+	// - Any other column is copied onto the record from the item's first row that has it
+	idIndex, keyIndex, valueIndex := -1, -1, -1
+	for j, header := range headers {
+		switch header {
+		case pivot.IDColumn:
+			idIndex = j
+		case pivot.KeyColumn:
+			keyIndex = j
+		case pivot.ValueColumn:
+			valueIndex = j
+		}
+	}
+
+	var records []map[string]interface{}
+	skipped := 0
+	byID := make(map[string]map[string]interface{})
+	for _, row := range rows {
+		if idIndex >= len(row) || keyIndex >= len(row) || valueIndex >= len(row) || row[idIndex] == "" {
+			skipped++
+			continue
+		}
+		id := row[idIndex]
+		record, exists := byID[id]
+		if !exists {
+			record = map[string]interface{}{pivot.IDColumn: id}
+			byID[id] = record
+			records = append(records, record)
+		}
+
+		for j, header := range headers {
+			if j == idIndex || j == keyIndex || j == valueIndex {
+				continue
+			}
+			if _, set := record[header]; !set && row[j] != "" {
+				record[header] = row[j]
+			}
+		}
+		if key := NormalizeHeader(row[keyIndex], aliases); key != "" {
+			record[key] = row[valueIndex]
+		}
+	}
+	return records, skipped
+}
+
+// Normalizes every header and rejects two columns collapsing onto one name, which
+// would otherwise silently overwrite values.
+func NormalizeHeaders(raw []string, aliases map[string]string) ([]string, error) {
+	headers := make([]string, len(raw))
+	seen := make(map[string]string)
+	for j, header := range raw {
+		normalized := NormalizeHeader(header, aliases)
+		if normalized == "" {
+			return nil, fmt.Errorf("column %d has an empty header", j+1)
+		}
+		if previous, duplicate := seen[normalized]; duplicate {
+			return nil, fmt.Errorf("columns %q and %q both map to %s", previous, header, normalized)
+		}
+		seen[normalized] = header
+		headers[j] = normalized
+	}
+	return headers, nil
+}
+
+// Returns the canonical field name of a CSV header.
+//   - Strips a UTF-8 BOM and surrounding whitespace
+//   - Case-folds and turns spaces/hyphens into underscores ("Item ID" → "item_id")
+//   - Resolves aliases first, then DefaultCSVAliases
+func NormalizeHeader(header string, aliases map[string]string) string {
+	normalized := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	normalized = strings.NewReplacer(" ", "_", "-", "_").Replace(normalized)
+	if canonical, ok := aliases[normalized]; ok {
+		return canonical
+	}
+	if canonical, ok := DefaultCSVAliases[normalized]; ok {
+		return canonical
+	}
+	return normalized
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Converts a CSV field to its record value.
+//   - Array Fields (ArrayFields):
+//   - CSV: "engine_oil_filter;spark_plugs;hydraulic_fluid"
+//   - JSON: ["engine_oil_filter", "spark_plugs", "hydraulic_fluid"]
+//   - Empty Values: Convert "" to null in JSON
+//   - Regular Values: Keep as strings
+func Value(header, value string) interface{} {
+	if containsString(ArrayFields, header) {
+		if value != "" {
+			return splitAndTrim(value, ";")
+		}
+		return []string{}
+	}
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func splitAndTrim(s string, sep string) []string {
+	// - Splits string on separator (;)
+	// - Trims whitespace from each part
+	// - Filters out empty strings
+	// - Example: "part1; part2 ; ; part3" → ["part1", "part2", "part3"]
+	parts := []string{}
+	splits := strings.Split(s, sep)
+	for _, part := range splits {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,218 @@
+package blademap
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//   Purpose: CSV fields are all text, while the same fields in a JSON extract are
+//   numbers, booleans and lists. Each column is given a type, from the data type's hints
+//   or inferred from its values, and its values are converted to it, so a CSV load lands
+//   the same values a JSON load would.
+
+//   Column Types:
+//   - string: Kept as text
+//   - int / float: JSON numbers ("2450.00" → 2450)
+//   - bool: true/false, yes/no, 1/0 (case-insensitive)
+//   - timestamp: RFC 3339 text; "2024-01-15 10:30:00" and "2024-01-15" are rewritten
+//     to RFC 3339 (UTC), RFC 3339 values are kept as they are
+//   - array: ";"-separated list ("a; b" → ["a", "b"])
+
+// CSV column types (CSVOptions.Types).
+const (
+	TypeString    = "string"
+	TypeInt       = "int"
+	TypeFloat     = "float"
+	TypeBool      = "bool"
+	TypeTimestamp = "timestamp"
+	TypeArray     = "array"
+)
+
+// Every CSV column type, for validating hints.
+var ColumnTypes = []string{TypeString, TypeInt, TypeFloat, TypeBool, TypeTimestamp, TypeArray}
+
+// Where a column's type came from (ColumnType.Origin).
+const (
+	OriginHint     = "hint"     // CSVOptions.Types
+	OriginDefault  = "default"  // ArrayFields, the identifying columns, or text when not inferring
+	OriginInferred = "inferred" // the column's values
+)
+
+// How one column of a CSV extract was typed.
+//   - Origin: Where Type came from (OriginHint, OriginDefault or OriginInferred)
+//   - Values: Non-empty values in the column
+//   - Invalid: Values that didn't convert to a hinted Type; they are kept as text
+//   - Example, ExampleRow: The first invalid value and its data row (1-based)
+type ColumnType struct {
+	Column     string `json:"column"`
+	Type       string `json:"type"`
+	Origin     string `json:"origin"`
+	Values     int    `json:"values"`
+	Invalid    int    `json:"invalid,omitempty"`
+	Example    string `json:"example,omitempty"`
+	ExampleRow int    `json:"exampleRow,omitempty"`
+}
+
+// Returns an error for a type that isn't one of ColumnTypes.
+func ValidateColumnType(columnType string) error {
+	if !containsString(ColumnTypes, columnType) {
+		return fmt.Errorf("unknown CSV column type %q (supported: %s)", columnType, strings.Join(ColumnTypes, ", "))
+	}
+	return nil
+}
+
+// Inference never touches the identifying columns: an all-digit item_id stays text.
+var textColumns = []string{"item_id", "item_type", "classification_marking"}
+
+var (
+	intPattern   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	floatPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+)
+
+// Layouts a timestamp hint accepts besides RFC 3339.
+var timestampLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// Converts the text values of records to their column types and returns how each column was typed.
+//   - Columns are reported in header order, then any other fields (long format) by name
+//   - Values that are already converted (not strings) are left alone
+func typeColumns(records []map[string]interface{}, headers []string, opts CSVOptions) []ColumnType {
+	fields := append([]string{}, headers...)
+	var extra []string
+	for _, record := range records {
+		for field := range record {
+			if !containsString(fields, field) && !containsString(extra, field) {
+				extra = append(extra, field)
+			}
+		}
+	}
+	sort.Strings(extra)
+	fields = append(fields, extra...)
+
+	report := make([]ColumnType, 0, len(fields))
+	for _, field := range fields {
+		column := ColumnType{Column: field, Type: TypeString, Origin: OriginDefault}
+		var values []string
+		for _, record := range records {
+			if value, ok := record[field].(string); ok && strings.TrimSpace(value) != "" {
+				values = append(values, value)
+			}
+		}
+		column.Values = len(values)
+		switch hint, hinted := opts.Types[field]; {
+		case hinted:
+			column.Type, column.Origin = hint, OriginHint
+		case containsString(ArrayFields, field):
+			column.Type = TypeArray
+		case opts.Infer && !containsString(textColumns, field):
+			column.Type, column.Origin = inferType(values), OriginInferred
+		}
+
+		for row, record := range records {
+			raw, ok := record[field].(string)
+			if !ok {
+				continue
+			}
+			value, valid := convertValue(column.Type, raw)
+			if !valid {
+				column.Invalid++
+				if column.Invalid == 1 {
+					column.Example, column.ExampleRow = raw, row+1
+				}
+			}
+			record[field] = value
+		}
+		report = append(report, column)
+	}
+	return report
+}
+
+// Returns the narrowest type every value converts to: int, float, bool, timestamp, else string.
+//   - Integers with leading zeros ("0042") are codes, not numbers, and stay text
+//   - Only RFC 3339 values are taken for timestamps
+func inferType(values []string) string {
+	if len(values) == 0 {
+		return TypeString
+	}
+	all := func(match func(string) bool) bool {
+		for _, value := range values {
+			if !match(strings.TrimSpace(value)) {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case all(func(v string) bool {
+		_, err := strconv.ParseInt(v, 10, 64)
+		return err == nil && intPattern.MatchString(v)
+	}):
+		return TypeInt
+	case all(func(v string) bool {
+		f, err := strconv.ParseFloat(v, 64)
+		return err == nil && floatPattern.MatchString(v) && !math.IsInf(f, 0)
+	}):
+		return TypeFloat
+	case all(func(v string) bool { _, ok := parseBool(v); return ok }):
+		return TypeBool
+	case all(func(v string) bool { _, err := time.Parse(time.RFC3339, v); return err == nil }):
+		return TypeTimestamp
+	}
+	return TypeString
+}
+
+// Converts one CSV field to its column type; a value that doesn't convert is returned as
+// text with false. Empty fields are null (arrays: an empty list).
+func convertValue(columnType, raw string) (interface{}, bool) {
+	switch columnType {
+	case TypeArray:
+		return splitAndTrim(raw, ";"), true
+	case TypeString:
+		if raw == "" {
+			return nil, true
+		}
+		return raw, true
+	}
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return nil, true
+	}
+	switch columnType {
+	case TypeInt:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n, true
+		}
+	case TypeFloat:
+		if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f, true
+		}
+	case TypeBool:
+		if b, ok := parseBool(value); ok {
+			return b, true
+		}
+	case TypeTimestamp:
+		if _, err := time.Parse(time.RFC3339, value); err == nil {
+			return value, true
+		}
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.UTC().Format(time.RFC3339), true
+			}
+		}
+	}
+	return raw, false
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, true
+	case "false", "no", "0":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,68 @@
+package blademap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+//   Purpose: Large BLADE extracts arrive as newline-delimited JSON (JSON Lines, .ndjson or
+//   .jsonl), one record per line. Reading them a record at a time keeps memory flat
+//   however big the file is, where a JSON array has to be held whole.
+
+// Reads newline-delimited JSON records one at a time.
+//   - Blank lines are skipped; every other line must be one JSON object
+//   - Lines may end in \n or \r\n and have no length limit
+//   - Errors name the line they were found on; a line that isn't a JSON object is a
+//     *LineError, and Next can be called again to read on past it
+type NDJSONReader struct {
+	reader *bufio.Reader
+	line   int
+}
+
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	return &NDJSONReader{reader: bufio.NewReaderSize(r, 64<<10)}
+}
+
+// Returns the next record as it appears in the file, or io.EOF after the last one.
+func (r *NDJSONReader) Next() (json.RawMessage, error) {
+	for {
+		data, err := r.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if len(data) == 0 && errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		r.line++
+		record := bytes.TrimSpace(data)
+		if len(record) == 0 {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			continue
+		}
+		if record[0] != '{' || !json.Valid(record) {
+			return nil, &LineError{Line: r.line, Text: string(record)}
+		}
+		return json.RawMessage(record), nil
+	}
+}
+
+// A line of newline-delimited JSON that isn't a JSON object.
+type LineError struct {
+	Line int
+	Text string // the line, trimmed
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: not a JSON object", e.Line)
+}
+
+// Returns the line number of the record Next returned last.
+func (r *NDJSONReader) Line() int {
+	return r.line
+}
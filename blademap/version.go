@@ -0,0 +1,89 @@
+package blademap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//   Purpose: BLADE upgrades change field semantics without renaming fields, so every
+//   load records which export format/version produced it. Analysts segment rows by
+//   metadata['source_version'] instead of guessing from load dates.
+
+//   Where the Version Comes From:
+//   - JSON: an export envelope {"blade_export": {"format": ..., "version": ...}, "records": [...]};
+//     a bare array of records carries no version
+//   - CSV: "#"-prefixed header lines before the column header, e.g. "# blade_export_version: 4.2"
+
+// Export format and version declared by a BLADE file; empty fields are unknown.
+type SourceVersion struct {
+	Format  string `json:"format,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Shape of a versioned BLADE JSON export.
+type jsonExport struct {
+	Export  SourceVersion   `json:"blade_export"`
+	Records json.RawMessage `json:"records"`
+}
+
+// Splits a JSON export into its records (as a JSON array) and the version it declares.
+//   - A bare array is returned exactly as given
+func UnwrapJSONExport(data []byte) (string, SourceVersion, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(trimmed, "{") {
+		return string(data), SourceVersion{}, nil
+	}
+	var export jsonExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return "", SourceVersion{}, fmt.Errorf("invalid BLADE export envelope: %w", err)
+	}
+	if len(export.Records) == 0 || !strings.HasPrefix(strings.TrimSpace(string(export.Records)), "[") {
+		return "", SourceVersion{}, fmt.Errorf("BLADE export envelope has no records array")
+	}
+	return string(export.Records), export.Export.normalized(), nil
+}
+
+// Consumes the "# key: value" lines ahead of a CSV header and returns the version they declare.
+// Unknown keys are ignored; "=" works as a separator too.
+func ReadCSVPreamble(reader *bufio.Reader) (SourceVersion, error) {
+	var version SourceVersion
+	if bom, _ := reader.Peek(3); string(bom) == "\ufeff" {
+		reader.Discard(3)
+	}
+	for {
+		next, err := reader.Peek(1)
+		if err != nil || next[0] != '#' {
+			return version.normalized(), nil
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return version, err
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "#"), ":")
+		if !found {
+			key, value, _ = strings.Cut(strings.TrimPrefix(line, "#"), "=")
+		}
+		switch NormalizeHeader(key, nil) {
+		case "blade_export_format", "export_format":
+			version.Format = value
+		case "blade_export_version", "export_version":
+			version.Version = value
+		}
+	}
+}
+
+func (v SourceVersion) normalized() SourceVersion {
+	return SourceVersion{Format: strings.TrimSpace(v.Format), Version: strings.TrimSpace(v.Version)}
+}
+
+// Adds the known parts of the version to load metadata (source_format, source_version).
+func (v SourceVersion) Apply(metadata map[string]string) {
+	if v.Format != "" {
+		metadata["source_format"] = v.Format
+	}
+	if v.Version != "" {
+		metadata["source_version"] = v.Version
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runBootstrap(ctx context.Context, cfg *config.Config, args []string) error {
+	// Bootstrap Targets:
+	// - dashboard: Lakeview dashboard over the BLADE tables
+	// - alerts: SQL alerts on per-table data freshness
+	if len(args) == 0 {
+		return fmt.Errorf("usage: bootstrap dashboard|alerts [flags]")
+	}
+
+	switch args[0] {
+	case "dashboard":
+		return runBootstrapDashboard(ctx, cfg, args[1:])
+	case "alerts":
+		return runBootstrapAlerts(ctx, cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown bootstrap target %q (supported: dashboard, alerts)", args[0])
+	}
+}
+
+func bladeTableNames(cfg *config.Config) ([]string, error) {
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, dataType := range source.ListTypes() {
+		schema, err := source.DescribeSchema(dataType)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, schema.TableName)
+	}
+	return tables, nil
+}
+
+func runBootstrapDashboard(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("bootstrap dashboard", flag.ContinueOnError)
+	name := flags.String("name", "BLADE Ingestion Overview", "dashboard display name")
+	parentPath := flags.String("parent-path", "", "workspace folder for the dashboard (default: your home folder)")
+	publish := flags.Bool("publish", true, "publish the dashboard after creating it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	tables, err := bladeTableNames(cfg)
+	if err != nil {
+		return err
+	}
+	info, err := dbClient.BootstrapDashboard(ctx, *name, *parentPath, tables, *publish)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("DASHBOARD CREATED")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	fmt.Printf("Name: %s\n", info.DisplayName)
+	fmt.Printf("ID: %s\n", info.DashboardID)
+	fmt.Printf("Path: %s\n", info.Path)
+	fmt.Printf("Published: %t\n", info.Published)
+	fmt.Printf("URL: %s", info.URL)
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	return nil
+}
+
+func runBootstrapAlerts(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("bootstrap alerts", flag.ContinueOnError)
+	sla := flags.Duration("sla", cfg.FreshnessSLA, "maximum time since the last batch before alerting")
+	destination := flags.String("destination-id", cfg.AlertDestinationID, "notification destination ID")
+	email := flags.String("email", cfg.AlertEmail, "user email to notify (when no destination ID is given)")
+	schedule := flags.String("schedule", cfg.AlertSchedule, "quartz cron schedule for evaluating the alerts")
+	parentPath := flags.String("parent-path", "", "workspace folder for the alerts (default: your home folder)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	tables, err := bladeTableNames(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("FRESHNESS ALERTS")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	for _, table := range tables {
+		info, err := dbClient.CreateFreshnessAlert(ctx, databricks.FreshnessAlertSpec{
+			TableName:     table,
+			SLA:           *sla,
+			DestinationID: *destination,
+			UserEmail:     *email,
+			Schedule:      *schedule,
+			ParentPath:    *parentPath,
+		})
+		if err != nil {
+			return err
+		}
+		action := "created"
+		if info.Updated {
+			action = "updated"
+		}
+		fmt.Printf("%s: alert %s %s (SLA %g min)\n", info.TableName, info.AlertID, action, info.SLAMinutes)
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"databricks-blade-poc/internal/config"
+)
+
+// A CLI subcommand; args excludes the command name itself.
+type command struct {
+	usage     string // invocation shown by help, e.g. "preflight [dataType...]"
+	summary   string
+	readOnly  bool // safe to run in read-only mode (BLADE_READ_ONLY)
+	workspace bool // needs a Databricks workspace, so unavailable with the local backend (BLADE_BACKEND=local)
+	run       func(ctx context.Context, cfg *config.Config, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"ingest": {
+			usage:   "ingest [--max-runtime d] [--force-recreate] [--output table|json|yaml] [--source path | --advana-snapshot dir] [--all [--workers n] | dataType] [JSON|CSV|NDJSON]",
+			summary: "ingest one BLADE data type (default command)",
+			run:     runIngest,
+		},
+		"ingest-all": {
+			usage:   "ingest-all [--workers n] [--max-runtime d] [--force-recreate] [JSON|CSV|NDJSON]",
+			summary: "ingest every enabled data type concurrently and print a summary (ingest --all)",
+			run: func(ctx context.Context, cfg *config.Config, args []string) error {
+				return runIngest(ctx, cfg, append([]string{"--all"}, args...))
+			},
+		},
+		"serve": {
+			usage:   "serve [--addr :8080]",
+			summary: "run the REST API (POST /ingest, GET /ingestions/{id}[/log], GET /datatypes, GET /healthz)",
+			run:     runServe,
+		},
+		"watch": {
+			usage:   "watch [--interval d] [--debounce d] [--backfill] [--once]",
+			summary: "ingest JSON/CSV/NDJSON files as they are dropped into the BLADE data path",
+			run:     runWatch,
+		},
+		"status": {
+			usage:    "status [--json] [--log] ingestionID",
+			summary:  "show the state of an ingestion (queued, running, completed, failed, partial) by its ID",
+			readOnly: true,
+			run:      runStatus,
+		},
+		"runs": {
+			usage:    "runs [--type dataType] [--status s] [--tenant t] [--since 72h|date] [--limit n] [--json] [runID]",
+			summary:  "list past ingestion runs from the local run store, newest first, or show one run's details",
+			readOnly: true,
+			run:      runRuns,
+		},
+		"preflight": {
+			usage:     "preflight [dataType...]",
+			summary:   "verify catalog/schema/table privileges before ingesting",
+			readOnly:  true,
+			workspace: true,
+			run:       runPreflight,
+		},
+		"bootstrap": {
+			usage:     "bootstrap dashboard|alerts [flags]",
+			summary:   "create a Lakeview dashboard or freshness alerts for the BLADE tables",
+			workspace: true,
+			run:       runBootstrap,
+		},
+		"seed-semantics": {
+			usage:   "seed-semantics [dataType...]",
+			summary: "attach Genie-ready descriptions, synonyms and example questions to the BLADE tables",
+			run:     runSeedSemantics,
+		},
+		"init": {
+			usage:     "init [--file .env] [--skip-checks]",
+			summary:   "interactive first-run setup: collects, live-checks and writes the config file",
+			readOnly:  true,
+			workspace: true,
+			run:       runInit,
+		},
+		"compare": {
+			usage:    "compare --left table[@batch] --right table[@batch] [--limit n]",
+			summary:  "diff two tables or batches by item_id (added/removed/changed rows and counts)",
+			readOnly: true,
+			run:      runCompare,
+		},
+		"query": {
+			usage:    "query [--limit n] [--format table|json|csv] statement",
+			summary:  "run a read-only SQL statement and print the rows as a table, JSON or CSV; raw_data is shown as JSON whatever its codec",
+			readOnly: true,
+			run:      runQuery,
+		},
+		"history": {
+			usage:     "history [--limit n] [--batches] [--json] table",
+			summary:   "list a table's Delta versions: operation, user, rows written and deleted, and optionally the batches each added or removed",
+			readOnly:  true,
+			workspace: true,
+			run:       runHistory,
+		},
+		"snapshot": {
+			usage:     "snapshot --as-of version|timestamp [--batch id] [--limit n] [--format table|json|csv] table",
+			summary:   "read a table as it was at a Delta version or timestamp (time travel), optionally one batch's rows",
+			readOnly:  true,
+			workspace: true,
+			run:       runSnapshot,
+		},
+		"rollback": {
+			usage:   "rollback --batch-id id [--restore] [--dry-run] [--json] dataType",
+			summary: "remove one ingestion batch's rows from a data type's tables (DELETE, or RESTORE to the version before it) and report the rows removed",
+			run:     runRollback,
+		},
+		"diagnose": {
+			usage:     "diagnose [--json] [dataType...]",
+			summary:   "check the connection step by step: auth, warehouse, catalog, schema, tables and write permission (probe table)",
+			readOnly:  true,
+			workspace: true,
+			run:       runDiagnose,
+		},
+		"list-warehouses": {
+			usage:     "list-warehouses [--json]",
+			summary:   "list the workspace's SQL warehouses (ID, name, state, size) to pick DATABRICKS_WAREHOUSE_ID or DATABRICKS_WAREHOUSE_NAME",
+			readOnly:  true,
+			workspace: true,
+			run:       runListWarehouses,
+		},
+		"doctor": {
+			usage:     "doctor",
+			summary:   "diagnose setup problems (runtime, config, network, credentials, warehouse, permissions, data)",
+			readOnly:  true,
+			workspace: true,
+			run:       runDoctor,
+		},
+		"verify": {
+			usage:    "verify [--manifest path]",
+			summary:  "check the binary and mappings file against the signed release manifest",
+			readOnly: true,
+			run:      runVerify,
+		},
+		"sign-release": {
+			usage:    "sign-release --key key.pem [--version v] [--out path] artifact...",
+			summary:  "write a signed release manifest of the given binaries and config files",
+			readOnly: true,
+			run:      runSignRelease,
+		},
+		"csv-types": {
+			usage:    "csv-types [--file path] [dataType...]",
+			summary:  "show the type each CSV column is converted to (hinted or inferred) and the values that don't fit",
+			readOnly: true,
+			run:      runCSVTypes,
+		},
+		"generate-mock": {
+			usage:    "generate-mock [--count n] [--seed n] [--start YYYY-MM-DD] [--format JSON|CSV|NDJSON|all] [--out dir] [--force] [dataType...]",
+			summary:  "synthesize realistic records into the BLADE data path for load tests and demos",
+			readOnly: true,
+			run:      runGenerateMock,
+		},
+		"loadtest": {
+			usage:   "loadtest [--records n] [--batch-size n] [--concurrency n] [--schema name] [--seed n] [--json] [dataType]",
+			summary: "ingest generated records concurrently and report throughput, statement latency percentiles and failures",
+			run:     runLoadTest,
+		},
+		"optimize": {
+			usage:   "optimize [--zorder cols] [dataType...]",
+			summary: "compact the BLADE tables' small files, Z-ordering the main tables by item_id and timestamp",
+			run:     runOptimize,
+		},
+		"vacuum": {
+			usage:   "vacuum [--retain d] [--dry-run] [dataType...]",
+			summary: "delete table files older than the retention period (BLADE_VACUUM_RETENTION, default 168h)",
+			run:     runVacuum,
+		},
+		"help": {
+			usage:    "help",
+			summary:  "list available commands",
+			readOnly: true,
+			run:      runHelp,
+		},
+	}
+}
+
+func runHelp(ctx context.Context, cfg *config.Config, args []string) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Usage: go run ./cmd [--profile name] [--backend databricks|local] <command> [arguments]")
+	if cfg.Profile != "" {
+		fmt.Printf("Profile %s (BLADE_PROFILES_FILE): %s, %s.%s\n", cfg.Profile, cfg.DatabricksHost, cfg.CatalogName, cfg.SchemaName)
+	}
+	if cfg.LocalBackend() {
+		fmt.Println("Local backend (BLADE_BACKEND): statements run against an embedded SQLite database; commands marked [disabled] need a workspace")
+	}
+	if cfg.ReadOnly {
+		fmt.Println("Read-only mode (BLADE_READ_ONLY): commands marked [disabled] are unavailable")
+	}
+	fmt.Println()
+	for _, name := range names {
+		summary := commands[name].summary
+		if cfg.ReadOnly && !commands[name].readOnly || cfg.LocalBackend() && commands[name].workspace {
+			summary = "[disabled] " + summary
+		}
+		fmt.Printf("  %-48s %s\n", commands[name].usage, summary)
+	}
+	return nil
+}
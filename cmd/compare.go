@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runCompare(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	leftSpec := flags.String("left", "", "left side: table[@batchID]")
+	rightSpec := flags.String("right", "", "right side: table[@batchID]")
+	limit := flags.Int("limit", 50, "differing rows to list (0 = counts only)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *leftSpec == "" || *rightSpec == "" {
+		return fmt.Errorf("both --left and --right are required, e.g. --left blade_maintenance_data@01J00CF700CEV24T40CVRXPY42 --right blade_maintenance_copy")
+	}
+	left, err := databricks.ParseCompareSide(*leftSpec)
+	if err != nil {
+		return fmt.Errorf("--left: %w", err)
+	}
+	right, err := databricks.ParseCompareSide(*rightSpec)
+	if err != nil {
+		return fmt.Errorf("--right: %w", err)
+	}
+
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	result, err := dbClient.Compare(ctx, left, right, *limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("BATCH COMPARISON")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	fmt.Printf("Left:  %s (%d rows)\n", left, result.LeftRows)
+	fmt.Printf("Right: %s (%d rows)\n\n", right, result.RightRows)
+	fmt.Printf("Added: %d  Removed: %d  Changed: %d  Unchanged: %d\n", result.Added, result.Removed, result.Changed, result.Unchanged)
+	if result.Changed > 0 {
+		fmt.Println("Changed rows per column:")
+		for _, column := range databricks.CompareColumns {
+			if count := result.FieldChanges[column]; count > 0 {
+				fmt.Printf("  %-24s %d\n", column, count)
+			}
+		}
+	}
+	if len(result.Rows) > 0 {
+		fmt.Println()
+		for _, row := range result.Rows {
+			fmt.Printf("  %-8s %s", row.Change, row.ItemID)
+			if len(row.Fields) > 0 {
+				fmt.Printf(" (%s)", strings.Join(row.Fields, ", "))
+			}
+			fmt.Println()
+		}
+		if shown := int64(len(result.Rows)); shown < result.Added+result.Removed+result.Changed {
+			fmt.Printf("  ... %d more (raise --limit)\n", result.Added+result.Removed+result.Changed-shown)
+		}
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+
+	// - Like diff, differences make the command fail, so scripts can gate on it
+	if !result.Identical() {
+		return fmt.Errorf("%s and %s differ", left, right)
+	}
+	return nil
+}
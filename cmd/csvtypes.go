@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/datasource"
+)
+
+func runCSVTypes(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --file: Report on this CSV file instead of the data type's mock file (one data type)
+	flags := flag.NewFlagSet("csv-types", flag.ContinueOnError)
+	file := flags.String("file", "", "CSV file to report on instead of the mock data")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	describer, ok := source.(datasource.CSVDescriber)
+	if !ok {
+		return fmt.Errorf("data source %s doesn't read CSV extracts", source.Name())
+	}
+	dataTypes := flags.Args()
+	if len(dataTypes) == 0 {
+		dataTypes = source.ListTypes()
+	}
+	if *file != "" && len(dataTypes) != 1 {
+		return fmt.Errorf("--file needs exactly one data type, got %v", dataTypes)
+	}
+
+	// Report:
+	// - One line per column: its type, whether it was hinted, a default or inferred, and how many
+	//   values it has; hinted columns also show the values that didn't convert
+	// - Fails when any hinted column has invalid values, so it can gate a new extract
+	invalid := 0
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("CSV COLUMN TYPES")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	for i, dataType := range dataTypes {
+		columns, err := describer.DescribeCSV(dataType, *file)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", dataType)
+		for _, column := range columns {
+			fmt.Printf("  %-28s %-10s %-9s %d value(s)", column.Column, column.Type, column.Origin, column.Values)
+			if column.Invalid > 0 {
+				invalid++
+				fmt.Printf(", %d invalid (first %q, row %d)", column.Invalid, column.Example, column.ExampleRow)
+			}
+			fmt.Println()
+		}
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	if invalid > 0 {
+		return fmt.Errorf("%d column(s) have values that don't match their type hint", invalid)
+	}
+	return nil
+}
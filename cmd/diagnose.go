@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runDiagnose(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --json: Print the reports as JSON (one per namespace)
+	flags := flag.NewFlagSet("diagnose", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the reports as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// - The connection test is one of the checks, so the client is created without it
+	dbClient, err := newDatabricksClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Target Tables:
+	// - Defaults to every data type's tables; data types with a catalog/schema override
+	//   are diagnosed in their own namespace
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	dataTypes := flags.Args()
+	if len(dataTypes) == 0 {
+		dataTypes = source.ListTypes()
+	}
+	type namespace struct{ catalog, schema string }
+	var namespaces []namespace
+	tables := map[namespace][]string{}
+	for _, dataType := range dataTypes {
+		schema, err := source.DescribeSchema(dataType)
+		if err != nil {
+			return err
+		}
+		key := namespace{schema.Catalog, schema.Schema}
+		if _, seen := tables[key]; !seen {
+			namespaces = append(namespaces, key)
+		}
+		tables[key] = append(tables[key], schema.Tables...)
+	}
+
+	var reports []*databricks.DiagnosticReport
+	failed := 0
+	for _, key := range namespaces {
+		report, err := dbClient.InNamespace(key.catalog, key.schema).Diagnose(ctx, tables[key])
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+		if !report.OK() {
+			failed++
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		for _, report := range reports {
+			printDiagnosticReport(report)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("connection diagnostics failed for %d of %d namespace(s)", failed, len(reports))
+	}
+	return nil
+}
+
+func printDiagnosticReport(report *databricks.DiagnosticReport) {
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("CONNECTION DIAGNOSTICS %s.%s", report.Catalog, report.Schema)
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	if report.Principal != "" {
+		fmt.Printf("Principal: %s\n", report.Principal)
+	}
+	fmt.Printf("Warehouse: %s\n\n", report.Warehouse)
+	for _, check := range report.Checks {
+		fmt.Printf("[%s] %-9s %-36s %s", check.Status, check.Name, check.Target, check.Detail)
+		if check.Status != databricks.DiagnosticSkip {
+			fmt.Printf(" (%s)", check.Duration.Round(time.Millisecond))
+		}
+		fmt.Println()
+		if check.Remediation != nil {
+			fmt.Printf("       fix: %s\n", check.Remediation.Hint)
+		}
+	}
+	fmt.Print(strings.Repeat("-", 50) + "\n")
+	if report.OK() {
+		fmt.Printf("No failures (in %s)\n", report.Duration.Round(time.Millisecond))
+	} else {
+		fmt.Printf("Failed (in %s)\n", report.Duration.Round(time.Millisecond))
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+}
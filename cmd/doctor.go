@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"go/version"
+	"net"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/auth"
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+	sdk "github.com/databricks/databricks-sdk-go"
+)
+
+// Oldest Go release the module builds with (go.mod).
+const doctorMinGoVersion = "go1.24"
+
+// Credentials or certificates expiring within this window are reported as warnings.
+const doctorExpiryWarning = 7 * 24 * time.Hour
+
+// Outcome of one doctor check.
+type doctorCheck struct {
+	name   string
+	status string // PASS, WARN, FAIL or SKIP (a check it depends on failed)
+	detail string
+	fix    string // what to do about a WARN or FAIL
+}
+
+func runDoctor(ctx context.Context, cfg *config.Config, args []string) error {
+	var checks []doctorCheck
+	add := func(check doctorCheck) bool {
+		checks = append(checks, check)
+		return check.status != "FAIL" && check.status != "SKIP"
+	}
+	skip := func(reason string, names ...string) {
+		for _, name := range names {
+			add(doctorCheck{name: name, status: "SKIP", detail: reason})
+		}
+	}
+
+	// Order:
+	// - Local checks first, then the network path to the workspace, then the checks
+	//   that need a working connection; a failure skips everything depending on it
+	// - Every check runs, so one run lists all problems instead of the first
+	add(doctorGoRuntime())
+	add(doctorMockData(ctx, cfg))
+	if !add(doctorConfig(cfg)) {
+		skip("needs a complete configuration", "DNS", "TLS", "Credentials", "Warehouse", "Catalog permissions")
+	} else {
+		parsed, _ := url.Parse(cfg.DatabricksHost)
+		if !add(doctorDNS(ctx, parsed.Host)) {
+			skip("needs the workspace host to resolve", "TLS", "Credentials", "Warehouse", "Catalog permissions")
+		} else if !add(doctorTLS(parsed.Host)) {
+			skip("needs a TLS connection to the workspace", "Credentials", "Warehouse", "Catalog permissions")
+		} else if dbClient, check := doctorToken(ctx, cfg); !add(check) {
+			skip("needs valid credentials", "Warehouse", "Catalog permissions")
+		} else {
+			add(doctorWarehouse(ctx, cfg, dbClient))
+			add(doctorPermissions(ctx, cfg, dbClient))
+		}
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("BLADE DOCTOR")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	failed := 0
+	for _, check := range checks {
+		fmt.Printf("[%s] %-20s %s\n", check.status, check.name, check.detail)
+		if check.fix != "" && (check.status == "FAIL" || check.status == "WARN") {
+			for _, line := range strings.Split(check.fix, "\n") {
+				fmt.Printf("       fix: %s\n", line)
+			}
+		}
+		if check.status == "FAIL" {
+			failed++
+		}
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func doctorGoRuntime() doctorCheck {
+	check := doctorCheck{name: "Go runtime", detail: fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH)}
+	if version.IsValid(runtime.Version()) && version.Compare(runtime.Version(), doctorMinGoVersion) < 0 {
+		check.status = "FAIL"
+		check.fix = fmt.Sprintf("install %s or newer from https://go.dev/dl/", doctorMinGoVersion)
+		return check
+	}
+	check.status = "PASS"
+	return check
+}
+
+// Loads every data type in both formats, the same way ingest does.
+func doctorMockData(ctx context.Context, cfg *config.Config) doctorCheck {
+	check := doctorCheck{name: "Mock data"}
+	source, err := newDataSource(cfg)
+	if err != nil {
+		check.status, check.detail = "FAIL", err.Error()
+		check.fix = "fix or unset BLADE_MAPPINGS_FILE"
+		return check
+	}
+	var problems []string
+	dataTypes := source.ListTypes()
+	for _, dataType := range dataTypes {
+		for _, format := range []string{"JSON", "CSV"} {
+			if _, err := source.FetchRecords(ctx, dataType, format); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	if len(problems) > 0 {
+		check.status, check.detail = "FAIL", fmt.Sprintf("%d of %d files unusable", len(problems), 2*len(dataTypes))
+		check.fix = strings.Join(problems, "\n") + "\nset BLADE_DATA_PATH to the directory holding {dataType}/{dataType}_data.json and .csv"
+		return check
+	}
+	check.status, check.detail = "PASS", fmt.Sprintf("%d data types in %s", len(dataTypes), cfg.BLADEDataPath)
+	return check
+}
+
+func doctorConfig(cfg *config.Config) doctorCheck {
+	check := doctorCheck{name: "Configuration", status: "FAIL"}
+	if err := cfg.Validate(); err != nil {
+		problems := strings.Split(err.Error(), "\n")
+		check.detail = fmt.Sprintf("%d setting(s) invalid", len(problems))
+		check.fix = strings.Join(problems, "\n") + "\nrun `go run ./cmd init` or fix them in .env"
+		return check
+	}
+
+	// - The auth provider reports its own missing settings (e.g. DATABRICKS_TOKEN for pat)
+	provider, err := auth.NewProvider(cfg)
+	if err == nil {
+		err = provider.Configure(&sdk.Config{})
+	}
+	if err != nil {
+		check.detail = err.Error()
+		check.fix = "set the credentials for DATABRICKS_AUTH_TYPE in .env, or run `go run ./cmd init`"
+		return check
+	}
+	check.status = "PASS"
+	check.detail = fmt.Sprintf("%s, %s auth, %s.%s", cfg.DatabricksHost, provider.Name(), cfg.CatalogName, cfg.SchemaName)
+	return check
+}
+
+func doctorDNS(ctx context.Context, host string) doctorCheck {
+	check := doctorCheck{name: "DNS"}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, hostOnly(host))
+	if err != nil {
+		check.status, check.detail = "FAIL", err.Error()
+		check.fix = "check DATABRICKS_HOST for typos; on VPN/private link, make sure the workspace's private DNS zone resolves"
+		return check
+	}
+	check.status, check.detail = "PASS", fmt.Sprintf("%s -> %s", hostOnly(host), strings.Join(addrs, ", "))
+	return check
+}
+
+func doctorTLS(host string) doctorCheck {
+	check := doctorCheck{name: "TLS"}
+	address := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		address = net.JoinHostPort(host, "443")
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", address, &tls.Config{})
+	if err != nil {
+		check.status, check.detail = "FAIL", err.Error()
+		check.fix = "check firewall/proxy rules for outbound HTTPS; behind TLS inspection, add the corporate CA to the system trust store"
+		return check
+	}
+	defer conn.Close()
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		check.status, check.detail = "PASS", "handshake OK"
+		return check
+	}
+	expires := certs[0].NotAfter
+	check.status, check.detail = "PASS", fmt.Sprintf("handshake OK, certificate valid until %s", expires.Format("2006-01-02"))
+	if time.Until(expires) < doctorExpiryWarning {
+		check.status = "WARN"
+		check.fix = "the workspace certificate is about to expire; if it is a proxy certificate, tell your network team"
+	}
+	return check
+}
+
+// Connects and authenticates; the returned client is nil when that failed.
+func doctorToken(ctx context.Context, cfg *config.Config) (*databricks.Client, doctorCheck) {
+	check := doctorCheck{name: "Credentials", status: "FAIL"}
+	dbClient, err := databricks.NewClient(cfg)
+	if err != nil {
+		check.detail = err.Error()
+		return nil, check
+	}
+	principal, err := dbClient.CurrentPrincipal(ctx)
+	if err != nil {
+		check.detail = err.Error()
+		check.fix = "the credentials were rejected: create a new token (User Settings > Developer > Access tokens) and update .env"
+		if strings.EqualFold(cfg.AuthType, "oauth-m2m") {
+			check.fix = "the service principal was rejected: check DATABRICKS_CLIENT_ID/DATABRICKS_CLIENT_SECRET and that the principal is added to the workspace"
+		}
+		return nil, check
+	}
+	check.status, check.detail = "PASS", "authenticated as "+principal
+
+	// Token Expiry:
+	// - Only personal access tokens expire on a schedule the workspace can report
+	// - The token in use can't be identified, so the latest expiry of the principal's
+	//   tokens is its upper bound
+	authType := strings.ToLower(cfg.AuthType)
+	if authType != "" && authType != "pat" {
+		return dbClient, check
+	}
+	expiresBy, tokens, err := dbClient.TokenExpiry(ctx)
+	switch {
+	case err != nil:
+		check.status = "WARN"
+		check.detail += fmt.Sprintf(" (token expiry unknown: %v)", err)
+	case tokens == 0:
+	case expiresBy.IsZero():
+		check.detail += ", token does not expire"
+	case time.Until(expiresBy) < doctorExpiryWarning:
+		check.status = "WARN"
+		check.detail += fmt.Sprintf(", token expires by %s", expiresBy.Format(time.RFC3339))
+		check.fix = "create a new token before then and update DATABRICKS_TOKEN"
+	default:
+		check.detail += fmt.Sprintf(", token expires %s at the latest", expiresBy.Format("2006-01-02"))
+	}
+	return dbClient, check
+}
+
+func doctorWarehouse(ctx context.Context, cfg *config.Config, dbClient *databricks.Client) doctorCheck {
+	check := doctorCheck{name: "Warehouse"}
+	if cfg.WarehouseID == "" && cfg.WarehouseName != "" {
+		if err := dbClient.ResolveWarehouse(ctx, cfg.WarehouseName, warehouseCachePath(cfg)); err != nil {
+			check.status, check.detail = "FAIL", err.Error()
+			check.fix = "check DATABRICKS_WAREHOUSE_NAME; list-warehouses shows the warehouses you can use"
+			return check
+		}
+	}
+	state, err := dbClient.WarehouseState(ctx)
+	if err != nil {
+		check.status, check.detail = "FAIL", err.Error()
+		check.fix = "check DATABRICKS_WAREHOUSE_ID (SQL Warehouses > Connection details) and that you have CAN USE on it"
+		return check
+	}
+	check.detail = state
+	switch state {
+	case "RUNNING", "STARTING":
+		check.status = "PASS"
+	case "STOPPED", "STOPPING":
+		check.status = "WARN"
+		check.fix = "start it in the workspace, or set BLADE_WAREHOUSE_AUTO_START=true so commands start it and wait up to BLADE_WAREHOUSE_START_TIMEOUT; until then commands refuse to run"
+		if cfg.WarehouseAutoStart {
+			check.fix = "commands start it (BLADE_WAREHOUSE_AUTO_START); expect the first run to wait a few minutes"
+		}
+	default:
+		check.status = "FAIL"
+		check.fix = "the warehouse is unusable; pick another DATABRICKS_WAREHOUSE_ID"
+	}
+	return check
+}
+
+func doctorPermissions(ctx context.Context, cfg *config.Config, dbClient *databricks.Client) doctorCheck {
+	check := doctorCheck{name: "Catalog permissions", status: "FAIL"}
+	if cfg.Tenant != "" {
+		scoped, err := dbClient.ForTenant(cfg.Tenant, cfg.TenantIsolation)
+		if err != nil {
+			check.detail = err.Error()
+			return check
+		}
+		dbClient = scoped
+	}
+	source, err := newDataSource(cfg)
+	if err != nil {
+		check.detail = err.Error()
+		return check
+	}
+	var tables []string
+	for _, dataType := range source.ListTypes() {
+		schema, _ := source.DescribeSchema(dataType)
+		tables = append(tables, schema.Tables...)
+	}
+	report, err := dbClient.Preflight(ctx, tables)
+	if err != nil {
+		check.detail = err.Error()
+		return check
+	}
+	missing := report.Missing()
+	if len(missing) > 0 {
+		check.detail = fmt.Sprintf("%d privilege(s) missing on %s.%s", len(missing), report.Catalog, report.Schema)
+		var grants []string
+		for _, m := range missing {
+			grants = append(grants, m.Remediation+";")
+		}
+		check.fix = "ask a workspace admin to run:\n" + strings.Join(grants, "\n")
+		return check
+	}
+	check.status, check.detail = "PASS", fmt.Sprintf("all privileges on %s.%s", report.Catalog, report.Schema)
+	return check
+}
+
+// Strips an explicit port from a URL host.
+func hostOnly(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
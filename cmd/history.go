@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runHistory(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --limit: Latest versions to show (default 20, 0 = all)
+	// - --batches: Also show the batches each version added or removed (reads the versions)
+	// - --json: Print the versions as JSON, with their operation parameters and metrics
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	limit := flags.Int("limit", 20, "latest versions to show (0 = all)")
+	batches := flags.Bool("batches", false, "show the batches each version added or removed")
+	asJSON := flags.Bool("json", false, "print the versions as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s", commands["history"].usage)
+	}
+
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	versions, err := dbClient.TableHistory(ctx, flags.Arg(0), *limit, *batches)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(versions)
+	}
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("HISTORY %s", flags.Arg(0))
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "VERSION\tTIMESTAMP\tOPERATION\tUSER\tWRITTEN\tDELETED"
+	if *batches {
+		header += "\tBATCHES"
+	}
+	fmt.Fprintln(table, header)
+	for _, version := range versions {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%d\t%d", version.Version, version.Timestamp, version.Operation,
+			version.User, version.RowsWritten(), version.RowsDeleted())
+		if *batches {
+			var changes []string
+			for _, batch := range version.BatchesAdded {
+				changes = append(changes, "+"+batch)
+			}
+			for _, batch := range version.BatchesRemoved {
+				changes = append(changes, "-"+batch)
+			}
+			fmt.Fprintf(table, "\t%s", strings.Join(changes, " "))
+		}
+		fmt.Fprintln(table)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return nil
+}
+
+func runSnapshot(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --as-of: Version (12) or timestamp (2024-06-01T10:00:00Z, 2024-06-01) to read the table at
+	// - --batch: Only the rows of this batch (metadata['batch_id'])
+	// - --limit: Rows to return (default 100)
+	// - --format: table (default), json or csv, like the query command
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	asOf := flags.String("as-of", "", "version or timestamp to read the table at")
+	batchID := flags.String("batch", "", "only the rows of this batch")
+	limit := flags.Int("limit", 100, "rows to return")
+	format := flags.String("format", databricks.QueryFormatTable, "output format: table, json or csv")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *asOf == "" {
+		return fmt.Errorf("usage: %s", commands["snapshot"].usage)
+	}
+	point, err := databricks.ParseTimeTravel(*asOf)
+	if err != nil {
+		return err
+	}
+	*format = strings.ToLower(*format)
+	if *format != databricks.QueryFormatTable && *format != databricks.QueryFormatJSON && *format != databricks.QueryFormatCSV {
+		return fmt.Errorf("unsupported snapshot format %q (supported: %s, %s, %s)", *format, databricks.QueryFormatTable, databricks.QueryFormatJSON, databricks.QueryFormatCSV)
+	}
+
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	result, err := dbClient.QueryAsOf(ctx, flags.Arg(0), point, *batchID, *limit)
+	if err != nil {
+		return err
+	}
+	if *format == databricks.QueryFormatCSV && result.Truncated {
+		fmt.Fprintf(os.Stderr, "Result truncated at %d rows; raise --limit\n", len(result.Rows))
+	}
+	return databricks.WriteQueryResult(os.Stdout, result, *format)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/datasource"
+	"databricks-blade-poc/internal/jobs"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/runstore"
+)
+
+// Ingests every enabled data type (ingest --all), one run each, continuing past failures.
+//   - args: Optional format (JSON, CSV or NDJSON) applied to every data type
+//   - Disabled data types (BLADE_DISABLED_DATA_TYPES) are skipped with their reason
+//   - forceRecreate: Passed on to every run (ingest --force-recreate)
+//   - workers: Runs executed at the same time (1 = one after another)
+func runIngestAll(ctx context.Context, cfg *config.Config, maxRuntime time.Duration, forceRecreate bool, workers int, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("ingest --all takes at most a format argument, got %v", args)
+	}
+	format := "JSON"
+	if len(args) > 0 {
+		format = strings.ToUpper(args[0])
+	}
+
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	toggles, err := datasource.ParseToggles(cfg.DisabledDataTypes)
+	if err != nil {
+		return err
+	}
+	for _, dataType := range toggles.Unknown(source) {
+		runlog.Printf(ctx, "BLADE_DISABLED_DATA_TYPES names unknown data type %q", dataType)
+	}
+
+	// Worker Pool:
+	// - Up to workers data types are prepared and ingested at the same time, each with its
+	//   own run (log, run store record, reports); the console report of a run is printed
+	//   in one piece when it finishes
+	// - A failing type doesn't stop the others (see jobs.RunEach)
+	dataTypes := source.ListTypes()
+	if workers <= 0 {
+		workers = 1
+	}
+	started := time.Now()
+	skip := func(dataType string) (string, bool) {
+		reason, disabled := toggles.Disabled(dataType)
+		if !disabled {
+			return "", false
+		}
+		runlog.Printf(ctx, "Skipping: %v", toggles.Check(dataType))
+		if reason != "" {
+			return "disabled: " + reason, true
+		}
+		return "disabled", true
+	}
+	outcomes := jobs.RunEach(ctx, dataTypes, workers, skip, func(ctx context.Context, dataType string) (*runstore.Record, error) {
+		return ingestCommand(ctx, cfg, []string{"--max-runtime", maxRuntime.String(), fmt.Sprintf("--force-recreate=%t", forceRecreate), dataType, format})
+	})
+
+	failed := jobs.Failed(outcomes)
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("ALL DATA TYPES (%s, %d worker(s))", format, workers)
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	for _, outcome := range outcomes {
+		fmt.Printf("%-14s %s\n", outcome.DataType, outcome.Summary())
+	}
+	fmt.Print(strings.Repeat("-", 50) + "\n")
+	fmt.Printf("Total: %d rows in %s\n", jobs.TotalRows(outcomes), time.Since(started).Round(time.Millisecond))
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d data types failed: %s", len(failed), len(dataTypes), strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"databricks-blade-poc/internal/auth"
+	"databricks-blade-poc/internal/blade"
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+// A single question of the setup wizard, answered into one environment variable.
+type initPrompt struct {
+	key      string
+	label    string
+	fallback string // default when neither the existing file nor the user provides a value
+	secret   bool   // never echoed back (the input itself is still visible)
+	optional bool
+	validate func(string) error
+}
+
+// Credential prompts per auth type; auth types without an entry need no extra settings.
+var initAuthPrompts = map[string][]initPrompt{
+	"pat": {{key: "DATABRICKS_TOKEN", label: "Personal access token", secret: true}},
+	"oauth-m2m": {
+		{key: "DATABRICKS_CLIENT_ID", label: "Service principal client ID"},
+		{key: "DATABRICKS_CLIENT_SECRET", label: "OAuth secret", secret: true},
+	},
+	"azure-client-secret": {
+		{key: "ARM_TENANT_ID", label: "Entra ID tenant ID"},
+		{key: "ARM_CLIENT_ID", label: "App registration client ID"},
+		{key: "ARM_CLIENT_SECRET", label: "Client secret", secret: true},
+		{key: "DATABRICKS_AZURE_RESOURCE_ID", label: "Workspace Azure resource ID", optional: true},
+	},
+	config.ConfigProfileAuthType: {
+		{key: "DATABRICKS_CONFIG_PROFILE", label: "Databricks CLI profile (~/.databrickscfg)"},
+	},
+	"azure-msi": {
+		{key: "DATABRICKS_AZURE_RESOURCE_ID", label: "Workspace Azure resource ID"},
+		{key: "ARM_CLIENT_ID", label: "User-assigned identity client ID", optional: true},
+	},
+}
+
+var initIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,254}$`)
+
+func runInit(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	path := flags.String("file", ".env", "config file to write")
+	skipChecks := flags.Bool("skip-checks", false, "write the file without the live connection and permission checks")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Defaults:
+	// - Values already in the target file are offered as defaults, so re-running
+	//   init only asks the user to confirm or fix what changed
+	// - Keys the wizard doesn't ask about are carried over unchanged
+	existing, err := config.ReadEnvFile(*path)
+	if err != nil {
+		return err
+	}
+	in := bufio.NewReader(os.Stdin)
+	fmt.Printf("BLADE ingestion setup - answers are written to %s (press Enter to keep [defaults])\n\n", *path)
+
+	answers := existing
+	for {
+		var settings []config.EnvSetting
+		settings, answers, err = askInitSettings(in, os.Stdout, answers)
+		if err != nil {
+			return err
+		}
+		if *skipChecks {
+			return writeInitFile(*path, settings, existing)
+		}
+
+		// Live Validation:
+		// - Connection test (host, credentials, warehouse), then the permission preflight
+		// - A failed check lets the user edit the answers, save anyway, or quit
+		checkErr := checkInitSettings(ctx, cfg, answers)
+		if checkErr == nil {
+			return writeInitFile(*path, settings, existing)
+		}
+		fmt.Printf("\nCheck failed: %v\n", checkErr)
+		choice, err := ask(in, os.Stdout, "[e]dit answers, [s]ave anyway or [q]uit", "e")
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(choice) {
+		case "s", "save":
+			return writeInitFile(*path, settings, existing)
+		case "q", "quit":
+			return fmt.Errorf("setup aborted, %s was not written", *path)
+		}
+		fmt.Println()
+	}
+}
+
+// Asks every wizard question and returns the settings to write plus the answers by key.
+func askInitSettings(in *bufio.Reader, out io.Writer, defaults map[string]string) ([]config.EnvSetting, map[string]string, error) {
+	prompts := []initPrompt{
+		{key: "DATABRICKS_HOST", label: "Workspace URL (https://...)", validate: validateWorkspaceURL},
+		{key: "DATABRICKS_AUTH_TYPE", label: "Auth method (" + strings.Join(auth.Names(), ", ") + ")", fallback: auth.DefaultAuthType, validate: validateAuthType},
+	}
+	answers := make(map[string]string, len(defaults))
+	for key, value := range defaults {
+		answers[key] = value
+	}
+	var settings []config.EnvSetting
+	askAll := func(prompts []initPrompt, comment string) error {
+		for i, prompt := range prompts {
+			value, err := askPrompt(in, out, prompt, answers[prompt.key])
+			if err != nil {
+				return err
+			}
+			answers[prompt.key] = value
+			if value == "" {
+				continue
+			}
+			setting := config.EnvSetting{Key: prompt.key, Value: value}
+			if i == 0 {
+				setting.Comment = comment
+			}
+			settings = append(settings, setting)
+		}
+		return nil
+	}
+
+	if err := askAll(prompts, "Databricks workspace"); err != nil {
+		return nil, nil, err
+	}
+	if err := askAll(initAuthPrompts[strings.ToLower(answers["DATABRICKS_AUTH_TYPE"])], "Credentials"); err != nil {
+		return nil, nil, err
+	}
+	if err := askAll([]initPrompt{
+		{key: "DATABRICKS_WAREHOUSE_ID", label: "SQL warehouse ID"},
+		{key: "DATABRICKS_CATALOG", label: "Catalog", fallback: "blade_poc", validate: validateIdentifier},
+		{key: "DATABRICKS_SCHEMA", label: "Schema", fallback: "logistics", validate: validateIdentifier},
+	}, "Target namespace"); err != nil {
+		return nil, nil, err
+	}
+	if err := askAll([]initPrompt{
+		{key: "BLADE_DATA_PATH", label: "BLADE data directory", fallback: "mock_blade_data/", validate: validateDirectory},
+		{key: "BLADE_MAPPINGS_FILE", label: "Mappings file (empty for the built-in mappings)", optional: true, validate: validateMappingsFile},
+	}, "BLADE data"); err != nil {
+		return nil, nil, err
+	}
+	return settings, answers, nil
+}
+
+// Asks one prompt until its answer validates.
+func askPrompt(in *bufio.Reader, out io.Writer, prompt initPrompt, current string) (string, error) {
+	fallback := current
+	if fallback == "" {
+		fallback = prompt.fallback
+	}
+	for {
+		shown := fallback
+		if prompt.secret && shown != "" {
+			shown = "********"
+		}
+		value, err := ask(in, out, prompt.label, shown)
+		if err != nil {
+			return "", err
+		}
+		if value == shown {
+			value = fallback
+		}
+		if value == "" && !prompt.optional {
+			fmt.Fprintln(out, "  a value is required")
+			continue
+		}
+		if value != "" && prompt.validate != nil {
+			if err := prompt.validate(value); err != nil {
+				fmt.Fprintf(out, "  %v\n", err)
+				continue
+			}
+		}
+		return value, nil
+	}
+}
+
+// Prints "label [fallback]: " and returns the trimmed answer, or fallback for an empty one.
+func ask(in *bufio.Reader, out io.Writer, label, fallback string) (string, error) {
+	if fallback != "" {
+		fmt.Fprintf(out, "%s [%s]: ", label, fallback)
+	} else {
+		fmt.Fprintf(out, "%s: ", label)
+	}
+	line, err := in.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("setup aborted: %w", err)
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return fallback, nil
+}
+
+// Connects with the answers applied over cfg and runs the permission preflight on every BLADE table.
+func checkInitSettings(ctx context.Context, cfg *config.Config, answers map[string]string) error {
+	candidate := *cfg
+	candidate.DatabricksHost = answers["DATABRICKS_HOST"]
+	candidate.AuthType = answers["DATABRICKS_AUTH_TYPE"]
+	candidate.DatabricksToken = answers["DATABRICKS_TOKEN"]
+	candidate.ClientID = answers["DATABRICKS_CLIENT_ID"]
+	candidate.ClientSecret = answers["DATABRICKS_CLIENT_SECRET"]
+	candidate.AzureTenantID = answers["ARM_TENANT_ID"]
+	candidate.AzureClientID = answers["ARM_CLIENT_ID"]
+	candidate.AzureClientSecret = answers["ARM_CLIENT_SECRET"]
+	candidate.AzureResourceID = answers["DATABRICKS_AZURE_RESOURCE_ID"]
+	candidate.DatabricksProfile = answers["DATABRICKS_CONFIG_PROFILE"]
+	candidate.WarehouseID = answers["DATABRICKS_WAREHOUSE_ID"]
+	candidate.CatalogName = answers["DATABRICKS_CATALOG"]
+	candidate.SchemaName = answers["DATABRICKS_SCHEMA"]
+	candidate.BLADEDataPath = answers["BLADE_DATA_PATH"]
+	candidate.MappingsFile = answers["BLADE_MAPPINGS_FILE"]
+
+	fmt.Print("\nTesting connection... ")
+	dbClient, err := databricks.NewClient(&candidate)
+	if err != nil {
+		return err
+	}
+	if err := dbClient.TestConnection(ctx); err != nil {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	fmt.Println("OK")
+
+	// Permissions:
+	// - Missing privileges are reported with their GRANTs but don't block saving;
+	//   they usually need a workspace admin, not a different answer
+	source, err := newDataSource(&candidate)
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for _, dataType := range source.ListTypes() {
+		schema, _ := source.DescribeSchema(dataType)
+		tables = append(tables, schema.Tables...)
+	}
+	fmt.Print("Checking permissions... ")
+	report, err := dbClient.Preflight(ctx, tables)
+	if err != nil {
+		return fmt.Errorf("permission preflight failed: %w", err)
+	}
+	missing := report.Missing()
+	if len(missing) == 0 {
+		fmt.Printf("OK (%s)\n", report.Principal)
+		return nil
+	}
+	fmt.Printf("%d privilege(s) missing for %s; ask a workspace admin to run:\n", len(missing), report.Principal)
+	for _, check := range missing {
+		fmt.Printf("  %s;\n", check.Remediation)
+	}
+	return nil
+}
+
+// Writes the wizard settings followed by the untouched keys of the previous file.
+func writeInitFile(path string, settings []config.EnvSetting, existing map[string]string) error {
+	asked := make(map[string]bool, len(settings))
+	for _, setting := range settings {
+		asked[setting.Key] = true
+	}
+	var kept []config.EnvSetting
+	for key, value := range existing {
+		if !asked[key] {
+			kept = append(kept, config.EnvSetting{Key: key, Value: value})
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Key < kept[j].Key })
+	if len(kept) > 0 {
+		kept[0].Comment = "Other settings"
+	}
+
+	if err := config.WriteEnvFile(path, append(settings, kept...)); err != nil {
+		return err
+	}
+	fmt.Printf("\nWrote %s", path)
+	if len(existing) > 0 {
+		fmt.Printf(" (previous version kept as %s.bak)", path)
+	}
+	fmt.Println("\nNext: go run ./cmd preflight, then go run ./cmd maintenance")
+	return nil
+}
+
+func validateWorkspaceURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("enter the workspace URL, e.g. https://dbc-a1b2c3d4-e5f6.cloud.databricks.com")
+	}
+	return nil
+}
+
+func validateAuthType(value string) error {
+	for _, name := range auth.Names() {
+		if strings.EqualFold(value, name) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported auth method %q (supported: %s)", value, strings.Join(auth.Names(), ", "))
+}
+
+func validateIdentifier(value string) error {
+	if !initIdentifierPattern.MatchString(value) {
+		return fmt.Errorf("%q is not a valid Unity Catalog name (letters, digits, underscores)", value)
+	}
+	return nil
+}
+
+func validateDirectory(value string) error {
+	info, err := os.Stat(value)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", value)
+	}
+	return nil
+}
+
+func validateMappingsFile(value string) error {
+	_, err := blade.LoadMappingsFile(value)
+	return err
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/datasource"
+)
+
+func runLoadTest(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --records: Records generated in memory (generate-mock's generator, no files)
+	// - --batch-size: Records per INSERT statement (default BLADE_INSERT_CHUNK_SIZE)
+	// - --concurrency: Ingestions running at the same time
+	// - --schema: Schema the tables are created in (default {BLADE_SCHEMA}_loadtest), so load
+	//   test rows never mix with real data; drop it when done
+	// - --seed: Generator seed (default: from the clock)
+	// - --json: Print the result as JSON instead of the report
+	flags := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	records := flags.Int("records", 10000, "records to generate and ingest")
+	batchSize := flags.Int("batch-size", cfg.InsertChunkSize, "records per INSERT statement")
+	concurrency := flags.Int("concurrency", 4, "ingestions running at the same time")
+	schema := flags.String("schema", cfg.SchemaName+"_loadtest", "schema to load into")
+	seed := flags.Int64("seed", 0, "generator seed (0 = pick one)")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("loadtest takes at most one data type, got %v", flags.Args())
+	}
+	dataType := "maintenance"
+	if flags.NArg() == 1 {
+		dataType = flags.Arg(0)
+	}
+	if *batchSize <= 0 || *concurrency <= 0 {
+		return fmt.Errorf("--batch-size and --concurrency must be positive")
+	}
+
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	generator, ok := source.(datasource.MockGenerator)
+	if !ok {
+		return fmt.Errorf("data source %s can't generate load test records", source.Name())
+	}
+	opts := datasource.MockOptions{Count: *records, Seed: *seed, Start: time.Now().UTC().AddDate(0, 0, -30)}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	req, err := generator.MockRequest(dataType, opts)
+	if err != nil {
+		return err
+	}
+	// - The data type's catalog/schema override would load outside the load-test schema
+	req.Catalog, req.Schema = "", ""
+
+	testCfg := *cfg
+	testCfg.SchemaName, testCfg.InsertChunkSize = *schema, *batchSize
+	dbClient, err := connectDatabricks(ctx, &testCfg)
+	if err != nil {
+		return err
+	}
+	result, err := dbClient.LoadTest(ctx, req, databricks.LoadTestOptions{Concurrency: *concurrency})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printLoadTest(result, testCfg.CatalogName+"."+*schema)
+	}
+	if result.FailedIngestions > 0 {
+		return fmt.Errorf("%d of %d load test ingestion(s) failed", result.FailedIngestions, result.Ingestions)
+	}
+	return nil
+}
+
+func printLoadTest(result *databricks.LoadTestResult, namespace string) {
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("LOAD TEST")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	fmt.Printf("Table:       %s.%s\n", namespace, result.TableName)
+	fmt.Printf("Records:     %d (%d per INSERT, %d concurrent ingestion(s))\n", result.Records, result.BatchSize, result.Concurrency)
+	fmt.Printf("Warm-up:     %d rows in %s (table creation, warehouse start-up)\n", result.WarmupRows, result.Warmup.Round(time.Millisecond))
+	fmt.Printf("Measured:    %d rows in %s\n", result.RowsIngested, result.Duration.Round(time.Millisecond))
+	fmt.Printf("Throughput:  %.1f rows/sec\n", result.RowsPerSecond)
+	fmt.Printf("Failures:    %d of %d ingestion(s), %d row(s), %.1f%% of statements\n",
+		result.FailedIngestions, result.Ingestions, result.RowsFailed, 100*result.StatementFailureRate())
+	if result.RowsRejected > 0 {
+		fmt.Printf("Rejected:    %d row(s) broke the table's rules\n", result.RowsRejected)
+	}
+	fmt.Println("\nStatement latency:")
+	fmt.Printf("  %-24s %6s %6s %9s %9s %9s %9s\n", "phase/kind", "count", "failed", "p50", "p90", "p99", "max")
+	for _, stats := range result.Statements {
+		name := stats.Kind
+		if stats.Phase != "" {
+			name = stats.Phase + "/" + stats.Kind
+		}
+		fmt.Printf("  %-24s %6d %6d %9s %9s %9s %9s\n", name, stats.Count, stats.Failed,
+			stats.P50.Round(time.Millisecond), stats.P90.Round(time.Millisecond), stats.P99.Round(time.Millisecond), stats.Max.Round(time.Millisecond))
+	}
+	for _, message := range result.Errors {
+		fmt.Printf("Error: %s\n", message)
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+}
@@ -1,60 +1,286 @@
 package main
 
 import (
+	"bytes" // For buffering a run's console report
 	"context" // For cancellation and timeout control
+	"flag" // For ingest command flags
 	"fmt" // For formatted output and string operations
+	"io" // For choosing where the console report is written
 	"log" // For logging messages and fatal errors
 	"strings" // For string manipulation (result formatting)
 	"os" // For command-line argument access
-	"databricks-blade-poc/internal/blade" // BLADE data type handling and file processing
+	"os/signal" // For cancelling the run on SIGINT/SIGTERM
+	"syscall" // For SIGTERM
+	"path/filepath" // For the local database file under BLADE_STATE_DIR
+	_ "databricks-blade-poc/internal/blade" // registers the BLADE data source provider
 	"databricks-blade-poc/internal/config" // Environment variable configuration management
 	"databricks-blade-poc/internal/databricks" // Databricks client and ingestion operations
+	"databricks-blade-poc/internal/datasource" // Pluggable data source providers
+	"databricks-blade-poc/internal/runlog" // Per-run log files
+	"databricks-blade-poc/internal/dictionary" // Inferred source schema for the data dictionary reporter
+	"databricks-blade-poc/internal/lineage" // OpenLineage source fields for the lineage reporter
+	"databricks-blade-poc/internal/local" // Embedded SQLite backend (--backend local)
+	"databricks-blade-poc/internal/report" // Pluggable result reporters
+	"databricks-blade-poc/internal/runstore" // Run history for listing past ingestions
+	"databricks-blade-poc/internal/timeline" // Per-statement timeline of the ingestion
+	"time" // For run history timestamps
 )
 
+// How long shutdown waits for statement submissions to return their IDs for canceling
+// (a submission returns within its server-side wait, 30s by default).
+const shutdownGrace = 35 * time.Second
+
 func main() {
 	// Purpose: Creates base context for all operations
 	// Usage: Passed to Databricks operations for cancellation/timeout control
-	// Future Enhancement: Could add timeout or cancellation handling
-	ctx := context.Background()
+	// Cancellation:
+	// - SIGINT/SIGTERM cancel the context with databricks.ErrInterrupted as the cause, so
+	//   an ingestion stops like one out of budget: its statements are canceled and it's
+	//   recorded as partial with the rows committed so far
+	// - Signal handling is then restored, so a second Ctrl-C exits immediately
+	// - The statement tracker rides along in ctx (see the shutdown below)
+	ctx, interrupt := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		received := <-signals
+		signal.Stop(signals)
+		log.Printf("Received %s, stopping (again to exit immediately)", received)
+		interrupt(fmt.Errorf("%w by %s", databricks.ErrInterrupted, received))
+	}()
+	statements := databricks.NewStatementTracker()
+	ctx = databricks.WithStatementTracker(ctx, statements)
 
 	// Configuration Source:
 	// - Loads from .env file if present
 	// - Falls back to environment variables
 	// - Uses defaults for optional settings
 
+	// - --profile NAME (before the command) selects a profile of BLADE_PROFILES_FILE,
+	//   like BLADE_PROFILE, overriding the one set in the environment
+	// - --backend local (before the command) runs against an embedded SQLite database
+	//   instead of a workspace, like BLADE_BACKEND=local
+
 	// Error Handling:
 	// - Fatal exit if configuration loading fails
 	// - Prevents proceeding with invalid/missing config
+	osArgs, err := globalFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 	cfg, err := config.LoadConfig()
 
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if cfg.Profile != "" {
+		log.Printf("Using profile %s: %s, warehouse %s, %s.%s", cfg.Profile, cfg.DatabricksHost, cfg.WarehouseID, cfg.CatalogName, cfg.SchemaName)
+	}
+
+	// Run Deadline:
+	// - BLADE_RUN_DEADLINE bounds the whole command; an ingestion that hits it ends
+	//   as partial, like one that runs out of --max-runtime
+	if cfg.RunDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.RunDeadline)
+		defer cancel()
+	}
+
+	// Command Dispatch:
+	// - The first argument (after --profile) names a subcommand (e.g. "preflight") when it matches one
+	// - Anything else falls through to the default ingestion command so the
+	//   original "main <dataType> <format>" invocation keeps working
+	name, args := "ingest", osArgs
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			name, args = args[0], args[1:]
+		}
+	}
+
+	// Read-Only Mode:
+	// - Commands that write (ingest, bootstrap, seed-semantics) are refused up front;
+	//   the Databricks client also rejects any write statement on its own
+	if cfg.ReadOnly && !commands[name].readOnly {
+		log.Fatalf("%s is disabled in read-only mode (BLADE_READ_ONLY)", name)
+	}
+
+	// Local Backend:
+	// - Commands about the workspace itself (privileges, dashboards, diagnostics) have
+	//   nothing to work on without one
+	if cfg.LocalBackend() && commands[name].workspace {
+		log.Fatalf("%s needs a Databricks workspace and is unavailable with the local backend (BLADE_BACKEND)", name)
+	}
+
+	// Release Integrity:
+	// - BLADE_INTEGRITY_REQUIRED refuses every command until the binary and mappings file
+	//   match the signed release manifest; verify, sign-release and help still run
+	if cfg.IntegrityRequired && !integrityExempt[name] {
+		if _, err := checkIntegrity(cfg, cfg.IntegrityManifest); err != nil {
+			log.Fatalf("%s refused: release integrity check failed: %v (run verify for details)", name, err)
+		}
+	}
+
+	// Shutdown:
+	// - Statements still executing in the warehouse when the command returns (e.g. after
+	//   an interrupt) are canceled before exiting instead of being left running
+	err = commands[name].run(ctx, cfg, args)
+	if canceled := statements.Shutdown(context.WithoutCancel(ctx), shutdownGrace); len(canceled) > 0 {
+		log.Printf("Canceled %d statement(s) still in flight: %s", len(canceled), strings.Join(canceled, ", "))
+	}
+
+	// Remediation:
+	// - Recognized workspace failures (missing grants, stopped warehouse, ...) are
+	//   followed by what to do about them
+	// - The exit code tells scripts a failed run from a partial one (see exitCode)
+	if err != nil {
+		if remedy, ok := databricks.Remediate(err); ok {
+			log.Printf("%s failed: %v\nHow to fix (%s): %s", name, err, remedy.Code, remedy.Hint)
+		} else {
+			log.Printf("%s failed: %v", name, err)
+		}
+		os.Exit(exitCode(err))
+	}
+}
+
+// Flags accepted before the command, and the environment variable each one sets.
+var globalFlagVariables = map[string]struct{ variable, value string }{
+	"--profile": {"BLADE_PROFILE", "a profile name"},
+	"--backend": {"BLADE_BACKEND", "databricks or local"},
+}
 
+// Removes the leading global flags (--profile NAME, --backend NAME, or --flag=VALUE) from
+// the arguments and sets their environment variables, which the configuration reads.
+func globalFlags(args []string) ([]string, error) {
+	for len(args) > 0 {
+		name, value, inline := strings.Cut(args[0], "=")
+		flag, ok := globalFlagVariables[name]
+		if !ok {
+			return args, nil
+		}
+		if inline {
+			args = args[1:]
+		} else if len(args) < 2 || args[1] == "" {
+			return nil, fmt.Errorf("%s needs %s", name, flag.value)
+		} else {
+			value, args = args[1], args[2:]
+		}
+		if value != "" {
+			os.Setenv(flag.variable, value)
+		}
+	}
+	return args, nil
+}
+
+// Exit codes of a command that returned an error, for scripts and pipelines; a command
+// that succeeds (warnings included) exits with 0.
+const (
+	exitFailed  = 1 // the command or its run failed
+	exitPartial = 3 // the run was stopped (--max-runtime, interrupt) with part of its data loaded
+)
+
+// Returns the exit code of a command's error.
+func exitCode(err error) int {
+	if databricks.IsPartial(err) {
+		return exitPartial
+	}
+	return exitFailed
+}
+
+// Creates the client the configuration describes (backend, warehouse, tenant scope)
+// without running anything on the warehouse; see connectDatabricks.
+func newDatabricksClient(ctx context.Context, cfg *config.Config) (*databricks.Client, error) {
 	// Required Variables Checked:
 	// - DATABRICKS_HOST: Workspace URL
-	// - DATABRICKS_TOKEN: Authentication token
-	// - DATABRICKS_WAREHOUSE_ID: SQL warehouse identifier
+	// - DATABRICKS_WAREHOUSE_ID: SQL warehouse identifier (or DATABRICKS_WAREHOUSE_NAME)
+	// - Credentials (e.g. DATABRICKS_TOKEN) are validated by the selected auth provider
 
-	// Validation Logic: All three must be non-empty strings
-	// Error Message: Directs user to check .env file
-	if cfg.DatabricksHost == "" || cfg.DatabricksToken == "" || cfg.WarehouseID == "" {
-		log.Fatal("The required Databricks environment variables are missing. Check your .env file")
+	// Validation Logic: cfg.Validate checks their format too, with the namespace and paths
+	// Error Message: Lists every problem at once, then directs user to .env or init
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration (fix it in .env or run `go run ./cmd init`):\n%w", err)
 	}
 
-	// Client Initialization:
-	// - Creates authenticated Databricks workspace client
-	// - Configures warehouse, catalog, and schema settings
-	// - Handles SDK initialization and authentication
+	// Local Backend:
+	// - BLADE_BACKEND=local (--backend local) runs every statement against an embedded
+	//   SQLite database (BLADE_LOCAL_DB, default {BLADE_STATE_DIR}/local.db), without a
+	//   workspace or credentials, for development and CI
+	var dbClient *databricks.Client
+	var err error
+	if cfg.LocalBackend() {
+		path := cfg.LocalDatabase
+		if path == "" {
+			path = filepath.Join(cfg.StateDir, local.DefaultDatabase)
+		}
+		executor, err := local.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		// - Volumes, the warehouse's tags and query history only exist in a workspace
+		offline := *cfg
+		if offline.VolumePath != "" {
+			runlog.Printf(ctx, "The local backend has no Volumes, ignoring BLADE_VOLUME_PATH (records are inserted)")
+		}
+		offline.StatementProgress, offline.TagWarehouse, offline.VolumePath = false, false, ""
+		if dbClient, err = databricks.NewClientWithExecutor(&offline, executor); err != nil {
+			return nil, fmt.Errorf("failed to create local client: %w", err)
+		}
+		runlog.Printf(ctx, "Using the local backend (%s)", path)
+	} else {
+		// Client Initialization:
+		// - Creates authenticated Databricks workspace client
+		// - Configures warehouse, catalog, and schema settings
+		// - Handles SDK initialization and authentication
+
+		// Error Scenarios:
+		// - Invalid host URL format
+		// - Authentication failures
+		// - Network connectivity issues
+		dbClient, err = databricks.NewClient(cfg)
 
-	// Error Scenarios:
-	// - Invalid host URL format
-	// - Authentication failures
-	// - Network connectivity issues
-	dbClient, err := databricks.NewClient(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Databricks client: %w", err)
+		}
+	}
 
+	// Warehouse Name:
+	// - DATABRICKS_WAREHOUSE_NAME is looked up when no DATABRICKS_WAREHOUSE_ID is set;
+	//   the resolved ID is cached in BLADE_STATE_DIR/cache (see warehouseCachePath)
+	if !cfg.LocalBackend() && cfg.WarehouseID == "" && cfg.WarehouseName != "" {
+		if err := dbClient.ResolveWarehouse(ctx, cfg.WarehouseName, warehouseCachePath(cfg)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tenant Scoping:
+	// - BLADE_TENANT isolates one exercise/org unit's catalog or schema (BLADE_TENANT_ISOLATION)
+	// - Every command works against the scoped namespace without further changes
+	if cfg.Tenant != "" {
+		dbClient, err = dbClient.ForTenant(cfg.Tenant, cfg.TenantIsolation)
+		if err != nil {
+			return nil, err
+		}
+		catalog, schema := dbClient.Namespace()
+		runlog.Printf(ctx, "Scoped to tenant %s (%s.%s)", cfg.Tenant, catalog, schema)
+	}
+
+	return dbClient, nil
+}
+
+// Creates the client and checks that the warehouse runs statements.
+func connectDatabricks(ctx context.Context, cfg *config.Config) (*databricks.Client, error) {
+	dbClient, err := newDatabricksClient(ctx, cfg)
 	if err != nil {
-		log.Fatalf("Failed to create Databricks client: %v", err)
+		return nil, err
+	}
+
+	// Warehouse Readiness:
+	// - A stopped warehouse fails fast, or is started (BLADE_WAREHOUSE_AUTO_START) and
+	//   waited for up to BLADE_WAREHOUSE_START_TIMEOUT, before the first statement
+	// - The local backend has no warehouse
+	if !cfg.LocalBackend() {
+		if err := dbClient.EnsureWarehouseRunning(ctx); err != nil {
+			return nil, err
+		}
 	}
 
 	// Pre-flight Validation:
@@ -66,13 +292,96 @@ func main() {
 	// - Shows "Testing..." message for user awareness
 	// - Confirms successful connection before proceeding
 	// - Fails fast if Databricks is unreachable
-	log.Println("Testing Databricks connection...")
+	runlog.Printf(ctx, "Testing Databricks connection...")
 	if err := dbClient.TestConnection(ctx); err != nil {
-		log.Fatalf("Failed to connect to Databricks: %v", err)
+		return nil, fmt.Errorf("failed to connect to Databricks: %w", err)
+	}
+	runlog.Printf(ctx, "Successfully connected to Databricks")
+
+	return dbClient, nil
+}
+
+// Returns the file warehouse names resolved from DATABRICKS_WAREHOUSE_NAME are cached in.
+func warehouseCachePath(cfg *config.Config) string {
+	return runstore.CachePath(cfg.StateDir, "warehouses.json")
+}
+
+func newDataSource(cfg *config.Config) (datasource.Provider, error) {
+	// Provider Selection:
+	// - BLADE_DATA_PROVIDER picks the registered source (default: blade)
+	// - Commands only use the datasource.Provider contract, so new feeds need no changes here
+	return datasource.NewProvider(cfg)
+}
+
+func runIngest(ctx context.Context, cfg *config.Config, args []string) error {
+	_, err := ingestCommand(ctx, cfg, args)
+	return err
+}
+
+// Runs the ingest command and returns the run's record (nil when it failed before a run started, or for --all).
+func ingestCommand(ctx context.Context, cfg *config.Config, args []string) (record *runstore.Record, err error) {
+	// Flags (before the positional arguments):
+	// - --max-runtime: End-to-end budget (default BLADE_MAX_RUNTIME, 0 = none); when it runs
+	//   out, outstanding statements are cancelled and a partial result is recorded
+	// - --source: Real BLADE file or directory loaded with COPY INTO instead of the mock data;
+	//   a /Volumes/... path is loaded in place, a local one is uploaded to BLADE_VOLUME_PATH first
+	// - --advana-snapshot: Advana-exported Parquet snapshot directory (with its manifest.json)
+	//   loaded with COPY INTO into the same table as the direct BLADE feed
+	// - --all: Every data type, each as its own run; disabled types are skipped
+	// - --workers: Data types --all ingests at the same time (default BLADE_INGEST_WORKERS, 4)
+	// - --force-recreate: Drop and recreate a table whose column types no longer match its
+	//   mapping (its rows are lost); without it such a run fails before loading anything
+	// - --resume: Batch of an earlier run to resume (BLADE_BATCH_MANIFEST=true), replaying only
+	//   the insert chunks it didn't commit
+	// - --output: table (default) prints the console report; json and yaml print the run's
+	//   report (as the json reporter writes it) to stdout instead, moving the console
+	//   report to stderr, so a script can parse stdout and check the exit code (see exitCode)
+	flags := flag.NewFlagSet("ingest", flag.ContinueOnError)
+	maxRuntime := flags.Duration("max-runtime", cfg.MaxRuntime, "stop and return a partial result after this long (0 = no limit)")
+	sourcePath := flags.String("source", "", "load this BLADE file or directory (local or /Volumes/...) with COPY INTO instead of the mock data")
+	snapshotDir := flags.String("advana-snapshot", "", "load this data type from an Advana Parquet snapshot directory with COPY INTO")
+	all := flags.Bool("all", false, "ingest every enabled data type, one run each")
+	workers := flags.Int("workers", cfg.IngestWorkers, "with --all: data types ingested at the same time")
+	forceRecreate := flags.Bool("force-recreate", false, "drop and recreate tables whose column types changed (deletes their rows)")
+	resume := flags.String("resume", "", "resume this batch, replaying only the insert chunks it didn't commit (needs BLADE_BATCH_MANIFEST=true)")
+	output := flags.String("output", outputTable, "how the result is printed: table, json or yaml")
+	if err := flags.Parse(args); err != nil {
+		return record, err
+	}
+	args = flags.Args()
+	if *output = strings.ToLower(*output); *output != outputTable && *output != report.FormatJSON && *output != report.FormatYAML {
+		return record, fmt.Errorf("unsupported --output %q (supported: %s, %s, %s)", *output, outputTable, report.FormatJSON, report.FormatYAML)
+	}
+	if *sourcePath != "" && *snapshotDir != "" {
+		return record, fmt.Errorf("--source and --advana-snapshot are alternative sources; use one")
+	}
+	if *all {
+		if *sourcePath != "" || *snapshotDir != "" {
+			return record, fmt.Errorf("--all loads the mock data of every data type and can't be combined with --source or --advana-snapshot")
+		}
+		if *resume != "" {
+			return record, fmt.Errorf("--resume names the batch of one data type and can't be combined with --all")
+		}
+		if *output != outputTable {
+			return record, fmt.Errorf("--output %s prints the result of one data type and can't be combined with --all", *output)
+		}
+		return nil, runIngestAll(ctx, cfg, *maxRuntime, *forceRecreate, *workers, args)
+	}
+
+	// Per-Run Logging:
+	// - Every ingestion run gets an ID and its own log file under BLADE_LOG_DIR
+	// - Lines are still mirrored to stderr for interactive use
+	// - The run rides along in ctx so client-side logging lands in the same file
+	run, err := runlog.Start(cfg.LogDir, runlog.NewRunID(), os.Stderr)
+	if err != nil {
+		return record, err
 	}
-	log.Println("Successfully connected to Databricks")
+	defer run.Close()
+	ctx = runlog.WithRun(ctx, run)
+	run.Printf("Logging to %s", run.Path)
 
 	// Adapter Configuration:
+	// - Provider: BLADE_DATA_PROVIDER (default: blade)
 	// - DataSource: "BLADE_LOGISTICS" (from config)
 	// - DataPath: "mock_blade_data/" (from config)
 
@@ -80,38 +389,57 @@ func main() {
 	// - Loads all 4 BLADE data type mappings
 	// - Indexes them by data type for fast lookup
 	// - Shows supported types for user reference
-	bladeAdapter := blade.NewBLADEAdapter(
-		cfg.BLADEDataSource,
-		cfg.BLADEDataPath,
-	)
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return record, err
+	}
 
-	log.Printf("Supported BLADE data types: %v", bladeAdapter.GetSupportedDataTypes())
+	runlog.Printf(ctx, "Supported %s data types: %v", source.Name(), source.ListTypes())
 
 	// Default Values:
 	// - dataType: "maintenance" if not specified
 	// - format: "JSON" if not specified
 
 	// Argument Processing:
-	// - os.Args[1]: Data type (maintenance, sortie, deployment, logistics)
-	// - os.Args[2]: Format (JSON or CSV, case-insensitive)
+	// - args[0]: Data type (maintenance, sortie, deployment, logistics)
+	// - args[1]: Format (JSON, CSV or NDJSON, case-insensitive)
 
 	// Format Validation:
 	// - Converts to uppercase for consistency
 	// - Validates against allowed values
 	// - Fatal error for invalid formats
+
 	dataType := "maintenance"
 	format := "JSON"
 	
-	if len(os.Args) > 1 {
-		dataType = os.Args[1]
+	if len(args) > 0 {
+		dataType = args[0]
 	}
 	
-	if len(os.Args) > 2 {
-		format = strings.ToUpper(os.Args[2])
-		if format != "JSON" && format != "CSV" {
-			log.Fatalf("Invalid format: %s. Use JSON or CSV", format)
+	if len(args) > 1 {
+		format = strings.ToUpper(args[1])
+		if format != "JSON" && format != "CSV" && format != "NDJSON" {
+			return record, fmt.Errorf("invalid format: %s. Use JSON, CSV or NDJSON", format)
 		}
 	}
+	if *snapshotDir != "" {
+		// - Snapshots are always Parquet; the manifest names the columns
+		if len(args) > 1 {
+			return record, fmt.Errorf("--advana-snapshot loads Parquet and takes no format argument")
+		}
+		format = "PARQUET"
+	}
+
+	// Disabled Data Types:
+	// - BLADE_DISABLED_DATA_TYPES refuses switched-off types (with the configured reason)
+	//   before anything is recorded or sent to the workspace
+	toggles, err := datasource.ParseToggles(cfg.DisabledDataTypes)
+	if err != nil {
+		return record, err
+	}
+	if err := toggles.Check(dataType); err != nil {
+		return record, err
+	}
 
 	// Two-Step Process:
 
@@ -127,34 +455,197 @@ func main() {
 	// - Verifies row count matches insertion
 	// - Returns detailed IngestionResult
 
-	// Error Handling: Fatal exit on any failure with descriptive messages
+	// Error Handling: Returns descriptive errors; main exits fatally on any of them
 
-	log.Printf("Starting ingestion for BLADE data (type: %s, format: %s)", dataType, format)
+	// Run History:
+	// - Each run is recorded in the local run store (BLADE_STATE_DIR) under its run ID
+	// - The deferred update marks it completed or failed with the final error/result
+	store, err := runstore.Open(cfg.StateDir)
+	if err != nil {
+		return record, err
+	}
+	started := time.Now().UTC()
+	record = &runstore.Record{
+		ID:          run.ID,
+		DataType:    dataType,
+		Format:      format,
+		Tenant:      cfg.Tenant,
+		Status:      runstore.StatusRunning,
+		SubmittedAt: started,
+		StartedAt:   &started,
+	}
+	if err := store.Save(record); err != nil {
+		return record, err
+	}
+	defer func() {
+		finished := time.Now().UTC()
+		record.FinishedAt = &finished
+		record.Status = runstore.StatusCompleted
+		if err != nil {
+			record.Status = runstore.StatusFailed
+			if databricks.IsPartial(err) {
+				record.Status = runstore.StatusPartial
+			}
+			record.Error = err.Error()
+			if remedy, ok := databricks.Remediate(err); ok {
+				record.Remediation = &remedy
+			}
+		}
+		if saveErr := store.Save(record); saveErr != nil {
+			runlog.Printf(ctx, "Could not record run history: %v", saveErr)
+		}
+	}()
 
-	req, err := bladeAdapter.PrepareIngestionRequest(dataType, format)
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return record, err
+	}
+
+	runlog.Printf(ctx, "Starting ingestion for BLADE data (type: %s, format: %s)", dataType, format)
+
+	var req *databricks.IngestionRequest
+	budget := *maxRuntime
+	if *sourcePath != "" {
+		loader, ok := source.(datasource.FileLoader)
+		if !ok {
+			return record, fmt.Errorf("data source %s cannot load files (--source)", source.Name())
+		}
+		req, err = loader.FetchFiles(dataType, format, *sourcePath)
+	} else if *snapshotDir != "" {
+		loader, ok := source.(datasource.SnapshotLoader)
+		if !ok {
+			return record, fmt.Errorf("data source %s cannot load Advana snapshots (--advana-snapshot)", source.Name())
+		}
+		req, err = loader.FetchSnapshot(dataType, *snapshotDir)
+	} else {
+		// - A live mode fetch counts against --max-runtime: the load gets what is left of it
+		fetchCtx := ctx
+		if budget > 0 {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithTimeout(ctx, budget)
+			defer cancel()
+		}
+		fetchStarted := time.Now()
+		req, err = source.FetchRecords(fetchCtx, dataType, format)
+		if budget > 0 {
+			budget = max(budget-time.Since(fetchStarted), time.Nanosecond)
+		}
+	}
 
 	if err != nil {
-		log.Fatalf("Failed to prepare ingestion request: %v", err)
+		return record, fmt.Errorf("failed to prepare ingestion request: %w", err)
 	}
+	req.ForceRecreate, req.ResumeBatchID = *forceRecreate, *resume
 
-	result, err := dbClient.IngestBLADEData(ctx, req)
+	result, err := ingestAndReport(ctx, cfg, dbClient, source, run, dataType, format, req, budget, *output)
+	record.Result = result
+	return record, err
+}
 
+// How ingest --output prints the result by default: the console reporter's table.
+const outputTable = "table"
+
+// Loads a prepared request and publishes the run's report to the configured reporters.
+//   - Shared by the CLI and the REST server (serve), so both report runs the same way
+//   - The result is returned even when the ingestion failed
+//   - output: outputTable, or report.FormatJSON / report.FormatYAML to also print the
+//     report to stdout, with the console report on stderr
+func ingestAndReport(ctx context.Context, cfg *config.Config, dbClient *databricks.Client, source datasource.Provider, run *runlog.Run, dataType, format string, req *databricks.IngestionRequest, maxRuntime time.Duration, output string) (*databricks.IngestionResult, error) {
+	// Reporters:
+	// - BLADE_REPORTERS picks the destinations (console, json, html, webhook, history, audit, lineage, dictionary, timeline)
+	// - Built before ingesting so a misconfigured reporter fails fast
+	// - Console output is written in one piece once published, so runs ingested in
+	//   parallel (ingest --all) don't interleave their reports
+	var console bytes.Buffer
+	consoleOut := io.Writer(os.Stdout)
+	if output != outputTable {
+		consoleOut = os.Stderr
+	}
+	defer func() { consoleOut.Write(console.Bytes()) }()
+	reporters, err := report.New(cfg.Reporters, report.Options{
+		Out:        &console,
+		Dir:        cfg.ReportDir,
+		WebhookURL: cfg.ReportWebhookURL,
+		LineageURL: cfg.LineageURL,
+		Namespace:  cfg.DatabricksHost,
+		History:    dbClient,
+		Audit:      dbClient,
+		Columns:    dbClient,
+		Gantt:      cfg.TimelineGantt,
+	})
 	if err != nil {
-		log.Fatalf("Ingestion failed: %v", err)
-	}
-
-	// Formatted Output Design:
-	// - Header/Footer: 50-character equals sign borders
-	// - Separator: Dashed line under title
-	// - Key Metrics: Table name, status, row count, timing
-	// - Source Indicator: Clearly marks as mock data
-	fmt.Printf("\n" + strings.Repeat("=", 50) + "\n")
-	fmt.Printf("BLADE INGESTION RESULTS")
-	fmt.Printf("\n" + strings.Repeat("-", 50) + "\n")
-	fmt.Printf("Table: %s\n", result.TableName)
-	fmt.Printf("Status: %s\n", result.Status)
-	fmt.Printf("Rows Ingested: %d\n", result.RowsIngested)
-	fmt.Printf("Duration: %s\n", result.Duration)
-	fmt.Printf("Source: BLADE (mock)")
-	fmt.Printf("\n" + strings.Repeat("=", 50) + "\n")
-}
\ No newline at end of file
+		return nil, err
+	}
+
+	// Query Cache:
+	// - Row counts and column descriptions are read once per run (and table write), however
+	//   many steps and reporters ask for them
+	// - BLADE_QUERY_CACHE_TTL > 0 keeps them in BLADE_STATE_DIR/cache for the following runs
+	queryCache, err := databricks.OpenQueryCache(runstore.CachePath(cfg.StateDir, "query-cache.json"), cfg.QueryCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	ctx = databricks.WithQueryCache(ctx, queryCache)
+	defer func() {
+		if saveErr := queryCache.Save(); saveErr != nil {
+			runlog.Printf(ctx, "Could not save the query cache: %v", saveErr)
+		}
+	}()
+
+	// Statement Timeline:
+	// - Every statement of the ingestion records its start/end (and phase) for the timeline reporter
+	// - The budget only bounds the ingestion itself; recording and reporting still run afterwards
+	runTimeline := timeline.New()
+	ingestCtx := timeline.WithTimeline(ctx, runTimeline)
+	if maxRuntime > 0 {
+		var cancel context.CancelFunc
+		ingestCtx, cancel = context.WithTimeout(ingestCtx, maxRuntime)
+		defer cancel()
+	}
+	started := time.Now().UTC()
+	result, err := dbClient.IngestBLADEData(ingestCtx, req)
+
+	// Result Reporting:
+	// - Failed runs are reported too, so webhooks and the history and audit tables see them
+	// - A reporter failing is logged but doesn't change the run's outcome
+	catalog, schema := dbClient.InNamespace(req.Catalog, req.Schema).Namespace()
+	target, _ := source.DescribeSchema(dataType)
+	runReport := &report.Report{
+		RunID:        run.ID,
+		DataType:     dataType,
+		Format:       format,
+		Tenant:       cfg.Tenant,
+		LogPath:      run.Path,
+		StartedAt:    started,
+		FinishedAt:   time.Now().UTC(),
+		Result:       result,
+		SourcePath:   req.SourcePath,
+		SourceURL:    req.Metadata["source_url"],
+		SourceHash:   databricks.SourceFileHash(req),
+		SourceFields: lineage.SourceFields(req.SampleData),
+		TargetTable:  fmt.Sprintf("%s.%s.%s", catalog, schema, req.TableName),
+		Tables:       target.Tables,
+		Semantics:    &target.Semantics,
+		SourceSchema: dictionary.InferFields(req.SampleData),
+	}
+	export := runTimeline.Export()
+	runReport.Timeline = &export
+	if err != nil {
+		runReport.Error = err.Error()
+	}
+	// - An interrupted run is still reported, with the progress it made
+	if publishErr := report.Publish(context.WithoutCancel(ctx), reporters, runReport); publishErr != nil {
+		runlog.Printf(ctx, "Reporting failed: %v", publishErr)
+	}
+	if output != outputTable {
+		if encodeErr := report.Encode(os.Stdout, runReport, output); encodeErr != nil {
+			runlog.Printf(ctx, "Could not print the result as %s: %v", output, encodeErr)
+		}
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("ingestion failed: %w", err)
+	}
+
+	return result, nil
+}
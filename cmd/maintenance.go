@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runOptimize(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --zorder: Columns the data types' main tables are Z-ordered by (child and crew
+	//   tables are only compacted, they have no timestamp; clustered tables are
+	//   clustered instead, and partition columns are left out)
+	flags := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	zorder := flags.String("zorder", strings.Join(databricks.DefaultZOrder, ","), "comma-separated columns to Z-order the main tables by (empty = compaction only)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var columns []string
+	for _, column := range strings.Split(*zorder, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+
+	targets, err := maintenanceTargets(cfg, flags.Args())
+	if err != nil {
+		return err
+	}
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("OPTIMIZE")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	failed := 0
+	for _, target := range targets {
+		var zorderBy []string
+		if target.main {
+			zorderBy = target.layout.ZOrderColumns(columns)
+		}
+		result, err := dbClient.InNamespace(target.catalog, target.schema).OptimizeTable(ctx, target.table, zorderBy)
+		if reportMaintenanceError(target.table, err, &failed) {
+			continue
+		}
+		fmt.Printf("%-36s %d file(s) compacted into %d in %s", result.Table, result.FilesRemoved, result.FilesAdded, result.Duration.Round(time.Millisecond))
+		if len(result.ZOrderBy) > 0 {
+			fmt.Printf(", Z-ordered by %s", strings.Join(result.ZOrderBy, ", "))
+		} else if target.main && target.layout.Clustered() {
+			fmt.Printf(", clustered by %s", strings.Join(target.layout.ClusterBy, ", "))
+		}
+		fmt.Println()
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	if failed > 0 {
+		return fmt.Errorf("%d of %d table(s) failed to optimize", failed, len(targets))
+	}
+	return nil
+}
+
+func runVacuum(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --retain: Table versions whose files are kept (default BLADE_VACUUM_RETENTION;
+	//   Delta refuses less than 168h)
+	// - --dry-run: List the files that would be deleted instead of deleting them
+	flags := flag.NewFlagSet("vacuum", flag.ContinueOnError)
+	retain := flags.Duration("retain", cfg.VacuumRetention, "retention period, at least 168h")
+	dryRun := flags.Bool("dry-run", false, "list the files that would be deleted")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *retain < databricks.MinVacuumRetention {
+		return fmt.Errorf("--retain %s is below Delta's minimum of %s", *retain, databricks.MinVacuumRetention)
+	}
+
+	targets, err := maintenanceTargets(cfg, flags.Args())
+	if err != nil {
+		return err
+	}
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	if *dryRun {
+		fmt.Print("VACUUM (DRY RUN)")
+	} else {
+		fmt.Print("VACUUM")
+	}
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	fmt.Printf("Retaining %s of table history\n", *retain)
+	failed := 0
+	for _, target := range targets {
+		result, err := dbClient.InNamespace(target.catalog, target.schema).VacuumTable(ctx, target.table, *retain, *dryRun)
+		if reportMaintenanceError(target.table, err, &failed) {
+			continue
+		}
+		if !result.DryRun {
+			fmt.Printf("%-36s vacuumed in %s\n", result.Table, result.Duration.Round(time.Millisecond))
+			continue
+		}
+		fmt.Printf("%-36s %d file(s) would be deleted\n", result.Table, len(result.Files))
+		for _, file := range result.Files {
+			fmt.Printf("  %s\n", file)
+		}
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	if failed > 0 {
+		return fmt.Errorf("%d of %d table(s) failed to vacuum", failed, len(targets))
+	}
+	return nil
+}
+
+// A table optimize or vacuum runs on; main is the data type's own table, created with layout.
+//   - catalog/schema: The data type's namespace override (empty: the configured one)
+type maintenanceTarget struct {
+	table   string
+	main    bool
+	layout  *databricks.TableLayout
+	catalog string
+	schema  string
+}
+
+// Returns the tables of the given data types (default: every data type), main table first.
+func maintenanceTargets(cfg *config.Config, dataTypes []string) ([]maintenanceTarget, error) {
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataTypes) == 0 {
+		dataTypes = source.ListTypes()
+	}
+	var targets []maintenanceTarget
+	for _, dataType := range dataTypes {
+		schema, err := source.DescribeSchema(dataType)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range schema.Tables {
+			target := maintenanceTarget{table: table, main: table == schema.TableName, catalog: schema.Catalog, schema: schema.Schema}
+			if target.main {
+				target.layout = schema.Layout
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// Prints a table's failure and reports whether there was one.
+//   - Tables that don't exist yet (data type never ingested) are skipped, not counted
+func reportMaintenanceError(table string, err error, failed *int) bool {
+	if err == nil {
+		return false
+	}
+	if remedy, ok := databricks.Remediate(err); ok && remedy.Code == databricks.RemedyTableNotFound {
+		fmt.Printf("%-36s skipped: not created yet\n", table)
+		return true
+	}
+	*failed++
+	fmt.Printf("%-36s FAILED: %v\n", table, err)
+	return true
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runPreflight(ctx context.Context, cfg *config.Config, args []string) error {
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Target Tables:
+	// - Defaults to every supported BLADE data type
+	// - Explicit data types narrow the check to the tables about to be loaded
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	dataTypes := args
+	if len(dataTypes) == 0 {
+		dataTypes = source.ListTypes()
+	}
+
+	// - Data types with a catalog/schema override are checked in their own namespace
+	type namespace struct{ catalog, schema string }
+	var namespaces []namespace
+	tables := map[namespace][]string{}
+	for _, dataType := range dataTypes {
+		schema, err := source.DescribeSchema(dataType)
+		if err != nil {
+			return err
+		}
+		key := namespace{schema.Catalog, schema.Schema}
+		if _, seen := tables[key]; !seen {
+			namespaces = append(namespaces, key)
+		}
+		tables[key] = append(tables[key], schema.Tables...)
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("PREFLIGHT PERMISSION CHECK")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	var missing []databricks.PermissionCheck
+	for i, key := range namespaces {
+		report, err := dbClient.InNamespace(key.catalog, key.schema).Preflight(ctx, tables[key])
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			fmt.Printf("Principal: %s\n", report.Principal)
+		}
+		fmt.Printf("Target: %s.%s\n\n", report.Catalog, report.Schema)
+		for _, check := range report.Checks {
+			status := "OK     "
+			if !check.Granted {
+				status = "MISSING"
+			}
+			fmt.Printf("[%s] %-14s %s", status, check.Privilege, check.Securable)
+			if check.Via != "" {
+				fmt.Printf(" (%s)", check.Via)
+			}
+			fmt.Println()
+		}
+		if i < len(namespaces)-1 {
+			fmt.Println()
+		}
+		missing = append(missing, report.Missing()...)
+	}
+
+	if len(missing) == 0 {
+		fmt.Printf("\nAll required privileges are in place")
+		fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+		return nil
+	}
+
+	fmt.Printf("\nAsk a workspace admin to run:\n")
+	for _, check := range missing {
+		fmt.Printf("  %s;\n", check.Remediation)
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return fmt.Errorf("%d required privilege(s) missing", len(missing))
+}
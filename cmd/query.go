@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runQuery(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags (before the statement):
+	// - --limit: Rows to return (default 100)
+	// - --format: table (default), json or csv; --json is short for --format json
+	// - raw_data is always printed as JSON, decoded from whatever codec stored it
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	limit := flags.Int("limit", 100, "rows to return")
+	format := flags.String("format", databricks.QueryFormatTable, "output format: table, json or csv")
+	asJSON := flags.Bool("json", false, "print the result as JSON (--format json)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	statement := strings.TrimSpace(strings.Join(flags.Args(), " "))
+	if statement == "" {
+		return fmt.Errorf("usage: %s", commands["query"].usage)
+	}
+	if *asJSON {
+		*format = databricks.QueryFormatJSON
+	}
+	*format = strings.ToLower(*format)
+	if *format != databricks.QueryFormatTable && *format != databricks.QueryFormatJSON && *format != databricks.QueryFormatCSV {
+		return fmt.Errorf("unsupported query format %q (supported: %s, %s, %s)", *format, databricks.QueryFormatTable, databricks.QueryFormatJSON, databricks.QueryFormatCSV)
+	}
+
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	result, err := dbClient.Query(ctx, statement, *limit)
+	if err != nil {
+		return err
+	}
+	// - CSV output stays a clean file; the truncation note goes to stderr
+	if *format == databricks.QueryFormatCSV && result.Truncated {
+		fmt.Fprintf(os.Stderr, "Result truncated at %d rows; raise --limit\n", len(result.Rows))
+	}
+	return databricks.WriteQueryResult(os.Stdout, result, *format)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runRollback(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --batch-id: The batch to remove (metadata['batch_id'], e.g. from the ingest result,
+	//   status or history --batches)
+	// - --restore: RESTORE the main table to the version before the batch instead of
+	//   deleting its rows; refused when later versions changed other batches
+	// - --dry-run: Count the rows that would be removed, change nothing
+	// - --json: Print the result as JSON
+	flags := flag.NewFlagSet("rollback", flag.ContinueOnError)
+	batchID := flags.String("batch-id", "", "batch to remove")
+	restore := flags.Bool("restore", false, "restore the main table to the version before the batch")
+	dryRun := flags.Bool("dry-run", false, "count the rows that would be removed")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *batchID == "" {
+		return fmt.Errorf("usage: %s", commands["rollback"].usage)
+	}
+	if *restore && cfg.LocalBackend() {
+		return fmt.Errorf("--restore needs Delta time travel, which the local backend (BLADE_BACKEND=local) doesn't have")
+	}
+
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	schema, err := source.DescribeSchema(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	result, err := dbClient.InNamespace(schema.Catalog, schema.Schema).RollbackBatch(ctx, *batchID, schema.Tables, databricks.RollbackOptions{Restore: *restore, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	if result.DryRun {
+		fmt.Printf("ROLLBACK %s (DRY RUN)", result.BatchID)
+	} else {
+		fmt.Printf("ROLLBACK %s", result.BatchID)
+	}
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	if result.RestoredVersion != nil {
+		fmt.Printf("%s restored to version %d\n", schema.TableName, *result.RestoredVersion)
+	}
+	for _, table := range result.Tables {
+		if table.Skipped {
+			fmt.Printf("%-36s skipped: not created yet\n", table.Table)
+			continue
+		}
+		fmt.Printf("%-36s %d row(s)\n", table.Table, table.Rows)
+		if table.Restored > 0 {
+			fmt.Printf("%-36s %d row(s) restored\n", table.Table+"_archive", table.Restored)
+		}
+	}
+	fmt.Print(strings.Repeat("-", 50) + "\n")
+	if result.DryRun {
+		fmt.Printf("Rows that would be removed: %d\n", result.RowsRemoved)
+		if result.RowsRestored > 0 {
+			fmt.Printf("Archived rows that would be restored: %d\n", result.RowsRestored)
+		}
+	} else {
+		fmt.Printf("Rows removed: %d (in %s)\n", result.RowsRemoved, result.Duration.Round(time.Millisecond))
+		if result.RowsRestored > 0 {
+			fmt.Printf("Archived rows restored: %d\n", result.RowsRestored)
+		}
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return nil
+}
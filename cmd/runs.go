@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/runstore"
+)
+
+func runRuns(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --type / --status / --tenant: Only runs of that data type, state or tenant
+	// - --since: Only runs submitted within that long (e.g. 72h) or since that date (2006-01-02 or RFC 3339)
+	// - --limit: Latest runs to show (default 20, 0 = all)
+	// - --json: Print the records as JSON (the API's Ingestion schema)
+	// - runID: Show that run's details instead of the list (like status)
+	// - Reads the local run store (BLADE_STATE_DIR), so it needs no workspace
+	flags := flag.NewFlagSet("runs", flag.ContinueOnError)
+	dataType := flags.String("type", "", "only runs of this data type")
+	status := flags.String("status", "", "only runs in this state (queued, running, completed, failed, partial)")
+	tenant := flags.String("tenant", "", "only runs of this tenant")
+	since := flags.String("since", "", "only runs submitted within this duration or since this date")
+	limit := flags.Int("limit", 20, "latest runs to show (0 = all)")
+	asJSON := flags.Bool("json", false, "print the records as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 || *limit < 0 {
+		return fmt.Errorf("usage: %s", commands["runs"].usage)
+	}
+
+	store, err := runstore.Open(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 1 {
+		record, found, err := store.Get(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("no ingestion with ID %s in %s", flags.Arg(0), cfg.StateDir)
+		}
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(record)
+		}
+		printRunRecord(record)
+		return nil
+	}
+
+	filter := runstore.Filter{DataType: *dataType, Status: *status, Tenant: *tenant}
+	switch filter.Status {
+	case "", runstore.StatusQueued, runstore.StatusRunning, runstore.StatusCompleted, runstore.StatusFailed, runstore.StatusPartial:
+	default:
+		return fmt.Errorf("invalid --status %q: use queued, running, completed, failed or partial", filter.Status)
+	}
+	if *since != "" {
+		if filter.Since, err = parseSince(*since, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// - The store pages at most MaxPageSize runs at a time; --limit 0 reads every page
+	var records []*runstore.Record
+	token, more := "", false
+	for {
+		pageSize := runstore.MaxPageSize
+		if *limit > 0 && *limit-len(records) < pageSize {
+			pageSize = *limit - len(records)
+		}
+		page, next, err := store.List(filter, token, pageSize)
+		if err != nil {
+			return err
+		}
+		records = append(records, page...)
+		if next == "" {
+			break
+		}
+		if *limit > 0 && len(records) >= *limit {
+			more = true
+			break
+		}
+		token = next
+	}
+
+	if *asJSON {
+		if records == nil {
+			records = []*runstore.Record{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	}
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("INGESTION RUNS")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tSUBMITTED\tDATA TYPE\tFORMAT\tSTATUS\tROWS\tDURATION\tTABLE")
+	for _, record := range records {
+		rows, duration, tableName := "-", "-", "-"
+		if record.Result != nil {
+			rows = fmt.Sprint(record.Result.RowsIngested)
+			duration = record.Result.Duration.Round(time.Millisecond).String()
+			tableName = record.Result.TableName
+		} else if record.StartedAt != nil && record.FinishedAt != nil {
+			duration = record.FinishedAt.Sub(*record.StartedAt).Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", record.ID, record.SubmittedAt.Local().Format("2006-01-02 15:04:05"),
+			record.DataType, record.Format, record.Status, rows, duration, tableName)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	fmt.Print(strings.Repeat("-", 50) + "\n")
+	fmt.Printf("%d run(s)", len(records))
+	if more {
+		fmt.Print(" (more with a higher --limit)")
+	}
+	fmt.Printf("; details: runs runID\n")
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return nil
+}
+
+// Parses --since: a duration before now (72h) or a date (2006-01-02, local time) or RFC 3339 time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration (72h), a date (2006-01-02) or an RFC 3339 time", value)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+)
+
+func runSeedSemantics(ctx context.Context, cfg *config.Config, args []string) error {
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Target Tables:
+	// - Defaults to every supported BLADE data type
+	// - Tables must already exist (run an ingestion first)
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	dataTypes := args
+	if len(dataTypes) == 0 {
+		dataTypes = source.ListTypes()
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("SEMANTIC METADATA")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	for _, dataType := range dataTypes {
+		schema, err := source.DescribeSchema(dataType)
+		if err != nil {
+			return err
+		}
+		if err := dbClient.InNamespace(schema.Catalog, schema.Schema).SeedSemantics(ctx, schema.TableName, schema.Semantics); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d column(s), %d example question(s)\n",
+			schema.TableName, len(schema.Semantics.Columns), len(schema.Semantics.ExampleQuestions))
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/datasource"
+	"databricks-blade-poc/internal/jobs"
+	"databricks-blade-poc/internal/quota"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/runstore"
+	"databricks-blade-poc/internal/server"
+)
+
+// How long a shutdown waits for running ingestions before cancelling them.
+const serveDrainTimeout = 5 * time.Minute
+
+func runServe(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --addr: Listen address (default BLADE_SERVE_ADDR, ":8080")
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", cfg.ServeAddr, "listen address")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Shared State:
+	// - One Databricks client for every request; requests naming a tenant get a scoped
+	//   copy (ForTenant), so the base client is connected without BLADE_TENANT
+	// - Runs are recorded in the same run store (BLADE_STATE_DIR) the CLI uses; runs a previous
+	//   server left queued or running are marked failed, their requests died with it
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	toggles, err := datasource.ParseToggles(cfg.DisabledDataTypes)
+	if err != nil {
+		return err
+	}
+	store, err := runstore.Open(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	interrupted, err := jobs.Recover(store)
+	if err != nil {
+		return fmt.Errorf("failed to recover interrupted runs: %w", err)
+	}
+	if interrupted > 0 {
+		runlog.Printf(ctx, "Marked %d ingestion(s) interrupted by the previous shutdown as failed", interrupted)
+	}
+	unscoped := *cfg
+	unscoped.Tenant = ""
+	dbClient, err := connectDatabricks(ctx, &unscoped)
+	if err != nil {
+		return err
+	}
+
+	var limiter *quota.Limiter
+	if cfg.QuotaRunsPerHour > 0 || cfg.QuotaRowsPerDay > 0 {
+		limiter = quota.NewLimiter(quota.Limits{RunsPerHour: cfg.QuotaRunsPerHour, RowsPerDay: int64(cfg.QuotaRowsPerDay)}, nil)
+	}
+
+	srv := server.New(server.Options{
+		Source:        source,
+		Toggles:       toggles,
+		Store:         store,
+		Limiter:       limiter,
+		DefaultTenant: cfg.Tenant,
+		Jobs:          jobs.Options{Workers: cfg.ServeWorkers, QueueSize: cfg.ServeQueueSize},
+		LogDir:        cfg.LogDir,
+		Ingest: func(ctx context.Context, record *runstore.Record, req *databricks.IngestionRequest) (*databricks.IngestionResult, error) {
+			// - Each run gets its own log file under its run ID, like a CLI run
+			run, err := runlog.Start(cfg.LogDir, record.ID, os.Stderr)
+			if err != nil {
+				return nil, err
+			}
+			defer run.Close()
+			ctx = runlog.WithRun(ctx, run)
+
+			runCfg, runClient := *cfg, dbClient
+			runCfg.Tenant = record.Tenant
+			if record.Tenant != "" {
+				if runClient, err = dbClient.ForTenant(record.Tenant, cfg.TenantIsolation); err != nil {
+					return nil, err
+				}
+			}
+			runlog.Printf(ctx, "Starting ingestion for BLADE data (type: %s, format: %s)", record.DataType, record.Format)
+			return ingestAndReport(ctx, &runCfg, runClient, source, run, record.DataType, record.Format, req, cfg.MaxRuntime, outputTable)
+		},
+	})
+
+	// Shutdown:
+	// - SIGINT/SIGTERM (main cancels ctx) stop accepting requests, then running ingestions get serveDrainTimeout
+	//   to finish before they are cancelled (and recorded as failed or partial)
+	httpServer := &http.Server{Addr: *addr, Handler: srv}
+	served := make(chan error, 1)
+	go func() {
+		served <- httpServer.ListenAndServe()
+	}()
+	runlog.Printf(ctx, "Serving the ingestion API on %s (spec at /openapi.yaml)", *addr)
+
+	select {
+	case err := <-served:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+	runlog.Printf(context.Background(), "Shutting down; waiting up to %s for running ingestions", serveDrainTimeout)
+	drainCtx, cancel := context.WithTimeout(context.Background(), serveDrainTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(drainCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return srv.Close(drainCtx)
+}
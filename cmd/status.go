@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/runstore"
+)
+
+func runStatus(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --json: Print the record as JSON (the API's Ingestion schema)
+	// - --log: Print the run's log file (BLADE_LOG_DIR) after the record
+	// - Reads the local run store (BLADE_STATE_DIR), so it sees runs of the CLI and of serve
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the record as JSON")
+	withLog := flags.Bool("log", false, "print the run's log after the record")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s", commands["status"].usage)
+	}
+
+	store, err := runstore.Open(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	record, found, err := store.Get(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no ingestion with ID %s in %s", flags.Arg(0), cfg.StateDir)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	} else {
+		printRunRecord(record)
+	}
+	if *withLog {
+		content, err := runlog.ReadLog(cfg.LogDir, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read the log of %s: %w", record.ID, err)
+		}
+		if !*asJSON {
+			fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+		}
+		os.Stdout.Write(content)
+	}
+	return nil
+}
+
+// Prints one run of the run store: its state, timing and, once finished, its result.
+func printRunRecord(record *runstore.Record) {
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("INGESTION %s", record.ID)
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	fmt.Printf("Status:     %s\n", record.Status)
+	fmt.Printf("Data Type:  %s (%s)\n", record.DataType, record.Format)
+	if record.Tenant != "" {
+		fmt.Printf("Tenant:     %s\n", record.Tenant)
+	}
+	fmt.Printf("Submitted:  %s\n", record.SubmittedAt.Format(time.RFC3339))
+	if record.StartedAt != nil {
+		fmt.Printf("Started:    %s\n", record.StartedAt.Format(time.RFC3339))
+	}
+	if record.FinishedAt != nil {
+		fmt.Printf("Finished:   %s\n", record.FinishedAt.Format(time.RFC3339))
+	}
+	if result := record.Result; result != nil {
+		fmt.Printf("Rows:       %d into %s\n", result.RowsIngested, result.TableName)
+		if result.RowsSkipped > 0 || result.RowsRejected > 0 {
+			fmt.Printf("Dropped:    %d duplicate(s), %d rejected\n", result.RowsSkipped, result.RowsRejected)
+		}
+		fmt.Printf("Duration:   %s\n", result.Duration.Round(time.Millisecond))
+		if batchID, ok := result.Metadata["batch_id"]; ok {
+			fmt.Printf("Batch:      %v\n", batchID)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Printf("Warning:    %s\n", warning.Message)
+		}
+	}
+	if record.Error != "" {
+		fmt.Printf("Error:      %s\n", record.Error)
+	}
+	if record.Remediation != nil {
+		fmt.Printf("How to fix: %s\n", record.Remediation.Hint)
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+}
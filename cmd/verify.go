@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/integrity"
+)
+
+// Commands that still run when the integrity check fails (BLADE_INTEGRITY_REQUIRED):
+// the check itself, signing a release and help.
+var integrityExempt = map[string]bool{"verify": true, "sign-release": true, "help": true}
+
+// Returns the artifacts this process runs or loads: its own executable and the mappings file.
+func integrityArtifacts(cfg *config.Config) ([]string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	artifacts := []string{executable}
+	if cfg.MappingsFile != "" {
+		artifacts = append(artifacts, cfg.MappingsFile)
+	}
+	return artifacts, nil
+}
+
+// Checks the running artifacts against the signed release manifest.
+//   - Returns the per-artifact checks, and an error when the manifest itself can't be trusted
+//     or any artifact failed
+func checkIntegrity(cfg *config.Config, manifestPath string) ([]integrity.Check, error) {
+	manifest, err := integrity.LoadManifest(manifestPath, integrity.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := integrityArtifacts(cfg)
+	if err != nil {
+		return nil, err
+	}
+	checks := manifest.Verify(artifacts)
+	for _, check := range checks {
+		if !check.Passed() {
+			return checks, fmt.Errorf("%s failed the integrity check (%s)", check.Path, check.Status)
+		}
+	}
+	return checks, nil
+}
+
+func runVerify(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --manifest: Signed release manifest (default BLADE_INTEGRITY_MANIFEST); its
+	//   signature is read from the same path with ".sig" appended
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	manifestPath := flags.String("manifest", cfg.IntegrityManifest, "signed release manifest")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("RELEASE INTEGRITY")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	fmt.Printf("Manifest: %s\n", *manifestPath)
+	checks, err := checkIntegrity(cfg, *manifestPath)
+	for _, check := range checks {
+		fmt.Printf("[%s] %s", strings.ToUpper(check.Status), check.Path)
+		if check.Detail != "" {
+			fmt.Printf(" (%s)", check.Detail)
+		}
+		fmt.Println()
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return err
+}
+
+func runSignRelease(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags (before the artifact paths):
+	// - --key: ed25519 release signing key (PEM, PKCS #8); never shipped with the release
+	// - --version: Release version recorded in the manifest
+	// - --out: Manifest path; the signature is written next to it as <out>.sig
+	flags := flag.NewFlagSet("sign-release", flag.ContinueOnError)
+	keyPath := flags.String("key", "", "ed25519 release signing key (PEM)")
+	version := flags.String("version", "", "release version recorded in the manifest")
+	out := flags.String("out", cfg.IntegrityManifest, "manifest to write")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *keyPath == "" || flags.NArg() == 0 {
+		return fmt.Errorf("usage: %s", commands["sign-release"].usage)
+	}
+
+	key, err := integrity.ReadPrivateKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	manifest, err := integrity.NewManifest(*version, flags.Args())
+	if err != nil {
+		return err
+	}
+	if err := manifest.WriteSigned(*out, key); err != nil {
+		return err
+	}
+	fmt.Printf("Signed %d artifact(s) into %s (signature %s.sig)\n", len(manifest.Files), *out, *out)
+	fmt.Printf("Embed the release key when building:\n")
+	fmt.Printf("  -ldflags \"-X databricks-blade-poc/internal/integrity.PublicKey=%s\"\n", integrity.EncodePublicKey(key))
+	return nil
+}
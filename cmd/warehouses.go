@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+func runListWarehouses(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --json: Print the warehouses as JSON
+	flags := flag.NewFlagSet("list-warehouses", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the warehouses as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: %s", commands["list-warehouses"].usage)
+	}
+
+	// - Discovery is for finding the warehouse to configure, so none has to be set yet
+	//   and the connection test (which runs a statement on it) is skipped
+	dbClient, err := databricks.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create Databricks client: %w", err)
+	}
+	warehouses, err := dbClient.ListWarehouses(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(warehouses)
+	}
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Print("SQL WAREHOUSES")
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "\tID\tNAME\tSTATE\tSIZE\tTYPE")
+	for _, warehouse := range warehouses {
+		// - * marks the configured warehouse (DATABRICKS_WAREHOUSE_ID, else DATABRICKS_WAREHOUSE_NAME)
+		marker := ""
+		if warehouse.ID == cfg.WarehouseID || cfg.WarehouseID == "" && cfg.WarehouseName != "" && strings.EqualFold(warehouse.Name, cfg.WarehouseName) {
+			marker = "*"
+		}
+		kind := warehouse.Type
+		if warehouse.Serverless {
+			kind = "SERVERLESS"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", marker, warehouse.ID, warehouse.Name, warehouse.State, warehouse.Size, kind)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	fmt.Print(strings.Repeat("-", 50) + "\n")
+	fmt.Printf("%d warehouse(s); set DATABRICKS_WAREHOUSE_ID to an ID or DATABRICKS_WAREHOUSE_NAME to a name\n", len(warehouses))
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+	return nil
+}
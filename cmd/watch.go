@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/datasource"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/runstore"
+	"databricks-blade-poc/internal/watch"
+)
+
+func runWatch(ctx context.Context, cfg *config.Config, args []string) error {
+	// Flags:
+	// - --interval: Time between scans (default BLADE_WATCH_INTERVAL, 5s)
+	// - --debounce: How long a file must be unchanged before it's ingested (default BLADE_WATCH_DEBOUNCE, 2s)
+	// - --backfill: On the first start, also ingest the files already in the data path
+	// - --once: Scan once and exit (e.g. from cron)
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := flags.Duration("interval", cfg.WatchInterval, "time between scans of the data path")
+	debounce := flags.Duration("debounce", cfg.WatchDebounce, "ingest a file once it has been unchanged this long")
+	backfill := flags.Bool("backfill", false, "on the first start, ingest the files already present")
+	once := flags.Bool("once", false, "scan once and exit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Watched Directories:
+	// - {BLADE_DATA_PATH}/{dataType}/ for every enabled data type of the provider
+	// - The ledger (BLADE_STATE_DIR/cache/watch-ledger.json) remembers each file's last
+	//   version and outcome, so a restart doesn't ingest it again
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+	loader, ok := source.(datasource.RecordFileLoader)
+	if !ok {
+		return fmt.Errorf("data source %s cannot load single files (watch)", source.Name())
+	}
+	toggles, err := datasource.ParseToggles(cfg.DisabledDataTypes)
+	if err != nil {
+		return err
+	}
+	var dataTypes []string
+	for _, dataType := range source.ListTypes() {
+		if _, disabled := toggles.Disabled(dataType); !disabled {
+			dataTypes = append(dataTypes, dataType)
+		}
+	}
+	store, err := runstore.Open(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	ledger, err := watch.OpenLedger(runstore.CachePath(cfg.StateDir, "watch-ledger.json"))
+	if err != nil {
+		return err
+	}
+	dbClient, err := connectDatabricks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	watcher := &watch.Watcher{
+		Root:      cfg.BLADEDataPath,
+		DataTypes: dataTypes,
+		Ledger:    ledger,
+		Debounce:  *debounce,
+		Backfill:  *backfill,
+		Ingest: func(ctx context.Context, dataType, path string) (string, int64, error) {
+			return ingestWatchedFile(ctx, cfg, dbClient, source, loader, store, dataType, path)
+		},
+	}
+
+	if *once {
+		outcomes, err := watcher.Scan(ctx)
+		printWatchOutcomes(outcomes)
+		return err
+	}
+	// - Runs until SIGINT/SIGTERM (main cancels ctx); a file being ingested then ends as partial
+	runlog.Printf(ctx, "Watching %s (%s) every %s", cfg.BLADEDataPath, strings.Join(dataTypes, ", "), *interval)
+	return watcher.Run(ctx, *interval)
+}
+
+// Ingests one watched file as its own run: run log, run store record and reporters, like ingest.
+func ingestWatchedFile(ctx context.Context, cfg *config.Config, dbClient *databricks.Client, source datasource.Provider, loader datasource.RecordFileLoader, store *runstore.Store, dataType, path string) (string, int64, error) {
+	run, err := runlog.Start(cfg.LogDir, runlog.NewRunID(), os.Stderr)
+	if err != nil {
+		return "", 0, err
+	}
+	defer run.Close()
+	ctx = runlog.WithRun(ctx, run)
+
+	// - Zip archives are opened to find the format of the file inside; a broken one
+	//   fails below, when the request is prepared
+	format, _ := blademap.DataFileFormat(path)
+	started := time.Now().UTC()
+	record := &runstore.Record{
+		ID:          run.ID,
+		DataType:    dataType,
+		Format:      format,
+		Tenant:      cfg.Tenant,
+		Status:      runstore.StatusRunning,
+		SubmittedAt: started,
+		StartedAt:   &started,
+	}
+	if err := store.Save(record); err != nil {
+		runlog.Printf(ctx, "Could not record run history: %v", err)
+	}
+
+	var result *databricks.IngestionResult
+	req, err := loader.FetchRecordFile(dataType, path)
+	if err != nil {
+		err = fmt.Errorf("failed to prepare ingestion request: %w", err)
+	} else {
+		runlog.Printf(ctx, "Starting ingestion of %s (type: %s, format: %s)", path, dataType, format)
+		result, err = ingestAndReport(ctx, cfg, dbClient, source, run, dataType, format, req, cfg.MaxRuntime, outputTable)
+	}
+
+	finished := time.Now().UTC()
+	record.Result, record.FinishedAt, record.Status = result, &finished, runstore.StatusCompleted
+	if err != nil {
+		record.Status = runstore.StatusFailed
+		if databricks.IsPartial(err) {
+			record.Status = runstore.StatusPartial
+		}
+		record.Error = err.Error()
+		if remedy, ok := databricks.Remediate(err); ok {
+			record.Remediation = &remedy
+		}
+	}
+	if saveErr := store.Save(record); saveErr != nil {
+		runlog.Printf(ctx, "Could not record run history: %v", saveErr)
+	}
+
+	var rows int64
+	if result != nil {
+		rows = result.RowsIngested
+	}
+	return run.ID, rows, err
+}
+
+func printWatchOutcomes(outcomes []watch.Outcome) {
+	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
+	fmt.Printf("WATCH SCAN (%d file(s) processed)", len(outcomes))
+	fmt.Print("\n" + strings.Repeat("-", 50) + "\n")
+	for _, outcome := range outcomes {
+		switch outcome.Entry.Status {
+		case watch.StatusIngested:
+			fmt.Printf("%-9s %s (%d rows, run %s)\n", outcome.Entry.Status, outcome.Path, outcome.Entry.Rows, outcome.Entry.RunID)
+		case watch.StatusFailed:
+			fmt.Printf("%-9s %s: %s\n", outcome.Entry.Status, outcome.Path, outcome.Entry.Error)
+		default:
+			fmt.Printf("%-9s %s\n", outcome.Entry.Status, outcome.Path)
+		}
+	}
+	fmt.Print(strings.Repeat("=", 50) + "\n")
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/secrets"
+	"github.com/databricks/databricks-sdk-go"
+)
+
+func init() {
+	Register("azure-client-secret", func(cfg *config.Config) (Provider, error) {
+		clientSecret, err := secrets.Resolve(context.Background(), "ARM_CLIENT_SECRET", cfg.AzureClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		return &AzureClientSecretProvider{TenantID: cfg.AzureTenantID, ClientID: cfg.AzureClientID, ClientSecret: clientSecret, ResourceID: cfg.AzureResourceID}, nil
+	})
+	Register("azure-msi", func(cfg *config.Config) (Provider, error) {
+		return &AzureMSIProvider{ClientID: cfg.AzureClientID, ResourceID: cfg.AzureResourceID}, nil
+	})
+}
+
+// Authenticates an Entra ID (Azure AD) service principal to an Azure Databricks workspace
+// with client credentials (ARM_TENANT_ID / ARM_CLIENT_ID / ARM_CLIENT_SECRET).
+//   - The SDK requests Entra ID tokens for the Azure Databricks application and refreshes
+//     them before they expire; the principal needs no Databricks-issued secret
+//   - ResourceID (DATABRICKS_AZURE_RESOURCE_ID, optional) also sends a management token,
+//     so a principal that is a workspace contributor but not yet a user is added on first use
+type AzureClientSecretProvider struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	ResourceID   string
+}
+
+func (p *AzureClientSecretProvider) Name() string {
+	return "azure-client-secret"
+}
+
+func (p *AzureClientSecretProvider) Configure(sdkConfig *databricks.Config) error {
+	if p.TenantID == "" || p.ClientID == "" || p.ClientSecret == "" {
+		return fmt.Errorf("ARM_TENANT_ID, ARM_CLIENT_ID and ARM_CLIENT_SECRET are required for azure-client-secret authentication")
+	}
+	sdkConfig.AuthType = p.Name()
+	sdkConfig.AzureTenantID = p.TenantID
+	sdkConfig.AzureClientID = p.ClientID
+	sdkConfig.AzureClientSecret = p.ClientSecret
+	sdkConfig.AzureResourceID = p.ResourceID
+	return nil
+}
+
+// Authenticates with the Azure managed identity of the VM, container or App Service the
+// CLI runs on, so no secret is configured at all.
+//   - ResourceID (DATABRICKS_AZURE_RESOURCE_ID): The workspace's Azure resource ID, which
+//     the SDK needs to exchange the identity's tokens for the workspace
+//   - ClientID (ARM_CLIENT_ID, optional): Picks a user-assigned identity instead of the
+//     system-assigned one
+type AzureMSIProvider struct {
+	ClientID   string
+	ResourceID string
+}
+
+func (p *AzureMSIProvider) Name() string {
+	return "azure-msi"
+}
+
+func (p *AzureMSIProvider) Configure(sdkConfig *databricks.Config) error {
+	if p.ResourceID == "" {
+		return fmt.Errorf("DATABRICKS_AZURE_RESOURCE_ID is required for azure-msi authentication")
+	}
+	sdkConfig.AuthType = p.Name()
+	sdkConfig.AzureUseMSI = true
+	sdkConfig.AzureClientID = p.ClientID
+	sdkConfig.AzureResourceID = p.ResourceID
+	return nil
+}
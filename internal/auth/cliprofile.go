@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"fmt"
+
+	"databricks-blade-poc/internal/config"
+	"github.com/databricks/databricks-sdk-go"
+)
+
+func init() {
+	Register(config.ConfigProfileAuthType, func(cfg *config.Config) (Provider, error) {
+		return &ConfigProfileProvider{Profile: cfg.DatabricksProfile, ConfigFile: cfg.DatabricksConfigFile}, nil
+	})
+}
+
+// Authenticates with a profile of the Databricks CLI config file (DATABRICKS_CONFIG_PROFILE,
+// ~/.databrickscfg unless DATABRICKS_CONFIG_FILE says otherwise).
+//   - The SDK reads the profile's credentials and auth type itself, so any profile the CLI
+//     can use works: a token, OAuth M2M, Azure, or a `databricks auth login` session
+type ConfigProfileProvider struct {
+	Profile    string
+	ConfigFile string
+}
+
+func (p *ConfigProfileProvider) Name() string {
+	return config.ConfigProfileAuthType
+}
+
+func (p *ConfigProfileProvider) Configure(sdkConfig *databricks.Config) error {
+	if p.Profile == "" {
+		return fmt.Errorf("DATABRICKS_CONFIG_PROFILE is required for %s authentication", p.Name())
+	}
+	sdkConfig.Profile = p.Profile
+	sdkConfig.ConfigFile = p.ConfigFile
+	return nil
+}
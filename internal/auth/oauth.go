@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/secrets"
+	"github.com/databricks/databricks-sdk-go"
+)
+
+func init() {
+	Register("oauth-m2m", func(cfg *config.Config) (Provider, error) {
+		clientID, err := secrets.Resolve(context.Background(), "DATABRICKS_CLIENT_ID", cfg.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		clientSecret, err := secrets.Resolve(context.Background(), "DATABRICKS_CLIENT_SECRET", cfg.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		return &OAuthM2MProvider{ClientID: clientID, ClientSecret: clientSecret}, nil
+	})
+}
+
+// Authenticates a service principal with OAuth machine-to-machine credentials
+// (DATABRICKS_CLIENT_ID / DATABRICKS_CLIENT_SECRET), for workspaces where PATs are prohibited.
+//   - The SDK exchanges the secret for short-lived access tokens at the workspace's
+//     OIDC token endpoint and refreshes them before they expire, so long runs keep working
+type OAuthM2MProvider struct {
+	ClientID     string
+	ClientSecret string
+}
+
+func (p *OAuthM2MProvider) Name() string {
+	return "oauth-m2m"
+}
+
+func (p *OAuthM2MProvider) Configure(sdkConfig *databricks.Config) error {
+	if p.ClientID == "" || p.ClientSecret == "" {
+		return fmt.Errorf("DATABRICKS_CLIENT_ID and DATABRICKS_CLIENT_SECRET are required for oauth-m2m authentication")
+	}
+	sdkConfig.AuthType = p.Name()
+	sdkConfig.ClientID = p.ClientID
+	sdkConfig.ClientSecret = p.ClientSecret
+	return nil
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/secrets"
+	"github.com/databricks/databricks-sdk-go"
+)
+
+func init() {
+	Register("pat", func(cfg *config.Config) (Provider, error) {
+		token, err := secrets.Resolve(context.Background(), "DATABRICKS_TOKEN", cfg.DatabricksToken)
+		if err != nil {
+			return nil, err
+		}
+		return &PATProvider{Token: token}, nil
+	})
+}
+
+// Authenticates with a Databricks personal access token (DATABRICKS_TOKEN).
+type PATProvider struct {
+	Token string
+}
+
+func (p *PATProvider) Name() string {
+	return "pat"
+}
+
+func (p *PATProvider) Configure(sdkConfig *databricks.Config) error {
+	if p.Token == "" {
+		return fmt.Errorf("DATABRICKS_TOKEN is required for pat authentication")
+	}
+	sdkConfig.AuthType = p.Name()
+	sdkConfig.Token = p.Token
+	return nil
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"github.com/databricks/databricks-sdk-go"
+)
+
+//   Purpose: Supplies credentials to the Databricks SDK configuration used by the client.
+//   Credential settings may reference a secret store (aws-sm://, azure-kv://, vault://),
+//   resolved when the provider is built (see internal/secrets).
+
+//   Contract:
+//   - Name: The auth type identifier selected via DATABRICKS_AUTH_TYPE (e.g. "pat")
+//   - Configure: Populates credential fields on the SDK config before the workspace
+//     client is created; returns an error if required settings are missing
+type Provider interface {
+	Name() string
+	Configure(sdkConfig *databricks.Config) error
+}
+
+// Builds a Provider from the application configuration.
+type Factory func(cfg *config.Config) (Provider, error)
+
+// Auth type used when DATABRICKS_AUTH_TYPE is not set.
+const DefaultAuthType = "pat"
+
+var factories = map[string]Factory{}
+
+// Makes an auth type selectable via DATABRICKS_AUTH_TYPE. Later registrations replace earlier ones.
+func Register(authType string, factory Factory) {
+	factories[strings.ToLower(authType)] = factory
+}
+
+// Returns the registered auth type names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolves the provider selected by cfg.AuthType (default: "pat").
+func NewProvider(cfg *config.Config) (Provider, error) {
+	authType := strings.ToLower(cfg.AuthType)
+	if authType == "" {
+		authType = DefaultAuthType
+	}
+
+	factory, exists := factories[authType]
+	if !exists {
+		return nil, fmt.Errorf("unsupported DATABRICKS_AUTH_TYPE %q (supported: %s)", cfg.AuthType, strings.Join(Names(), ", "))
+	}
+	return factory(cfg)
+}
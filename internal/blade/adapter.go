@@ -1,13 +1,15 @@
 package blade
 
 import (
-	"encoding/csv"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"databricks-blade-poc/blademap"
 	"databricks-blade-poc/internal/databricks"
 )
 
@@ -15,9 +17,29 @@ type BLADEAdapter struct {
 	dataSource string // a specific BLADE deployment
 	basePath string // the root volume path where BLADE stores data files
 	mappings map[string]BLADEDataMapping // map of data type -> table configuration (for quick lookup)
+	mu sync.Mutex // guards mappings while a watched mappings file is reloaded
+	watch *mappingWatch // set when the mappings come from a file (hot reload)
+	live *liveSource // set in live mode: records come from the BLADE REST API (see live.go)
 }
 
 func NewBLADEAdapter(dataSource, basePath string) *BLADEAdapter {
+	return NewBLADEAdapterWithMappings(dataSource, basePath, GetBLADEMappings())
+}
+
+// Builds an adapter over a custom set of mappings (e.g. tests or mappings with CSV pivot options).
+// Mappings from user-editable config must pass LintMappings first.
+func NewBLADEAdapterWithMappings(dataSource, basePath string, bladeMappings []BLADEDataMapping) *BLADEAdapter {
+	// - dataSource: "BLADE_LOGISTICS" (from config)
+	// - basePath: "mock_blade_data/" (from config)
+	// - mappings: Index of all 4 supported data types
+	return &BLADEAdapter{
+		dataSource: dataSource,
+		basePath:   basePath,
+		mappings:   indexMappings(bladeMappings),
+	}
+}
+
+func indexMappings(bladeMappings []BLADEDataMapping) map[string]BLADEDataMapping {
 	// - Creates empty map to store data type configurations
 	// - Key: string (data type like "maintenance")
 	// - Value: BLADEDataMapping struct with table name, source path, description
@@ -28,27 +50,20 @@ func NewBLADEAdapter(dataSource, basePath string) *BLADEAdapter {
 	// - mappings["sortie"] → sortie mapping
 	// - mappings["deployment"] → deployment mapping
 	// - mappings["logistics"] → logistics mapping
-	for _, mapping := range GetBLADEMappings() {
+	for _, mapping := range bladeMappings {
 		mappings[mapping.DataType] = mapping
 	}
-
-	// - dataSource: "BLADE_LOGISTICS" (from config)
-	// - basePath: "mock_blade_data/" (from config)
-	// - mappings: Index of all 4 supported data types
-	return &BLADEAdapter{
-		dataSource: dataSource,
-		basePath:   basePath,
-		mappings:   mappings,
-	}
+	return mappings
 }
 
 // this function serves as the bridge between BLADE data types/formats and Databricks ingestion requirements
-func (b *BLADEAdapter) PrepareIngestionRequest(dataType string, format string) (*databricks.IngestionRequest, error) {
+//   - ctx: Bounds the live mode fetch (cancellation, --max-runtime, BLADE_RUN_DEADLINE)
+func (b *BLADEAdapter) PrepareIngestionRequest(ctx context.Context, dataType string, format string) (*databricks.IngestionRequest, error) {
 	// - Looks up dataType in the pre-indexed mappings from NewBLADEAdapter
 	// - Fast O(1) lookup - no iteration needed
 	// - Returns error immediately for invalid types like "invalid_type"
 	// - mapping contains: TableName, SourcePath, Description for this data type
-	mapping, exists := b.mappings[dataType]
+	mapping, exists := b.currentMappings()[dataType]
 
 	if !exists {
 		return nil, fmt.Errorf("Unsupported BLADE data type: %s", dataType)
@@ -60,51 +75,260 @@ func (b *BLADEAdapter) PrepareIngestionRequest(dataType string, format string) (
 		format = "JSON"
 	}
 
+	// - Live mode (BLADE_MODE=live) pulls the records from the BLADE REST API instead of
+	//   the files; they are JSON, so no other format can be asked for (see live.go)
+	if b.live != nil && format != "JSON" {
+		return nil, fmt.Errorf("BLADE_MODE=live fetches JSON from the BLADE API: %s can't be loaded, drop the format", format)
+	}
+
+	// - NDJSON files ({dataType}_data.ndjson) aren't loaded here: the request names the
+	//   file and the client streams it chunk by chunk (see prepareStreamRequest)
+	if format == "NDJSON" {
+		return b.prepareStreamRequest(mapping, deliveredFile(filepath.Join(b.basePath, dataType, dataType+"_data.ndjson")))
+	}
+
 	var sampleData string
+	var version blademap.SourceVersion
+	var warnings []databricks.Warning
+	var rejects []databricks.Rejection
 	var err error
 	
-	switch format {
-	case "JSON":
-		sampleData, err = b.loadMockDataFile(dataType)
-	case "CSV":
-		sampleData, err = b.loadMockCSVAsJSON(dataType)
+	sourcePath := "mock://" + dataType
+	switch {
+	case b.live != nil:
+		sourcePath = b.live.endpoint(dataType)
+		sampleData, version, err = b.live.fetch(ctx, dataType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s from the BLADE API: %w", dataType, err)
+		}
+	case format == "JSON":
+		sampleData, version, err = b.loadMockDataFile(dataType)
+	case format == "CSV":
+		sampleData, version, warnings, rejects, err = b.loadMockCSVAsJSON(mapping)
 	default:
-		return nil, fmt.Errorf("Unsupported format: %s. Use JSON or CSV", format)
+		return nil, fmt.Errorf("Unsupported format: %s. Use JSON, CSV or NDJSON", format)
 	}
 	
 	if err != nil {
 		return nil, fmt.Errorf("failed to load mock data for %s: %w", dataType, err)
 	}
 
+	// - Export format/version declared by the file (see sourceversion.go) travel in the
+	//   request metadata and end up on every row and in the run result
+	metadata := map[string]string{
+		"source_system": "BLADE",
+		"data_type":     dataType,
+		"integration":   "databricks_poc",
+		"description":   mapping.Description,
+		"mode":          "mock_data",
+		"original_format": format,
+	}
+	if b.live != nil {
+		metadata["mode"] = databricks.ModeLiveAPI
+		metadata["source_url"] = sourcePath
+	}
+	version.Apply(metadata)
+
 	return &databricks.IngestionRequest{
 		TableName:     mapping.TableName,
-		SourcePath:    "mock://" + dataType,
+		Catalog:       mapping.Catalog,
+		Schema:        mapping.Schema,
+		SourcePath:    sourcePath,
 		FileFormat:    "JSON", 
 		FormatOptions: "'multiLine' = 'true', 'inferSchema' = 'true'",
 		DataSource:    b.dataSource,
 		SampleData:    sampleData,
+		TableType:     mapping.TableType,
+		StoragePath:   mapping.StoragePath,
+		Validations:   mapping.Validations,
+		ChildTables:   mapping.ChildTables,
+		TTL:           mapping.TTL,
+		Columns:       mapping.Columns,
+		Layout:        mapping.Layout,
+		Dedup:         mapping.Dedup,
+		Rules:         mapping.Rules,
+		Warnings:      warnings,
+		Rejects:       rejects,
+		Metadata:      metadata,
+	}, nil
+}
+
+// Builds a request that loads real BLADE files with COPY INTO instead of the mock data.
+//   - sourcePath: A /Volumes/... file or directory loaded in place, or a local one uploaded first
+//   - JSON files may hold one record per line or a top-level array (NDJSON: one record per
+//     line only, so COPY INTO can split large files); CSV files need a header row and may
+//     start with "#" preamble lines
+//   - Gzipped files (.json.gz, .csv.gz) are decompressed by the warehouse; zip archives
+//     can't be read by COPY INTO and are refused
+func (b *BLADEAdapter) PrepareFileIngestionRequest(dataType string, format string, sourcePath string) (*databricks.IngestionRequest, error) {
+	mapping, exists := b.currentMappings()[dataType]
+	if !exists {
+		return nil, fmt.Errorf("Unsupported BLADE data type: %s", dataType)
+	}
+	if blademap.IsZip(sourcePath) {
+		return nil, fmt.Errorf("COPY INTO can't read zip archives: unpack %s or gzip its files instead", sourcePath)
+	}
+	if format == "" {
+		format = "JSON"
+	}
+
+	var formatOptions string
+	switch format {
+	case "JSON":
+		formatOptions = "'multiLine' = 'true'"
+	case "NDJSON":
+		format, formatOptions = "JSON", "'multiLine' = 'false'"
+	case "CSV":
+		formatOptions = "'header' = 'true', 'comment' = '#'"
+	default:
+		return nil, fmt.Errorf("Unsupported format: %s. Use JSON, CSV or NDJSON", format)
+	}
+
+	return &databricks.IngestionRequest{
+		TableName:     mapping.TableName,
+		Catalog:       mapping.Catalog,
+		Schema:        mapping.Schema,
+		SourcePath:    sourcePath,
+		FileFormat:    format,
+		FormatOptions: formatOptions,
+		DataSource:    b.dataSource,
+		TableType:     mapping.TableType,
+		StoragePath:   mapping.StoragePath,
+		Validations:   mapping.Validations,
+		ChildTables:   mapping.ChildTables,
+		TTL:           mapping.TTL,
+		Columns:       mapping.Columns,
+		Layout:        mapping.Layout,
+		Dedup:         mapping.Dedup,
+		Rules:         mapping.Rules,
 		Metadata: map[string]string{
-			"source_system": "BLADE",
-			"data_type":     dataType,
-			"integration":   "databricks_poc",
-			"description":   mapping.Description,
-			"mode":          "mock_data",
+			"source_system":   "BLADE",
+			"data_type":       dataType,
+			"integration":     "databricks_poc",
+			"description":     mapping.Description,
+			"mode":            databricks.ModeCopyInto,
 			"original_format": format,
 		},
 	}, nil
 }
 
+// Builds a request that inserts the records of one local JSON, CSV or NDJSON file, like the mock data.
+//   - The format follows the extension (.json, .csv, .ndjson or .jsonl); CSV is converted
+//     with the mapping's aliases and pivot options, NDJSON is streamed
+//   - Gzipped files (.json.gz, ...) and zip archives holding one data file are
+//     decompressed while they are read (see blademap/archive.go)
+//   - Used by watch for files dropped into the data path
+func (b *BLADEAdapter) PrepareRecordFileIngestionRequest(dataType string, filePath string) (*databricks.IngestionRequest, error) {
+	mapping, exists := b.currentMappings()[dataType]
+	if !exists {
+		return nil, fmt.Errorf("Unsupported BLADE data type: %s", dataType)
+	}
+
+	var sampleData string
+	var version blademap.SourceVersion
+	var warnings []databricks.Warning
+	var rejects []databricks.Rejection
+	format, err := blademap.DataFileFormat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "JSON":
+		sampleData, version, err = loadJSONFile(filePath)
+	case "CSV":
+		sampleData, version, warnings, rejects, err = loadCSVFile(mapping, filePath)
+	case "NDJSON":
+		return b.prepareStreamRequest(mapping, filePath)
+	default:
+		return nil, fmt.Errorf("Unsupported file %s. Use .json, .csv, .ndjson or .jsonl, gzipped or not, or a .zip holding one", filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]string{
+		"source_system":   "BLADE",
+		"data_type":       dataType,
+		"integration":     "databricks_poc",
+		"description":     mapping.Description,
+		"mode":            "mock_data",
+		"original_format": format,
+		"source_file":     filePath,
+	}
+	version.Apply(metadata)
+
+	return &databricks.IngestionRequest{
+		TableName:     mapping.TableName,
+		Catalog:       mapping.Catalog,
+		Schema:        mapping.Schema,
+		SourcePath:    "file://" + filepath.ToSlash(filePath),
+		FileFormat:    "JSON",
+		FormatOptions: "'multiLine' = 'true', 'inferSchema' = 'true'",
+		DataSource:    b.dataSource,
+		SampleData:    sampleData,
+		TableType:     mapping.TableType,
+		StoragePath:   mapping.StoragePath,
+		Validations:   mapping.Validations,
+		ChildTables:   mapping.ChildTables,
+		TTL:           mapping.TTL,
+		Columns:       mapping.Columns,
+		Layout:        mapping.Layout,
+		Dedup:         mapping.Dedup,
+		Rules:         mapping.Rules,
+		Warnings:      warnings,
+		Rejects:       rejects,
+		Metadata:      metadata,
+	}, nil
+}
+
+// Builds a record_stream request over a local NDJSON file.
+//   - The records aren't read here: the client streams the file chunk by chunk while
+//     loading, so its size doesn't matter (see databricks/stream.go)
+//   - The file only has to exist; a malformed line fails the load at that line
+func (b *BLADEAdapter) prepareStreamRequest(mapping BLADEDataMapping, filePath string) (*databricks.IngestionRequest, error) {
+	if _, err := os.Stat(filePath); err != nil {
+		return nil, fmt.Errorf("failed to read data file %s: %w", filePath, err)
+	}
+	return &databricks.IngestionRequest{
+		TableName:   mapping.TableName,
+		Catalog:     mapping.Catalog,
+		Schema:      mapping.Schema,
+		SourcePath:  "file://" + filepath.ToSlash(filePath),
+		FileFormat:  "JSON",
+		DataSource:  b.dataSource,
+		TableType:   mapping.TableType,
+		StoragePath: mapping.StoragePath,
+		Validations: mapping.Validations,
+		ChildTables: mapping.ChildTables,
+		TTL:         mapping.TTL,
+		Columns:     mapping.Columns,
+		Layout:      mapping.Layout,
+		Dedup:       mapping.Dedup,
+		Rules:       mapping.Rules,
+		Metadata: map[string]string{
+			"source_system":   "BLADE",
+			"data_type":       mapping.DataType,
+			"integration":     "databricks_poc",
+			"description":     mapping.Description,
+			"mode":            databricks.ModeRecordStream,
+			"original_format": "NDJSON",
+			"source_file":     filePath,
+		},
+	}, nil
+}
+
 func (b *BLADEAdapter) GetSupportedDataTypes() []string {
 	// - Creates empty string slice with zero length but capacity = len(b.mappings)
 	// - Pre-allocates memory for exactly the right number of elements (4 in current implementation)
 	// - Performance optimization - avoids slice growth/reallocation during appends
-	types := make([]string, 0, len(b.mappings))
+	mappings := b.currentMappings()
+	types := make([]string, 0, len(mappings))
 
 	// - Iterates over the mappings map using range on keys only
 	// - dataType gets each key ("maintenance", "sortie", "deployment", "logistics")
 	// - Appends each data type name to the types slice
 	// - Note: Map iteration order is not guaranteed in Go
-	for dataType := range b.mappings {
+	for dataType := range mappings {
 		types = append(types, dataType)
 	}
 
@@ -112,7 +336,7 @@ func (b *BLADEAdapter) GetSupportedDataTypes() []string {
 	return types
 }
 
-func (b *BLADEAdapter) loadMockDataFile(dataType string) (string, error) {
+func (b *BLADEAdapter) loadMockDataFile(dataType string) (string, blademap.SourceVersion, error) {
 	// - Uses string formatting to build standardized file names
   	// - Pattern: {dataType}_data.json
   	// - Examples:
@@ -129,119 +353,158 @@ func (b *BLADEAdapter) loadMockDataFile(dataType string) (string, error) {
     // 	- "mock_blade_data/sortie/sortie_data.json"
     // 	- "mock_blade_data/deployment/deployment_data.json"
     // 	- "mock_blade_data/logistics/logistics_data.json"
+	// - A compressed delivery (maintenance_data.json.gz, maintenance_data.zip) stands in
+	//   for a missing plain file
 	filePath := filepath.Join(b.basePath, dataType, fileName)
-	
-	// - Uses ioutil.ReadFile() to read entire file into memory as []byte
+	return loadJSONFile(deliveredFile(filePath))
+}
+
+// Returns the file a mock data path is delivered as: the plain file, else its gzipped
+// copy (name.json.gz), else a zip archive of the same name (name.zip). A missing file is
+// returned as the plain path, for the error message.
+func deliveredFile(plain string) string {
+	for _, candidate := range []string{plain, plain + ".gz", strings.TrimSuffix(plain, filepath.Ext(plain)) + ".zip"} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return plain
+}
+
+// Reads a JSON records file (a bare array or a versioned export envelope).
+func loadJSONFile(filePath string) (string, blademap.SourceVersion, error) {
+	// - Reads the entire (decompressed) file into memory as []byte
   	// - Handles common file errors:
     // 	- File doesn't exist: no such file or directory
     // 	- Permission denied: permission denied
     // 	- Directory instead of file: is a directory
+    // 	- Corrupt archive, or a zip without exactly one JSON file
   	// - Error wrapping: Preserves original error with context about which file failed
-	data, err := ioutil.ReadFile(filePath)
+	file, _, err := blademap.OpenDataFile(filePath, "JSON")
 	if err != nil {
-		return "", fmt.Errorf("failed to read mock data file %s: %w", filePath, err)
+		return "", blademap.SourceVersion{}, fmt.Errorf("failed to read data file %s: %w", filePath, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", blademap.SourceVersion{}, fmt.Errorf("failed to read data file %s: %w", filePath, err)
 	}
 	
-	// - Converts []byte to string for JSON processing
-  	// - Returns the raw JSON content exactly as stored in the file
-	return string(data), nil
+	// - A bare array is returned exactly as stored in the file
+	// - A versioned export envelope is unwrapped to its records array
+	records, version, err := blademap.UnwrapJSONExport(data)
+	if err != nil {
+		return "", blademap.SourceVersion{}, fmt.Errorf("failed to read data file %s: %w", filePath, err)
+	}
+	return records, version, nil
 }
 
-func (b *BLADEAdapter) loadMockCSVAsJSON(dataType string) (string, error) {
+func (b *BLADEAdapter) loadMockCSVAsJSON(mapping BLADEDataMapping) (string, blademap.SourceVersion, []databricks.Warning, []databricks.Rejection, error) {
+	dataType := mapping.DataType
+
 	// - Builds CSV file name: {dataType}_data.csv
 	// - Constructs full path: mock_blade_data/maintenance/maintenance_data.csv
-	// - Same pattern as loadMockDataFile but targets .csv files
+	// - Same pattern as loadMockDataFile but targets .csv files (or their .gz/.zip delivery)
 	fileName := fmt.Sprintf("%s_data.csv", dataType)
 	filePath := filepath.Join(b.basePath, dataType, fileName)
-	
-	// - Opens file for reading (not loading entire file into memory)
+	return loadCSVFile(mapping, deliveredFile(filePath))
+}
+
+// Parses a CSV records file with the mapping's aliases, pivot and column type hints.
+//   - Columns without a hint get the type inferred from their values
+//   - Rows that aren't valid CSV are set aside when the mapping's rules skip or quarantine
+//     invalid records; otherwise the first one fails the parse
+func parseCSVFile(mapping BLADEDataMapping, filePath string) (*blademap.CSVResult, error) {
+	// - Opens file for reading (not loading entire file into memory), decompressing
+	//   .gz files and zip archives on the fly
 	// - Uses defer to ensure file is closed even if function exits early
 	// - Error handling for missing files, permissions, etc.
-	file, err := os.Open(filePath)
+	file, _, err := blademap.OpenDataFile(filePath, "CSV")
 	if err != nil {
-		return "", fmt.Errorf("failed to open CSV file %s: %w", filePath, err)
+		return nil, fmt.Errorf("failed to open CSV file %s: %w", filePath, err)
 	}
 	defer file.Close()
-	
-	// - Creates Go's standard CSV reader
-  	// - Handles CSV parsing, quote escaping, field separation automatically
-	reader := csv.NewReader(file)
-
-	// - ReadAll() parses entire CSV to [][]string (array of rows, each row is array of fields)
-	// - Validates CSV has at least 2 rows (headers + at least 1 data row)
-	// - Structure: records[0] = headers, records[1+] = data rows
-	records, err := reader.ReadAll()
+
+	parsed, err := blademap.ParseCSV(file, blademap.CSVOptions{
+		Aliases:     mapping.CSVAliases,
+		Pivot:       mapping.CSVPivot,
+		Types:       mapping.CSVTypes,
+		Infer:       true,
+		KeepBadRows: mapping.Rules.Mode() != databricks.OnInvalidFail,
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read CSV file %s: %w", filePath, err)
+		return nil, fmt.Errorf("failed to read CSV file %s: %w", filePath, err)
+	}
+	return parsed, nil
+}
+
+// Returns how the columns of a data type's CSV file are typed: the mock file when
+// filePath is empty. Hinted columns report the values that didn't convert.
+func (b *BLADEAdapter) DescribeCSVColumns(dataType string, filePath string) ([]blademap.ColumnType, error) {
+	mapping, exists := b.currentMappings()[dataType]
+	if !exists {
+		return nil, fmt.Errorf("Unsupported BLADE data type: %s", dataType)
 	}
-	if len(records) < 2 {
-		return "", fmt.Errorf("CSV file %s has no data rows", filePath)
+	if filePath == "" {
+		filePath = deliveredFile(filepath.Join(b.basePath, dataType, dataType+"_data.csv"))
 	}
+	parsed, err := parseCSVFile(mapping, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Columns, nil
+}
 
-	// - First row contains column names
-  	// - Example: ["item_id", "item_type", "classification_marking", "timestamp", "parts_required", ...]
-	headers := records[0]
-	
-	var jsonRecords []map[string]interface{}
-	
-	// 	 Row-by-Row Processing:
-	// 	 - Skips header row (starts at i = 1)
-	// 	 - Creates map[string]interface{} for each data row
-	// 	 - Maps CSV columns to JSON fields using headers as keys
-
-	//   Special Field Handling:
-	//   - Array Fields (parts_required, compliance_refs):
-	//     - CSV: "engine_oil_filter;spark_plugs;hydraulic_fluid"
-	//     - JSON: ["engine_oil_filter", "spark_plugs", "hydraulic_fluid"]
-	//     - Uses splitAndTrim() helper to split on semicolon and clean whitespace
-	//   - Empty Values: Convert "" to null in JSON
-	//   - Regular Values: Keep as strings
-	for i := 1; i < len(records); i++ {
-		record := make(map[string]interface{})
-
-		for j, header := range headers {
-			if j < len(records[i]) {
-				value := records[i][j]
-				
-				if header == "parts_required" || header == "compliance_refs" {
-					if value != "" {
-						parts := splitAndTrim(value, ";")
-						record[header] = parts
-					} else {
-						record[header] = []string{}
-					}
-				} else if value == "" {
-					record[header] = nil
-				} else {
-					record[header] = value
-				}
-			}
+// Reads a CSV records file with the mapping's aliases and pivot options, as JSON records.
+// Rows that aren't valid CSV (kept when the mapping's rules skip or quarantine invalid
+// records) are returned as parse rejections.
+func loadCSVFile(mapping BLADEDataMapping, filePath string) (string, blademap.SourceVersion, []databricks.Warning, []databricks.Rejection, error) {
+	// Parsing (blademap.ParseCSV, shared with other BLADE consumers):
+	// - "#" preamble lines declare the export version
+	// - Headers are normalized and aliased (mapping CSVAliases, then the built-in ones)
+	// - Wide rows need the required columns; long-format files are pivoted with CSVPivot
+	// - Values are converted to the column types (mapping CSVTypes hints, else inferred)
+	parsed, err := parseCSVFile(mapping, filePath)
+	if err != nil {
+		return "", blademap.SourceVersion{}, nil, nil, err
+	}
+
+	// - Source values dropped on the way become warnings on the request (and the result)
+	var warnings []databricks.Warning
+	if parsed.RaggedRows > 0 {
+		warnings = append(warnings, databricks.Warning{Code: databricks.WarnSkippedFields,
+			Message: fmt.Sprintf("%s: ignored the fields past the %d header columns in %d row(s)", filePath, len(parsed.Headers), parsed.RaggedRows)})
+	}
+	if parsed.SkippedRows > 0 {
+		warnings = append(warnings, databricks.Warning{Code: databricks.WarnSkippedFields,
+			Message: fmt.Sprintf("%s: skipped %d long-format row(s) without an id, key and value", filePath, parsed.SkippedRows)})
+	}
+	for _, column := range parsed.Columns {
+		if column.Invalid > 0 {
+			warnings = append(warnings, databricks.Warning{Code: databricks.WarnCSVType,
+				Message: fmt.Sprintf("%s: %d value(s) of column %s aren't %s (first %q, row %d); kept as text", filePath, column.Invalid, column.Column, column.Type, column.Example, column.ExampleRow)})
 		}
-		
-		jsonRecords = append(jsonRecords, record)
 	}
-	
+
+	var rejects []databricks.Rejection
+	for _, bad := range parsed.BadRows {
+		rejects = append(rejects, databricks.Rejection{Index: bad.Row, Line: bad.Line, Stage: databricks.RejectStageParse,
+			Reasons: []string{"invalid CSV row: " + bad.Error}, Payload: bad.Text})
+	}
+
 	// - Marshals []map[string]interface{} to JSON string
   	// - Returns JSON that matches the structure of native JSON files
-	jsonData, err := json.Marshal(jsonRecords)
+	jsonData, err := json.Marshal(parsed.Records)
 	if err != nil {
-		return "", fmt.Errorf("failed to convert CSV to JSON: %w", err)
+		return "", blademap.SourceVersion{}, nil, nil, fmt.Errorf("failed to convert CSV to JSON: %w", err)
 	}
 	
-	return string(jsonData), nil
-}
-
-func splitAndTrim(s string, sep string) []string {
-	// - Splits string on separator (;)
-	// - Trims whitespace from each part
-	// - Filters out empty strings
-	// - Example: "part1; part2 ; ; part3" → ["part1", "part2", "part3"]
-	parts := []string{}
-	splits := strings.Split(s, sep)
-	for _, part := range splits {
-		if trimmed := strings.TrimSpace(part); trimmed != "" {
-			parts = append(parts, trimmed)
-		}
-	}
-	return parts
-}
\ No newline at end of file
+	return string(jsonData), parsed.Version, warnings, rejects, nil
+}
+
+func (b *BLADEAdapter) GetMapping(dataType string) (BLADEDataMapping, bool) {
+	// - Exposes a single mapping for callers that need table names or
+	//   descriptions without preparing a full ingestion request
+	mapping, exists := b.currentMappings()[dataType]
+	return mapping, exists
+}
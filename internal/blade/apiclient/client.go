@@ -0,0 +1,197 @@
+// Package apiclient pulls BLADE records from a BLADE REST endpoint instead of the mock
+// files, page by page, for the adapter's live mode (BLADE_MODE=live).
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/blademap"
+)
+
+//   Protocol:
+//   - GET {baseURL}/{dataType}?pageSize=N[&pageToken=T] with "Authorization: Bearer {token}"
+//     (no header without a token)
+//   - A page is the BLADE JSON export envelope plus the token of the next page:
+//     {"blade_export": {"format": ..., "version": ...}, "records": [...], "nextPageToken": "..."};
+//     an empty or missing nextPageToken ends the listing
+//   - A bare array of records is a single page
+//   - Every page has to declare the same export format/version, so one load never mixes
+//     the field semantics of two BLADE versions
+
+// Page size used when Options.PageSize is not set.
+const DefaultPageSize = 500
+
+// How a Client reaches the endpoint.
+//   - Token: Bearer token sent with every request ("" = none)
+//   - PageSize: Records asked for per page (<= 0 uses DefaultPageSize)
+//   - Timeout: Bound of each request (0 = none besides ctx)
+//   - HTTPClient: Defaults to http.DefaultClient
+type Options struct {
+	Token      string
+	PageSize   int
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+// Returned for non-2xx responses.
+type APIError struct {
+	StatusCode int
+	URL        string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("BLADE API returned %d for %s: %s", e.StatusCode, e.URL, e.Message)
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		message += " (check BLADE_API_TOKEN)"
+	case http.StatusNotFound:
+		message += " (check BLADE_API_URL and the data type)"
+	}
+	return message
+}
+
+// Calls the BLADE REST endpoint at a base URL (e.g. "https://blade.example.mil/api/v1").
+type Client struct {
+	baseURL string
+	opts    Options
+}
+
+// Creates a client for the endpoint at baseURL; it has to be an http(s) URL.
+func New(baseURL string, opts Options) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("BLADE API URL %q is not an http(s) URL", baseURL)
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), opts: opts}, nil
+}
+
+// Returns a copy of the client that sends token instead (e.g. once a secret reference is resolved).
+func (c *Client) WithToken(token string) *Client {
+	copied := *c
+	copied.opts.Token = token
+	return &copied
+}
+
+// Returns the URL a data type's records are listed at.
+func (c *Client) Endpoint(dataType string) string {
+	return c.baseURL + "/" + url.PathEscape(dataType)
+}
+
+// Shape of one page (see Protocol).
+type page struct {
+	Export        blademap.SourceVersion `json:"blade_export"`
+	Records       []json.RawMessage      `json:"records"`
+	NextPageToken string                 `json:"nextPageToken"`
+}
+
+// Pulls every page of a data type and returns the records as one JSON array, with the
+// export version the pages declare.
+func (c *Client) FetchRecords(ctx context.Context, dataType string) (string, blademap.SourceVersion, error) {
+	var records []json.RawMessage
+	var version blademap.SourceVersion
+	seen := map[string]bool{}
+	token := ""
+	for pages := 0; ; pages++ {
+		current, err := c.fetchPage(ctx, dataType, token)
+		if err != nil {
+			return "", blademap.SourceVersion{}, err
+		}
+		if pages == 0 {
+			version = current.Export
+		} else if current.Export != version {
+			return "", blademap.SourceVersion{}, fmt.Errorf("BLADE API changed the export version of %s during the listing (page %d: %+v, first page: %+v): load it again",
+				dataType, pages+1, current.Export, version)
+		}
+		records = append(records, current.Records...)
+
+		token = current.NextPageToken
+		if token == "" {
+			break
+		}
+		// - A token seen before would page forever
+		if seen[token] {
+			return "", blademap.SourceVersion{}, fmt.Errorf("BLADE API returned page token %q of %s twice", token, dataType)
+		}
+		seen[token] = true
+	}
+
+	if records == nil {
+		records = []json.RawMessage{}
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return "", blademap.SourceVersion{}, fmt.Errorf("failed to encode the %s records: %w", dataType, err)
+	}
+	return string(data), version, nil
+}
+
+func (c *Client) fetchPage(ctx context.Context, dataType, token string) (*page, error) {
+	query := url.Values{"pageSize": {strconv.Itoa(c.opts.PageSize)}}
+	if token != "" {
+		query.Set("pageToken", token)
+	}
+	endpoint := c.Endpoint(dataType)
+	if c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s failed: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of %s: %w", endpoint, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message := strings.TrimSpace(string(body))
+		if len(message) > 200 {
+			message = message[:200] + "..."
+		}
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, URL: endpoint, Message: message}
+	}
+
+	var current page
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(body, &current.Records)
+	} else {
+		err = json.Unmarshal(body, &current)
+		if err == nil && current.Records == nil {
+			err = fmt.Errorf("no records array")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid BLADE API response from %s: %w", endpoint, err)
+	}
+	current.Export.Format, current.Export.Version = strings.TrimSpace(current.Export.Format), strings.TrimSpace(current.Export.Version)
+	return &current, nil
+}
@@ -0,0 +1,843 @@
+package blade
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/datasource"
+)
+
+//   Purpose: The checked-in mock files hold five records per data type, too few for load
+//   tests or a convincing demo. GenerateMockData synthesizes any number of records with
+//   the fields of those fixtures, drawn from real aircraft, units, bases and part numbers,
+//   and writes them as the JSON, CSV and NDJSON files the adapter reads.
+
+//   Generation:
+//   - Deterministic: the same seed, start and count produce byte-identical files
+//   - Timestamps are spread evenly over the 30 days from the start, in record order
+//   - Item IDs embed the record number, so they are unique within a file
+//   - JSON and NDJSON records are nested like the fixtures (with their key order); CSV rows
+//     use the fixtures' flattened columns, lists joined with ";" and nulls left empty
+
+// Span the generated timestamps are spread over.
+const mockWindow = 30 * 24 * time.Hour
+
+// A generated record: the nested JSON object, and the columns only its CSV row has (the
+// other CSV columns are read from the object).
+type mockRecord struct {
+	object *mockObject
+	csv    map[string]interface{}
+}
+
+// Per data type: the fixture's CSV header (nil = the JSON keys) and the record generator.
+var mockDataTypes = map[string]struct {
+	csvColumns []string
+	generate   func(m *mockSource, n int, at time.Time) mockRecord
+}{
+	"maintenance": {nil, generateMaintenance},
+	"sortie": {[]string{
+		"item_id", "item_type", "classification_marking", "timestamp", "mission_number", "mission_type", "squadron",
+		"flight_lead", "flight_lead_callsign", "number_of_aircraft", "primary_tail_number", "takeoff_time",
+		"planned_duration_hours", "actual_duration_hours", "airspace_used", "range_used", "mission_objectives",
+		"weather_conditions", "mission_outcome", "debrief_notes", "fuel_consumed_total", "ordnance_expended",
+		"support_assets", "coordination_agencies", "next_sortie_planned",
+	}, generateSortie},
+	"deployment": {[]string{
+		"item_id", "item_type", "classification_marking", "timestamp", "deployment_name", "deployment_order",
+		"deploying_unit", "home_station", "deployed_location", "deployment_start_date", "deployment_end_date",
+		"deployment_duration", "personnel_count", "officers", "enlisted", "commander", "aircraft_deploying",
+		"aircraft_type", "equipment_tons", "deployment_phases", "phase_1_complete", "phase_2_start", "mission_type",
+		"supported_operation", "coordination_cell", "transportation_method", "deployment_status",
+		"readiness_percentage", "pre_deployment_training", "challenges", "point_of_contact",
+	}, generateDeployment},
+	"logistics": {[]string{
+		"item_id", "item_type", "classification_marking", "timestamp", "supply_category", "urgency", "requested_by",
+		"requester_poc", "requester_phone", "primary_nsn", "primary_description", "total_items",
+		"estimated_total_cost", "fund_cite", "project_code", "approval_status", "approved_by", "approved_date",
+		"expected_delivery", "actual_delivery", "carrier", "tracking_number", "storage_location", "remarks",
+		"related_work_order",
+	}, generateLogistics},
+}
+
+// Returns the data types GenerateMockData can synthesize, in sorted order.
+func MockDataTypes() []string {
+	types := make([]string, 0, len(mockDataTypes))
+	for dataType := range mockDataTypes {
+		types = append(types, dataType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Writes opts.Count synthetic records of dataType as {dir}/{dataType}/{dataType}_data.{json,csv,ndjson}
+// and returns the files written.
+//   - Every format is checked, and existing files refused (unless opts.Overwrite), before
+//     anything is written
+//   - Files are written under a hidden name and renamed, so watch never picks up half a file
+func (b *BLADEAdapter) GenerateMockData(dataType string, opts datasource.MockOptions) ([]string, error) {
+	if err := b.checkMockOptions(dataType, opts); err != nil {
+		return nil, err
+	}
+	dir := opts.Dir
+	if dir == "" {
+		dir = b.basePath
+	}
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = []string{"JSON", "CSV", "NDJSON"}
+	}
+	var paths []string
+	for _, format := range formats {
+		format = strings.ToUpper(format)
+		if format != "JSON" && format != "CSV" && format != "NDJSON" {
+			return nil, fmt.Errorf("unsupported format: %s (use JSON, CSV or NDJSON)", format)
+		}
+		path := filepath.Join(dir, dataType, fmt.Sprintf("%s_data.%s", dataType, strings.ToLower(format)))
+		if _, err := os.Stat(path); err == nil && !opts.Overwrite {
+			return nil, fmt.Errorf("%s already exists (overwrite it with --force, or write elsewhere with --out)", path)
+		}
+		paths = append(paths, path)
+	}
+
+	records := generateMockRecords(dataType, opts)
+	for _, path := range paths {
+		var data []byte
+		var err error
+		switch filepath.Ext(path) {
+		case ".json":
+			data, err = mockJSON(records)
+		case ".ndjson":
+			data, err = mockNDJSON(records)
+		case ".csv":
+			data, err = mockCSV(records, mockDataTypes[dataType].csvColumns)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		if err := writeMockFile(path, data); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// Builds a request loading opts.Count synthetic records of dataType from memory, like
+// PrepareIngestionRequest does from the JSON file (loadtest); opts.Formats, Dir and
+// Overwrite don't apply.
+func (b *BLADEAdapter) PrepareMockRequest(dataType string, opts datasource.MockOptions) (*databricks.IngestionRequest, error) {
+	if err := b.checkMockOptions(dataType, opts); err != nil {
+		return nil, err
+	}
+	sampleData, err := mockJSON(generateMockRecords(dataType, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mock records: %w", err)
+	}
+	mapping := b.currentMappings()[dataType]
+	return &databricks.IngestionRequest{
+		TableName:     mapping.TableName,
+		Catalog:       mapping.Catalog,
+		Schema:        mapping.Schema,
+		SourcePath:    "mock://" + dataType,
+		FileFormat:    "JSON",
+		FormatOptions: "'multiLine' = 'true', 'inferSchema' = 'true'",
+		DataSource:    b.dataSource,
+		SampleData:    string(sampleData),
+		TableType:     mapping.TableType,
+		StoragePath:   mapping.StoragePath,
+		Validations:   mapping.Validations,
+		ChildTables:   mapping.ChildTables,
+		TTL:           mapping.TTL,
+		Columns:       mapping.Columns,
+		Layout:        mapping.Layout,
+		Dedup:         mapping.Dedup,
+		Rules:         mapping.Rules,
+		Metadata: map[string]string{
+			"source_system":   "BLADE",
+			"data_type":       dataType,
+			"integration":     "databricks_poc",
+			"description":     mapping.Description,
+			"mode":            "mock_data",
+			"original_format": "JSON",
+			"mock_seed":       strconv.FormatInt(opts.Seed, 10),
+		},
+	}, nil
+}
+
+func (b *BLADEAdapter) checkMockOptions(dataType string, opts datasource.MockOptions) error {
+	if _, exists := b.currentMappings()[dataType]; !exists {
+		return fmt.Errorf("unsupported BLADE data type: %s", dataType)
+	}
+	if _, exists := mockDataTypes[dataType]; !exists {
+		return fmt.Errorf("no mock generator for data type %s (available: %s)", dataType, strings.Join(MockDataTypes(), ", "))
+	}
+	if opts.Count <= 0 {
+		return fmt.Errorf("invalid record count %d: must be positive", opts.Count)
+	}
+	return nil
+}
+
+// Generates the records; each data type gets its own stream of the seed, so generating one
+// type alone gives the same records as generating it with the others.
+func generateMockRecords(dataType string, opts datasource.MockOptions) []mockRecord {
+	hash := fnv.New64a()
+	hash.Write([]byte(dataType))
+	m := &mockSource{rand.New(rand.NewSource(opts.Seed ^ int64(hash.Sum64())))}
+	start := opts.Start.UTC().Truncate(time.Minute)
+	records := make([]mockRecord, opts.Count)
+	for i := range records {
+		at := start.Add(mockWindow * time.Duration(i) / time.Duration(opts.Count)).Truncate(time.Minute)
+		records[i] = mockDataTypes[dataType].generate(m, i+1, at)
+	}
+	return records
+}
+
+func writeMockFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	temp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".part")
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func mockJSON(records []mockRecord) ([]byte, error) {
+	objects := make([]*mockObject, len(records))
+	for i, record := range records {
+		objects[i] = record.object
+	}
+	data, err := json.MarshalIndent(objects, "", "  ")
+	return append(data, '\n'), err
+}
+
+func mockNDJSON(records []mockRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record.object)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func mockCSV(records []mockRecord, columns []string) ([]byte, error) {
+	if columns == nil {
+		columns = records[0].object.keys
+	}
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	row := make([]string, len(columns))
+	for _, record := range records {
+		for i, column := range columns {
+			value, flat := record.csv[column]
+			if !flat {
+				value = record.object.values[column]
+			}
+			row[i] = csvValue(value)
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// Spells a value the way the CSV fixtures do: lists joined with ";", money with cents.
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ";")
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// A JSON object that keeps its keys in the order they were set, like the fixtures.
+type mockObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newMockObject() *mockObject {
+	return &mockObject{values: map[string]interface{}{}}
+}
+
+func (o *mockObject) set(key string, value interface{}) *mockObject {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+	return o
+}
+
+func (o *mockObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Random choices over the vocabulary below.
+type mockSource struct {
+	*rand.Rand
+}
+
+func (m *mockSource) pick(values ...string) string {
+	return values[m.Intn(len(values))]
+}
+
+// An integer in [min, max].
+func (m *mockSource) between(min, max int) int {
+	return min + m.Intn(max-min+1)
+}
+
+// An amount in [min, max], rounded to cents.
+func (m *mockSource) amount(min, max float64) float64 {
+	return math.Round((min+m.Float64()*(max-min))*100) / 100
+}
+
+func (m *mockSource) chance(p float64) bool {
+	return m.Float64() < p
+}
+
+// Up to n distinct values, at least one, in their original order.
+func (m *mockSource) some(n int, values ...string) []string {
+	keep := m.Perm(len(values))[:m.between(1, min(n, len(values)))]
+	sort.Ints(keep)
+	picked := make([]string, len(keep))
+	for i, index := range keep {
+		picked[i] = values[index]
+	}
+	return picked
+}
+
+// "Rank Last, First M." with one of the given ranks.
+func (m *mockSource) person(ranks ...string) string {
+	return fmt.Sprintf("%s %s, %s %c.", m.pick(ranks...), m.pick(lastNames...), m.pick(firstNames...), 'A'+rune(m.Intn(26)))
+}
+
+// Tail number: fiscal year and serial, e.g. 87-0294.
+func (m *mockSource) tail(a aircraft) string {
+	return fmt.Sprintf("%02d-%04d", m.between(a.years[0], a.years[1])%100, m.Intn(10000))
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+var (
+	officerRanks  = []string{"2nd Lt", "1st Lt", "Capt", "Capt", "Maj"}
+	enlistedRanks = []string{"A1C", "SrA", "SrA", "SSgt", "SSgt", "TSgt"}
+	seniorNCOs    = []string{"TSgt", "MSgt", "MSgt", "SMSgt"}
+	firstNames    = []string{"Michael", "Sarah", "Carlos", "James", "Jennifer", "Robert", "Emily", "David", "Maria", "Jason",
+		"Ashley", "Christopher", "Nicole", "Daniel", "Kevin", "Rachel", "Brandon", "Amanda", "Tyler", "Laura"}
+	lastNames = []string{"Johnson", "Williams", "Rodriguez", "Thompson", "Smith", "Davis", "Miller", "Garcia", "Martinez",
+		"Anderson", "Taylor", "Harrison", "Wilson", "Moore", "Jackson", "Lee", "Nguyen", "Patel", "Brooks", "Carter"}
+)
+
+type base struct {
+	name     string
+	code     string // mission number prefix
+	airspace []string
+	rangeID  string
+	diverts  []string
+}
+
+var bases = []base{
+	{"Nellis AFB", "NL", []string{"R-4806W", "R-4807A", "R-4808N"}, "NTTR", []string{"Creech AFB", "Tonopah Test Range"}},
+	{"Holloman AFB", "HM", []string{"R-5107B", "R-5107C", "R-5111A"}, "White Sands", []string{"Kirtland AFB", "Cannon AFB"}},
+	{"Luke AFB", "LF", []string{"R-2301E", "R-2304", "R-2305"}, "BMGR", []string{"Davis-Monthan AFB", "Gila Bend AAF"}},
+	{"Davis-Monthan AFB", "DM", []string{"R-2303A", "R-2303B", "R-2304"}, "BMGR", []string{"Luke AFB", "Tucson IAP"}},
+	{"Langley AFB", "LY", []string{"W-72A", "W-386", "R-6604"}, "Dare County", []string{"Seymour Johnson AFB", "NAS Oceana"}},
+	{"Seymour Johnson AFB", "SJ", []string{"R-5314A", "R-5306D", "W-122"}, "Dare County", []string{"MCAS Cherry Point", "Langley AFB"}},
+	{"Eglin AFB", "EG", []string{"W-151A", "W-155", "R-2915A"}, "Eglin Range", []string{"Tyndall AFB", "NAS Pensacola"}},
+	{"Hill AFB", "HL", []string{"R-6404A", "R-6405", "R-6406A"}, "UTTR", []string{"Mountain Home AFB", "Salt Lake City IAP"}},
+	{"Moody AFB", "MY", []string{"R-3008A", "R-3008C", "W-132"}, "Grand Bay Range", []string{"Robins AFB", "Valdosta Regional"}},
+}
+
+type aircraft struct {
+	model      string // e.g. F-16C
+	prefix     string // item ID prefix, e.g. F16
+	techOrder  string // technical order series, e.g. 1F-16C
+	years      [2]int // fiscal years of the fleet's serials
+	twoSeat    bool   // second crew member (WSO)
+	squadrons  []string
+	callsigns  []string
+	loadouts   []string
+	fuelLoad   int // internal fuel in lbs
+	burnPerHr  int // fuel burned per flight hour in lbs
+	partsRange [2]float64
+}
+
+var fleet = []aircraft{
+	{"F-16C", "F16", "1F-16C", [2]int{1985, 1992}, false,
+		[]string{"34th Fighter Squadron", "64th Aggressor Squadron", "310th Fighter Squadron", "421st Fighter Squadron"},
+		[]string{"Viper", "Venom", "Bandit"}, []string{"2x AIM-120C, 2x AIM-9X, 1x ALQ-131", "2x GBU-12, 2x AIM-120C, 1x SNIPER XR"}, 7000, 6500, [2]float64{300, 45000}},
+	{"F-16D", "F16", "1F-16D", [2]int{1986, 1991}, true,
+		[]string{"34th Fighter Squadron", "310th Fighter Squadron"},
+		[]string{"Viper", "Venom"}, []string{"2x AIM-9X, 1x ACMI pod", "2x AIM-120C, 2x AIM-9X"}, 6000, 6200, [2]float64{300, 42000}},
+	{"A-10C", "A10", "1A-10C", [2]int{1978, 1982}, false,
+		[]string{"354th Fighter Squadron", "357th Fighter Squadron", "74th Fighter Squadron"},
+		[]string{"Hawg", "Tusk", "Dirty"}, []string{"2x GBU-38, 4x AGM-65, 1x LITENING", "14x BDU-33, 1x TGP, 1150 rds TP"}, 10700, 3500, [2]float64{200, 28000}},
+	{"F-22A", "F22", "1F-22A", [2]int{2003, 2009}, false,
+		[]string{"27th Fighter Squadron", "94th Fighter Squadron", "90th Fighter Squadron"},
+		[]string{"Raptor", "Ghost", "Spear"}, []string{"6x AIM-120D, 2x AIM-9X", "2x GBU-32, 2x AIM-120D, 2x AIM-9X"}, 18000, 9000, [2]float64{2000, 180000}},
+	{"F-15E", "F15", "1F-15E", [2]int{1987, 2001}, true,
+		[]string{"336th Fighter Squadron", "335th Fighter Squadron", "389th Fighter Squadron"},
+		[]string{"Rocco", "Chief", "Thunder"}, []string{"4x GBU-31, 2x AIM-120C, 2x AIM-9X", "8x GBU-39, 2x AIM-120C, 1x SNIPER XR"}, 13000, 8000, [2]float64{400, 60000}},
+	{"F-35A", "F35", "1F-35A", [2]int{2012, 2020}, false,
+		[]string{"58th Fighter Squadron", "4th Fighter Squadron", "421st Fighter Squadron"},
+		[]string{"Lightning", "Fuego", "Bolt"}, []string{"4x AIM-120D (internal)", "2x GBU-31, 2x AIM-120D (internal)"}, 18250, 8500, [2]float64{1500, 150000}},
+}
+
+// Maintenance work the generator draws from; code is the first part of the maintenance code.
+var maintenanceTasks = []struct {
+	itemType, code, maintenanceType string
+	descriptions, parts             []string
+	hours                           [2]int
+	safety                          string
+	refs                            []string
+}{
+	{"engine_maintenance", "ENG", "scheduled",
+		[]string{"100-hour engine inspection", "Engine borescope inspection", "Fuel nozzle replacement", "Oil analysis - elevated metals"},
+		[]string{"engine_oil_filter", "spark_plugs", "hydraulic_fluid", "fuel_nozzle", "ignition_exciter", "o_ring_kit"},
+		[2]int{4, 16}, "Standard engine safety protocols apply", []string{"-6", "-2-70JG-00-1"}},
+	{"avionics_check", "AVI", "unscheduled",
+		[]string{"Navigation system calibration - pilot reported drift", "Radar intermittent fault isolation", "IFF transponder failed BIT", "MFD display flicker"},
+		[]string{"nav_processor_board", "radar_receiver_module", "iff_transponder", "display_unit", "coax_cable_assy"},
+		[2]int{2, 10}, "Electronics safety procedures required", []string{"-2-21FI-00-1", "-2-94FR-00-1"}},
+	{"structural_inspection", "STR", "scheduled",
+		[]string{"Phase inspection - wing attach fittings", "Bulkhead crack inspection", "Canopy sill corrosion treatment", "Landing gear trunnion NDI"},
+		[]string{"corrosion_inhibitor", "sealant_kit", "fastener_kit", "primer"},
+		[2]int{8, 40}, "NDI technicians required; fuel tanks purged before access", []string{"-3", "-36"}},
+	{"weapons_system", "WPN", "unscheduled",
+		[]string{"Gun system jam - feed chute inspection", "Pylon release test failure", "Missile launcher rail alignment", "Bomb rack ejector cartridge replacement"},
+		[]string{"feed_chute", "ejector_cartridge", "launcher_rail", "harness_assy"},
+		[2]int{3, 12}, "Explosive safety briefing required; aircraft safed before work", []string{"-33-1-2", "-34-1-1"}},
+	{"landing_gear", "LDG", "unscheduled",
+		[]string{"Main landing gear strut servicing", "Nose wheel steering fault", "Brake assembly wear limit reached", "Tire replacement - cut tread"},
+		[]string{"brake_assy", "tire_main", "strut_seal_kit", "nitrogen_service"},
+		[2]int{2, 8}, "Aircraft on jacks; wheel chocks and safety pins installed", []string{"-2-32JG-00-1", "-6"}},
+}
+
+func generateMaintenance(m *mockSource, n int, at time.Time) mockRecord {
+	plane := fleet[m.Intn(len(fleet))]
+	task := maintenanceTasks[m.Intn(len(maintenanceTasks))]
+	site := bases[m.Intn(len(bases))]
+	hours := m.between(task.hours[0], task.hours[1])
+	estimated := at.Add(time.Duration(hours*3) * time.Hour)
+
+	// - About two thirds of the work orders are closed, with actual hours near the estimate
+	var completed, actualHours interface{}
+	if m.chance(0.65) {
+		actual := math.Round(float64(hours)*(0.75+m.Float64()*0.5)*4) / 4
+		actualHours = actual
+		completed = timestamp(at.Add(time.Duration(actual * 3 * float64(time.Hour))).Truncate(15 * time.Minute))
+	}
+	return mockRecord{object: newMockObject().
+		set("item_id", fmt.Sprintf("%s-%05d-%s-%d", plane.prefix, n, task.code, at.Year())).
+		set("item_type", task.itemType).
+		set("classification_marking", "UNCLASSIFIED").
+		set("timestamp", timestamp(at)).
+		set("aircraft_tail", m.tail(plane)).
+		set("aircraft_type", plane.model).
+		set("maintenance_type", task.maintenanceType).
+		set("maintenance_code", fmt.Sprintf("%s-%d", task.code, m.between(100, 599))).
+		set("description", m.pick(task.descriptions...)).
+		set("estimated_completion", timestamp(estimated)).
+		set("actual_completion", completed).
+		set("parts_required", m.some(3, task.parts...)).
+		set("parts_cost", m.amount(plane.partsRange[0], plane.partsRange[1])).
+		set("labor_hours_estimated", hours).
+		set("labor_hours_actual", actualHours).
+		set("technician_assigned", m.person(enlistedRanks...)).
+		set("technician_id", fmt.Sprintf("AF-%d-%04d", m.between(2012, 2023), m.Intn(10000))).
+		set("supervisor", m.person(seniorNCOs...)).
+		set("priority", m.pick("routine", "routine", "routine", "high", "urgent")).
+		set("base_location", site.name).
+		set("hangar", fmt.Sprintf("H-%d", m.between(1, 12))).
+		set("work_order", fmt.Sprintf("WO-%s-%03d", at.Format("2006-0102"), n%1000)).
+		set("safety_notes", task.safety).
+		set("compliance_refs", []string{"TO " + plane.techOrder + m.pick(task.refs...), m.pick("AFI 21-101", "AFI 21-103", "AFMAN 21-200")}).
+		set("previous_maintenance_date", timestamp(at.AddDate(0, 0, -m.between(60, 120)).Truncate(time.Hour))).
+		set("next_scheduled_date", timestamp(at.AddDate(0, 0, m.between(60, 120)).Truncate(time.Hour)))}
+}
+
+var sortieMissions = []struct {
+	itemType, missionType string
+	profiles, objectives  []string
+	ordnance              string
+	agencies              []string
+}{
+	{"training_mission", "air_combat_training", []string{"2v2 BFM transitioning to 4v4 ACM", "4v4 DCA with red air", "2v1 intercepts"},
+		[]string{"BFM", "ACM", "Tactical intercepts", "Defensive counter air"}, "", []string{"Approach Control", "Center", "Range Control"}},
+	{"close_air_support", "cas_training", []string{"CAS with JTAC, 9-line and talk-on", "Urban CAS with friendly forces in contact"},
+		[]string{"9-line execution", "Type 1/2 control", "Target talk-on", "Laser designation"}, "BDU-33 practice bombs; TP rounds", []string{"JTAC", "Range Control", "ASOC"}},
+	{"air_sovereignty", "alert_scramble", []string{"Alert scramble and intercept of unknown track", "Noble Eagle alert launch exercise"},
+		[]string{"Scramble timeline", "Visual identification", "Shadow and escort"}, "", []string{"NORAD", "Eastern Air Defense Sector", "Center"}},
+	{"large_force_exercise", "red_flag", []string{"Offensive counter air package vs IADS", "Strike package with SEAD support"},
+		[]string{"Package integration", "SEAD", "Strike on target area", "Egress under threat"}, "Inert GBU-12; simulated AIM-120", []string{"Exercise White Force", "AWACS", "Range Control"}},
+	{"combat_search_rescue", "csar_training", []string{"RESCORT for HH-60W recovery of isolated personnel", "On-scene commander for CSAR task force"},
+		[]string{"Survivor authentication", "Threat suppression", "Helicopter escort"}, "", []string{"JPRC", "Rescue Coordination Center", "Range Control"}},
+}
+
+func generateSortie(m *mockSource, n int, at time.Time) mockRecord {
+	plane := fleet[m.Intn(len(fleet))]
+	mission := sortieMissions[m.Intn(len(sortieMissions))]
+	site := bases[m.Intn(len(bases))]
+	squadron := m.pick(plane.squadrons...)
+	callsign := m.pick(plane.callsigns...)
+	loadout := m.pick(plane.loadouts...)
+
+	count := 2 * m.between(1, 2)
+	var aircraftList []*mockObject
+	for i := 1; i <= count; i++ {
+		entry := newMockObject().
+			set("tail_number", m.tail(plane)).
+			set("type", plane.model).
+			set("callsign", fmt.Sprintf("%s %02d", callsign, i)).
+			set("pilot", m.person(officerRanks...)).
+			set("pilot_hours_"+strings.ToLower(plane.prefix), m.between(150, 2800))
+		if plane.twoSeat {
+			entry.set("copilot", m.person(officerRanks...))
+		}
+		entry.set("fuel_load", plane.fuelLoad).set("configuration", loadout)
+		aircraftList = append(aircraftList, entry)
+	}
+	lead := aircraftList[0].values
+
+	takeoff := at.Add(30 * time.Minute)
+	planned := float64(m.between(2, 7)) / 2
+	actual := math.Round((planned-0.2+m.Float64()*0.3)*100) / 100
+	landing := takeoff.Add(time.Duration(actual * float64(time.Hour))).Truncate(time.Minute)
+	airspace := m.some(2, site.airspace...)
+	winds := fmt.Sprintf("%03d/%d", m.between(0, 35)*10, m.between(3, 25))
+	outcome := m.pick("successful", "successful", "successful", "partially_successful", "cancelled_weather")
+	tanker := m.pick("None required", "KC-135 on AR track", "KC-46 on AR track")
+	var ordnance, supportAssets interface{}
+	if mission.ordnance != "" {
+		ordnance = mission.ordnance
+	}
+	if tanker != "None required" {
+		supportAssets = strings.TrimSuffix(tanker, " on AR track")
+	}
+
+	return mockRecord{
+		object: newMockObject().
+			set("item_id", fmt.Sprintf("SORTIE-%s-%s-%05d", squadronCode(squadron), at.Format("20060102"), n)).
+			set("item_type", mission.itemType).
+			set("classification_marking", "UNCLASSIFIED").
+			set("timestamp", timestamp(at)).
+			set("mission_number", fmt.Sprintf("%s-%02d-%04d", site.code, at.Year()%100, n%10000)).
+			set("mission_type", mission.missionType).
+			set("squadron", squadron).
+			set("flight_lead", lead["pilot"]).
+			set("flight_lead_callsign", lead["callsign"]).
+			set("aircraft", aircraftList).
+			set("takeoff_time", timestamp(takeoff)).
+			set("landing_time", timestamp(landing)).
+			set("flight_duration", fmt.Sprintf("%.1f hours", actual)).
+			set("airspace", airspace[0]).
+			set("mission_profile", m.pick(mission.profiles...)).
+			set("weather_brief", fmt.Sprintf("%s, winds %s, %s", m.pick("VMC", "VMC", "IMC"), winds, m.pick("ceiling unlimited", "scattered 8000", "broken 4500"))).
+			set("divert_bases", site.diverts).
+			set("tanker_support", tanker).
+			set("range_clearance", fmt.Sprintf("%s-%s-%02d", strings.ReplaceAll(strings.ToUpper(site.rangeID), " ", ""), at.Format("2006-0102"), takeoff.Hour())).
+			set("base_location", site.name).
+			set("debrief_time", timestamp(landing.Add(30*time.Minute))).
+			set("mission_effectiveness", m.pick("pending_debrief", "effective", "effective", "partially_effective")),
+		csv: map[string]interface{}{
+			"number_of_aircraft":     count,
+			"primary_tail_number":    lead["tail_number"],
+			"planned_duration_hours": planned,
+			"actual_duration_hours":  actual,
+			"airspace_used":          airspace,
+			"range_used":             site.rangeID,
+			"mission_objectives":     m.some(3, mission.objectives...),
+			"weather_conditions":     []string{m.pick("Clear", "Scattered clouds", "Overcast"), m.pick("Visibility 10+ miles", "Visibility 7 miles"), "Winds " + winds},
+			"mission_outcome":        outcome,
+			"debrief_notes":          []string{m.pick("All training objectives met", "Objectives partially met", "Good flight discipline"), m.pick("No safety issues", "Minor comm issues on range frequency", "Tanker rendezvous delayed 10 minutes")},
+			"fuel_consumed_total":    int(actual*float64(plane.burnPerHr*count)/100) * 100,
+			"ordnance_expended":      ordnance,
+			"support_assets":         supportAssets,
+			"coordination_agencies":  mission.agencies,
+			"next_sortie_planned":    timestamp(takeoff.AddDate(0, 0, m.between(1, 3))),
+		},
+	}
+}
+
+// "34th Fighter Squadron" → "34FS".
+func squadronCode(squadron string) string {
+	number := strings.TrimRight(strings.Fields(squadron)[0], "stndrh")
+	if strings.Contains(squadron, "Aggressor") {
+		return number + "AGRS"
+	}
+	return number + "FS"
+}
+
+var deploymentLocations = []struct {
+	location, cell, operation string
+}{
+	{"Al Udeid AB, Qatar", "AFCENT A3", "OIR"},
+	{"Prince Sultan AB, Saudi Arabia", "AFCENT A3", "OIR"},
+	{"Muwaffaq Salti AB, Jordan", "AFCENT A3", "OIR"},
+	{"Kadena AB, Japan", "PACAF A3", "Pacific Deterrence"},
+	{"Osan AB, Republic of Korea", "PACAF A3", "Pacific Deterrence"},
+	{"Andersen AFB, Guam", "PACAF A3", "Pacific Deterrence"},
+	{"Spangdahlem AB, Germany", "USAFE A3", "Atlantic Resolve"},
+	{"Lask AB, Poland", "USAFE A3", "Atlantic Resolve"},
+}
+
+func generateDeployment(m *mockSource, n int, at time.Time) mockRecord {
+	plane := fleet[m.Intn(len(fleet))]
+	destination := deploymentLocations[m.Intn(len(deploymentLocations))]
+	unit := m.pick(plane.squadrons...)
+	home := bases[m.Intn(len(bases))].name
+
+	itemType := m.pick("personnel_deployment", "personnel_deployment", "exercise_deployment", "equipment_deployment")
+	name := fmt.Sprintf("Operation %s %s", m.pick("DESERT", "PACIFIC", "IRON", "NORTHERN", "STEEL", "SILENT"), m.pick("GUARDIAN", "SHIELD", "LANCE", "FALCON", "RESOLVE", "SENTRY"))
+	missionType := m.pick("combat_air_patrol", "theater_security_package", "agile_combat_employment")
+	if itemType == "exercise_deployment" {
+		name = fmt.Sprintf("Exercise %s %d", m.pick("COPE NORTH", "COBRA WARRIOR", "PITCH BLACK", "RED FLAG-Alaska", "ASTRAL KNIGHT"), at.Year())
+		missionType = "exercise_participation"
+	}
+	months := m.pick("3", "4", "6", "6")
+	monthCount, _ := strconv.Atoi(months)
+	start := at.AddDate(0, 0, m.between(14, 60)).Truncate(24 * time.Hour)
+	end := start.AddDate(0, monthCount, 0)
+	if itemType == "exercise_deployment" {
+		months = "3 weeks"
+		end = start.AddDate(0, 0, 21)
+	} else {
+		months += " months"
+	}
+
+	aircraftCount := 2 * m.between(2, 9)
+	if itemType == "equipment_deployment" {
+		aircraftCount = 0
+	}
+	pilots := aircraftCount + aircraftCount/2
+	maintainers := aircraftCount*7 + m.between(5, 20)
+	support := m.between(25, 80)
+	officers := pilots + m.between(4, 10)
+	personnel := pilots + maintainers + support
+	commander := m.person("Lt Col")
+	opsOfficer := m.person("Maj")
+	readiness := m.between(70, 100)
+
+	return mockRecord{
+		object: newMockObject().
+			set("item_id", fmt.Sprintf("DEPLOY-%d-%05d", at.Year(), n)).
+			set("item_type", itemType).
+			set("classification_marking", "UNCLASSIFIED").
+			set("timestamp", timestamp(at)).
+			set("deployment_name", name).
+			set("deployment_order", fmt.Sprintf("DEPORD-%02d-%04d", at.Year()%100, n%10000)).
+			set("deploying_unit", unit).
+			set("home_station", home).
+			set("deployed_location", destination.location).
+			set("deployment_start_date", timestamp(start)).
+			set("deployment_end_date", timestamp(end)).
+			set("deployment_duration", months).
+			set("personnel_count", personnel).
+			set("personnel_breakdown", newMockObject().
+				set("officers", officers).
+				set("enlisted", personnel-officers).
+				set("pilots", pilots).
+				set("maintenance", maintainers).
+				set("support", support)).
+			set("key_personnel", newMockObject().
+				set("commander", commander).
+				set("operations_officer", opsOfficer).
+				set("maintenance_officer", m.person("Capt", "Maj")).
+				set("first_sergeant", m.person("MSgt", "SMSgt"))).
+			set("aircraft_deploying", aircraftCount).
+			set("aircraft_type", plane.model).
+			set("support_equipment", fmt.Sprintf("%dx cargo pallets AGE", m.between(2, 12))).
+			set("airlift_required", newMockObject().
+				set("pax_missions", 1+personnel/150).
+				set("cargo_missions", m.between(2, 8)).
+				set("tanker_support", m.pick("Required for fighter movement", "Not required"))).
+			set("pre_deployment_training", m.pick("Complete", "In progress")).
+			set("medical_clearance", fmt.Sprintf("%d%% complete", m.between(85, 100))).
+			set("security_clearances", m.pick("Current", "Current", "3 pending")).
+			set("family_support_plan", "Activated").
+			set("rear_detachment_size", m.between(8, 30)),
+		csv: map[string]interface{}{
+			"officers":                officers,
+			"enlisted":                personnel - officers,
+			"commander":               commander,
+			"equipment_tons":          m.between(80, 900),
+			"deployment_phases":       []string{"Pre-deployment training", "Movement", "Reception and integration", "Mission execution", "Redeployment"},
+			"phase_1_complete":        timestamp(start.AddDate(0, 0, -7)),
+			"phase_2_start":           timestamp(start),
+			"mission_type":            missionType,
+			"supported_operation":     destination.operation,
+			"coordination_cell":       destination.cell,
+			"transportation_method":   m.pick("AMC strategic airlift", "Fighter drag with tanker support", "Commercial charter and AMC airlift"),
+			"deployment_status":       m.pick("planning", "preparing", "preparing"),
+			"readiness_percentage":    readiness,
+			"pre_deployment_training": m.some(3, "Combat skills training complete", "CBRNE training scheduled", "SERE refresher complete", "Weapons qualification in progress"),
+			"challenges":              m.pick("Limited strategic airlift availability", "Parts shortages for deploying aircraft", "Host nation diplomatic clearances pending", ""),
+			"point_of_contact":        opsOfficer,
+		},
+	}
+}
+
+// Stock items the supply requests order; cost is a unit price range.
+var stockItems = []struct {
+	nsn, partNumber, description, unit string
+	cost                               [2]float64
+	category                           string
+}{
+	{"1560-01-234-5678", "16C1234-805", "ACTUATOR ASSY, FLIGHT CONTROL", "each", [2]float64{6000, 12000}, "aircraft_parts"},
+	{"2840-01-345-6789", "ENG-4421B", "FILTER, ENGINE OIL", "each", [2]float64{90, 160}, "aircraft_parts"},
+	{"1620-01-456-7890", "MLG-7734-3", "BRAKE ASSY, MAIN LANDING GEAR", "each", [2]float64{4200, 7800}, "aircraft_parts"},
+	{"5841-01-567-8901", "RAD-3310A", "RECEIVER MODULE, RADAR", "each", [2]float64{18000, 42000}, "aircraft_parts"},
+	{"2620-01-678-9012", "TIR-2210", "TIRE, PNEUMATIC, AIRCRAFT", "each", [2]float64{900, 1800}, "aircraft_parts"},
+	{"9150-00-180-6266", "MIL-PRF-5606", "HYDRAULIC FLUID", "gallon", [2]float64{35, 60}, "consumables"},
+	{"8030-01-061-3434", "PR-1422B2", "SEALING COMPOUND", "kit", [2]float64{80, 140}, "consumables"},
+	{"6850-01-432-1234", "CC-4498", "CORROSION PREVENTIVE COMPOUND", "can", [2]float64{20, 45}, "consumables"},
+	{"4920-01-789-0123", "AGE-2210", "HYDRAULIC TEST STAND, PORTABLE", "each", [2]float64{25000, 60000}, "age_equipment"},
+	{"6115-01-890-1234", "AM32A-60", "GENERATOR SET, DIESEL ENGINE", "each", [2]float64{40000, 90000}, "age_equipment"},
+}
+
+func generateLogistics(m *mockSource, n int, at time.Time) mockRecord {
+	plane := fleet[m.Intn(len(fleet))]
+	site := bases[m.Intn(len(bases))]
+	unit := m.pick(plane.squadrons...)
+	category := m.pick("aircraft_parts", "aircraft_parts", "consumables", "age_equipment")
+	itemType := m.pick("supply_request", "supply_request", "bench_stock_request")
+	urgency := m.pick("routine", "routine", "priority")
+	if category == "aircraft_parts" && m.chance(0.2) {
+		itemType, urgency = "micap_request", "urgent"
+	}
+
+	var candidates []int
+	for i, item := range stockItems {
+		if item.category == category {
+			candidates = append(candidates, i)
+		}
+	}
+	var items []*mockObject
+	total := 0.0
+	for _, index := range m.Perm(len(candidates))[:m.between(1, min(3, len(candidates)))] {
+		item := stockItems[candidates[index]]
+		quantity := m.between(1, 4)
+		if item.unit != "each" {
+			quantity = m.between(5, 60)
+		}
+		cost := m.amount(item.cost[0], item.cost[1])
+		total += cost * float64(quantity)
+		items = append(items, newMockObject().
+			set("nsn", item.nsn).
+			set("part_number", item.partNumber).
+			set("description", item.description).
+			set("quantity_requested", quantity).
+			set("unit_of_measure", item.unit).
+			set("unit_cost", cost).
+			set("justification", m.pick("Scheduled replacement for tail number "+m.tail(plane), "Replenish squadron maintenance stock", "Aircraft NMCS awaiting part", "Bench stock below reorder point")))
+	}
+	total = math.Round(total*100) / 100
+
+	status := m.pick("pending_approval", "approved", "approved", "shipped", "delivered")
+	required := at.AddDate(0, 0, map[string]int{"urgent": 2, "priority": 7, "routine": 21}[urgency]).Truncate(24 * time.Hour)
+	var approvedBy, approvedDate, actualDelivery, carrier, tracking interface{}
+	if status != "pending_approval" {
+		approvedBy = m.person("Capt", "Maj")
+		approvedDate = timestamp(at.Add(time.Duration(m.between(1, 8)) * time.Hour))
+	}
+	if status == "shipped" || status == "delivered" {
+		carrier = m.pick("FedEx", "UPS", "DLA Distribution", "AMC channel mission")
+		tracking = fmt.Sprintf("TRK%010d", m.Int63n(1e10))
+	}
+	if status == "delivered" {
+		actualDelivery = timestamp(required.AddDate(0, 0, -m.between(0, 2)))
+	}
+	primary := items[0].values
+	requester := m.person("Capt", "TSgt", "MSgt")
+
+	return mockRecord{
+		object: newMockObject().
+			set("item_id", fmt.Sprintf("LOG-%d-%05d", at.Year(), n)).
+			set("item_type", itemType).
+			set("classification_marking", "UNCLASSIFIED").
+			set("timestamp", timestamp(at)).
+			set("supply_category", category).
+			set("urgency", urgency).
+			set("requested_by", unit).
+			set("requester_poc", requester).
+			set("requester_phone", fmt.Sprintf("DSN %03d-%04d", m.between(200, 899), m.Intn(10000))).
+			set("items", items).
+			set("total_cost", total).
+			set("supply_status", status).
+			set("approval_authority", fmt.Sprintf("%d %s/LG", m.between(1, 388), m.pick("FW", "WG"))).
+			set("base_location", site.name).
+			set("delivery_location", fmt.Sprintf("Building %d, Supply Dock %c", m.between(100, 999), 'A'+rune(m.Intn(4)))).
+			set("required_delivery_date", timestamp(required)).
+			set("fund_cite", fmt.Sprintf("57 3400 %d BA01 %06d S%05d %06d", at.Year(), m.Intn(1000000), m.Intn(100000), m.Intn(1000000))).
+			set("priority_code", fmt.Sprintf("%02d", map[string]int{"urgent": 2, "priority": 6, "routine": 13}[urgency])).
+			set("project_code", "X"+strings.ReplaceAll(plane.model, "-", "")),
+		csv: map[string]interface{}{
+			"primary_nsn":          primary["nsn"],
+			"primary_description":  primary["description"],
+			"total_items":          len(items),
+			"estimated_total_cost": total,
+			"approval_status":      status,
+			"approved_by":          approvedBy,
+			"approved_date":        approvedDate,
+			"expected_delivery":    timestamp(required),
+			"actual_delivery":      actualDelivery,
+			"carrier":              carrier,
+			"tracking_number":      tracking,
+			"storage_location":     fmt.Sprintf("Building %d Bay %d", m.between(100, 999), m.between(1, 9)),
+			"remarks":              fmt.Sprintf("%dx %s for %s", primary["quantity_requested"], strings.ToLower(primary["description"].(string)), plane.model),
+			"related_work_order":   fmt.Sprintf("WO-%s-%03d", at.Format("2006-0102"), m.between(1, 999)),
+		},
+	}
+}
@@ -0,0 +1,226 @@
+package blade
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/databricks"
+)
+
+//   Purpose: Guards mapping config before use. Once mappings come from a user-editable
+//   file, every table name, column name and SQL fragment in it ends up inside SQL the
+//   client builds, so anything outside a small allowlist grammar is rejected.
+
+var (
+	// Unity Catalog identifiers the client interpolates unquoted
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,254}$`)
+
+	// Tokens a SQL fragment may consist of; anything else fails the lint
+	sqlTokenPattern = regexp.MustCompile(`^(?:` +
+		`\s+` + // whitespace
+		`|\{table\}` + // validation rule placeholder
+		`|:[A-Za-z_][A-Za-z0-9_]*` + // named parameter marker
+		`|[A-Za-z_][A-Za-z0-9_]*` + // identifier or keyword
+		`|[0-9]+(?:\.[0-9]+)?` + // number
+		`|'(?:[^'\;]|\\.)*'` + // string literal (no semicolons)
+		"|`[A-Za-z0-9_ ]+`" + // quoted identifier
+		`|<=|>=|<>|!=|==|\|\||[=<>+\-*/%(),.\[\]$]` + // operators and punctuation
+		`)`)
+
+	// Keywords that start or smuggle statements other than a read-only query
+	forbiddenSQLKeywords = map[string]bool{
+		"ALTER": true, "ANALYZE": true, "CACHE": true, "CALL": true, "COPY": true, "CREATE": true,
+		"DELETE": true, "DESCRIBE": true, "DROP": true, "EXECUTE": true, "GRANT": true, "INSERT": true,
+		"MERGE": true, "MSCK": true, "OPTIMIZE": true, "REFRESH": true, "RESTORE": true,
+		"REVOKE": true, "SET": true, "SHOW": true, "TRUNCATE": true, "UNCACHE": true, "UPDATE": true,
+		"USE": true, "VACUUM": true,
+	}
+)
+
+// Checks every mapping against the identifier and SQL fragment allowlists; returns all problems found.
+func LintMappings(mappings []BLADEDataMapping) error {
+	var errs []error
+	seen := make(map[string]bool)
+	for _, mapping := range mappings {
+		problem := func(format string, args ...interface{}) {
+			errs = append(errs, fmt.Errorf("mapping %q: %s", mapping.DataType, fmt.Sprintf(format, args...)))
+		}
+
+		// Names:
+		// - DataType and TableName become identifiers and file paths, Catalog and Schema identifiers
+		// - Duplicate data types would silently shadow each other
+		if !identifierPattern.MatchString(mapping.DataType) {
+			problem("data type must be an identifier")
+		} else if seen[mapping.DataType] {
+			problem("duplicate data type")
+		}
+		seen[mapping.DataType] = true
+		if !identifierPattern.MatchString(mapping.TableName) {
+			problem("table name %q is not a valid identifier", mapping.TableName)
+		}
+		if mapping.Catalog != "" && !identifierPattern.MatchString(mapping.Catalog) {
+			problem("catalog %q is not a valid identifier", mapping.Catalog)
+		}
+		if mapping.Schema != "" && !identifierPattern.MatchString(mapping.Schema) {
+			problem("schema %q is not a valid identifier", mapping.Schema)
+		}
+		if strings.ContainsAny(mapping.StoragePath, "';\\") || strings.Contains(mapping.StoragePath, "..") {
+			problem("storage path %q contains forbidden characters", mapping.StoragePath)
+		}
+
+		// Columns:
+		// - CSV aliases and pivot columns name record fields
+		// - Semantic column names go into ALTER TABLE ... ALTER COLUMN
+		for alias, column := range mapping.CSVAliases {
+			if !identifierPattern.MatchString(blademap.NormalizeHeader(alias, nil)) || !identifierPattern.MatchString(column) {
+				problem("CSV alias %q → %q is not a valid column name", alias, column)
+			}
+		}
+		for field, columnType := range mapping.CSVTypes {
+			if !identifierPattern.MatchString(field) {
+				problem("CSV type hint for %q is not a valid column name", field)
+			}
+			if err := blademap.ValidateColumnType(columnType); err != nil {
+				problem("CSV column %s: %v", field, err)
+			}
+		}
+		if pivot := mapping.CSVPivot.Normalized(); pivot != nil {
+			for _, column := range []string{pivot.IDColumn, pivot.KeyColumn, pivot.ValueColumn} {
+				if !identifierPattern.MatchString(column) {
+					problem("CSV pivot column %q is not a valid column name", column)
+				}
+			}
+		}
+		for _, column := range mapping.Semantics.Columns {
+			if !identifierPattern.MatchString(column.Name) {
+				problem("semantic column %q is not a valid column name", column.Name)
+			}
+		}
+
+		// Child Tables:
+		// - Table and field names become identifiers in the child table DDL
+		for _, child := range mapping.ChildTables {
+			if !identifierPattern.MatchString(child.Table) {
+				problem("child table name %q is not a valid identifier", child.Table)
+			}
+			if child.Exploder != "" {
+				if !databricks.IsChildExploder(child.Exploder) {
+					problem("child table %s names unknown exploder %q", child.Table, child.Exploder)
+				}
+				continue
+			}
+			for _, segment := range strings.Split(child.Path, ".") {
+				if !identifierPattern.MatchString(segment) {
+					problem("child table %s path %q is not a dotted field path", child.Table, child.Path)
+					break
+				}
+			}
+			for _, field := range child.Fields {
+				if !identifierPattern.MatchString(field) {
+					problem("child table %s field %q is not a valid column name", child.Table, field)
+				}
+			}
+		}
+
+		// Typed Columns:
+		// - Names go into the table DDL, fields into the COPY INTO transformation, types into casts
+		for _, column := range mapping.Columns {
+			if !identifierPattern.MatchString(column.Name) || (column.Field != "" && !identifierPattern.MatchString(column.Field)) {
+				problem("typed column %q (field %q) is not a valid column name", column.Name, column.Field)
+			}
+			if _, err := column.SQLType(); err != nil {
+				problem("%v", err)
+			}
+		}
+
+		// Layout:
+		// - Partition and clustering columns go into the table DDL and must be columns of it
+		if layout := mapping.Layout; layout != nil {
+			if err := layout.Check(mapping.Columns); err != nil {
+				problem("%v", err)
+			}
+		}
+
+		// TTL:
+		// - The field is cast in the COPY INTO transformation, the view name becomes an identifier
+		if ttl := mapping.TTL; ttl != nil {
+			if !identifierPattern.MatchString(ttl.Field) {
+				problem("TTL field %q is not a valid field name", ttl.Field)
+			}
+			if ttl.View != "" && !identifierPattern.MatchString(ttl.View) {
+				problem("TTL view name %q is not a valid identifier", ttl.View)
+			}
+			if _, err := ttl.Duration(); err != nil {
+				problem("%v", err)
+			}
+		}
+
+		// Dedup:
+		// - Fields only pick the record values hashed, but each must name one
+		if dedup := mapping.Dedup; dedup != nil {
+			for _, field := range dedup.Fields {
+				if strings.TrimSpace(field) == "" {
+					problem("dedup lists an empty field")
+				}
+			}
+		}
+
+		// Rules:
+		// - Patterns compile and bounds parse; the mode is checked with the request
+		if rules := mapping.Rules; rules != nil {
+			if err := rules.Check(); err != nil {
+				problem("%v", err)
+			}
+			switch strings.ToLower(rules.OnInvalid) {
+			case "", databricks.OnInvalidFail, databricks.OnInvalidSkip, databricks.OnInvalidQuarantine:
+			default:
+				problem("rules onInvalid %q must be %s, %s or %s", rules.OnInvalid, databricks.OnInvalidFail, databricks.OnInvalidSkip, databricks.OnInvalidQuarantine)
+			}
+		}
+
+		// SQL Fragments:
+		// - Conditions are WHERE predicates, custom SQL must be a single SELECT
+		for _, rule := range mapping.Validations {
+			if !identifierPattern.MatchString(rule.Name) {
+				problem("validation rule name %q is not a valid identifier", rule.Name)
+			}
+			if rule.Condition != "" {
+				if err := lintSQLFragment(rule.Condition); err != nil {
+					problem("validation %s condition: %v", rule.Name, err)
+				}
+			}
+			if rule.SQL != "" {
+				if err := lintSQLFragment(rule.SQL); err != nil {
+					problem("validation %s sql: %v", rule.Name, err)
+				} else if first := strings.ToUpper(strings.Fields(rule.SQL)[0]); first != "SELECT" && first != "WITH" {
+					problem("validation %s sql must be a SELECT query", rule.Name)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Tokenizes a SQL fragment against the allowlist grammar and rejects statement keywords.
+func lintSQLFragment(fragment string) error {
+	if strings.TrimSpace(fragment) == "" {
+		return fmt.Errorf("empty SQL fragment")
+	}
+	for rest := fragment; rest != ""; {
+		if strings.HasPrefix(rest, "--") || strings.HasPrefix(rest, "/*") {
+			return fmt.Errorf("comments are not allowed")
+		}
+		token := sqlTokenPattern.FindString(rest)
+		if token == "" {
+			return fmt.Errorf("unexpected character %q", rest[:1])
+		}
+		if forbiddenSQLKeywords[strings.ToUpper(token)] {
+			return fmt.Errorf("keyword %s is not allowed", strings.ToUpper(token))
+		}
+		rest = rest[len(token):]
+	}
+	return nil
+}
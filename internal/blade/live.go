@@ -0,0 +1,57 @@
+package blade
+
+import (
+	"context"
+	"time"
+
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/blade/apiclient"
+	"databricks-blade-poc/internal/secrets"
+)
+
+//   Purpose: In live mode (BLADE_MODE=live) PrepareIngestionRequest pulls a data type's
+//   records from the BLADE REST API instead of reading {dataType}_data.json, and builds
+//   the same request from them: mappings, version metadata, rules and validations apply
+//   as they do to the mock files.
+
+//   Notes:
+//   - The request's SourcePath (and metadata source_url) is the data type's endpoint, and
+//     its mode is live_api, so rows and reports name the API as their source (blade_api)
+//   - The fetch runs under the caller's ctx: SIGINT/SIGTERM, --max-runtime and
+//     BLADE_RUN_DEADLINE stop it like they stop the load
+//   - BLADE_API_TOKEN may be a secret store reference; it is resolved on the first fetch,
+//     so commands that never fetch don't need the secret store
+//   - Only PrepareIngestionRequest (ingest, ingest --all, serve) fetches: files given to
+//     ingest --source, watch and the Advana snapshots are loaded as before
+
+// Where live mode fetches from.
+type liveSource struct {
+	client *apiclient.Client
+	token  string // BLADE_API_TOKEN as configured (plain or a secret reference)
+}
+
+// Makes PrepareIngestionRequest fetch the records from the BLADE REST API at baseURL.
+//   - token: Bearer token or secret reference ("" = none)
+//   - pageSize: Records per page (<= 0 uses apiclient.DefaultPageSize)
+//   - timeout: Bound of each page request (0 = none)
+func (b *BLADEAdapter) UseLiveAPI(baseURL, token string, pageSize int, timeout time.Duration) error {
+	client, err := apiclient.New(baseURL, apiclient.Options{PageSize: pageSize, Timeout: timeout})
+	if err != nil {
+		return err
+	}
+	b.live = &liveSource{client: client, token: token}
+	return nil
+}
+
+func (l *liveSource) endpoint(dataType string) string {
+	return l.client.Endpoint(dataType)
+}
+
+// Pulls every page of a data type's records as one JSON array.
+func (l *liveSource) fetch(ctx context.Context, dataType string) (string, blademap.SourceVersion, error) {
+	token, err := secrets.Resolve(ctx, "BLADE_API_TOKEN", l.token)
+	if err != nil {
+		return "", blademap.SourceVersion{}, err
+	}
+	return l.client.WithToken(token).FetchRecords(ctx, dataType)
+}
@@ -0,0 +1,143 @@
+package blade
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+//   Purpose: Loads BLADE mappings from a JSON or YAML file (BLADE_MAPPINGS_FILE) instead
+//   of the compiled-in set, so one file can be shared across dev/test/prod deployments
+//   and new data types can be added without a rebuild.
+
+//   File Format:
+//   - {"mappings": [ ...BLADEDataMapping... ]}, or the same structure in YAML (.yaml/.yml)
+//   - YAML uses the JSON field names, so both forms validate identically
+//   - Every string value may reference ${ENV_VAR} or ${ENV_VAR:-default}
+//   - Interpolation runs on decoded string values, so substituted text can't break the JSON
+type mappingFile struct {
+	Mappings []BLADEDataMapping `json:"mappings"`
+}
+
+// Reads, interpolates and lints a mappings file.
+func LoadMappingsFile(path string) ([]BLADEDataMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mappings file %s: %w", path, err)
+	}
+
+	var raw interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mappings file %s: %w", path, err)
+	}
+	expanded, err := interpolateValues(raw)
+	if err != nil {
+		return nil, fmt.Errorf("mappings file %s: %w", path, err)
+	}
+	data, err = json.Marshal(expanded)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unknown fields are rejected so a typo doesn't silently drop a setting
+	var file mappingFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid mappings file %s: %w", path, err)
+	}
+	if len(file.Mappings) == 0 {
+		return nil, fmt.Errorf("mappings file %s defines no mappings", path)
+	}
+	if err := LintMappings(file.Mappings); err != nil {
+		return nil, fmt.Errorf("mappings file %s failed lint: %w", path, err)
+	}
+	return file.Mappings, nil
+}
+
+// Interpolates every string in a decoded JSON value (keys are left as written).
+func interpolateValues(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		return config.Interpolate(value)
+	case []interface{}:
+		for i, item := range value {
+			expanded, err := interpolateValues(item)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = expanded
+		}
+	case map[string]interface{}:
+		for key, item := range value {
+			expanded, err := interpolateValues(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			value[key] = expanded
+		}
+	}
+	return value, nil
+}
+
+// Builds an adapter over a mappings file that picks up edits without a restart.
+//   - The file is checked on every mapping lookup; a changed file is reloaded and linted
+//   - A file that no longer loads is logged and the previous mappings stay in use
+func NewBLADEAdapterFromFile(dataSource, basePath, path string) (*BLADEAdapter, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mappings file %s: %w", path, err)
+	}
+	mappings, err := LoadMappingsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	adapter := NewBLADEAdapterWithMappings(dataSource, basePath, mappings)
+	adapter.watch = &mappingWatch{path: path, modTime: info.ModTime(), size: info.Size()}
+	return adapter, nil
+}
+
+// The mappings file an adapter was loaded from and the version last seen.
+type mappingWatch struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// Returns the adapter's mappings, reloading them first if the watched file changed.
+func (b *BLADEAdapter) currentMappings() map[string]BLADEDataMapping {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.watch == nil {
+		return b.mappings
+	}
+
+	info, err := os.Stat(b.watch.path)
+	if err != nil || (info.ModTime().Equal(b.watch.modTime) && info.Size() == b.watch.size) {
+		return b.mappings
+	}
+	// - The version is recorded even when it fails to load, so a broken file is reported once
+	b.watch.modTime, b.watch.size = info.ModTime(), info.Size()
+	mappings, err := LoadMappingsFile(b.watch.path)
+	if err != nil {
+		log.Printf("Keeping previous BLADE mappings: %v", err)
+		return b.mappings
+	}
+	b.mappings = indexMappings(mappings)
+	log.Printf("Reloaded %d BLADE mappings from %s", len(b.mappings), b.watch.path)
+	return b.mappings
+}
@@ -1,20 +1,67 @@
 package blade
 
+import (
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/databricks"
+)
+
 //   Purpose: Defines the configuration for each supported BLADE data type.
 
 //   Fields:
 //   - DataType: The BLADE data category identifier ("maintenance", "sortie", etc.)
 //   - TableName: The corresponding Databricks table name where this data will be stored
+//   - Catalog/Schema: Optional namespace for this data type's tables (e.g. sortie in an ops schema,
+//     logistics in supply); empty uses DATABRICKS_CATALOG/DATABRICKS_SCHEMA
 //   - SourcePath: Mock path identifier for POC (uses "mock://" protocol)
 //   - Description: Human-readable description of what this data type contains
+//   - TableType: "MANAGED" (default) or "EXTERNAL" for data owners who require BLADE data to stay in their storage account
+//   - StoragePath: EXTERNAL only - path under DATABRICKS_EXTERNAL_LOCATION (or a full URL); defaults to the table name
+//   - Validations: Post-load SQL checks run against each ingested batch (defaults to databricks.DefaultValidationRules)
+//   - CSVAliases: Extra CSV header spellings (normalized form → canonical field name) for this data type
+//   - CSVPivot: Optional long-format (id/key/value) CSV layout to pivot back into one record per item
+//   - CSVTypes: CSV column type hints (field → string, int, float, bool, timestamp or array); other columns are inferred
+//   - Semantics: Column descriptions, synonyms and example questions seeded for Genie spaces (seed-semantics)
+//   - ChildTables: One-to-many arrays in each record (e.g. parts_required) materialized into child tables
+//   - TTL: Per-record expiry derived from a field, stamped into metadata['expires_at'], plus a view of unexpired rows
+//   - Columns: Typed columns filled from record fields after the standard columns (raw_data keeps the full record)
+//   - Layout: Partition columns (e.g. days(timestamp)) or liquid clustering keys the table is created with
+//   - Dedup: Skip records whose content hash (of the listed fields, or the whole record) the table already holds
+//   - Rules: Per-record checks run before loading; invalid records fail the load, are skipped or quarantined (defaults to databricks.DefaultRecordRules)
 
 type BLADEDataMapping struct {
 	DataType    string `json:"dataType"` // BLADE data type
 	TableName   string `json:"tableName"` // corresponding Databricks table name
+	Catalog     string `json:"catalog,omitempty"` // overrides the configured catalog for this data type
+	Schema      string `json:"schema,omitempty"`  // overrides the configured schema for this data type
 	SourcePath  string `json:"sourcePath"` // mock source path for POC (not a real data path)
 	Description string `json:"description"`
+	TableType   string `json:"tableType,omitempty"`   // MANAGED (default) or EXTERNAL
+	StoragePath string `json:"storagePath,omitempty"` // EXTERNAL tables only: LOCATION relative to the configured external location
+	Validations []databricks.ValidationRule `json:"validations,omitempty"`
+	CSVAliases  map[string]string           `json:"csvAliases,omitempty"`
+	CSVPivot    *CSVPivot                   `json:"csvPivot,omitempty"`
+	CSVTypes    map[string]string           `json:"csvTypes,omitempty"`
+	Semantics   databricks.TableSemantics   `json:"semantics"`
+	ChildTables []databricks.ChildTable     `json:"childTables,omitempty"`
+	TTL         *databricks.TTLPolicy       `json:"ttl,omitempty"`
+	Columns     []databricks.TypedColumn    `json:"columns,omitempty"`
+	Layout      *databricks.TableLayout     `json:"layout,omitempty"`
+	Dedup       *databricks.DedupPolicy     `json:"dedup,omitempty"`
+	Rules       *databricks.RecordRules     `json:"rules,omitempty"`
 }
 
+// Returns every table a load of this mapping writes: the main table first, then its child tables.
+func (m BLADEDataMapping) Tables() []string {
+	tables := []string{m.TableName}
+	for _, child := range m.ChildTables {
+		tables = append(tables, child.Table)
+	}
+	return tables
+}
+
+// Long-format (id/key/value) CSV layout; parsing lives in the standalone blademap package.
+type CSVPivot = blademap.CSVPivot
+
 //   Purpose: Returns the complete set of supported BLADE data type configurations.
 func GetBLADEMappings() []BLADEDataMapping {
 	return []BLADEDataMapping{
@@ -26,6 +73,36 @@ func GetBLADEMappings() []BLADEDataMapping {
 			TableName:   "blade_maintenance_data",
 			SourcePath:  "mock://maintenance",
 			Description: "Aircraft maintenance schedules and predictive maintenance data",
+			Validations: append(databricks.DefaultValidationRules(),
+				databricks.ValidationRule{
+					Name:      "aircraft_tail_present",
+					Condition: "get_json_object(raw_data, '$.aircraft_tail') IS NULL",
+					Severity:  databricks.SeverityWarn,
+				},
+			),
+			Rules:       databricks.DefaultRecordRules(),
+			Semantics:   databricks.TableSemantics{
+				Description: "Aircraft maintenance records from BLADE: scheduled and unscheduled work orders, parts, labor hours and technician assignments per aircraft tail number.",
+				Columns:     databricks.DefaultColumnSemantics(),
+				ExampleQuestions: []string{
+					"Which aircraft tails had unscheduled maintenance in the last 30 days?",
+					"How many maintenance records were loaded per day this week?",
+					"What are the most common maintenance item types?",
+				},
+			},
+			ChildTables: []databricks.ChildTable{
+				{Table: "blade_maintenance_parts", Path: "parts_required"},
+			},
+			Columns: []databricks.TypedColumn{
+				{Name: "aircraft_tail", Type: "STRING", Comment: "Aircraft tail number"},
+				{Name: "maintenance_type", Type: "STRING", Comment: "scheduled or unscheduled"},
+				{Name: "priority", Type: "STRING"},
+				{Name: "parts_required", Type: "ARRAY<STRING>", Comment: "Part identifiers needed for the work order"},
+				{Name: "parts_cost", Type: "DOUBLE", Comment: "Parts cost in USD"},
+				{Name: "labor_hours_estimated", Type: "DOUBLE"},
+				{Name: "labor_hours_actual", Type: "DOUBLE", Comment: "NULL until the work is completed"},
+				{Name: "estimated_completion", Type: "TIMESTAMP"},
+			},
 		},
 		// - Data Type: Flight operations and mission data
 		// - Table: blade_sortie_schedules in Databricks
@@ -35,6 +112,23 @@ func GetBLADEMappings() []BLADEDataMapping {
 			TableName:   "blade_sortie_schedules",
 			SourcePath:  "mock://sortie", 
 			Description: "Flight schedules and sortie planning data",
+			Validations: databricks.DefaultValidationRules(),
+			Rules:       databricks.DefaultRecordRules(),
+			// - Schedules drop out of blade_sortie_schedules_current 30 days after the sortie
+			TTL:         &databricks.TTLPolicy{Field: "timestamp", After: "30d"},
+			Semantics:   databricks.TableSemantics{
+				Description: "Sortie schedules from BLADE: training and combat missions, pilot assignments and aircraft configurations.",
+				Columns:     databricks.DefaultColumnSemantics(),
+				ExampleQuestions: []string{
+					"How many sorties are scheduled for next week?",
+					"Which mission types were flown most often last month?",
+				},
+			},
+			// - Crew sits in several arrays per sortie (each aircraft's pilot, copilot and crew,
+			//   plus the sortie's own crew), so a Go exploder builds the rows instead of a path
+			ChildTables: []databricks.ChildTable{
+				{Table: databricks.SortieCrewTable, Exploder: databricks.SortieCrewExploder},
+			},
 		},
 		// - Data Type: Personnel and equipment deployment operations
 		// - Table: blade_deployment_plans in Databricks
@@ -44,6 +138,16 @@ func GetBLADEMappings() []BLADEDataMapping {
 			TableName:   "blade_deployment_plans",
 			SourcePath:  "mock://deployment", 
 			Description: "Deployment preparation and logistics planning",
+			Validations: databricks.DefaultValidationRules(),
+			Rules:       databricks.DefaultRecordRules(),
+			Semantics:   databricks.TableSemantics{
+				Description: "Deployment plans from BLADE: squadron rotations, equipment movements, timelines and personnel manifests.",
+				Columns:     databricks.DefaultColumnSemantics(),
+				ExampleQuestions: []string{
+					"Which deployments start in the next 90 days?",
+					"How many deployment plans exist per classification marking?",
+				},
+			},
 		},
 		// - Data Type: Supply chain and logistics operations
 		// - Table: blade_logistics_general in Databricks
@@ -53,6 +157,23 @@ func GetBLADEMappings() []BLADEDataMapping {
 			TableName:   "blade_logistics_general",
 			SourcePath:  "mock://logistics",
 			Description: "General logistics and supply chain data",
+			Validations: databricks.DefaultValidationRules(),
+			Rules:       databricks.DefaultRecordRules(),
+			Semantics:   databricks.TableSemantics{
+				Description: "General logistics records from BLADE: supply requests, fuel, munitions, equipment transfers and HAZMAT shipments.",
+				Columns:     databricks.DefaultColumnSemantics(),
+				ExampleQuestions: []string{
+					"How many HAZMAT shipments were recorded this month?",
+					"What are the open supply requests by item type?",
+				},
+			},
+			ChildTables: []databricks.ChildTable{
+				{
+					Table:  "blade_logistics_items",
+					Path:   "items",
+					Fields: []string{"nsn", "part_number", "description", "quantity_requested", "unit_of_measure", "unit_cost"},
+				},
+			},
 		},
 	}
 }
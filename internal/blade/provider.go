@@ -0,0 +1,96 @@
+package blade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/datasource"
+)
+
+//   Purpose: Registers BLADE as the "blade" data source provider.
+
+func init() {
+	datasource.Register("blade", func(cfg *config.Config) (datasource.Provider, error) {
+		// Mapping Source:
+		// - BLADE_MAPPINGS_FILE replaces the built-in mappings with a JSON or YAML file whose
+		//   ${ENV_VAR} references are expanded, so one file serves every deployment
+		// - Edits to the file are picked up by the running process (hot reload)
+		// - The built-in set is used when it isn't set
+		adapter := NewBLADEAdapter(cfg.BLADEDataSource, cfg.BLADEDataPath)
+		if cfg.MappingsFile != "" {
+			var err error
+			if adapter, err = NewBLADEAdapterFromFile(cfg.BLADEDataSource, cfg.BLADEDataPath, cfg.MappingsFile); err != nil {
+				return nil, err
+			}
+		}
+
+		// Record Source:
+		// - BLADE_MODE=live pulls the records from the BLADE REST API (BLADE_API_URL, BLADE_API_TOKEN,
+		//   BLADE_API_PAGE_SIZE), each page bounded by BLADE_HTTP_TIMEOUT; mock (default) reads the files
+		if cfg.LiveMode() {
+			if err := adapter.UseLiveAPI(cfg.BLADEAPIURL, cfg.BLADEAPIToken, cfg.BLADEAPIPageSize, cfg.HTTPTimeout); err != nil {
+				return nil, err
+			}
+		}
+		return adapter, nil
+	})
+}
+
+func (b *BLADEAdapter) Name() string {
+	return "blade"
+}
+
+func (b *BLADEAdapter) ListTypes() []string {
+	types := b.GetSupportedDataTypes()
+	sort.Strings(types)
+	return types
+}
+
+func (b *BLADEAdapter) FetchRecords(ctx context.Context, dataType string, format string) (*databricks.IngestionRequest, error) {
+	return b.PrepareIngestionRequest(ctx, dataType, format)
+}
+
+func (b *BLADEAdapter) FetchFiles(dataType string, format string, sourcePath string) (*databricks.IngestionRequest, error) {
+	return b.PrepareFileIngestionRequest(dataType, format, sourcePath)
+}
+
+func (b *BLADEAdapter) FetchRecordFile(dataType string, filePath string) (*databricks.IngestionRequest, error) {
+	return b.PrepareRecordFileIngestionRequest(dataType, filePath)
+}
+
+func (b *BLADEAdapter) FetchSnapshot(dataType string, snapshotDir string) (*databricks.IngestionRequest, error) {
+	return b.PrepareSnapshotIngestionRequest(dataType, snapshotDir)
+}
+
+func (b *BLADEAdapter) DescribeCSV(dataType string, filePath string) ([]blademap.ColumnType, error) {
+	return b.DescribeCSVColumns(dataType, filePath)
+}
+
+func (b *BLADEAdapter) GenerateMock(dataType string, opts datasource.MockOptions) ([]string, error) {
+	return b.GenerateMockData(dataType, opts)
+}
+
+func (b *BLADEAdapter) MockRequest(dataType string, opts datasource.MockOptions) (*databricks.IngestionRequest, error) {
+	return b.PrepareMockRequest(dataType, opts)
+}
+
+func (b *BLADEAdapter) DescribeSchema(dataType string) (datasource.Schema, error) {
+	mapping, exists := b.currentMappings()[dataType]
+	if !exists {
+		return datasource.Schema{}, fmt.Errorf("unsupported BLADE data type: %s", dataType)
+	}
+	return datasource.Schema{
+		DataType:    mapping.DataType,
+		TableName:   mapping.TableName,
+		Catalog:     mapping.Catalog,
+		Schema:      mapping.Schema,
+		Description: mapping.Description,
+		Tables:      mapping.Tables(),
+		Semantics:   mapping.Semantics,
+		Layout:      mapping.Layout,
+	}, nil
+}
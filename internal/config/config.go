@@ -1,35 +1,380 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	DatabricksHost string
 	DatabricksToken string
+	AuthType string // selects the auth provider (default: pat)
+	ClientID string // oauth-m2m: service principal application ID
+	ClientSecret string // oauth-m2m: service principal OAuth secret
+	AzureTenantID string // azure-client-secret: Entra ID tenant of the service principal
+	AzureClientID string // azure-client-secret: application ID; azure-msi: user-assigned identity (optional)
+	AzureClientSecret string // azure-client-secret: client secret of the service principal
+	AzureResourceID string // Azure resource ID of the workspace (required for azure-msi)
+	DatabricksProfile string // Databricks CLI profile to authenticate with (config-profile auth)
+	DatabricksConfigFile string // Databricks CLI config file ("" = ~/.databrickscfg)
 	WarehouseID string
+	WarehouseName string // resolved to the warehouse's ID when WarehouseID is empty
 	CatalogName string
 	SchemaName string
+	ExternalLocation string // storage root for EXTERNAL tables, e.g. abfss://blade@acct.dfs.core.windows.net/poc
+	VolumePath string // UC Volume directory for COPY INTO uploads, e.g. /Volumes/blade_poc/logistics/landing
+	Tenant string // optional exercise/org unit ID that scopes the namespace
+	Profile string // named profile of BLADE_PROFILES_FILE applied over the environment ("" = none)
+	TenantIsolation string // "schema" (default) or "catalog"
 
-	BLADEDataPath string
+	BLADEDataPath string // root of the {dataType}/{dataType}_data.{json,csv} files
 	BLADEDataSource string
+	DataProvider string // selects the data source provider (default: blade)
+	BLADEMode string // "mock" (default): records from the files under BLADEDataPath; "live": from the BLADE REST API
+	BLADEAPIURL string // live mode: base URL of the BLADE REST API ({url}/{dataType} lists a data type's records)
+	BLADEAPIToken string // live mode: bearer token of the BLADE REST API (may be a secret reference)
+	BLADEAPIPageSize int // live mode: records asked for per page
+	DisabledDataTypes string // "dataType[=reason], ..." switched off without editing the mappings
+	MappingsFile string // JSON or YAML mappings replacing the built-in set (supports ${ENV_VAR} interpolation, reloaded on change)
+	LogDir string // per-run log files are written here as {runID}.log
+	StateDir string // run history records ({runID}.json); caches and the watch ledger in its cache/ subdirectory
+	QueryCacheTTL time.Duration // reuse row counts/column descriptions across runs (0 = within a run only)
+	Reporters string // comma-separated result reporters (default: console)
+	ReportDir string // JSON/HTML reports are written here
+	ReportWebhookURL string
+	LineageURL string // OpenLineage endpoint for the lineage reporter
+	TimelineGantt bool // timeline reporter also prints an ASCII Gantt chart
+	VerifySampleSize int // ingested records read back and compared with the source (0 disables)
+	LoadMode string // "direct" (default) or "staged" for all-or-nothing runs
+	RowCountMismatch string // "warn" (default) or "fail" when a load's table didn't grow by the rows it inserted
+	BatchIDStrategy string // "ulid" (default), "content" or "unix"
+	RawDataCodec string // how raw_data is stored: "none" (default, plain JSON) or "zstd"
+	ClassificationRoutes string // MARKING=schema:NAME / MARKING=table:SUFFIX routing policy
+	ClassificationAllowed string // comma-separated markings records may carry (empty = any)
+	ClassificationCeiling string // highest classification level records may carry (empty = none)
+	InsertChunkSize int // records per INSERT statement (0 = a single INSERT per load)
+	MaxRuntime time.Duration // end-to-end ingestion budget (0 = none)
+	ArchiveSuperseded bool // move older batches of a re-delivered source into {table}_archive
+	VacuumRetention time.Duration // table versions vacuum keeps the files of (default and minimum 168h)
+	BatchManifest bool // record loads in blade_ingestion_batches; skip or resume re-delivered sources
+	ReadOnly bool // audit mode: only SELECT/DESCRIBE operations, write commands disabled
+	RecordFile string // cassette the run's Databricks API calls are recorded to
+	ReplayFile string // cassette served instead of calling the workspace
+	Backend string // "databricks" (default) or "local": statements run against an embedded SQLite database
+	LocalDatabase string // SQLite file of the local backend ("" = {StateDir}/local.db, ":memory:" = not kept)
+	IntegrityManifest string // signed release manifest the binary and mappings file are checked against
+	IntegrityRequired bool // refuse to run any command until the integrity check passes
+
+	// cost-attribution tags on created catalogs/schemas/tables (and optionally the warehouse)
+	CostProject string
+	CostOwner string
+	CostEnvironment string
+	TagWarehouse bool
+
+	// data freshness alerts (bootstrap alerts)
+	FreshnessSLA time.Duration
+	AlertDestinationID string
+	AlertEmail string
+	AlertSchedule string
+
+	// server mode (serve) listen address, job workers/queue and quotas per tenant/data type (0 = unlimited)
+	ServeAddr string
+	ServeWorkers int // ingestions run concurrently by serve (default 2)
+	ServeQueueSize int // accepted ingestions waiting for a worker (default 100)
+	QuotaRunsPerHour int
+	QuotaRowsPerDay int
+
+	// ingest --all: data types ingested at the same time (1 = one after another)
+	IngestWorkers int
+
+	// watch mode: how often the data path is scanned and how long a file must be unchanged before it's ingested
+	WatchInterval time.Duration
+	WatchDebounce time.Duration
+
+	// debug logging of submitted statements (redacted, truncated, throttled)
+	SQLDebug bool
+	SQLLogMaxChars int
+	SQLLogPerSecond int
+
+	// HTTP connection pool to the workspace
+	HTTPTimeout time.Duration
+	ThrottleRetries int // rate-limited (429) API calls retried after the workspace's Retry-After (default 5)
+	HTTPMaxIdleConns int
+
+	// statements still running after their server-side wait are polled to completion
+	StatementPollInterval time.Duration
+	StatementTimeout time.Duration
+	StatementProgress bool // log live query history metrics while polling
+
+	// deadlines of the connection test, of DDL and DML statements (0 = none besides StatementTimeout)
+	// and of a whole command (0 = none); SIGINT/SIGTERM cancel the command as well
+	ConnectTimeout time.Duration
+	DDLTimeout time.Duration
+	DMLTimeout time.Duration
+	RunDeadline time.Duration
+
+	// statements submitted while the warehouse is auto-stopping
+	WarehouseRetryAttempts int
+	WarehouseRetryDelay time.Duration
+
+	// warehouse readiness check before the connection test: start a stopped warehouse and how long to wait for RUNNING
+	WarehouseAutoStart bool
+	WarehouseStartTimeout time.Duration
 }
 
 func LoadConfig() (*Config, error) {
 	_ = godotenv.Load(".env")
 
-	return &Config{
+	retryAttempts, err := getEnvIntOrDefault("BLADE_WAREHOUSE_RETRY_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+	retryDelay, err := getEnvDurationOrDefault("BLADE_WAREHOUSE_RETRY_DELAY", 20*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	startTimeout, err := getEnvDurationOrDefault("BLADE_WAREHOUSE_START_TIMEOUT", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	verifySample, err := getEnvIntOrDefault("BLADE_VERIFY_SAMPLE_SIZE", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRuntime, err := getEnvDurationOrDefault("BLADE_MAX_RUNTIME", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCacheTTL, err := getEnvDurationOrDefault("BLADE_QUERY_CACHE_TTL", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlLogMaxChars, err := getEnvIntOrDefault("BLADE_SQL_LOG_MAX_CHARS", 2000)
+	if err != nil {
+		return nil, err
+	}
+	sqlLogPerSecond, err := getEnvIntOrDefault("BLADE_SQL_LOG_PER_SECOND", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	httpTimeout, err := getEnvDurationOrDefault("BLADE_HTTP_TIMEOUT", 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	throttleRetries, err := getEnvIntOrDefault("BLADE_THROTTLE_RETRIES", 5)
+	if err != nil {
+		return nil, err
+	}
+	httpMaxIdle, err := getEnvIntOrDefault("BLADE_HTTP_MAX_IDLE_CONNS", 16)
+	if err != nil {
+		return nil, err
+	}
+
+	apiPageSize, err := getEnvIntOrDefault("BLADE_API_PAGE_SIZE", 500)
+	if err != nil {
+		return nil, err
+	}
+	if apiPageSize < 1 {
+		return nil, fmt.Errorf("invalid BLADE_API_PAGE_SIZE %d: must be positive", apiPageSize)
+	}
+
+	insertChunkSize, err := getEnvIntOrDefault("BLADE_INSERT_CHUNK_SIZE", 500)
+	if err != nil {
+		return nil, err
+	}
+	if insertChunkSize < 0 {
+		return nil, fmt.Errorf("invalid BLADE_INSERT_CHUNK_SIZE %d: must be 0 (single INSERT) or positive", insertChunkSize)
+	}
+
+	pollInterval, err := getEnvDurationOrDefault("BLADE_STATEMENT_POLL_INTERVAL", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	statementTimeout, err := getEnvDurationOrDefault("BLADE_STATEMENT_TIMEOUT", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	connectTimeout, err := getEnvDurationOrDefault("BLADE_CONNECT_TIMEOUT", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	ddlTimeout, err := getEnvDurationOrDefault("BLADE_DDL_TIMEOUT", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	dmlTimeout, err := getEnvDurationOrDefault("BLADE_DML_TIMEOUT", 0)
+	if err != nil {
+		return nil, err
+	}
+	runDeadline, err := getEnvDurationOrDefault("BLADE_RUN_DEADLINE", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaRuns, err := getEnvIntOrDefault("BLADE_QUOTA_RUNS_PER_HOUR", 0)
+	if err != nil {
+		return nil, err
+	}
+	quotaRows, err := getEnvIntOrDefault("BLADE_QUOTA_ROWS_PER_DAY", 0)
+	if err != nil {
+		return nil, err
+	}
+	serveWorkers, err := getEnvIntOrDefault("BLADE_SERVE_WORKERS", 2)
+	if err != nil {
+		return nil, err
+	}
+	serveQueueSize, err := getEnvIntOrDefault("BLADE_SERVE_QUEUE_SIZE", 100)
+	if err != nil {
+		return nil, err
+	}
+	ingestWorkers, err := getEnvIntOrDefault("BLADE_INGEST_WORKERS", 4)
+	if err != nil {
+		return nil, err
+	}
+	watchInterval, err := getEnvDurationOrDefault("BLADE_WATCH_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	watchDebounce, err := getEnvDurationOrDefault("BLADE_WATCH_DEBOUNCE", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	freshnessSLA, err := getEnvDurationOrDefault("BLADE_FRESHNESS_SLA", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	vacuumRetention, err := getEnvDurationOrDefault("BLADE_VACUUM_RETENTION", 168*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
 		DatabricksHost: os.Getenv("DATABRICKS_HOST"),
 		DatabricksToken: os.Getenv("DATABRICKS_TOKEN"),
+		AuthType: os.Getenv("DATABRICKS_AUTH_TYPE"),
+		ClientID: os.Getenv("DATABRICKS_CLIENT_ID"),
+		ClientSecret: os.Getenv("DATABRICKS_CLIENT_SECRET"),
+		AzureTenantID: os.Getenv("ARM_TENANT_ID"),
+		AzureClientID: os.Getenv("ARM_CLIENT_ID"),
+		AzureClientSecret: os.Getenv("ARM_CLIENT_SECRET"),
+		AzureResourceID: os.Getenv("DATABRICKS_AZURE_RESOURCE_ID"),
+		DatabricksProfile: os.Getenv("DATABRICKS_CONFIG_PROFILE"),
+		DatabricksConfigFile: os.Getenv("DATABRICKS_CONFIG_FILE"),
 		WarehouseID: os.Getenv("DATABRICKS_WAREHOUSE_ID"),
+		WarehouseName: os.Getenv("DATABRICKS_WAREHOUSE_NAME"),
 		CatalogName: getEnvOrDefault("DATABRICKS_CATALOG", "blade_poc"),
 		SchemaName: getEnvOrDefault("DATABRICKS_SCHEMA", "logistics"),
+		ExternalLocation: os.Getenv("DATABRICKS_EXTERNAL_LOCATION"),
+		VolumePath: os.Getenv("BLADE_VOLUME_PATH"),
+		Tenant: os.Getenv("BLADE_TENANT"),
+		TenantIsolation: getEnvOrDefault("BLADE_TENANT_ISOLATION", "schema"),
 
+		BLADEDataPath: getEnvOrDefault("BLADE_DATA_PATH", "mock_blade_data/"),
 		// hardcoded for PoC
-		BLADEDataPath: "mock_blade_data/",
 		BLADEDataSource: "BLADE_LOGISTICS",
-	}, nil
+		DataProvider: os.Getenv("BLADE_DATA_PROVIDER"),
+		BLADEMode: getEnvOrDefault("BLADE_MODE", BLADEModeMock),
+		BLADEAPIURL: os.Getenv("BLADE_API_URL"),
+		BLADEAPIToken: os.Getenv("BLADE_API_TOKEN"),
+		BLADEAPIPageSize: apiPageSize,
+		DisabledDataTypes: os.Getenv("BLADE_DISABLED_DATA_TYPES"),
+		MappingsFile: os.Getenv("BLADE_MAPPINGS_FILE"),
+		LogDir: getEnvOrDefault("BLADE_LOG_DIR", "logs"),
+		StateDir: getEnvOrDefault("BLADE_STATE_DIR", "state"),
+		QueryCacheTTL: queryCacheTTL,
+		Reporters: getEnvOrDefault("BLADE_REPORTERS", "console"),
+		ReportDir: getEnvOrDefault("BLADE_REPORT_DIR", "reports"),
+		ReportWebhookURL: os.Getenv("BLADE_REPORT_WEBHOOK_URL"),
+		LineageURL: os.Getenv("BLADE_LINEAGE_URL"),
+		TimelineGantt: os.Getenv("BLADE_TIMELINE_GANTT") == "true",
+		VerifySampleSize: verifySample,
+		LoadMode: getEnvOrDefault("BLADE_LOAD_MODE", "direct"),
+		RowCountMismatch: getEnvOrDefault("BLADE_ROW_COUNT_MISMATCH", "warn"),
+		BatchIDStrategy: getEnvOrDefault("BLADE_BATCH_ID", "ulid"),
+		RawDataCodec: getEnvOrDefault("BLADE_RAW_DATA_CODEC", "none"),
+		ClassificationRoutes: os.Getenv("BLADE_CLASSIFICATION_ROUTES"),
+		ClassificationAllowed: os.Getenv("BLADE_CLASSIFICATION_ALLOWED"),
+		ClassificationCeiling: os.Getenv("BLADE_CLASSIFICATION_CEILING"),
+		InsertChunkSize: insertChunkSize,
+		MaxRuntime: maxRuntime,
+		ArchiveSuperseded: os.Getenv("BLADE_ARCHIVE_SUPERSEDED") == "true",
+		VacuumRetention: vacuumRetention,
+		BatchManifest: os.Getenv("BLADE_BATCH_MANIFEST") == "true",
+		ReadOnly: os.Getenv("BLADE_READ_ONLY") == "true",
+		RecordFile: os.Getenv("BLADE_RECORD"),
+		ReplayFile: os.Getenv("BLADE_REPLAY"),
+		Backend: getEnvOrDefault("BLADE_BACKEND", BackendDatabricks),
+		LocalDatabase: os.Getenv("BLADE_LOCAL_DB"),
+		IntegrityManifest: getEnvOrDefault("BLADE_INTEGRITY_MANIFEST", "release-manifest.json"),
+		IntegrityRequired: os.Getenv("BLADE_INTEGRITY_REQUIRED") == "true",
+
+		CostProject: getEnvOrDefault("BLADE_COST_PROJECT", "blade-poc"),
+		CostOwner: os.Getenv("BLADE_COST_OWNER"),
+		CostEnvironment: os.Getenv("BLADE_COST_ENVIRONMENT"),
+		TagWarehouse: os.Getenv("BLADE_TAG_WAREHOUSE") == "true",
+
+		FreshnessSLA: freshnessSLA,
+		AlertDestinationID: os.Getenv("BLADE_ALERT_DESTINATION_ID"),
+		AlertEmail: os.Getenv("BLADE_ALERT_EMAIL"),
+		AlertSchedule: getEnvOrDefault("BLADE_ALERT_SCHEDULE", "0 0 * * * ?"),
+
+		ServeAddr: getEnvOrDefault("BLADE_SERVE_ADDR", ":8080"),
+		ServeWorkers: serveWorkers,
+		ServeQueueSize: serveQueueSize,
+		IngestWorkers: ingestWorkers,
+		WatchInterval: watchInterval,
+		WatchDebounce: watchDebounce,
+		QuotaRunsPerHour: quotaRuns,
+		QuotaRowsPerDay: quotaRows,
+
+		SQLDebug: os.Getenv("BLADE_SQL_DEBUG") == "true",
+		SQLLogMaxChars: sqlLogMaxChars,
+		SQLLogPerSecond: sqlLogPerSecond,
+
+		HTTPTimeout: httpTimeout,
+		ThrottleRetries: throttleRetries,
+		HTTPMaxIdleConns: httpMaxIdle,
+
+		StatementPollInterval: pollInterval,
+		StatementTimeout: statementTimeout,
+		StatementProgress: getEnvOrDefault("BLADE_STATEMENT_PROGRESS", "true") == "true",
+
+		ConnectTimeout: connectTimeout,
+		DDLTimeout: ddlTimeout,
+		DMLTimeout: dmlTimeout,
+		RunDeadline: runDeadline,
+
+		WarehouseRetryAttempts: retryAttempts,
+		WarehouseRetryDelay: retryDelay,
+		WarehouseAutoStart: os.Getenv("BLADE_WAREHOUSE_AUTO_START") == "true",
+		WarehouseStartTimeout: startTimeout,
+	}
+
+	// Profiles:
+	// - BLADE_PROFILE (ingest --profile) selects a profile of BLADE_PROFILES_FILE, whose
+	//   workspace, warehouse, catalog and schema replace the environment's (see profiles.go)
+	if err := applyProfile(cfg); err != nil {
+		return nil, err
+	}
+
+	// Databricks CLI Profile:
+	// - DATABRICKS_CONFIG_PROFILE authenticates with a profile of ~/.databrickscfg, whose
+	//   host and warehouse fill in the ones not set (see databrickscfg.go)
+	if err := applyDatabricksProfile(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -37,4 +382,28 @@ func getEnvOrDefault(key, defaultValue string) string {
 		return value;
 	}
 	return defaultValue;
-}
\ No newline at end of file
+}
+
+func getEnvIntOrDefault(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return parsed, nil
+}
+
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q (use Go durations like 30s or 2m): %w", key, value, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	sdkconfig "github.com/databricks/databricks-sdk-go/config"
+)
+
+//   Purpose: Users with the Databricks CLI set up already have their workspaces and
+//   credentials in ~/.databrickscfg. Naming one of its profiles (DATABRICKS_CONFIG_PROFILE)
+//   lets the client authenticate with it, instead of copying the token into .env.
+
+//   Using a CLI Profile:
+//   - DATABRICKS_CONFIG_PROFILE names the profile, DATABRICKS_CONFIG_FILE the file
+//     (default ~/.databrickscfg)
+//   - The profile's host and warehouse_id fill DATABRICKS_HOST and DATABRICKS_WAREHOUSE_ID
+//     when those are unset; a DATABRICKS_HOST pointing elsewhere is refused, so the
+//     profile's credentials never go to another workspace
+//   - Without DATABRICKS_AUTH_TYPE the config-profile auth type is selected, and the SDK
+//     authenticates as the profile says (token, OAuth, Azure, databricks-cli login, ...)
+
+// Auth type that authenticates with a Databricks CLI profile (see internal/auth).
+const ConfigProfileAuthType = "config-profile"
+
+// Reads the host and warehouse of the Databricks CLI profile the config names.
+func applyDatabricksProfile(cfg *Config) error {
+	if cfg.DatabricksProfile == "" {
+		return nil
+	}
+	file, err := sdkconfig.LoadFile(cfg.DatabricksConfigFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("DATABRICKS_CONFIG_PROFILE %s: no Databricks CLI config file %s (run `databricks configure --profile %s`, or set DATABRICKS_CONFIG_FILE)", cfg.DatabricksProfile, displayConfigFile(cfg.DatabricksConfigFile), cfg.DatabricksProfile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read Databricks CLI config file %s: %w", displayConfigFile(cfg.DatabricksConfigFile), err)
+	}
+	section, err := file.GetSection(cfg.DatabricksProfile)
+	if err != nil {
+		return fmt.Errorf("DATABRICKS_CONFIG_PROFILE %s isn't a profile of %s (defined: %s)", cfg.DatabricksProfile, file.Path(), strings.Join(cliProfiles(file), ", "))
+	}
+
+	host := strings.TrimRight(section.Key("host").String(), "/")
+	switch {
+	case cfg.DatabricksHost == "":
+		cfg.DatabricksHost = host
+	case host != "" && strings.TrimRight(cfg.DatabricksHost, "/") != host:
+		return fmt.Errorf("DATABRICKS_HOST %s isn't the host of profile %s in %s (%s): unset DATABRICKS_HOST or pick the profile of that workspace", cfg.DatabricksHost, cfg.DatabricksProfile, file.Path(), host)
+	}
+	if cfg.WarehouseID == "" && cfg.WarehouseName == "" {
+		cfg.WarehouseID = section.Key("warehouse_id").String()
+	}
+	if cfg.AuthType == "" {
+		cfg.AuthType = ConfigProfileAuthType
+	}
+	return nil
+}
+
+func displayConfigFile(path string) string {
+	if path == "" {
+		return "~/.databrickscfg"
+	}
+	return path
+}
+
+// Returns the profiles of a CLI config file, leaving out an empty DEFAULT section.
+func cliProfiles(file *sdkconfig.File) []string {
+	var names []string
+	for _, section := range file.Sections() {
+		if section.Name() != "DEFAULT" || len(section.Keys()) > 0 {
+			names = append(names, section.Name())
+		}
+	}
+	return names
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// A single KEY=value line of a .env file, optionally preceded by a comment.
+type EnvSetting struct {
+	Key     string
+	Value   string
+	Comment string
+}
+
+// Reads an existing .env file; a missing file yields no settings.
+func ReadEnvFile(path string) (map[string]string, error) {
+	values, err := godotenv.Read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Writes settings to path in order, in the format LoadConfig reads.
+//   - Values are double-quoted and escaped, so "$", quotes and newlines survive a reload
+//   - The file holds credentials and is created with mode 0600
+//   - An existing file is kept as {path}.bak
+func WriteEnvFile(path string, settings []EnvSetting) error {
+	var b strings.Builder
+	for _, setting := range settings {
+		if setting.Comment != "" {
+			fmt.Fprintf(&b, "\n# %s\n", setting.Comment)
+		}
+		line, err := godotenv.Marshal(map[string]string{setting.Key: setting.Value})
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", setting.Key, err)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Rename(path, path+".bak"); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.TrimLeft(b.String(), "\n")), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Expands ${VAR} and ${VAR:-default} references in s from the environment.
+//
+// Only the braced form is recognized, so JSON paths such as '$.aircraft_tail' in SQL
+// fragments pass through untouched; "$${" produces a literal "${". A reference to an
+// unset variable without a default is an error naming every missing variable.
+func Interpolate(s string) (string, error) {
+	var b strings.Builder
+	missing := make(map[string]bool)
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", s)
+		}
+		b.WriteString(s[:i])
+		reference := s[i+2 : i+end]
+		name, fallback, hasDefault := strings.Cut(reference, ":-")
+		if !isEnvName(name) {
+			return "", fmt.Errorf("invalid variable reference ${%s}", reference)
+		}
+		if value, set := os.LookupEnv(name); set && value != "" {
+			b.WriteString(value)
+		} else if hasDefault {
+			b.WriteString(fallback)
+		} else {
+			missing[name] = true
+		}
+		s = s[i+end+1:]
+	}
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("environment variable(s) not set: %s", strings.Join(names, ", "))
+	}
+	return b.String(), nil
+}
+
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//   Purpose: One .env describes one workspace, so pushing the same data to dev, staging
+//   and prod meant swapping files by hand, and a stale DATABRICKS_TOKEN could follow the
+//   binary to the wrong host. Named profiles in one file each carry their own workspace,
+//   warehouse, catalog and schema, and a run picks one with --profile (or BLADE_PROFILE).
+
+//   File Format (BLADE_PROFILES_FILE, default blade-profiles.yaml; YAML or JSON):
+//   - {"default": "dev", "profiles": {"dev": {...Profile...}, "prod": {...}}}
+//   - Every string value may reference ${ENV_VAR} or ${ENV_VAR:-default}, so secrets stay
+//     in the environment ("token: ${DATABRICKS_TOKEN_PROD}")
+//   - Unknown fields are rejected so a typo doesn't silently drop a setting
+
+//   Applying a Profile:
+//   - The settings the profile sets replace the environment's; the rest are kept
+//   - A profile that sets its own host must set its own credentials too (token, clientId
+//     and clientSecret, an Azure client secret or managed identity, or a Databricks CLI
+//     profile), so one workspace's token is never sent to another
+//   - readOnly: true forces BLADE_READ_ONLY, e.g. for a prod profile used to audit
+//   - BLADE_COST_ENVIRONMENT defaults to the profile's name
+
+// Default location of the profiles file (BLADE_PROFILES_FILE).
+const DefaultProfilesFile = "blade-profiles.yaml"
+
+// A named Databricks environment.
+//   - Empty fields keep the value from the environment (.env, DATABRICKS_*/BLADE_*)
+type Profile struct {
+	Host              string `yaml:"host"`
+	Token             string `yaml:"token"`
+	AuthType          string `yaml:"authType"`
+	ClientID          string `yaml:"clientId"`
+	ClientSecret      string `yaml:"clientSecret"`
+	AzureTenantID     string `yaml:"azureTenantId"`
+	AzureClientID     string `yaml:"azureClientId"`
+	AzureClientSecret string `yaml:"azureClientSecret"`
+	AzureResourceID   string `yaml:"azureResourceId"`
+	DatabricksProfile string `yaml:"databricksProfile"`
+	WarehouseID       string `yaml:"warehouseId"`
+	WarehouseName     string `yaml:"warehouseName"`
+	Catalog           string `yaml:"catalog"`
+	Schema            string `yaml:"schema"`
+	ExternalLocation  string `yaml:"externalLocation"`
+	VolumePath        string `yaml:"volumePath"`
+	Tenant            string `yaml:"tenant"`
+	ReadOnly          bool   `yaml:"readOnly"`
+}
+
+type profilesFile struct {
+	Default  string             `yaml:"default"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Reads and interpolates a profiles file.
+//   - Returns the profiles and the name of the default one ("" when the file has none)
+func LoadProfiles(path string) (map[string]Profile, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read profiles file %s: %w", path, err)
+	}
+	var file profilesFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, "", fmt.Errorf("invalid profiles file %s: %w", path, err)
+	}
+	if len(file.Profiles) == 0 {
+		return nil, "", fmt.Errorf("profiles file %s defines no profiles", path)
+	}
+	if _, ok := file.Profiles[file.Default]; file.Default != "" && !ok {
+		return nil, "", fmt.Errorf("profiles file %s: default profile %q isn't defined", path, file.Default)
+	}
+
+	for name, profile := range file.Profiles {
+		for _, field := range []*string{&profile.Host, &profile.Token, &profile.AuthType, &profile.ClientID, &profile.ClientSecret,
+			&profile.AzureTenantID, &profile.AzureClientID, &profile.AzureClientSecret, &profile.AzureResourceID, &profile.DatabricksProfile, &profile.WarehouseID, &profile.WarehouseName, &profile.Catalog, &profile.Schema, &profile.ExternalLocation, &profile.VolumePath, &profile.Tenant} {
+			if *field, err = Interpolate(*field); err != nil {
+				return nil, "", fmt.Errorf("profiles file %s, profile %s: %w", path, name, err)
+			}
+		}
+		file.Profiles[name] = profile
+	}
+	return file.Profiles, file.Default, nil
+}
+
+// Applies the profile named by BLADE_PROFILE (or the file's default) to the config.
+//   - Without a profile file, and no profile asked for, the config is left as loaded
+func applyProfile(cfg *Config) error {
+	path, name := getEnvOrDefault("BLADE_PROFILES_FILE", DefaultProfilesFile), os.Getenv("BLADE_PROFILE")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && name == "" {
+		return nil
+	}
+	profiles, defaultName, err := LoadProfiles(path)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = defaultName
+	}
+	if name == "" {
+		return nil
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for known := range profiles {
+			names = append(names, known)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("profile %q isn't defined in %s (defined: %s)", name, path, strings.Join(names, ", "))
+	}
+	return profile.apply(name, cfg)
+}
+
+// Replaces the config's settings with the ones the profile sets.
+func (p Profile) apply(name string, cfg *Config) error {
+	if p.Host != "" && p.Host != cfg.DatabricksHost && !p.hasCredentials() {
+		return fmt.Errorf("profile %s points at %s but sets no credentials (token, clientId and clientSecret, azureClientSecret, databricksProfile, or authType azure-msi with azureResourceId); the environment's are for %s", name, p.Host, cfg.DatabricksHost)
+	}
+	if p.Host != "" && p.Host != cfg.DatabricksHost {
+		// - The environment's credentials belong to its host, and are dropped with it
+		cfg.DatabricksHost, cfg.DatabricksToken, cfg.ClientID, cfg.ClientSecret = p.Host, "", "", ""
+		cfg.AzureTenantID, cfg.AzureClientID, cfg.AzureClientSecret, cfg.AzureResourceID = "", "", "", ""
+		cfg.DatabricksProfile = ""
+	}
+	for _, setting := range []struct {
+		value string
+		field *string
+	}{
+		{p.Token, &cfg.DatabricksToken}, {p.AuthType, &cfg.AuthType}, {p.ClientID, &cfg.ClientID}, {p.ClientSecret, &cfg.ClientSecret},
+		{p.AzureTenantID, &cfg.AzureTenantID}, {p.AzureClientID, &cfg.AzureClientID}, {p.AzureClientSecret, &cfg.AzureClientSecret}, {p.AzureResourceID, &cfg.AzureResourceID},
+		{p.DatabricksProfile, &cfg.DatabricksProfile},
+		{p.WarehouseID, &cfg.WarehouseID}, {p.WarehouseName, &cfg.WarehouseName}, {p.Catalog, &cfg.CatalogName}, {p.Schema, &cfg.SchemaName},
+		{p.ExternalLocation, &cfg.ExternalLocation}, {p.VolumePath, &cfg.VolumePath}, {p.Tenant, &cfg.Tenant},
+	} {
+		if setting.value != "" {
+			*setting.field = setting.value
+		}
+	}
+	if p.DatabricksProfile != "" && p.AuthType == "" {
+		// - A CLI profile authenticates as the profile says (config-profile)
+		cfg.AuthType = ""
+	}
+	cfg.ReadOnly = cfg.ReadOnly || p.ReadOnly
+	if cfg.CostEnvironment == "" {
+		cfg.CostEnvironment = name
+	}
+	cfg.Profile = name
+	return nil
+}
+
+// Reports whether the profile sets credentials of its own.
+func (p Profile) hasCredentials() bool {
+	return p.Token != "" || p.ClientSecret != "" || p.AzureClientSecret != "" || p.DatabricksProfile != "" ||
+		(strings.EqualFold(p.AuthType, "azure-msi") && p.AzureResourceID != "")
+}
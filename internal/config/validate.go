@@ -0,0 +1,194 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//   Purpose: A typo in .env used to surface one failure at a time, often as an SDK or
+//   warehouse error far from its cause (a host without https://, a warehouse's HTTP path
+//   pasted as its ID). Validate checks the settings a connection needs before anything
+//   connects and lists every problem at once, each with what to set instead.
+
+//   Checks:
+//   - BLADE_BACKEND: databricks or local; the local backend needs no workspace, so the
+//     host, credentials and warehouse aren't checked for it
+//   - DATABRICKS_HOST: an https:// workspace URL without a path
+//   - Credentials of DATABRICKS_AUTH_TYPE: a personal access token without whitespace or
+//     quotes (dapi + 32 hex characters when it is a Databricks PAT), or an OAuth client
+//     ID and secret, Entra ID client credentials or the workspace's Azure resource ID (with
+//     an Azure Databricks host), or a Databricks CLI profile; skipped when BLADE_REPLAY
+//     serves a cassette
+//   - DATABRICKS_WAREHOUSE_ID: the 16 hex characters ending the warehouse's HTTP path, unless
+//     DATABRICKS_WAREHOUSE_NAME names the warehouse instead (resolved when connecting)
+//   - DATABRICKS_CATALOG / DATABRICKS_SCHEMA: plain identifiers, since they are written
+//     into statements unquoted
+//   - Files and directories the run reads: BLADE_DATA_PATH (blade provider),
+//     BLADE_MAPPINGS_FILE, BLADE_REPLAY; BLADE_VOLUME_PATH must be under /Volumes/
+
+// Backends selectable with BLADE_BACKEND (or --backend).
+const (
+	BackendDatabricks = "databricks" // a SQL warehouse of the workspace
+	BackendLocal      = "local"      // an embedded SQLite database (see internal/local)
+)
+
+// Where the BLADE provider gets its records from (BLADE_MODE).
+const (
+	BLADEModeMock = "mock" // the {dataType}_data files under BLADE_DATA_PATH
+	BLADEModeLive = "live" // the BLADE REST API at BLADE_API_URL (see internal/blade/apiclient)
+)
+
+var (
+	// Databricks personal access tokens; other bearer tokens (e.g. Entra ID) aren't checked.
+	patPattern = regexp.MustCompile(`^dapi[0-9a-f]{32}(-\d+)?$`)
+
+	warehouseIDPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+	// Identifiers the client writes into SQL unquoted.
+	namespacePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,254}$`)
+)
+
+// Checks the settings a connection to the workspace needs.
+//   - Returns nil, or one error per problem (errors.Join), each naming the variable and
+//     how to fix it
+func (c *Config) Validate() error {
+	var errs []error
+	problem := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch strings.ToLower(c.Backend) {
+	case "", BackendDatabricks, BackendLocal:
+	default:
+		problem("BLADE_BACKEND %q is not a backend: use %s or %s", c.Backend, BackendDatabricks, BackendLocal)
+	}
+
+	switch parsed, err := url.Parse(c.DatabricksHost); {
+	case c.LocalBackend():
+	case c.DatabricksHost == "":
+		problem("DATABRICKS_HOST is not set: use the workspace URL, e.g. https://dbc-a1b2c3d4-e5f6.cloud.databricks.com")
+	case err != nil || parsed.Host == "":
+		problem("DATABRICKS_HOST %q is not a URL: use the workspace URL, e.g. https://dbc-a1b2c3d4-e5f6.cloud.databricks.com", c.DatabricksHost)
+	case parsed.Scheme != "https":
+		problem("DATABRICKS_HOST %q must use https: use https://%s", c.DatabricksHost, parsed.Host)
+	case strings.Trim(parsed.Path, "/") != "" || parsed.RawQuery != "":
+		problem("DATABRICKS_HOST %q has a path: use the workspace URL alone, https://%s", c.DatabricksHost, parsed.Host)
+	}
+
+	if c.ReplayFile == "" && !c.LocalBackend() {
+		switch strings.ToLower(c.AuthType) {
+		case "", "pat":
+			switch token := c.DatabricksToken; {
+			case token == "":
+				problem("DATABRICKS_TOKEN is not set: create a personal access token (User Settings > Developer > Access tokens), or set DATABRICKS_AUTH_TYPE=oauth-m2m")
+			case strings.ContainsAny(token, " \t\r\n\"'"):
+				problem("DATABRICKS_TOKEN contains whitespace or quotes: paste the token alone")
+			case strings.HasPrefix(token, "dapi") && !patPattern.MatchString(token):
+				problem("DATABRICKS_TOKEN looks like a truncated personal access token (dapi followed by 32 hex characters): copy it again")
+			}
+		case "oauth-m2m":
+			if c.ClientID == "" {
+				problem("DATABRICKS_CLIENT_ID is not set: use the application ID of the service principal")
+			}
+			if c.ClientSecret == "" {
+				problem("DATABRICKS_CLIENT_SECRET is not set: create an OAuth secret for the service principal")
+			}
+		case "azure-client-secret":
+			for _, setting := range []struct{ name, value, fix string }{
+				{"ARM_TENANT_ID", c.AzureTenantID, "use the Directory (tenant) ID of the app registration"},
+				{"ARM_CLIENT_ID", c.AzureClientID, "use the Application (client) ID of the app registration"},
+				{"ARM_CLIENT_SECRET", c.AzureClientSecret, "create a client secret under Certificates & secrets of the app registration"},
+			} {
+				if setting.value == "" {
+					problem("%s is not set: %s", setting.name, setting.fix)
+				}
+			}
+		case ConfigProfileAuthType:
+			if c.DatabricksProfile == "" {
+				problem("DATABRICKS_CONFIG_PROFILE is not set: name the profile of ~/.databrickscfg to authenticate with (databricks auth profiles lists them)")
+			}
+		case "azure-msi":
+			if c.AzureResourceID == "" {
+				problem("DATABRICKS_AZURE_RESOURCE_ID is not set: use the workspace's resource ID, /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Databricks/workspaces/<name>")
+			}
+		}
+		if strings.HasPrefix(strings.ToLower(c.AuthType), "azure-") && c.DatabricksHost != "" && !isAzureHost(c.DatabricksHost) {
+			problem("DATABRICKS_HOST %q is not an Azure Databricks workspace, which %s authenticates to: use its https://adb-<id>.<n>.azuredatabricks.net URL, or another DATABRICKS_AUTH_TYPE", c.DatabricksHost, c.AuthType)
+		}
+	}
+
+	switch id := c.WarehouseID; {
+	case c.LocalBackend():
+	case id == "" && strings.TrimSpace(c.WarehouseName) != "":
+	case id == "":
+		problem("DATABRICKS_WAREHOUSE_ID is not set: use the ID of the SQL warehouse (Connection details, the end of its HTTP path), or its name as DATABRICKS_WAREHOUSE_NAME (list-warehouses shows both)")
+	case strings.Contains(id, "/"):
+		problem("DATABRICKS_WAREHOUSE_ID %q is an HTTP path: use its last part, %s", id, id[strings.LastIndex(id, "/")+1:])
+	case !warehouseIDPattern.MatchString(id):
+		problem("DATABRICKS_WAREHOUSE_ID %q is not a warehouse ID (16 hex characters, the end of the warehouse's HTTP path /sql/1.0/warehouses/<id>)", id)
+	}
+
+	for _, setting := range []struct{ name, value string }{{"DATABRICKS_CATALOG", c.CatalogName}, {"DATABRICKS_SCHEMA", c.SchemaName}} {
+		if !namespacePattern.MatchString(setting.value) {
+			problem("%s %q is not a plain identifier: use letters, digits and underscores, not starting with a digit (e.g. blade_poc)", setting.name, setting.value)
+		}
+	}
+
+	if c.VolumePath != "" && !strings.HasPrefix(c.VolumePath, "/Volumes/") {
+		problem("BLADE_VOLUME_PATH %q is not a Unity Catalog volume: use /Volumes/<catalog>/<schema>/<volume>[/dir]", c.VolumePath)
+	}
+	switch mode := strings.ToLower(c.BLADEMode); {
+	case mode == "", mode == BLADEModeMock:
+	case mode != BLADEModeLive:
+		problem("BLADE_MODE %q is not a mode: use %s (mock data files) or %s (the BLADE REST API)", c.BLADEMode, BLADEModeMock, BLADEModeLive)
+	case c.BLADEAPIURL == "":
+		problem("BLADE_API_URL is not set: BLADE_MODE=live needs the base URL of the BLADE REST API, e.g. https://blade.example.mil/api/v1")
+	default:
+		if parsed, err := url.Parse(c.BLADEAPIURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			problem("BLADE_API_URL %q is not a URL: use the base URL of the BLADE REST API, e.g. https://blade.example.mil/api/v1", c.BLADEAPIURL)
+		}
+	}
+	if provider := strings.ToLower(c.DataProvider); (provider == "" || provider == "blade") && !c.LiveMode() {
+		if info, err := os.Stat(c.BLADEDataPath); err != nil || !info.IsDir() {
+			problem("BLADE_DATA_PATH %q is not a directory: point it at the directory holding {dataType}/{dataType}_data.json", c.BLADEDataPath)
+		}
+	}
+	for _, setting := range []struct{ name, path string }{{"BLADE_MAPPINGS_FILE", c.MappingsFile}, {"BLADE_REPLAY", c.ReplayFile}} {
+		if setting.path == "" {
+			continue
+		}
+		if info, err := os.Stat(setting.path); err != nil || info.IsDir() {
+			problem("%s %q is not a file: fix the path or unset it", setting.name, setting.path)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reports whether the BLADE provider pulls its records from the BLADE REST API (BLADE_MODE=live).
+func (c *Config) LiveMode() bool {
+	return strings.EqualFold(c.BLADEMode, BLADEModeLive)
+}
+
+// Reports whether statements run against the embedded SQLite database (BLADE_BACKEND=local).
+func (c *Config) LocalBackend() bool {
+	return strings.EqualFold(c.Backend, BackendLocal)
+}
+
+// Reports whether a workspace URL is an Azure Databricks workspace (public, government or
+// China cloud).
+func isAzureHost(host string) bool {
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return false
+	}
+	for _, suffix := range []string{".azuredatabricks.net", ".databricks.azure.us", ".databricks.azure.cn"} {
+		if strings.HasSuffix(parsed.Hostname(), suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Describes a data freshness alert for one BLADE table.
+//   - SLA: Maximum time allowed since the newest batch landed
+//   - DestinationID / UserEmail: Who gets notified (at least one is required)
+//   - Schedule: Quartz cron expression for evaluation (default: hourly)
+type FreshnessAlertSpec struct {
+	TableName     string
+	SLA           time.Duration
+	DestinationID string
+	UserEmail     string
+	Schedule      string
+	TimezoneID    string
+	ParentPath    string
+}
+
+// Describes an alert provisioned by CreateFreshnessAlert.
+//   - Updated: An alert with the same display name existed and was updated in place
+type AlertInfo struct {
+	AlertID     string  `json:"alertId"`
+	DisplayName string  `json:"displayName"`
+	TableName   string  `json:"tableName"`
+	SLAMinutes  float64 `json:"slaMinutes"`
+	Updated     bool    `json:"updated"`
+}
+
+// Fields of an existing alert that CreateFreshnessAlert replaces (AlertsV2 update mask).
+const freshnessAlertUpdateMask = "display_name,custom_summary,query_text,warehouse_id,schedule,evaluation"
+
+// Provisions a Databricks SQL alert that triggers when no new batch has landed in the table within the SLA window.
+//
+// The alert is identified by its display name ("BLADE freshness: {table}"): when one
+// already exists it is updated in place, so re-running `bootstrap alerts` doesn't add
+// duplicates. The SLA must be at least a minute, the resolution of the freshness query.
+func (c *Client) CreateFreshnessAlert(ctx context.Context, spec FreshnessAlertSpec) (*AlertInfo, error) {
+	if c.readOnly {
+		return nil, fmt.Errorf("%w: alerts can't be created", ErrReadOnly)
+	}
+	if spec.DestinationID == "" && spec.UserEmail == "" {
+		return nil, fmt.Errorf("freshness alert for %s needs a notification destination ID or user email", spec.TableName)
+	}
+	if spec.SLA < time.Minute {
+		return nil, fmt.Errorf("freshness alert for %s needs an SLA of at least 1m, got %s", spec.TableName, spec.SLA)
+	}
+	if spec.Schedule == "" {
+		spec.Schedule = "0 0 * * * ?"
+	}
+	if spec.TimezoneID == "" {
+		spec.TimezoneID = "UTC"
+	}
+
+	// Freshness Query:
+	// - Minutes since the newest ingestion_timestamp in the table
+	// - An empty table reports a huge value so it triggers as well
+	freshnessSQL := fmt.Sprintf(`
+		SELECT COALESCE(
+			(unix_timestamp(current_timestamp()) - unix_timestamp(MAX(ingestion_timestamp))) / 60,
+			1000000000
+		) AS minutes_since_last_batch
+		FROM %s.%s.%s
+	`, c.catalog, c.schema, spec.TableName)
+
+	// - Fractional minutes, so an SLA like 90s isn't truncated
+	slaMinutes := spec.SLA.Minutes()
+	subscription := sql.AlertV2Subscription{DestinationId: spec.DestinationID}
+	if spec.DestinationID == "" {
+		subscription = sql.AlertV2Subscription{UserEmail: spec.UserEmail}
+	}
+	displayName := fmt.Sprintf("BLADE freshness: %s", spec.TableName)
+
+	definition := sql.AlertV2{
+		DisplayName:   displayName,
+		CustomSummary: fmt.Sprintf("No new BLADE batch in %s for more than %s", spec.TableName, spec.SLA),
+		QueryText:     freshnessSQL,
+		WarehouseId:   c.warehouseID,
+		ParentPath:    spec.ParentPath,
+		Schedule: &sql.CronSchedule{
+			QuartzCronSchedule: spec.Schedule,
+			TimezoneId:         spec.TimezoneID,
+		},
+		Evaluation: &sql.AlertV2Evaluation{
+			Source:             &sql.AlertV2OperandColumn{Name: "minutes_since_last_batch", Aggregation: sql.AggregationMax},
+			ComparisonOperator: sql.ComparisonOperatorGreaterThan,
+			Threshold:          &sql.AlertV2Operand{Value: &sql.AlertV2OperandValue{DoubleValue: slaMinutes}},
+			EmptyResultState:   sql.AlertEvaluationStateTriggered,
+			Notification: &sql.AlertV2Notification{
+				NotifyOnOk:    true,
+				Subscriptions: []sql.AlertV2Subscription{subscription},
+			},
+		},
+	}
+
+	existing, err := c.findAlertByDisplayName(ctx, displayName)
+	if err != nil {
+		return nil, err
+	}
+
+	var alert *sql.AlertV2
+	if existing != nil {
+		// Existing Alert:
+		// - Only the fields this tool owns are replaced; owner, parent path and
+		//   notification history stay as they are
+		alert, err = c.workspace.AlertsV2.UpdateAlert(ctx, sql.UpdateAlertV2Request{
+			Id:         existing.Id,
+			Alert:      definition,
+			UpdateMask: freshnessAlertUpdateMask,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update freshness alert %s for %s: %w", existing.Id, spec.TableName, err)
+		}
+		runlog.Printf(ctx, "Updated freshness alert %s for %s (SLA %s)", alert.Id, spec.TableName, spec.SLA)
+	} else {
+		alert, err = c.workspace.AlertsV2.CreateAlert(ctx, sql.CreateAlertV2Request{Alert: definition})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create freshness alert for %s: %w", spec.TableName, err)
+		}
+		runlog.Printf(ctx, "Created freshness alert %s for %s (SLA %s)", alert.Id, spec.TableName, spec.SLA)
+	}
+
+	return &AlertInfo{
+		AlertID:     alert.Id,
+		DisplayName: alert.DisplayName,
+		TableName:   spec.TableName,
+		SLAMinutes:  slaMinutes,
+		Updated:     existing != nil,
+	}, nil
+}
+
+// Returns the workspace's alert with the given display name, or nil when there is none.
+//   - A trashed alert is skipped, so it is replaced by a new one
+//   - Several matches (created by hand or by an older version) resolve to the first listed
+func (c *Client) findAlertByDisplayName(ctx context.Context, displayName string) (*sql.AlertV2, error) {
+	alerts, err := c.workspace.AlertsV2.ListAlertsAll(ctx, sql.ListAlertsV2Request{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	for i := range alerts {
+		if alerts[i].DisplayName == displayName && alerts[i].LifecycleState != sql.LifecycleStateTrashed {
+			return &alerts[i], nil
+		}
+	}
+	return nil, nil
+}
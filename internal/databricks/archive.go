@@ -0,0 +1,140 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Suffix of the table that keeps superseded batches of a BLADE table.
+const archiveTableSuffix = "_archive"
+
+// Audit columns the archive table adds to the archived table's columns.
+var archiveAuditColumns = []TypedColumn{
+	{Name: "archived_at", Type: "TIMESTAMP"},
+	{Name: "superseded_by", Type: "STRING"},
+}
+
+// Moves every older batch of the request's source into {table}_archive, then deletes it from the table.
+//
+// A batch is superseded when a newer batch with the same source_path has landed
+// (a re-delivered file). Archived rows keep all columns plus archived_at and
+// superseded_by (the batch that replaced them). Returns the number of rows moved.
+func (c *Client) archiveSupersededBatches(ctx context.Context, req *IngestionRequest, batchID string) (int64, error) {
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName)
+	archive := table + archiveTableSuffix
+	params := []sql.StatementParameterListItem{
+		stringParam("batch_id", batchID),
+		stringParam("source_path", req.SourcePath),
+	}
+	superseded := "metadata['source_path'] = :source_path AND metadata['batch_id'] <> :batch_id"
+
+	// Archive Table:
+	// - The mapping's columns (see archiveColumns) plus the audit columns, copied by name so
+	//   a column added to either table later can't shift values into the wrong column
+	// - Created on first use, and given the columns the table gained since (schema migration)
+	columns, err := c.ensureArchiveTable(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	names := strings.TrimPrefix(typedColumnNames(columns), ", ")
+
+	// Copy, then Delete:
+	// - Delta can't commit both tables in one transaction, so the copy skips batches
+	//   the archive already holds; a run interrupted between the two statements is
+	//   completed by the next one without duplicating archived rows
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			INSERT INTO %[1]s (%[4]s, archived_at, superseded_by)
+			SELECT %[4]s, current_timestamp(), :batch_id FROM %[2]s
+			WHERE %[3]s
+			AND metadata['batch_id'] NOT IN (SELECT DISTINCT metadata['batch_id'] FROM %[1]s)
+		`, archive, table, superseded, names),
+		Parameters: params,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to copy superseded batches to %s: %w", archive, err)
+	}
+
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement:  fmt.Sprintf("DELETE FROM %s WHERE %s", table, superseded),
+		Parameters: params,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete superseded batches from %s: %w", table, err)
+	}
+
+	// DELETE reports num_affected_rows as its single result row
+	var archived int64
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		archived, _ = strconv.ParseInt(rows[0][0], 10, 64)
+	}
+	runlog.Printf(ctx, "Archived %d superseded row(s) from %s into %s", archived, table, archive)
+	return archived, nil
+}
+
+// Columns of the request's table that are archived: the standard columns, the mapping's
+// typed columns and the layout's generated columns (kept as plain values).
+func archiveColumns(req *IngestionRequest) []TypedColumn {
+	columns := make([]TypedColumn, 0, len(StandardColumns)+len(req.Columns))
+	for _, name := range StandardColumns {
+		columns = append(columns, TypedColumn{Name: name, Type: standardColumnTypes[name]})
+	}
+	columns = append(columns, req.Columns...)
+	for _, col := range req.Layout.generatedColumns() {
+		columns = append(columns, TypedColumn{Name: col.name, Type: col.sqlType})
+	}
+	return columns
+}
+
+// Creates {table}_archive, or adds the columns it lacks, and returns the archived columns.
+//   - Column comments aren't copied; Catalog Explorer shows them on the table
+//   - A column whose type changed (--force-recreate) keeps its archived type, so the copy
+//     fails until the archive is migrated by hand
+func (c *Client) ensureArchiveTable(ctx context.Context, req *IngestionRequest) ([]TypedColumn, error) {
+	archiveName := req.TableName + archiveTableSuffix
+	archive := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, archiveName)
+	columns := archiveColumns(req)
+	plain := make([]TypedColumn, len(columns))
+	for i, col := range columns {
+		plain[i] = TypedColumn{Name: col.Name, Type: declaredType(col)}
+	}
+
+	existing, err := c.DescribeColumns(ctx, archiveName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check archive table %s: %w", archive, err)
+	}
+	if len(existing) == 0 {
+		if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+			Statement: fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s\n\t\t)", archive, strings.TrimPrefix(typedColumnsDDL(append(plain, archiveAuditColumns...)), ",")),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create archive table %s: %w", archive, err)
+		}
+		c.applyCostTags(ctx, "TABLE", archive)
+		return columns, nil
+	}
+
+	// - Names are case-insensitive, as in Unity Catalog
+	present := make(map[string]bool, len(existing))
+	for _, col := range existing {
+		present[strings.ToLower(col.Name)] = true
+	}
+	var missing []TypedColumn
+	for _, col := range plain {
+		if !present[strings.ToLower(col.Name)] {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		runlog.Printf(ctx, "Adding columns to archive table %s: %s", archive, strings.TrimPrefix(typedColumnNames(missing), ", "))
+		if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+			Statement: fmt.Sprintf("ALTER TABLE %s ADD COLUMNS (%s\n\t\t)", archive, strings.TrimPrefix(typedColumnsDDL(missing), ",")),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add columns to archive table %s: %w", archive, err)
+		}
+	}
+	return columns, nil
+}
@@ -0,0 +1,146 @@
+package databricks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: The run history table (history.go) and the local run store say what the
+//   tool did; an audit trail also has to say who did it and to which data. The audit
+//   table gets one append-only row per ingestion with the workspace principal, the
+//   local operator and host, the SHA-256 of the source and the outcome.
+
+//   Notes:
+//   - The principal is current_user() of the INSERT itself, so it's the identity the
+//     warehouse ran the load as, whatever the credentials were
+//   - The table is created with delta.appendOnly, so its rows can't be updated or deleted
+//   - Failed runs are recorded too, unless they failed before reaching the workspace
+
+// Table that receives one audit row per ingestion (created on first use next to the BLADE tables).
+const AuditTable = "blade_ingestion_audit"
+
+// One ingestion as recorded in the audit table.
+//   - Operator / Host: The local OS user and machine that ran the tool
+//   - SourceHash: SHA-256 of the source (see SourceFileHash); empty when it couldn't be read
+type AuditRecord struct {
+	RunID        string
+	DataType     string
+	Format       string
+	Tenant       string
+	Operator     string
+	Host         string
+	TableName    string
+	BatchID      string
+	SourcePath   string
+	SourceHash   string
+	Status       string
+	RowsIngested int64
+	RowsRejected int64
+	Duration     time.Duration
+	Error        string
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// Returns the SHA-256 (hex) of what a request loads:
+//   - A local file (path or file:// URL): its bytes as delivered
+//   - Otherwise the records the request carries (SampleData, e.g. mock data or CSV converted to JSON)
+//   - "" for sources that aren't local (Volume paths, directories, Records streams)
+func SourceFileHash(req *IngestionRequest) string {
+	path := strings.TrimPrefix(req.SourcePath, localFilePrefix)
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+		file, err := os.Open(path)
+		if err != nil {
+			return ""
+		}
+		defer file.Close()
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return ""
+		}
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+	if req.SampleData == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(req.SampleData))
+	return hex.EncodeToString(sum[:])
+}
+
+// Appends an ingestion to the audit table, creating the table if needed.
+func (c *Client) RecordAudit(ctx context.Context, record AuditRecord) error {
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, AuditTable)
+
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				run_id STRING COMMENT 'Run ID (log file, run store and reports)',
+				data_type STRING COMMENT 'BLADE data type',
+				format STRING COMMENT 'Source format (JSON, CSV, NDJSON)',
+				tenant STRING COMMENT 'Tenant of the run (BLADE_TENANT)',
+				principal STRING COMMENT 'Workspace identity the load ran as (current_user())',
+				operator STRING COMMENT 'Local OS user who ran the tool',
+				host STRING COMMENT 'Machine the tool ran on',
+				table_name STRING COMMENT 'Table loaded',
+				batch_id STRING COMMENT 'Batch ID of the loaded rows',
+				source_path STRING COMMENT 'Source file or URL',
+				source_hash STRING COMMENT 'SHA-256 of the source (empty when not local)',
+				status STRING COMMENT 'completed, failed or partial',
+				rows_ingested BIGINT COMMENT 'Rows loaded',
+				rows_rejected BIGINT COMMENT 'Records rejected by the table rules',
+				duration_ms BIGINT COMMENT 'Load duration in milliseconds',
+				error STRING COMMENT 'Error of a failed run',
+				started_at TIMESTAMP COMMENT 'When the load started (UTC)',
+				finished_at TIMESTAMP COMMENT 'When the run finished (UTC)'
+			)
+			COMMENT 'One row per BLADE ingestion: who loaded which source, when and with what outcome'
+			TBLPROPERTIES ('delta.appendOnly' = 'true')
+		`, fullName),
+	}); err != nil {
+		return fmt.Errorf("failed to create audit table %s: %w", fullName, err)
+	}
+	c.applyCostTags(ctx, "TABLE", fullName)
+
+	// Values are bound as named parameters; paths and error messages can contain anything
+	_, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			INSERT INTO %s VALUES (
+				:run_id, :data_type, :format, :tenant, current_user(), :operator, :host, :table_name, :batch_id,
+				:source_path, :source_hash, :status, CAST(:rows_ingested AS BIGINT), CAST(:rows_rejected AS BIGINT),
+				CAST(:duration_ms AS BIGINT), :error, CAST(:started_at AS TIMESTAMP), CAST(:finished_at AS TIMESTAMP)
+			)
+		`, fullName),
+		Parameters: []sql.StatementParameterListItem{
+			stringParam("run_id", record.RunID),
+			stringParam("data_type", record.DataType),
+			stringParam("format", record.Format),
+			stringParam("tenant", record.Tenant),
+			stringParam("operator", record.Operator),
+			stringParam("host", record.Host),
+			stringParam("table_name", record.TableName),
+			stringParam("batch_id", record.BatchID),
+			stringParam("source_path", record.SourcePath),
+			stringParam("source_hash", record.SourceHash),
+			stringParam("status", record.Status),
+			stringParam("rows_ingested", strconv.FormatInt(record.RowsIngested, 10)),
+			stringParam("rows_rejected", strconv.FormatInt(record.RowsRejected, 10)),
+			stringParam("duration_ms", strconv.FormatInt(record.Duration.Milliseconds(), 10)),
+			stringParam("error", record.Error),
+			stringParam("started_at", record.StartedAt.UTC().Format("2006-01-02 15:04:05")),
+			stringParam("finished_at", record.FinishedAt.UTC().Format("2006-01-02 15:04:05")),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record run %s in %s: %w", record.RunID, fullName, err)
+	}
+	return nil
+}
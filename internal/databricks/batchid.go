@@ -0,0 +1,117 @@
+package databricks
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+//   Purpose: Generates the batch ID stamped into metadata['batch_id'] of every row of a
+//   load. Post-load validations, sample verification, archiving, child tables and the
+//   staging table name all key off it, so two loads must never share one by accident.
+
+//   Strategies (BLADE_BATCH_ID):
+//   - ulid (default): Time-ordered 26-character ULID; unique across concurrent runs
+//   - content: Derived from the data type, table and records, so re-delivering
+//     identical data yields the same ID (idempotent re-runs, easy duplicate spotting);
+//     concurrent staged loads of the same data would share a staging table
+//   - unix: Legacy Unix seconds; collides for runs started in the same second
+type BatchIDStrategy func(req *IngestionRequest) string
+
+// Batch ID strategy used when BLADE_BATCH_ID is not set.
+const DefaultBatchIDStrategy = "ulid"
+
+var batchIDStrategies = map[string]BatchIDStrategy{
+	"ulid":    func(*IngestionRequest) string { return NewULID(time.Now()) },
+	"content": contentBatchID,
+	"unix":    func(*IngestionRequest) string { return fmt.Sprintf("%d", time.Now().Unix()) },
+}
+
+// Makes a batch ID strategy selectable via BLADE_BATCH_ID. Later registrations replace earlier ones.
+//   - IDs end up in staging table names, so they must be plain identifier characters
+func RegisterBatchIDStrategy(name string, strategy BatchIDStrategy) {
+	batchIDStrategies[strings.ToLower(name)] = strategy
+}
+
+// Returns the registered batch ID strategy names in sorted order.
+func BatchIDStrategies() []string {
+	names := make([]string, 0, len(batchIDStrategies))
+	for name := range batchIDStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolves a strategy by name ("" = DefaultBatchIDStrategy).
+func batchIDStrategy(name string) (string, BatchIDStrategy, error) {
+	name = strings.ToLower(name)
+	if name == "" {
+		name = DefaultBatchIDStrategy
+	}
+	strategy, exists := batchIDStrategies[name]
+	if !exists {
+		return "", nil, fmt.Errorf("unsupported BLADE_BATCH_ID %q (supported: %s)", name, strings.Join(BatchIDStrategies(), ", "))
+	}
+	return name, strategy, nil
+}
+
+// Crockford base32, the ULID alphabet (no I, L, O, U).
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Returns a ULID: 48 bits of milliseconds since the epoch followed by 80 random bits,
+// so IDs sort by creation time and don't collide within the same millisecond.
+func NewULID(now time.Time) string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(now.UnixMilli())<<16)
+	_, _ = rand.Read(id[6:])
+
+	// - 128 bits as 26 base32 digits, the first holding only the top 3 bits
+	var out [26]byte
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := 25; i >= 0; i-- {
+		out[i] = ulidAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// Derives the batch ID from the source's hash (sourceHash); a Records source can't be
+// hashed, so its loads get a ULID.
+func contentBatchID(req *IngestionRequest) string {
+	if hash := sourceHash(req); hash != "" {
+		return hash[:32]
+	}
+	return NewULID(time.Now())
+}
+
+// Hashes what identifies the delivered data; COPY INTO loads hash the source path and
+// declared version since the files themselves never pass through the client.
+//   - record_stream loads hash their file's content, read through once more; a Records
+//     source can only be read once, so it isn't hashed ("")
+func sourceHash(req *IngestionRequest) string {
+	if req.Metadata["mode"] == ModeRecordStream && req.Records != nil {
+		return ""
+	}
+	hash := sha256.New()
+	for _, part := range []string{req.Metadata["data_type"], req.TableName, req.SourcePath, req.Metadata["source_version"], req.SampleData} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	if req.Metadata["mode"] == ModeRecordStream {
+		if file, err := os.Open(streamPath(req)); err == nil {
+			io.Copy(hash, file)
+			file.Close()
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
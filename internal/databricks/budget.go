@@ -0,0 +1,76 @@
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Returned (wrapped) when the run's context deadline (--max-runtime) ends ingestion early.
+var ErrRunBudgetExceeded = errors.New("run budget exceeded")
+
+// Returned (wrapped) when SIGINT/SIGTERM ends ingestion early: the run's context is
+// canceled with ErrInterrupted as its cause.
+var ErrInterrupted = errors.New("interrupted")
+
+// Reports whether the run budget carried by ctx has run out, or the run was interrupted;
+// either way the ingestion stops with a partial result instead of failing.
+func budgetExceeded(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(context.Cause(ctx), ErrInterrupted)
+}
+
+// Returns why ctx stopped the run: ErrInterrupted or ErrRunBudgetExceeded.
+func stopReason(ctx context.Context) error {
+	if errors.Is(context.Cause(ctx), ErrInterrupted) {
+		return ErrInterrupted
+	}
+	return ErrRunBudgetExceeded
+}
+
+// Reports whether err ended a run early (run budget or interrupt), so it's recorded as partial.
+func IsPartial(err error) bool {
+	return errors.Is(err, ErrRunBudgetExceeded) || errors.Is(err, ErrInterrupted)
+}
+
+// Builds the partial result returned when the budget runs out (or the run is interrupted) before phase.
+//   - rows: Rows already committed to the target (0 if the insert never completed)
+//   - The result's metadata records the phase so the run can be resumed or re-run
+func partialResult(ctx context.Context, req *IngestionRequest, start time.Time, phase string, rows int64, batchID string) (*IngestionResult, error) {
+	err := fmt.Errorf("%w: stopped before %s", stopReason(ctx), phase)
+	return &IngestionResult{
+		RowsIngested: rows,
+		Duration:     time.Since(start),
+		TableName:    req.TableName,
+		Status:       "partial",
+		Error:        err,
+		Metadata: map[string]interface{}{
+			"stopped_before": phase,
+			"batch_id":       batchID,
+		},
+	}, err
+}
+
+// Fits a statement's server-side wait into the remaining run budget.
+//   - The API accepts 0s or 5s-50s; the wait never ends after the deadline
+//   - OnWaitTimeout CANCEL makes the warehouse cancel the statement itself when
+//     the budget runs out, instead of leaving it running after the client gave up
+func fitToBudget(ctx context.Context, req *sql.ExecuteStatementRequest) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := time.Until(deadline)
+	current, err := time.ParseDuration(req.WaitTimeout)
+	if err != nil || current == 0 || remaining >= current {
+		return
+	}
+	seconds := int(remaining / time.Second)
+	if seconds < 5 {
+		seconds = 5
+	}
+	req.WaitTimeout = fmt.Sprintf("%ds", seconds)
+	req.OnWaitTimeout = sql.ExecuteStatementRequestOnWaitTimeoutCancel
+}
@@ -0,0 +1,275 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// A one-to-many detail table materialized from an array inside each parent record.
+//   - Table: Child table name (e.g. "blade_maintenance_parts")
+//   - Path: Dot-separated path to the array in a record ("parts_required", "shipment.items");
+//     arrays met on the way are walked element by element
+//   - Fields: Element fields copied into their own STRING columns; scalar elements land in "value"
+//   - Exploder: Named exploder (see childExploders) that builds the rows in code instead of
+//     Path/Fields, for arrays spread over several places in a record (e.g. "sortie_crew")
+type ChildTable struct {
+	Table    string   `json:"table"`
+	Path     string   `json:"path,omitempty"`
+	Fields   []string `json:"fields,omitempty"`
+	Exploder string   `json:"exploder,omitempty"`
+}
+
+// Builds a child table's rows in code.
+//   - ParentColumn: Column holding the parent's item_id (parent_id for path-based tables)
+//   - Columns: Columns between position and tenant, filled from ChildRow.Fields
+//   - Explode: One row per element; records without an item_id are skipped
+type ChildExploder struct {
+	ParentColumn string
+	Columns      []TypedColumn
+	Explode      func(records []map[string]interface{}) []ChildRow
+}
+
+// Exploders a ChildTable can name.
+var childExploders = map[string]ChildExploder{
+	SortieCrewExploder: {ParentColumn: "sortie_id", Columns: sortieCrewColumns, Explode: sortieCrewRows},
+}
+
+// Reports whether name is an exploder a ChildTable can name.
+func IsChildExploder(name string) bool {
+	_, ok := childExploders[name]
+	return ok
+}
+
+// Columns every child table has; declared fields may not reuse them.
+var childTableColumns = []string{"parent_id", "batch_id", "position", "value", "raw_data", "tenant", "ingestion_timestamp"}
+
+// A single element of a parent record's child array.
+type ChildRow struct {
+	ParentID string            `json:"parentId"` // item_id of the parent record
+	Position int               `json:"position"` // order of the element within the parent
+	Value    string            `json:"value,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	RawData  string            `json:"rawData"`
+}
+
+// Checks the child table declaration against the parent table it hangs off.
+func (t ChildTable) validate(parentTable string) error {
+	if !tableNamePattern.MatchString(t.Table) {
+		return fmt.Errorf("invalid child table name %q", t.Table)
+	}
+	if t.Table == parentTable {
+		return fmt.Errorf("child table %s must differ from its parent table", t.Table)
+	}
+	if t.Exploder != "" {
+		if !IsChildExploder(t.Exploder) {
+			return fmt.Errorf("child table %s names unknown exploder %q", t.Table, t.Exploder)
+		}
+		if t.Path != "" || len(t.Fields) > 0 {
+			return fmt.Errorf("child table %s has exploder %s, which takes no path or fields", t.Table, t.Exploder)
+		}
+		return nil
+	}
+	if strings.TrimSpace(t.Path) == "" {
+		return fmt.Errorf("child table %s needs a path", t.Table)
+	}
+	for _, field := range t.Fields {
+		if !tableNamePattern.MatchString(field) {
+			return fmt.Errorf("child table %s has invalid field %q", t.Table, field)
+		}
+		for _, column := range childTableColumns {
+			if strings.EqualFold(field, column) {
+				return fmt.Errorf("child table %s field %q clashes with a standard column", t.Table, field)
+			}
+		}
+	}
+	return nil
+}
+
+// Explodes the array at child.Path of every record into child rows. Records without an item_id are skipped.
+func ExplodeChildRows(records []map[string]interface{}, child ChildTable) []ChildRow {
+	var rows []ChildRow
+	for _, record := range records {
+		parentID, _ := record["item_id"].(string)
+		if parentID == "" {
+			continue
+		}
+		for i, element := range childElements(record, strings.Split(child.Path, ".")) {
+			raw, _ := json.Marshal(element)
+			row := ChildRow{ParentID: parentID, Position: i + 1, RawData: string(raw)}
+			if object, ok := element.(map[string]interface{}); ok {
+				row.Fields = make(map[string]string, len(child.Fields))
+				for _, field := range child.Fields {
+					if value, exists := object[field]; exists && value != nil {
+						row.Fields[field] = childValue(value)
+					}
+				}
+			} else {
+				row.Value = childValue(element)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Returns the column keyed to the parent and the columns between position and tenant.
+func (t ChildTable) layout() (string, []TypedColumn) {
+	if exploder, ok := childExploders[t.Exploder]; ok {
+		return exploder.ParentColumn, exploder.Columns
+	}
+	columns := []TypedColumn{{Name: "value", Type: "STRING"}}
+	for _, field := range t.Fields {
+		columns = append(columns, TypedColumn{Name: field, Type: "STRING"})
+	}
+	return "parent_id", append(columns, TypedColumn{Name: "raw_data", Type: "STRING"})
+}
+
+// Explodes the child rows of records, with the table's exploder or its path.
+func (t ChildTable) explode(records []map[string]interface{}) []ChildRow {
+	if exploder, ok := childExploders[t.Exploder]; ok {
+		return exploder.Explode(records)
+	}
+	return ExplodeChildRows(records, t)
+}
+
+// Returns the row's value for a column of the table's layout.
+func (r ChildRow) column(name string) string {
+	switch name {
+	case "value":
+		return r.Value
+	case "raw_data":
+		return r.RawData
+	}
+	return r.Fields[name]
+}
+
+// Follows path through value and returns the elements of the array(s) it ends at.
+func childElements(value interface{}, path []string) []interface{} {
+	if array, ok := value.([]interface{}); ok {
+		var elements []interface{}
+		for _, item := range array {
+			if len(path) == 0 {
+				if item != nil {
+					elements = append(elements, item)
+				}
+				continue
+			}
+			elements = append(elements, childElements(item, path)...)
+		}
+		return elements
+	}
+	if len(path) == 0 {
+		return nil
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	next, exists := object[path[0]]
+	if !exists {
+		return nil
+	}
+	if _, isArray := next.([]interface{}); !isArray && len(path) == 1 {
+		return nil
+	}
+	return childElements(next, path[1:])
+}
+
+// Renders a JSON value as the string stored in a child column (nested values stay JSON).
+func childValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Creates every child table of the request if missing and inserts this batch's child rows.
+func (c *Client) insertChildTables(ctx context.Context, req *IngestionRequest, batchID string) (map[string]int64, error) {
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return nil, fmt.Errorf("failed to parse sample data: %w", err)
+	}
+
+	counts := make(map[string]int64, len(req.ChildTables))
+	for _, child := range req.ChildTables {
+		rows, err := c.insertChildTable(ctx, req, child, child.explode(records), batchID)
+		if err != nil {
+			return counts, err
+		}
+		counts[child.Table] = rows
+	}
+	return counts, nil
+}
+
+func (c *Client) insertChildTable(ctx context.Context, req *IngestionRequest, child ChildTable, rows []ChildRow, batchID string) (int64, error) {
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, child.Table)
+	parent := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName)
+
+	// Referential Key:
+	// - (parent_id, batch_id) points back at the parent row's item_id and metadata['batch_id']
+	//   (sortie_id for blade_sortie_crew); item_id alone repeats across re-deliveries, so the
+	//   batch is part of the key. It's documented on the columns rather than declared, since
+	//   the parent has no primary key
+	// - Declared fields become STRING columns, the whole element is kept in raw_data;
+	//   exploder tables bring their own columns
+	parentColumn, layout := child.layout()
+	columnsDDL := ""
+	for _, column := range layout {
+		columnsDDL += fmt.Sprintf("\n\t\t\t\t`%s` %s,", column.Name, column.Type)
+	}
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				%s STRING NOT NULL COMMENT 'item_id of the parent row in %s',
+				batch_id STRING NOT NULL COMMENT 'metadata[''batch_id''] of the parent row',
+				position INT,%s
+				tenant STRING,
+				ingestion_timestamp TIMESTAMP
+			)
+		`, table, parentColumn, parent, columnsDDL),
+	}); err != nil {
+		return 0, fmt.Errorf("failed to create child table %s: %w", table, err)
+	}
+	c.applyCostTags(ctx, "TABLE", table)
+	if len(rows) == 0 {
+		runlog.Printf(ctx, "No %s rows found in batch %s", child.Table, batchID)
+		return 0, nil
+	}
+
+	columns := []string{parentColumn, "batch_id", "position"}
+	for _, column := range layout {
+		columns = append(columns, "`"+column.Name+"`")
+	}
+	columns = append(columns, "tenant", "ingestion_timestamp")
+
+	var params paramList
+	batch, tenant := params.text(batchID), params.text(c.tenant)
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		value := []string{params.text(row.ParentID), batch, fmt.Sprint(row.Position)}
+		for _, column := range layout {
+			value = append(value, params.bind(row.column(column.Name), column.Type))
+		}
+		value = append(value, tenant, "current_timestamp()")
+		values = append(values, "("+strings.Join(value, ", ")+")")
+	}
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+			table, strings.Join(columns, ", "), strings.Join(values, ",\n")),
+		Parameters: params.params,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to insert child rows into %s: %w", table, err)
+	}
+	runlog.Printf(ctx, "Inserted %d child row(s) into %s", len(rows), table)
+	return int64(len(rows)), nil
+}
@@ -0,0 +1,195 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Every table carries a classification_marking, but nothing looked at it: a
+//   SECRET record in an UNCLASSIFIED extract was loaded like any other. A classification
+//   policy checks each record's marking before it is written, and every load reports how
+//   many of its rows carry each marking.
+
+//   Policy (BLADE_CLASSIFICATION_ALLOWED, BLADE_CLASSIFICATION_CEILING; both optional):
+//   - Allowed: The markings a record may carry, e.g. "UNCLASSIFIED, CUI"; a marking also
+//     allows its "//"-qualified forms ("CUI" allows "CUI//SP-PRVCY"). Other records are
+//     rejected like records that break the table's rules (see rejects.go)
+//   - Ceiling: The highest level the environment may hold (MarkingLevels); a record above
+//     it, or whose marking has no level, blocks the whole load before anything is written
+//   - Applies to record loads (mock data, record files, streams) and every route of a
+//     routed load; COPY INTO loads never hold the records and are only counted
+
+// Classification levels, lowest first, that a ceiling is compared against. A marking's
+// level is its part before "//" ("SECRET//NOFORN" is SECRET).
+var MarkingLevels = []string{"UNCLASSIFIED", "CUI", "CONFIDENTIAL", "SECRET", "TOP SECRET"}
+
+// Banner abbreviations of the MarkingLevels.
+var markingAbbreviations = map[string]string{"U": "UNCLASSIFIED", "C": "CONFIDENTIAL", "S": "SECRET", "TS": "TOP SECRET"}
+
+// Key of unmarked records in a marking distribution.
+const UnmarkedKey = "(unmarked)"
+
+// Which classification markings a client loads.
+//   - Allowed: Normalized markings records may carry (empty = any)
+//   - Ceiling: Highest level records may carry, one of MarkingLevels (empty = none)
+type ClassificationPolicy struct {
+	Allowed []string `json:"allowed,omitempty"`
+	Ceiling string   `json:"ceiling,omitempty"`
+}
+
+// Parses BLADE_CLASSIFICATION_ALLOWED (comma-separated markings) and
+// BLADE_CLASSIFICATION_CEILING; returns nil when both are empty.
+func ParseClassificationPolicy(allowed, ceiling string) (*ClassificationPolicy, error) {
+	policy := &ClassificationPolicy{}
+	for _, marking := range strings.Split(allowed, ",") {
+		marking = normalizeMarking(marking)
+		if marking == "" {
+			continue
+		}
+		if containsValue(policy.Allowed, marking) {
+			return nil, fmt.Errorf("classification %s is allowed twice", marking)
+		}
+		policy.Allowed = append(policy.Allowed, marking)
+	}
+	if ceiling = normalizeMarking(ceiling); ceiling != "" {
+		level, ok := markingLevel(ceiling)
+		if !ok || strings.Contains(ceiling, "//") {
+			return nil, fmt.Errorf("invalid classification ceiling %q (use one of %s)", ceiling, strings.Join(MarkingLevels, ", "))
+		}
+		policy.Ceiling = MarkingLevels[level]
+	}
+	if len(policy.Allowed) == 0 && policy.Ceiling == "" {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// Returns the position of a marking's level in MarkingLevels, or false when it has none.
+func markingLevel(marking string) (int, bool) {
+	base, _, _ := strings.Cut(normalizeMarking(marking), "//")
+	base = strings.TrimSpace(base)
+	if full, ok := markingAbbreviations[base]; ok {
+		base = full
+	}
+	for i, level := range MarkingLevels {
+		if base == level {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Reports whether the policy allows a marking.
+func (p *ClassificationPolicy) allows(marking string) bool {
+	if p == nil || len(p.Allowed) == 0 {
+		return true
+	}
+	marking = normalizeMarking(marking)
+	for _, allowed := range p.Allowed {
+		if marking == allowed || strings.HasPrefix(marking, allowed+"//") {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns an error naming the markings above the ceiling (or without a level), with
+// their record counts; nil when every record is within it.
+func (p *ClassificationPolicy) checkCeiling(records []map[string]interface{}, table string) error {
+	if p == nil || p.Ceiling == "" {
+		return nil
+	}
+	ceiling, _ := markingLevel(p.Ceiling)
+	above := make(map[string]int)
+	for _, record := range records {
+		marking := normalizeMarking(recordText(record, "classification_marking"))
+		if level, ok := markingLevel(marking); !ok || level > ceiling {
+			above[markingKey(marking)]++
+		}
+	}
+	if len(above) == 0 {
+		return nil
+	}
+	parts := make([]string, 0, len(above))
+	for _, marking := range sortedKeys(above) {
+		parts = append(parts, fmt.Sprintf("%s (%d)", marking, above[marking]))
+	}
+	return fmt.Errorf("records for %s are marked above the classification ceiling %s: %s", table, p.Ceiling, strings.Join(parts, ", "))
+}
+
+// Returns the key a marking is counted under in a marking distribution.
+func markingKey(marking string) string {
+	if marking = normalizeMarking(marking); marking == "" {
+		return UnmarkedKey
+	}
+	return marking
+}
+
+// Counts the records per marking into distribution, leaving out the refused ones (by
+// their position in records).
+func countMarkings(distribution map[string]int64, records []map[string]interface{}, refused []Rejection) {
+	skip := make(map[int]bool, len(refused))
+	for _, rejection := range refused {
+		skip[rejection.Index] = true
+	}
+	for i, record := range records {
+		if !skip[i] {
+			distribution[markingKey(recordText(record, "classification_marking"))]++
+		}
+	}
+}
+
+// Returns the marking distribution of the request's records.
+func sampleMarkings(req *IngestionRequest) (map[string]int64, error) {
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return nil, fmt.Errorf("failed to parse sample data: %w", err)
+	}
+	distribution := make(map[string]int64)
+	countMarkings(distribution, records, nil)
+	return distribution, nil
+}
+
+// Reads the marking distribution of a batch back from its table, for loads whose records
+// never pass through the client (COPY INTO).
+func (c *Client) batchMarkings(ctx context.Context, table string, batchID string) (map[string]int64, error) {
+	var params paramList
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			SELECT classification_marking, COUNT(*) FROM %s.%s.%s
+			WHERE metadata['batch_id'] = %s
+			GROUP BY classification_marking
+		`, c.catalog, c.schema, table, params.text(batchID)),
+		Parameters: params.params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count markings of batch %s: %w", batchID, err)
+	}
+	distribution := make(map[string]int64)
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		count, err := strconv.ParseInt(row[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse marking count %q: %w", row[1], err)
+		}
+		distribution[markingKey(row[0])] += count
+	}
+	return distribution, nil
+}
+
+// Logs a load's marking distribution in a stable order.
+func logMarkings(ctx context.Context, table string, distribution map[string]int64) {
+	parts := make([]string, 0, len(distribution))
+	for _, marking := range sortedKeys(distribution) {
+		parts = append(parts, fmt.Sprintf("%s=%d", marking, distribution[marking]))
+	}
+	runlog.Printf(ctx, "Markings loaded into %s: %s", table, strings.Join(parts, ", "))
+}
@@ -2,37 +2,129 @@ package databricks
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 	"github.com/databricks/databricks-sdk-go"
 	"github.com/databricks/databricks-sdk-go/service/sql"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/auth"
 	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/replay"
 )
 
 type Client struct {
 	workspace *databricks.WorkspaceClient
+	statements StatementExecutor // every statement goes through it (w.StatementExecution unless NewClientWithExecutor)
 	warehouseID string
 	catalog string
 	schema string
+	externalLocation string
+	volumePath string // UC Volume directory local files are uploaded to for COPY INTO
+	retryAttempts int
+	retryDelay time.Duration
+	statementPollInterval time.Duration // GetStatement polling of statements still running after WaitTimeout
+	statementTimeout time.Duration // overall limit per statement, including polling (0 = none)
+	statementProgress bool // log query history metrics of statements while they are polled
+	connectTimeout time.Duration // limit of TestConnection (0 = none)
+	ddlTimeout time.Duration // limit per DDL statement (0 = none)
+	dmlTimeout time.Duration // limit per DML statement (0 = none)
+	tenant string // set by ForTenant; tags ingested rows
+	tenantIsolation string // set by ForTenant: TenantIsolationSchema or TenantIsolationCatalog
+	verifySampleSize int // records read back after each ingestion (0 disables)
+	loadMode string // LoadModeDirect or LoadModeStaged
+	rowCountMismatch string // RowCountWarn or RowCountFail
+	batchIDName string // batch ID strategy name, reported in run metadata
+	newBatchID BatchIDStrategy // generates metadata['batch_id'] for each load
+	rawDataCodecName string // raw_data codec name, reported in run metadata
+	rawDataCodec RawDataCodec // how raw_data is stored (plain JSON by default)
+	classificationRoutes []ClassificationRoute // records split by classification_marking (empty = no routing)
+	classification *ClassificationPolicy // markings records may carry (nil = any)
+	insertChunkSize int // records per INSERT statement (0 = one statement per load)
+	archiveSuperseded bool // move older batches of a re-delivered source to {table}_archive
+	batchManifest bool // record loads in blade_ingestion_batches, skipping or resuming known sources
+	sqlLog *sqlLogger // redacted statement logging (nil unless BLADE_SQL_DEBUG)
+	readOnly bool // audit mode: only SELECT/DESCRIBE/SHOW statements, no workspace objects created
+	costTags []costTag // cost-attribution tags applied to created catalogs/schemas/tables
+	tagWarehouse bool // also add the cost tags to the SQL warehouse
+	warehouseAutoStart bool // EnsureWarehouseRunning starts a stopped warehouse
+	warehouseStartTimeout time.Duration // EnsureWarehouseRunning's wait for RUNNING (0 = none)
+	tagged *sync.Map // objects already tagged by this process ("TABLE cat.schema.table"), shared by ForTenant copies
 }
 
 func NewClient(cfg *config.Config) (*Client, error) {
+	// Provider Selection:
+	// - DATABRICKS_AUTH_TYPE picks a registered auth.Provider (default: "pat")
+	// - New auth schemes register themselves in internal/auth without touching this function
+	provider, err := auth.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithAuth(cfg, provider)
+}
+
+func NewClientWithAuth(cfg *config.Config, provider auth.Provider) (*Client, error) {
 	// Purpose: Creates the core Databricks workspace client using the official SDK.
 
 	// Configuration Source:
 	// - cfg.DatabricksHost: From DATABRICKS_HOST environment variable
 	// 	- Example: "https://dbc-a1b2c3d4-e5f6.cloud.databricks.com"
-	// - cfg.DatabricksToken: From DATABRICKS_TOKEN environment variable
-	// 	- Example: "dapi123abc456def789ghi012jkl345mno"
+	// - provider: Fills in the credentials (token, client ID/secret, ...)
+	// 	- Tests can pass a fake provider instead of real credentials
 
 	// SDK Authentication:
-	// - Uses Personal Access Token authentication method
+	// - Auth method is whatever the provider configures (PAT by default)
 	// - SDK handles HTTPS requests, token headers, and API versioning automatically
 	// - Validates token format and host URL structure
-	w, err := databricks.NewWorkspaceClient(&databricks.Config{
+	// Connection Reuse:
+	// - One pooled transport keeps TLS connections to the workspace alive between statements
+	// - The SDK's HTTPTimeoutSeconds bounds a single API call; it is set from BLADE_HTTP_TIMEOUT,
+	//   rounded up to whole seconds (statement waits are bounded by WaitTimeout, longer
+	//   statements are polled, see awaitStatement)
+	// - The Client is read-only after construction, so one instance is safe to share
+	//   across goroutines (ForTenant copies share the same pool)
+	// Record / Replay:
+	// - BLADE_RECORD writes every API call of the run to a cassette file
+	// - BLADE_REPLAY serves a cassette instead of calling the workspace; no
+	//   credentials are needed, so the auth provider is skipped
+	// Rate Limiting:
+	// - 429s are retried after the delay the workspace asks for (BLADE_THROTTLE_RETRIES times),
+	//   below the recorder, so cassettes hold only the final responses
+	var transport http.RoundTripper = newThrottleTransport(newHTTPTransport(cfg.HTTPMaxIdleConns), cfg.ThrottleRetries, cfg.HTTPTimeout)
+	if cfg.RecordFile != "" && cfg.ReplayFile != "" {
+		return nil, fmt.Errorf("BLADE_RECORD and BLADE_REPLAY can't be used together")
+	}
+	if cfg.RecordFile != "" {
+		recorder, err := replay.NewRecorder(cfg.RecordFile, transport)
+		if err != nil {
+			return nil, err
+		}
+		transport = recorder
+	}
+	if cfg.ReplayFile != "" {
+		replayer, err := replay.NewReplayer(cfg.ReplayFile)
+		if err != nil {
+			return nil, err
+		}
+		transport = replayer
+	}
+
+	sdkConfig := &databricks.Config{
 		Host: cfg.DatabricksHost,
-		Token: cfg.DatabricksToken,
-	})
+		HTTPTransport: transport,
+		HTTPTimeoutSeconds: httpTimeoutSeconds(cfg.HTTPTimeout),
+	}
+	if cfg.ReplayFile != "" {
+		sdkConfig.AuthType, sdkConfig.Token = "pat", "replay"
+	} else if err := provider.Configure(sdkConfig); err != nil {
+		return nil, fmt.Errorf("failed to configure %s authentication: %w", provider.Name(), err)
+	}
+	w, err := databricks.NewWorkspaceClient(sdkConfig)
 
 	// Common Error Scenarios:
 	// - Invalid Host URL: Malformed or unreachable Databricks workspace URL
@@ -50,6 +142,8 @@ func NewClient(cfg *config.Config) (*Client, error) {
 
 	// Field Population:
 	// - workspace: The authenticated SDK client for all API operations
+	// - statements: The workspace's statement execution service (see executor.go)
+	// 	- Purpose: Lets tests run every statement against a mock instead of the workspace
 	// - warehouseID: From DATABRICKS_WAREHOUSE_ID env var
 	// 	- Example: "abc123def456ghi789"
 	// 	- Purpose: SQL warehouse for query execution
@@ -57,11 +151,119 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	// 	- Purpose: Top-level namespace for database objects
 	// - schema: From DATABRICKS_SCHEMA env var (default: "logistics")
 	// 	- Purpose: Second-level namespace within catalog
+	// - externalLocation: From DATABRICKS_EXTERNAL_LOCATION env var (optional)
+	// 	- Purpose: Storage root under which EXTERNAL tables get their LOCATION
+	// - volumePath: From BLADE_VOLUME_PATH env var (optional)
+	// 	- Purpose: Unity Catalog Volume directory local BLADE files are uploaded to before COPY INTO
+	// - retryAttempts/retryDelay: From BLADE_WAREHOUSE_RETRY_* env vars (default: 3 / 20s)
+	// 	- Purpose: Resubmit statements that raced a warehouse auto-stop
+	// - warehouseAutoStart/warehouseStartTimeout: From BLADE_WAREHOUSE_AUTO_START /
+	//   BLADE_WAREHOUSE_START_TIMEOUT env vars (default: false / 10m)
+	// 	- Purpose: A cold warehouse is started and waited for before the first statement
+	// - statementPollInterval/statementTimeout: From BLADE_STATEMENT_POLL_INTERVAL / BLADE_STATEMENT_TIMEOUT
+	//   env vars (default: 2s / 10m)
+	// 	- Purpose: Long-running DDL and INSERTs are followed to completion, not returned while pending
+	// - statementProgress: From BLADE_STATEMENT_PROGRESS env var (default: true)
+	// 	- Purpose: Shows whether a long COPY INTO/INSERT is progressing or stuck while it is polled
+	// - connectTimeout/ddlTimeout/dmlTimeout: From BLADE_CONNECT_TIMEOUT / BLADE_DDL_TIMEOUT /
+	//   BLADE_DML_TIMEOUT env vars (default: 5m / 5m / none)
+	// 	- Purpose: Per-kind statement deadlines, carried by the statement's context
+	// - verifySampleSize: From BLADE_VERIFY_SAMPLE_SIZE env var (default: 5)
+	// 	- Purpose: Records read back and compared field by field after each load
+	// - loadMode: From BLADE_LOAD_MODE env var (default: "direct")
+	// 	- Purpose: "staged" makes each run all-or-nothing via a staging table
+	// - rowCountMismatch: From BLADE_ROW_COUNT_MISMATCH env var (default: "warn")
+	// 	- Purpose: "fail" fails a run whose table didn't grow by the rows it inserted
+	// - insertChunkSize: From BLADE_INSERT_CHUNK_SIZE env var (default: 500)
+	// 	- Purpose: Keeps large loads under the statement size limit, one INSERT per chunk
+	// - classificationRoutes: From BLADE_CLASSIFICATION_ROUTES env var (default: none)
+	// 	- Purpose: Physically separates mixed-classification extracts by schema or table
+	// - classification: From BLADE_CLASSIFICATION_ALLOWED / BLADE_CLASSIFICATION_CEILING
+	//   env vars (default: any marking)
+	// 	- Purpose: Keeps markings the environment may not hold out of its tables
+	// - archiveSuperseded: From BLADE_ARCHIVE_SUPERSEDED env var (default: false)
+	// 	- Purpose: Keep primary tables to the latest delivery of each source
+	// - batchManifest: From BLADE_BATCH_MANIFEST env var (default: false)
+	// 	- Purpose: Re-runs skip sources already loaded and resume unfinished ones
+	loadMode := strings.ToLower(cfg.LoadMode)
+	if loadMode == "" {
+		loadMode = LoadModeDirect
+	}
+	if loadMode != LoadModeDirect && loadMode != LoadModeStaged {
+		return nil, fmt.Errorf("unsupported load mode %q (supported: %s, %s)", cfg.LoadMode, LoadModeDirect, LoadModeStaged)
+	}
+	rowCountMismatch, err := rowCountMismatchMode(cfg.RowCountMismatch)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := ParseClassificationRoutes(cfg.ClassificationRoutes)
+	if err != nil {
+		return nil, err
+	}
+	classification, err := ParseClassificationPolicy(cfg.ClassificationAllowed, cfg.ClassificationCeiling)
+	if err != nil {
+		return nil, err
+	}
+	// - batchIDName/newBatchID: From BLADE_BATCH_ID env var (default: "ulid")
+	// 	- Purpose: Unique batch IDs across concurrent runs, or content-derived ones for idempotent re-runs
+	batchIDName, newBatchID, err := batchIDStrategy(cfg.BatchIDStrategy)
+	if err != nil {
+		return nil, err
+	}
+	// - rawDataCodecName/rawDataCodec: From BLADE_RAW_DATA_CODEC env var (default: "none")
+	// 	- Purpose: Compressed raw_data for large tables, decoded wherever the tool reads it
+	rawDataCodecName, codec, err := rawDataCodec(cfg.RawDataCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	// - readOnly: From BLADE_READ_ONLY env var (default: false)
+	// 	- Purpose: Inspectors with read-only credentials; every write is refused client-side
+	// - sqlLog: From BLADE_SQL_DEBUG / BLADE_SQL_LOG_* env vars (default: off)
+	// 	- Purpose: Debug log of every statement without record content
+	// - costTags/tagWarehouse: From BLADE_COST_PROJECT / BLADE_COST_OWNER / BLADE_COST_ENVIRONMENT
+	//   and BLADE_TAG_WAREHOUSE env vars (default: project=blade-poc, warehouse untagged)
+	// 	- Purpose: FinOps attribution of the spend this integration causes
+	var sqlLog *sqlLogger
+	if cfg.SQLDebug {
+		sqlLog = newSQLLogger(cfg.SQLLogMaxChars, cfg.SQLLogPerSecond)
+	}
+
 	return &Client{
 		workspace: w,
+		statements: w.StatementExecution,
 		warehouseID: cfg.WarehouseID,
 		catalog: cfg.CatalogName,
 		schema: cfg.SchemaName,
+		externalLocation: cfg.ExternalLocation,
+		volumePath: cfg.VolumePath,
+		retryAttempts: cfg.WarehouseRetryAttempts,
+		retryDelay: cfg.WarehouseRetryDelay,
+		warehouseAutoStart: cfg.WarehouseAutoStart,
+		warehouseStartTimeout: cfg.WarehouseStartTimeout,
+		statementPollInterval: cfg.StatementPollInterval,
+		statementTimeout: cfg.StatementTimeout,
+		statementProgress: cfg.StatementProgress,
+		connectTimeout: cfg.ConnectTimeout,
+		ddlTimeout: cfg.DDLTimeout,
+		dmlTimeout: cfg.DMLTimeout,
+		verifySampleSize: cfg.VerifySampleSize,
+		loadMode: loadMode,
+		rowCountMismatch: rowCountMismatch,
+		batchIDName: batchIDName,
+		newBatchID: newBatchID,
+		rawDataCodecName: rawDataCodecName,
+		rawDataCodec: codec,
+		classificationRoutes: routes,
+		classification: classification,
+		insertChunkSize: cfg.InsertChunkSize,
+		archiveSuperseded: cfg.ArchiveSuperseded,
+		batchManifest: cfg.BatchManifest,
+		sqlLog: sqlLog,
+		readOnly: cfg.ReadOnly,
+		costTags: costTagsFromConfig(cfg),
+		tagWarehouse: cfg.TagWarehouse,
+		tagged: &sync.Map{},
 	}, nil
 }
 
@@ -84,27 +286,36 @@ func (c *Client) TestConnection(ctx context.Context) error {
 	// Request Parameters:
 	// - Statement: The SQL to execute ("SELECT 1 as test")
 	// - WarehouseId: SQL warehouse for query execution (from client config)
-	// - WaitTimeout: Maximum time to wait for completion ("10s")
+	// - WaitTimeout: The client default, shortened to fit the connect timeout
 
 	// Context Usage:
 	// - Enables caller to cancel operation early
-	// - Provides timeout control beyond the 10s statement timeout
+	// - connectTimeout (BLADE_CONNECT_TIMEOUT) bounds the whole test, including
+	//   polling while a stopped warehouse starts up
 	// - Propagates cancellation through call chain
-	resp, err := c.workspace.StatementExecution.ExecuteStatement(
+	parent := ctx
+	if c.connectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.connectTimeout)
+		defer cancel()
+	}
+	resp, err := c.executeStatement(
 		ctx,
 		sql.ExecuteStatementRequest{
 			Statement:   testSQL,
 			WarehouseId: c.warehouseID,
-			WaitTimeout: "10s",
 		},
 	)
+	if err != nil && c.connectTimeout > 0 && parent.Err() == nil && errors.Is(err, context.DeadlineExceeded) && !deadlineFrom(ctx, parent) {
+		err = fmt.Errorf("connection test did not finish within %s (BLADE_CONNECT_TIMEOUT): %w", c.connectTimeout, err)
+	}
 	
 	if err != nil {
 		return fmt.Errorf("failed to test Databricks connection: %w", err)
 	}
 	
 	if resp.Status != nil {
-		fmt.Printf("Connection test status: %v\n", resp.Status.State)
+		runlog.Printf(ctx, "Connection test status: %v", resp.Status.State)
 	}
 	
 	return nil
@@ -117,12 +328,12 @@ func (c *Client) ensureCatalogAndSchema(ctx context.Context) error {
 	// - IF NOT EXISTS: Prevents errors if catalog already exists
 	// - Logging: Shows exact SQL for debugging and audit trail
 	createCatalogSQL := fmt.Sprintf("CREATE CATALOG IF NOT EXISTS %s", c.catalog)
-	log.Printf("Creating catalog with SQL: %s", createCatalogSQL)
+	runlog.Printf(ctx, "Creating catalog with SQL: %s", createCatalogSQL)
 	
 	// Execution Details:
 	// - Statement: The generated CREATE CATALOG SQL
 	// - WarehouseId: SQL warehouse for DDL execution
-	// - WaitTimeout: 30 seconds (longer than connection test due to DDL complexity)
+	// - WaitTimeout: The client default; the statement is bounded by BLADE_DDL_TIMEOUT
 
 	// Error Handling:
 	// - Returns immediately if catalog creation fails
@@ -132,19 +343,19 @@ func (c *Client) ensureCatalogAndSchema(ctx context.Context) error {
 	// Success Logging:
 	// - Confirms catalog exists (either created or already existed)
 	// - Uses "created/verified" to indicate both scenarios
-	_, err := c.workspace.StatementExecution.ExecuteStatement(
+	_, err := c.executeStatement(
 		ctx,
 		sql.ExecuteStatementRequest{
 			Statement:   createCatalogSQL,
 			WarehouseId: c.warehouseID,
-			WaitTimeout: "30s",
 		},
 	)
 	
 	if err != nil {
 		return fmt.Errorf("failed to create catalog %s: %w", c.catalog, err)
 	}
-	log.Printf("Successfully created/verified catalog: %s", c.catalog)
+	runlog.Printf(ctx, "Successfully created/verified catalog: %s", c.catalog)
+	c.applyCostTags(ctx, "CATALOG", c.catalog)
 	
 	// SQL Generation:
 	// - Uses both catalog and schema names from client config
@@ -152,7 +363,7 @@ func (c *Client) ensureCatalogAndSchema(ctx context.Context) error {
 	// - Two-part naming: catalog.schema format required by Databricks
 	// - IF NOT EXISTS: Safe to run multiple times
 	createSchemaSQL := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s.%s", c.catalog, c.schema)
-	log.Printf("Creating schema with SQL: %s", createSchemaSQL)
+	runlog.Printf(ctx, "Creating schema with SQL: %s", createSchemaSQL)
 	
 	// Execution Details:
 	// - Same pattern as catalog creation
@@ -162,19 +373,24 @@ func (c *Client) ensureCatalogAndSchema(ctx context.Context) error {
 	// Success Flow:
 	// - Logs successful schema creation/verification
 	// - Returns nil to indicate both operations succeeded
-	_, err = c.workspace.StatementExecution.ExecuteStatement(
+	_, err = c.executeStatement(
 		ctx,
 		sql.ExecuteStatementRequest{
 			Statement:   createSchemaSQL,
 			WarehouseId: c.warehouseID,
-			WaitTimeout: "30s",
 		},
 	)
 	
 	if err != nil {
 		return fmt.Errorf("failed to create schema %s.%s: %w", c.catalog, c.schema, err)
 	}
-	log.Printf("Successfully created/verified schema: %s.%s", c.catalog, c.schema)
+	runlog.Printf(ctx, "Successfully created/verified schema: %s.%s", c.catalog, c.schema)
+	c.applyCostTags(ctx, "SCHEMA", fmt.Sprintf("%s.%s", c.catalog, c.schema))
+
+	// Cost Attribution:
+	// - Created objects carry the configured cost tags; with BLADE_TAG_WAREHOUSE the
+	//   warehouse running the statements gets them too (see tags.go)
+	c.applyWarehouseCostTags(ctx)
 	
 	return nil
 }
@@ -193,42 +409,69 @@ func (c *Client) ensureTableExists(ctx context.Context, req *IngestionRequest) e
 		return err
 	}
 	
+	// Table Type:
+	// - MANAGED (default): Unity Catalog owns the storage, no LOCATION clause
+	// - EXTERNAL: Data stays in the data owner's storage account under the
+	//   configured external location
+	locationClause := ""
+	if strings.EqualFold(req.TableType, ExternalTable) {
+		location, err := c.externalTableLocation(req)
+		if err != nil {
+			return err
+		}
+		locationClause = "LOCATION " + sqlString(location)
+	}
+
+	// Layout:
+	// - PARTITIONED BY or CLUSTER BY from the mapping's layout; partition transforms add
+	//   their generated columns (see layout.go)
+	layoutColumns, layoutClause := req.Layout.ddl()
+
 	// SQL Template Breakdown:
 	// 	Three-Part Table Name:
 	// 	- %s.%s.%s → blade_poc.logistics.blade_maintenance_data
 	// 	- catalog.schema.table format required by Databricks Unity Catalog
+	// 	Typed Columns:
+	// 	- The mapping's typed columns (e.g. parts_required ARRAY<STRING>) follow the standard ones
+	// 	Comments:
+	// 	- Standard columns carry their DefaultColumnSemantics description, the table the
+	// 	  mapping's Description, so the catalog documents a table from the moment it exists
+	// 	  (seed-semantics replaces them with the mapping's Genie semantics)
+	comment := standardColumnComment
 	createTableSQL := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.%s.%s (
-			item_id STRING,
-			item_type STRING,
-			classification_marking STRING,
-			timestamp TIMESTAMP,
-			data_source STRING,
-			raw_data STRING,
-			ingestion_timestamp TIMESTAMP,
-			metadata MAP<STRING, STRING>
-		)
-	`, c.catalog, c.schema, req.TableName)
-	log.Printf("Creating table with SQL: %s", createTableSQL)
+			item_id STRING%s,
+			item_type STRING%s,
+			classification_marking STRING%s,
+			timestamp TIMESTAMP%s,
+			data_source STRING%s,
+			raw_data STRING%s,
+			ingestion_timestamp TIMESTAMP%s,
+			metadata MAP<STRING, STRING>%s%s%s
+		) %s %s %s
+	`, c.catalog, c.schema, req.TableName,
+		comment("item_id"), comment("item_type"), comment("classification_marking"), comment("timestamp"),
+		comment("data_source"), comment("raw_data"), comment("ingestion_timestamp"), comment("metadata"),
+		typedColumnsDDL(req.Columns), layoutColumns, layoutClause, locationClause, tableCommentClause(req))
+	runlog.Printf(ctx, "Creating table %s.%s.%s if missing (full statement logged with BLADE_SQL_DEBUG)", c.catalog, c.schema, req.TableName)
 
 	// Request Parameters:
 	// - Statement: The generated CREATE TABLE SQL
 	// - WarehouseId: SQL warehouse for DDL execution
 	// - Catalog/Schema: Explicit context (redundant with SQL but required by API)
-	// - WaitTimeout: 30 seconds for DDL completion
+	// - WaitTimeout: The client default; the statement is bounded by BLADE_DDL_TIMEOUT
 
 	// Why Context Parameters:
 	// - Databricks API requires explicit catalog/schema context
 	// - Ensures operation executes in correct namespace
 	// - Provides additional validation beyond SQL statement
-	resp, err := c.workspace.StatementExecution.ExecuteStatement(
+	_, err := c.executeStatement(
 		ctx,
 		sql.ExecuteStatementRequest{ 
 			Statement:   createTableSQL,   
 			WarehouseId: c.warehouseID,  
 			Catalog:     c.catalog,     
 			Schema:      c.schema,       
-			WaitTimeout: "30s",   
 		},
 	)
 
@@ -247,24 +490,49 @@ func (c *Client) ensureTableExists(ctx context.Context, req *IngestionRequest) e
 		return fmt.Errorf("Failed to create table %s: %w", req.TableName, err)
 	}
 
-	// Status Monitoring:
-	// - PENDING: DDL operation still running (common for large tables)
-	// - SUCCEEDED: Table creation completed
-	// - FAILED: Creation failed (would have returned error above)
-
-	// Why Monitor Pending:
-	// - DDL operations can be asynchronous in Databricks
-	// - Provides user feedback for long-running operations
-	// - Helps distinguish between network delays vs. actual processing time
-	if resp.Status != nil && resp.Status.State == sql.StatementStatePending {
-		fmt.Printf("Table creation pending for %s\n", req.TableName)
+	// Schema Migration:
+	// - CREATE TABLE IF NOT EXISTS keeps a table created from an older mapping as it is,
+	//   so its columns are compared with the mapping: missing ones are added, changed
+	//   types fail unless --force-recreate allows dropping the table (see migrateTable)
+	if err := c.migrateTable(ctx, req, createTableSQL); err != nil {
+		return err
 	}
+	c.applyCostTags(ctx, "TABLE", fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName))
+	c.applyTableTags(ctx, req)
+
+	// Status:
+	// - executeStatement polls a still-running DDL to completion (see awaitStatement),
+	//   so reaching this point means the table exists
+	// - FAILED/CANCELED and polling timeouts were returned as errors above
 
 	// - Table exists and is ready for data insertion
   	// - All prerequisites (catalog, schema) also verified
 	return nil
 }
 
+func (c *Client) externalTableLocation(req *IngestionRequest) (string, error) {
+	// Location Resolution:
+	// - Full URLs (abfss://, s3://, gs://) in StoragePath are used as-is
+	// - Otherwise StoragePath (default: table name) is appended to DATABRICKS_EXTERNAL_LOCATION
+	// - Example: "abfss://blade@acct.dfs.core.windows.net/poc" + "blade_maintenance_data"
+	// - Either way the result is checked here: mappings can come from code, files or the API
+	path := req.StoragePath
+	if path == "" {
+		path = req.TableName
+	}
+	location := path
+	if !strings.Contains(path, "://") {
+		if c.externalLocation == "" {
+			return "", fmt.Errorf("table %s requests an EXTERNAL table but DATABRICKS_EXTERNAL_LOCATION is not set", req.TableName)
+		}
+		location = strings.TrimRight(c.externalLocation, "/") + "/" + strings.TrimLeft(path, "/")
+	}
+
+	if strings.ContainsAny(location, "'\\`") || strings.IndexFunc(location, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("invalid external location for table %s: %q", req.TableName, location)
+	}
+	return location, nil
+}
 
 func (c *Client) getRowCount(ctx context.Context, tableName string) (int64, error) {
 	// SQL Generation:
@@ -278,19 +546,21 @@ func (c *Client) getRowCount(ctx context.Context, tableName string) (int64, erro
 	// - WarehouseId: SQL warehouse for query execution
 	// - Catalog/Schema: Explicit context for the operation
 	// - Statement: The generated COUNT query
-	// - WaitTimeout: 30 seconds for query completion
+	// - WaitTimeout: The client default (30 seconds) for query completion
 
 	// Parameter Order Note:
 	// - Statement comes after context parameters (different from other functions)
 	// - Still functionally equivalent
-	resp, err := c.workspace.StatementExecution.ExecuteStatement(
+	// Caching:
+	// - Answered from the run's query cache (if any) until the next write to the table
+	resp, err := c.cachedStatement(
 		ctx,
+		fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, tableName),
 		sql.ExecuteStatementRequest{
 			WarehouseId: c.warehouseID,
   			Catalog: c.catalog,
   			Schema: c.schema,
   			Statement: countSQL,
-			WaitTimeout: "30s",
 		},
 	)
 
@@ -314,8 +584,9 @@ func (c *Client) getRowCount(ctx context.Context, tableName string) (int64, erro
 	// - Provides visibility into query execution
 	// - Helps debug performance or execution issues
 	// - Confirms successful operation completion
+	// - Logged rather than printed, so stdout only carries command output (query, ingest --output)
 	if resp.Status != nil {
-		fmt.Printf("Row count query status: %v\n", resp.Status.State)
+		runlog.Printf(ctx, "Row count query status: %v", resp.Status.State)
 	}
 
 	// Structure Validation:
@@ -348,7 +619,7 @@ func (c *Client) getRowCount(ctx context.Context, tableName string) (int64, erro
 		// Success Logging:
 		// - Logs the actual count with full table path
 		// - Example: "Table blade_poc.logistics.blade_maintenance_data contains 5 rows"
-		log.Printf("Table %s.%s.%s contains %d rows", c.catalog, c.schema, tableName, count)
+		runlog.Printf(ctx, "Table %s.%s.%s contains %d rows", c.catalog, c.schema, tableName, count)
 		return count, nil
 	}
 
@@ -0,0 +1,135 @@
+package databricks
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+//   Purpose: raw_data keeps every record as JSON next to the columns extracted from it,
+//   so it dominates table size. A codec can store it compressed instead; every read of
+//   raw_data this tool does (verification, coercion checks, validation rules, compare,
+//   the TTL view, the query command) decodes it transparently.
+
+// Codecs (BLADE_RAW_DATA_CODEC):
+//   - none (default): Plain JSON text
+//   - zstd: zstd frame, base64 encoded, in the same STRING column; Databricks SQL reads
+//     it with CAST(zstd_decompress(unbase64(raw_data)) AS STRING) (DBR 15.2+ / DBSQL)
+//   - Rows written before a codec was chosen stay plain; decoding passes them through,
+//     so a table can mix both
+type RawDataCodec interface {
+	// Encodes a record's JSON for storage.
+	Encode(json []byte) (string, error)
+	// SQL expression storing the JSON text computed by expr (COPY INTO loads).
+	EncodeSQL(expr string) string
+	// Decodes a stored value; values the codec didn't produce are returned as they are.
+	Decode(stored string) (string, error)
+	// SQL expression returning the JSON text of the stored column, passing other values through.
+	DecodeSQL(column string) string
+}
+
+// Codec used when BLADE_RAW_DATA_CODEC is not set.
+const DefaultRawDataCodec = "none"
+
+var rawDataCodecs = map[string]RawDataCodec{
+	"none": plainCodec{},
+	"zstd": zstdCodec{},
+}
+
+// Makes a raw_data codec selectable via BLADE_RAW_DATA_CODEC. Later registrations replace earlier ones.
+//   - Decode must pass through values the codec didn't encode (plain JSON, other codecs)
+func RegisterRawDataCodec(name string, codec RawDataCodec) {
+	rawDataCodecs[strings.ToLower(name)] = codec
+}
+
+// Returns the registered raw_data codec names in sorted order.
+func RawDataCodecs() []string {
+	names := make([]string, 0, len(rawDataCodecs))
+	for name := range rawDataCodecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolves a codec by name ("" = DefaultRawDataCodec).
+func rawDataCodec(name string) (string, RawDataCodec, error) {
+	name = strings.ToLower(name)
+	if name == "" {
+		name = DefaultRawDataCodec
+	}
+	codec, exists := rawDataCodecs[name]
+	if !exists {
+		return "", nil, fmt.Errorf("unsupported BLADE_RAW_DATA_CODEC %q (supported: %s)", name, strings.Join(RawDataCodecs(), ", "))
+	}
+	return name, codec, nil
+}
+
+// Returns the JSON text of a stored raw_data value, whichever registered codec wrote it.
+func DecodeRawData(stored string) (string, error) {
+	decoded := stored
+	for _, name := range RawDataCodecs() {
+		var err error
+		if decoded, err = rawDataCodecs[name].Decode(decoded); err != nil {
+			return "", fmt.Errorf("failed to decode raw_data (%s): %w", name, err)
+		}
+	}
+	return decoded, nil
+}
+
+// Returns the table as a SQL source whose raw_data column holds JSON text, for
+// statements reading raw_data (the table itself when the codec stores plain JSON).
+func (c *Client) decodedSource(table string) string {
+	decoded := c.rawDataCodec.DecodeSQL("raw_data")
+	if decoded == "raw_data" {
+		return table
+	}
+	return fmt.Sprintf("(SELECT * EXCEPT (raw_data), %s AS raw_data FROM %s) AS decoded", decoded, table)
+}
+
+type plainCodec struct{}
+
+func (plainCodec) Encode(json []byte) (string, error)   { return string(json), nil }
+func (plainCodec) EncodeSQL(expr string) string         { return expr }
+func (plainCodec) Decode(stored string) (string, error) { return stored, nil }
+func (plainCodec) DecodeSQL(column string) string       { return column }
+
+// Base64 of the zstd frame magic number (28 B5 2F FD); JSON text never starts with it.
+const zstdBase64Prefix = "KLUv/"
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+type zstdCodec struct{}
+
+func (zstdCodec) Encode(json []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(zstdEncoder.EncodeAll(json, nil)), nil
+}
+
+func (zstdCodec) EncodeSQL(expr string) string {
+	return fmt.Sprintf("base64(zstd_compress(%s))", expr)
+}
+
+func (zstdCodec) Decode(stored string) (string, error) {
+	if !strings.HasPrefix(stored, zstdBase64Prefix) {
+		return stored, nil
+	}
+	frame, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return "", err
+	}
+	decoded, err := zstdDecoder.DecodeAll(frame, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+func (zstdCodec) DecodeSQL(column string) string {
+	return fmt.Sprintf("CASE WHEN startswith(%[1]s, '%[2]s') THEN CAST(zstd_decompress(unbase64(%[1]s)) AS STRING) ELSE %[1]s END", column, zstdBase64Prefix)
+}
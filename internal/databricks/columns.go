@@ -0,0 +1,55 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// A column of a table as Unity Catalog reports it.
+type ColumnInfo struct {
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+	Nullable bool   `json:"nullable"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Lists the columns of a table in the client's catalog/schema in ordinal order (empty when the table doesn't exist).
+//   - Answered from the run's query cache (if any) until the next write to the table
+func (c *Client) DescribeColumns(ctx context.Context, tableName string) ([]ColumnInfo, error) {
+	resp, err := c.cachedStatement(ctx, fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, tableName), sql.ExecuteStatementRequest{
+		Statement: `
+			SELECT column_name, full_data_type, is_nullable, comment
+			FROM system.information_schema.columns
+			WHERE table_catalog = :catalog AND table_schema = :schema AND table_name = :table
+			ORDER BY ordinal_position
+		`,
+		Parameters: []sql.StatementParameterListItem{
+			stringParam("catalog", c.catalog),
+			stringParam("schema", c.schema),
+			stringParam("table", tableName),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe columns of %s.%s.%s: %w", c.catalog, c.schema, tableName, err)
+	}
+
+	var rows [][]string
+	if resp.Result != nil {
+		rows = resp.Result.DataArray
+	}
+	columns := make([]ColumnInfo, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 4 {
+			continue
+		}
+		columns = append(columns, ColumnInfo{
+			Name:     row[0],
+			DataType: row[1],
+			Nullable: row[2] == "YES",
+			Comment:  row[3],
+		})
+	}
+	return columns, nil
+}
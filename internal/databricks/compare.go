@@ -0,0 +1,203 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Checks that two loads hold the same data, e.g. a COPY INTO load against
+//   an INSERT load of the same source. Rows are matched by item_id and compared on the
+//   standard columns; load-specific columns (ingestion_timestamp, metadata) are ignored.
+
+//   Sides:
+//   - "table", "schema.table" or "catalog.schema.table"; unqualified parts default to
+//     the client's namespace
+//   - "@batchID" narrows a side to one batch (metadata['batch_id'])
+//   - When a side holds several rows for an item_id (re-deliveries), the most recently
+//     ingested one is compared; rows without an item_id are ignored
+
+// Columns compared between the two sides, in report order.
+var CompareColumns = []string{"item_type", "classification_marking", "timestamp", "data_source", "raw_data"}
+
+// One side of a comparison: a table, optionally narrowed to one batch.
+type CompareSide struct {
+	Table   string `json:"table"` // one to three dot-separated identifiers
+	BatchID string `json:"batchId,omitempty"`
+}
+
+// Parses "table[@batchID]".
+func ParseCompareSide(spec string) (CompareSide, error) {
+	table, batchID, _ := strings.Cut(strings.TrimSpace(spec), "@")
+	if err := checkTableReference(table); err != nil {
+		return CompareSide{}, err
+	}
+	return CompareSide{Table: table, BatchID: strings.TrimSpace(batchID)}, nil
+}
+
+// Checks a "table", "schema.table" or "catalog.schema.table" reference (see qualifiedTable).
+func checkTableReference(table string) error {
+	parts := strings.Split(table, ".")
+	if len(parts) > 3 {
+		return fmt.Errorf("invalid table %q: use table, schema.table or catalog.schema.table", table)
+	}
+	for _, part := range parts {
+		if !tableNamePattern.MatchString(part) {
+			return fmt.Errorf("invalid table %q: use table, schema.table or catalog.schema.table", table)
+		}
+	}
+	return nil
+}
+
+// Returns the side as written on the command line.
+func (s CompareSide) String() string {
+	if s.BatchID == "" {
+		return s.Table
+	}
+	return s.Table + "@" + s.BatchID
+}
+
+// A row that differs between the two sides.
+//   - Change: "added" (only right), "removed" (only left) or "changed"
+//   - Fields: Compared columns whose values differ (changed rows only)
+type RowDiff struct {
+	ItemID string   `json:"itemId"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Aggregate and row-level differences between two sides.
+//   - FieldChanges: Changed rows per compared column
+//   - Rows: Up to the requested limit of differing rows, ordered by item_id
+type CompareResult struct {
+	Left         CompareSide      `json:"left"`
+	Right        CompareSide      `json:"right"`
+	LeftRows     int64            `json:"leftRows"`
+	RightRows    int64            `json:"rightRows"`
+	Added        int64            `json:"added"`
+	Removed      int64            `json:"removed"`
+	Changed      int64            `json:"changed"`
+	Unchanged    int64            `json:"unchanged"`
+	FieldChanges map[string]int64 `json:"fieldChanges"`
+	Rows         []RowDiff        `json:"rows,omitempty"`
+}
+
+// Reports whether both sides hold the same rows.
+func (r *CompareResult) Identical() bool {
+	return r.Added == 0 && r.Removed == 0 && r.Changed == 0
+}
+
+// Compares two sides by item_id and returns counts plus up to limit differing rows.
+func (c *Client) Compare(ctx context.Context, left, right CompareSide, limit int) (*CompareResult, error) {
+	// Diff:
+	// - Both sides are deduplicated per item_id, then full-outer-joined on it
+	// - Batch filters are bound parameters; an empty batch means the whole table
+	// - <=> compares NULLs as equal, so a field missing on both sides is no change
+	var differs, fields []string
+	for _, column := range CompareColumns {
+		differs = append(differs, fmt.Sprintf("NOT (l.%s <=> r.%s)", column, column))
+		fields = append(fields, fmt.Sprintf("IF(NOT (l.%s <=> r.%s), '%s', NULL)", column, column, column))
+	}
+	side := func(s CompareSide, param string) string {
+		return fmt.Sprintf(`
+			SELECT item_id, %s FROM %s
+			WHERE item_id IS NOT NULL AND (:%s = '' OR metadata['batch_id'] = :%s)
+			QUALIFY row_number() OVER (PARTITION BY item_id ORDER BY ingestion_timestamp DESC) = 1`,
+			strings.Join(CompareColumns, ", "), c.decodedSource(c.qualifiedTable(s.Table)), param, param)
+	}
+	diffCTE := fmt.Sprintf(`
+		WITH l AS (%s),
+		r AS (%s),
+		d AS (
+			SELECT
+				coalesce(l.item_id, r.item_id) AS item_id,
+				CASE
+					WHEN l.item_id IS NULL THEN 'added'
+					WHEN r.item_id IS NULL THEN 'removed'
+					WHEN %s THEN 'changed'
+					ELSE 'unchanged'
+				END AS change,
+				concat_ws(',', %s) AS fields
+			FROM l FULL OUTER JOIN r ON l.item_id = r.item_id
+		)`, side(left, "left_batch"), side(right, "right_batch"), strings.Join(differs, " OR "), strings.Join(fields, ", "))
+	params := []sql.StatementParameterListItem{
+		stringParam("left_batch", left.BatchID),
+		stringParam("right_batch", right.BatchID),
+	}
+	params[0].ForceSendFields = []string{"Value"}
+	params[1].ForceSendFields = []string{"Value"}
+
+	// Aggregates: one row of side sizes, change counts and per-column change counts
+	var fieldCounts []string
+	for _, column := range CompareColumns {
+		fieldCounts = append(fieldCounts, fmt.Sprintf("count_if(change = 'changed' AND array_contains(split(fields, ','), '%s'))", column))
+	}
+	aggregateSQL := diffCTE + fmt.Sprintf(`
+		SELECT
+			count_if(change <> 'added'), count_if(change <> 'removed'),
+			count_if(change = 'added'), count_if(change = 'removed'),
+			count_if(change = 'changed'), count_if(change = 'unchanged'),
+			%s
+		FROM d`, strings.Join(fieldCounts, ", "))
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{Statement: aggregateSQL, Parameters: params})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s with %s: %w", left, right, err)
+	}
+	if len(rows) == 0 || len(rows[0]) < 6+len(CompareColumns) {
+		return nil, fmt.Errorf("failed to compare %s with %s: no result", left, right)
+	}
+	counts := make([]int64, len(rows[0]))
+	for i, value := range rows[0] {
+		if counts[i], err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("failed to parse comparison count %q: %w", value, err)
+		}
+	}
+	result := &CompareResult{
+		Left: left, Right: right,
+		LeftRows: counts[0], RightRows: counts[1],
+		Added: counts[2], Removed: counts[3], Changed: counts[4], Unchanged: counts[5],
+		FieldChanges: make(map[string]int64, len(CompareColumns)),
+	}
+	for i, column := range CompareColumns {
+		result.FieldChanges[column] = counts[6+i]
+	}
+	if result.Identical() || limit <= 0 {
+		return result, nil
+	}
+
+	// Rows: the first limit differences by item_id
+	rowsSQL := diffCTE + fmt.Sprintf(`
+		SELECT item_id, change, fields FROM d
+		WHERE change <> 'unchanged'
+		ORDER BY item_id
+		LIMIT %d`, limit)
+	rows, err = c.queryRows(ctx, sql.ExecuteStatementRequest{Statement: rowsSQL, Parameters: params})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list differences between %s and %s: %w", left, right, err)
+	}
+	for _, row := range rows {
+		if len(row) < 3 {
+			continue
+		}
+		diff := RowDiff{ItemID: row[0], Change: row[1]}
+		if row[2] != "" {
+			diff.Fields = strings.Split(row[2], ",")
+		}
+		result.Rows = append(result.Rows, diff)
+	}
+	return result, nil
+}
+
+// Qualifies a one- or two-part table name with the client's catalog (and schema).
+func (c *Client) qualifiedTable(table string) string {
+	switch strings.Count(table, ".") {
+	case 0:
+		return fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table)
+	case 1:
+		return c.catalog + "." + table
+	}
+	return table
+}
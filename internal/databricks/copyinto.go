@@ -0,0 +1,233 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/files"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Loads real BLADE export files instead of the mock records carried in
+//   the request. The files are read server-side by COPY INTO from a Unity Catalog
+//   Volume, so no record content passes through the client or the statement API.
+
+//   Source Paths (IngestionRequest.SourcePath in copy_into mode):
+//   - "/Volumes/catalog/schema/volume/..." is loaded in place (a file or a directory)
+//   - Anything else is a local file or directory, uploaded first to
+//     {BLADE_VOLUME_PATH}/{dataType}/{batchID}/ and loaded from there
+//   - COPY INTO skips files it already loaded into the table, so re-running an
+//     in-place path only picks up new files; uploads always land in a fresh directory
+
+// Request mode that loads SourcePath with COPY INTO instead of inserting SampleData.
+const ModeCopyInto = "copy_into"
+
+// Prefix of Unity Catalog Volume paths.
+const volumePathPrefix = "/Volumes/"
+
+var (
+	// File formats COPY INTO reads that the standard BLADE table can be built from
+	copyFileFormats = map[string]bool{"JSON": true, "CSV": true, "PARQUET": true}
+
+	// FORMAT_OPTIONS is interpolated into the statement: only 'key' = 'value' pairs
+	formatOptionsPattern = regexp.MustCompile(`^\s*'[A-Za-z0-9_.]+'\s*=\s*'[^'\\]*'\s*(,\s*'[A-Za-z0-9_.]+'\s*=\s*'[^'\\]*'\s*)*$`)
+)
+
+// Checks the copy_into specific fields of a request.
+func (r *IngestionRequest) validateCopyInto() error {
+	if strings.TrimSpace(r.SourcePath) == "" {
+		return fmt.Errorf("source path is required in %s mode", ModeCopyInto)
+	}
+	if !copyFileFormats[strings.ToUpper(r.FileFormat)] {
+		return fmt.Errorf("unsupported file format %q for %s (supported: JSON, CSV, PARQUET)", r.FileFormat, ModeCopyInto)
+	}
+	if r.FormatOptions != "" && !formatOptionsPattern.MatchString(r.FormatOptions) {
+		return fmt.Errorf("invalid format options %q: use 'key' = 'value' pairs separated by commas", r.FormatOptions)
+	}
+	for field, column := range r.SourceColumns {
+		if strings.ContainsAny(field+column, "`'\n") || column == "" {
+			return fmt.Errorf("invalid source column %q for field %s", column, field)
+		}
+	}
+	return nil
+}
+
+// Loads the request's files into its table with one COPY INTO and returns the rows inserted and the path loaded.
+func (c *Client) copyIntoTable(ctx context.Context, req *IngestionRequest, batchID string) (int64, string, error) {
+	source := req.SourcePath
+	if !strings.HasPrefix(source, volumePathPrefix) {
+		uploaded, err := c.uploadToVolume(ctx, req, batchID)
+		if err != nil {
+			return 0, "", err
+		}
+		source = uploaded
+	}
+
+	// Transformation:
+	// - Maps each file record onto the standard BLADE columns, like insertRecords;
+	//   the whole record is kept as JSON in raw_data (encoded by the raw_data codec)
+	// - Batch-level values are literals: COPY INTO doesn't take parameter markers
+	//   in its source query
+	// - A TTL policy's expires_at is computed from the record's field server-side
+	// - Typed columns are cast from their file fields
+	// - With SourceColumns (Advana snapshots), fields are read from the renamed file
+	//   columns and raw_data holds the record under the BLADE field names
+	expiresAt := ""
+	if req.TTL != nil {
+		expiresAt = ", 'expires_at', " + req.TTL.expiresAtSQL()
+	}
+	sourceKind, snapshot := "blade_file", ""
+	if req.SourceColumns != nil {
+		sourceKind = "advana_snapshot"
+		if id := req.Metadata["snapshot_id"]; id != "" {
+			snapshot = ", 'snapshot_id', " + sqlString(id)
+		}
+	}
+	metadata := fmt.Sprintf("map('source', %s, 'batch_id', %s, 'data_type', %s, 'tenant', %s, 'source_path', %s, 'source_format', %s, 'source_version', %s%s%s)",
+		sqlString(sourceKind), sqlString(batchID), sqlString(req.Metadata["data_type"]), sqlString(c.tenant), sqlString(req.SourcePath),
+		sqlString(req.Metadata["source_format"]), sqlString(req.Metadata["source_version"]), snapshot, expiresAt)
+	formatOptions := ""
+	if req.FormatOptions != "" {
+		formatOptions = fmt.Sprintf("FORMAT_OPTIONS (%s)", req.FormatOptions)
+	}
+	copySQL := fmt.Sprintf(`
+		COPY INTO %s.%s.%s
+		FROM (
+			SELECT
+				CAST(%s AS STRING) AS item_id,
+				CAST(%s AS STRING) AS item_type,
+				CAST(%s AS STRING) AS classification_marking,
+				CAST(%s AS TIMESTAMP) AS timestamp,
+				%s AS data_source,
+				%s AS raw_data,
+				current_timestamp() AS ingestion_timestamp,
+				%s AS metadata%s
+			FROM %s
+		)
+		FILEFORMAT = %s
+		%s
+	`, c.catalog, c.schema, req.TableName,
+		fileColumn(req.SourceColumns, "item_id"), fileColumn(req.SourceColumns, "item_type"),
+		fileColumn(req.SourceColumns, "classification_marking"), fileColumn(req.SourceColumns, "timestamp"),
+		sqlString(req.DataSource), c.rawDataCodec.EncodeSQL(rawRecordSQL(req.SourceColumns)), metadata, typedColumnSelect(req.Columns, req.FileFormat, req.SourceColumns), sqlString(source),
+		strings.ToUpper(req.FileFormat), formatOptions)
+
+	runlog.Printf(ctx, "Executing COPY INTO %s.%s.%s from %s", c.catalog, c.schema, req.TableName, source)
+	resp, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement:   copySQL,
+		WarehouseId: c.warehouseID,
+		Catalog:     c.catalog,
+		Schema:      c.schema,
+	})
+	if err != nil {
+		return 0, source, fmt.Errorf("failed to copy %s into %s: %w", source, req.TableName, err)
+	}
+
+	rows, err := copiedRows(resp)
+	if err != nil {
+		return 0, source, err
+	}
+	runlog.Printf(ctx, "COPY INTO loaded %d rows from %s", rows, source)
+	return rows, source, nil
+}
+
+// Returns the quoted file column holding field: its SourceColumns entry, else the field itself.
+func fileColumn(sourceColumns map[string]string, field string) string {
+	if column, ok := sourceColumns[field]; ok {
+		return fmt.Sprintf("`%s`", column)
+	}
+	return fmt.Sprintf("`%s`", field)
+}
+
+// Returns the SQL expression of a file record's JSON.
+//   - Without SourceColumns the record is kept as it is in the file
+//   - With them only the mapped columns are kept, under their BLADE field names
+func rawRecordSQL(sourceColumns map[string]string) string {
+	if sourceColumns == nil {
+		return "to_json(struct(*))"
+	}
+	fields := make([]string, 0, len(sourceColumns))
+	for field := range sourceColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	pairs := make([]string, len(fields))
+	for i, field := range fields {
+		pairs[i] = fmt.Sprintf("%s, %s", sqlString(field), fileColumn(sourceColumns, field))
+	}
+	return fmt.Sprintf("to_json(named_struct(%s))", strings.Join(pairs, ", "))
+}
+
+// Uploads the local file, or every file of the local directory, to this batch's Volume directory.
+func (c *Client) uploadToVolume(ctx context.Context, req *IngestionRequest, batchID string) (string, error) {
+	if c.readOnly {
+		return "", fmt.Errorf("%w: files can't be uploaded", ErrReadOnly)
+	}
+	if c.volumePath == "" {
+		return "", fmt.Errorf("local source %s needs BLADE_VOLUME_PATH to upload to", req.SourcePath)
+	}
+
+	info, err := os.Stat(req.SourcePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read source %s: %w", req.SourcePath, err)
+	}
+	localFiles := []string{req.SourcePath}
+	if info.IsDir() {
+		entries, err := os.ReadDir(req.SourcePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to list source %s: %w", req.SourcePath, err)
+		}
+		localFiles = nil
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				localFiles = append(localFiles, filepath.Join(req.SourcePath, entry.Name()))
+			}
+		}
+		if len(localFiles) == 0 {
+			return "", fmt.Errorf("source directory %s has no files to load", req.SourcePath)
+		}
+	}
+
+	dir := path.Join(strings.TrimRight(c.volumePath, "/"), req.Metadata["data_type"], batchID)
+	for _, local := range localFiles {
+		target := path.Join(dir, filepath.Base(local))
+		f, err := os.Open(local)
+		if err != nil {
+			return "", fmt.Errorf("failed to open %s: %w", local, err)
+		}
+		runlog.Printf(ctx, "Uploading %s to %s", local, target)
+		err = c.workspace.Files.Upload(ctx, files.UploadRequest{FilePath: target, Contents: f, Overwrite: true})
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to upload %s to %s: %w", local, target, err)
+		}
+	}
+	return dir, nil
+}
+
+// Reads num_inserted_rows from a COPY INTO result.
+func copiedRows(resp *sql.StatementResponse) (int64, error) {
+	if resp.Result == nil || len(resp.Result.DataArray) == 0 || resp.Manifest == nil || resp.Manifest.Schema == nil {
+		return 0, fmt.Errorf("COPY INTO returned no row counts")
+	}
+	row := resp.Result.DataArray[0]
+	for i, column := range resp.Manifest.Schema.Columns {
+		if column.Name != "num_inserted_rows" || i >= len(row) {
+			continue
+		}
+		rows, err := strconv.ParseInt(row[i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse COPY INTO row count %q: %w", row[i], err)
+		}
+		return rows, nil
+	}
+	return 0, fmt.Errorf("COPY INTO result has no num_inserted_rows column")
+}
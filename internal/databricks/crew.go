@@ -0,0 +1,115 @@
+package databricks
+
+import "strings"
+
+// Child table holding one row per crew member assigned to a sortie, and the exploder
+// (ChildTable.Exploder) that fills it.
+const (
+	SortieCrewTable    = "blade_sortie_crew"
+	SortieCrewExploder = "sortie_crew"
+)
+
+// A single crew assignment exploded out of a sortie record.
+type CrewMember struct {
+	SortieID   string `json:"sortieId"` // item_id of the parent sortie
+	TailNumber string `json:"tailNumber,omitempty"`
+	Callsign   string `json:"callsign,omitempty"`
+	Name       string `json:"name"`
+	Role       string `json:"role"`     // pilot, copilot, crew, or the role given in the source
+	Position   int    `json:"position"` // order of the member within the sortie
+}
+
+// Explodes the crew assignments of sortie records into one CrewMember per person.
+//
+// Crew is read from each aircraft (pilot, copilot and its crew array) and from a
+// sortie-level crew array. Crew array entries may be names or objects with
+// name/role fields. Records without an item_id are skipped.
+func ExplodeSortieCrew(records []map[string]interface{}) []CrewMember {
+	var members []CrewMember
+	for _, record := range records {
+		sortieID, _ := record["item_id"].(string)
+		if sortieID == "" {
+			continue
+		}
+		position := 0
+		add := func(member CrewMember) {
+			if member.Name == "" {
+				return
+			}
+			position++
+			member.SortieID, member.Position = sortieID, position
+			members = append(members, member)
+		}
+
+		aircraft, _ := record["aircraft"].([]interface{})
+		for _, entry := range aircraft {
+			plane, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tail, _ := plane["tail_number"].(string)
+			callsign, _ := plane["callsign"].(string)
+			for _, role := range []string{"pilot", "copilot"} {
+				name, _ := plane[role].(string)
+				add(CrewMember{TailNumber: tail, Callsign: callsign, Name: name, Role: role})
+			}
+			for _, member := range crewEntries(plane["crew"]) {
+				member.TailNumber, member.Callsign = tail, callsign
+				add(member)
+			}
+		}
+		for _, member := range crewEntries(record["crew"]) {
+			add(member)
+		}
+	}
+	return members
+}
+
+// Reads a crew array whose entries are either plain names or {name, role, tail_number, callsign} objects.
+func crewEntries(value interface{}) []CrewMember {
+	entries, _ := value.([]interface{})
+	var members []CrewMember
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case string:
+			members = append(members, CrewMember{Name: strings.TrimSpace(entry), Role: "crew"})
+		case map[string]interface{}:
+			member := CrewMember{Role: "crew"}
+			member.Name, _ = entry["name"].(string)
+			if role, _ := entry["role"].(string); role != "" {
+				member.Role = strings.ToLower(role)
+			}
+			member.TailNumber, _ = entry["tail_number"].(string)
+			member.Callsign, _ = entry["callsign"].(string)
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// Columns of blade_sortie_crew after sortie_id, batch_id and position.
+var sortieCrewColumns = []TypedColumn{
+	{Name: "tail_number", Type: "STRING"},
+	{Name: "callsign", Type: "STRING"},
+	{Name: "member_name", Type: "STRING"},
+	{Name: "role", Type: "STRING"},
+}
+
+// Child rows of blade_sortie_crew (the sortie_crew exploder).
+func sortieCrewRows(records []map[string]interface{}) []ChildRow {
+	members := ExplodeSortieCrew(records)
+	rows := make([]ChildRow, 0, len(members))
+	for _, member := range members {
+		rows = append(rows, ChildRow{
+			ParentID: member.SortieID,
+			Position: member.Position,
+			Fields: map[string]string{
+				"tail_number": member.TailNumber,
+				"callsign":    member.Callsign,
+				"member_name": member.Name,
+				"role":        member.Role,
+			},
+		})
+	}
+	return rows
+}
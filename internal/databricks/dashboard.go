@@ -0,0 +1,217 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/dashboards"
+)
+
+// Describes the dashboard created by BootstrapDashboard.
+type DashboardInfo struct {
+	DashboardID string `json:"dashboardId"`
+	DisplayName string `json:"displayName"`
+	Path        string `json:"path"`
+	URL         string `json:"url"`
+	Published   bool   `json:"published"`
+}
+
+// Minimal subset of the Lakeview serialized dashboard format (.lvdash.json).
+type lvDashboard struct {
+	Datasets []lvDataset `json:"datasets"`
+	Pages    []lvPage    `json:"pages"`
+}
+
+type lvDataset struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	QueryLines  []string `json:"queryLines"`
+}
+
+type lvPage struct {
+	Name        string           `json:"name"`
+	DisplayName string           `json:"displayName"`
+	Layout      []lvLayoutWidget `json:"layout"`
+}
+
+type lvLayoutWidget struct {
+	Widget   lvWidget   `json:"widget"`
+	Position lvPosition `json:"position"`
+}
+
+type lvPosition struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type lvWidget struct {
+	Name    string          `json:"name"`
+	Queries []lvNamedQuery  `json:"queries"`
+	Spec    json.RawMessage `json:"spec"`
+}
+
+type lvNamedQuery struct {
+	Name  string  `json:"name"`
+	Query lvQuery `json:"query"`
+}
+
+type lvQuery struct {
+	DatasetName   string    `json:"datasetName"`
+	Fields        []lvField `json:"fields"`
+	Disaggregated bool      `json:"disaggregated"`
+}
+
+type lvField struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// Creates (and optionally publishes) a Lakeview dashboard over the given BLADE tables
+// showing row counts over time, the latest batches, and data quality failures.
+func (c *Client) BootstrapDashboard(ctx context.Context, displayName, parentPath string, tables []string, publish bool) (*DashboardInfo, error) {
+	if c.readOnly {
+		return nil, fmt.Errorf("%w: dashboards can't be created", ErrReadOnly)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables to build a dashboard for")
+	}
+
+	serialized, err := json.Marshal(c.bladeDashboard(tables))
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize dashboard: %w", err)
+	}
+
+	// Dashboard Creation:
+	// - Draft is created in parentPath (or the caller's home folder when empty)
+	// - Bound to the client's warehouse so datasets run without extra setup
+	dashboard, err := c.workspace.Lakeview.Create(ctx, dashboards.CreateDashboardRequest{
+		Dashboard: dashboards.Dashboard{
+			DisplayName:         displayName,
+			ParentPath:          parentPath,
+			WarehouseId:         c.warehouseID,
+			SerializedDashboard: string(serialized),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dashboard %q: %w", displayName, err)
+	}
+	runlog.Printf(ctx, "Created Lakeview dashboard %s (%s)", dashboard.DashboardId, dashboard.Path)
+
+	info := &DashboardInfo{
+		DashboardID: dashboard.DashboardId,
+		DisplayName: dashboard.DisplayName,
+		Path:        dashboard.Path,
+		URL:         strings.TrimRight(c.workspace.Config.Host, "/") + "/dashboardsv3/" + dashboard.DashboardId,
+	}
+
+	if publish {
+		if _, err := c.workspace.Lakeview.Publish(ctx, dashboards.PublishRequest{
+			DashboardId:      dashboard.DashboardId,
+			WarehouseId:      c.warehouseID,
+			EmbedCredentials: false,
+		}); err != nil {
+			return info, fmt.Errorf("dashboard %s created but publishing failed: %w", dashboard.DashboardId, err)
+		}
+		info.Published = true
+	}
+
+	return info, nil
+}
+
+func (c *Client) bladeDashboard(tables []string) lvDashboard {
+	// Datasets:
+	// - row_counts: Rows landed per table per day (from ingestion_timestamp)
+	// - latest_batches: Most recent batch_ids with row counts and load time
+	// - quality_failures: Per-table violation counts for the default validation rules
+	var rowCounts, batches, quality []string
+	for _, table := range tables {
+		fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table)
+
+		rowCounts = append(rowCounts, fmt.Sprintf(
+			"SELECT '%s' AS table_name, date_trunc('DAY', ingestion_timestamp) AS ingest_day, COUNT(*) AS row_count FROM %s GROUP BY ALL",
+			table, fullName))
+
+		batches = append(batches, fmt.Sprintf(
+			"SELECT '%s' AS table_name, metadata['batch_id'] AS batch_id, MIN(ingestion_timestamp) AS loaded_at, COUNT(*) AS row_count FROM %s GROUP BY ALL",
+			table, fullName))
+
+		checks := []string{fmt.Sprintf("'%s' AS table_name", table)}
+		for _, rule := range DefaultValidationRules() {
+			checks = append(checks, fmt.Sprintf("SUM(CASE WHEN %s THEN 1 ELSE 0 END) AS %s", rule.Condition, rule.Name))
+		}
+		quality = append(quality, fmt.Sprintf("SELECT %s FROM %s", strings.Join(checks, ", "), fullName))
+	}
+
+	qualityFields := []string{"table_name"}
+	for _, rule := range DefaultValidationRules() {
+		qualityFields = append(qualityFields, rule.Name)
+	}
+
+	return lvDashboard{
+		Datasets: []lvDataset{
+			{Name: "row_counts", DisplayName: "Row counts over time", QueryLines: []string{strings.Join(rowCounts, "\nUNION ALL\n")}},
+			{Name: "latest_batches", DisplayName: "Latest batches", QueryLines: []string{strings.Join(batches, "\nUNION ALL\n") + "\nORDER BY loaded_at DESC\nLIMIT 50"}},
+			{Name: "quality_failures", DisplayName: "Quality failures", QueryLines: []string{strings.Join(quality, "\nUNION ALL\n")}},
+		},
+		Pages: []lvPage{{
+			Name:        "blade_overview",
+			DisplayName: "BLADE Ingestion",
+			Layout: []lvLayoutWidget{
+				{
+					Widget: lvWidget{
+						Name:    "row_counts_chart",
+						Queries: []lvNamedQuery{datasetQuery("row_counts", "table_name", "ingest_day", "row_count")},
+						Spec: json.RawMessage(`{"version":3,"widgetType":"line","frame":{"showTitle":true,"title":"Rows ingested per day"},` +
+							`"encodings":{"x":{"fieldName":"ingest_day","scale":{"type":"temporal"}},` +
+							`"y":{"fieldName":"row_count","scale":{"type":"quantitative"}},` +
+							`"color":{"fieldName":"table_name","scale":{"type":"categorical"}}}}`),
+					},
+					Position: lvPosition{X: 0, Y: 0, Width: 6, Height: 6},
+				},
+				{
+					Widget: lvWidget{
+						Name:    "latest_batches_table",
+						Queries: []lvNamedQuery{datasetQuery("latest_batches", "table_name", "batch_id", "loaded_at", "row_count")},
+						Spec:    tableSpec("Latest batches", "table_name", "batch_id", "loaded_at", "row_count"),
+					},
+					Position: lvPosition{X: 0, Y: 6, Width: 3, Height: 6},
+				},
+				{
+					Widget: lvWidget{
+						Name:    "quality_failures_table",
+						Queries: []lvNamedQuery{datasetQuery("quality_failures", qualityFields...)},
+						Spec:    tableSpec("Quality failures", qualityFields...),
+					},
+					Position: lvPosition{X: 3, Y: 6, Width: 3, Height: 6},
+				},
+			},
+		}},
+	}
+}
+
+func datasetQuery(dataset string, fields ...string) lvNamedQuery {
+	query := lvQuery{DatasetName: dataset, Disaggregated: true}
+	for _, field := range fields {
+		query.Fields = append(query.Fields, lvField{Name: field, Expression: "`" + field + "`"})
+	}
+	return lvNamedQuery{Name: "main_query", Query: query}
+}
+
+func tableSpec(title string, fields ...string) json.RawMessage {
+	columns := make([]map[string]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, map[string]string{"fieldName": field, "title": field})
+	}
+	spec, _ := json.Marshal(map[string]interface{}{
+		"version":    1,
+		"widgetType": "table",
+		"frame":      map[string]interface{}{"showTitle": true, "title": title},
+		"encodings":  map[string]interface{}{"columns": columns},
+	})
+	return spec
+}
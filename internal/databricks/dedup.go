@@ -0,0 +1,127 @@
+package databricks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: BLADE re-delivers overlapping extracts (a daily export repeats the last
+//   week), so without deduplication the same record piles up once per delivery. A dedup
+//   policy hashes each record, stamps the hash into metadata['content_hash'], and skips
+//   records whose hash the target table already holds (or that repeat within the batch).
+
+// Content-hash deduplication of a table's records.
+//   - Fields: Record fields that identify a record's content, hashed in this order; empty
+//     hashes the whole record, so any changed field makes it a new row
+//   - Applies to record loads; COPY INTO loads aren't deduplicated (COPY INTO itself
+//     skips files it already loaded)
+type DedupPolicy struct {
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Number of hashes looked up per statement.
+const dedupLookupSize = 500
+
+// Checks the policy of the table it is declared on.
+func (p DedupPolicy) validate(table string) error {
+	for _, field := range p.Fields {
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("table %s has an empty dedup field", table)
+		}
+	}
+	return nil
+}
+
+// Returns the record's content hash (hex SHA-256).
+//   - Values are hashed as JSON, so 42 and "42" differ and a missing field equals null
+func (p DedupPolicy) hash(record map[string]interface{}) string {
+	hash := sha256.New()
+	if len(p.Fields) == 0 {
+		// - encoding/json sorts map keys, so equal records always encode the same
+		encoded, _ := json.Marshal(record)
+		hash.Write(encoded)
+	}
+	for _, field := range p.Fields {
+		encoded, _ := json.Marshal(record[field])
+		hash.Write(encoded)
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Drops the request's records already in its table and repeats within the batch.
+//   - Returns a copy of the request holding only the new records, so the insert, crew,
+//     child tables and verification all see the same set, and the number skipped
+func (c *Client) dedupRecords(ctx context.Context, req *IngestionRequest) (*IngestionRequest, int64, error) {
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse sample data: %w", err)
+	}
+	hashes := make([]string, len(records))
+	for i, record := range records {
+		hashes[i] = req.Dedup.hash(record)
+	}
+	existing, err := c.existingHashes(ctx, req.TableName, hashes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	kept := make([]map[string]interface{}, 0, len(records))
+	for i, record := range records {
+		if existing[hashes[i]] {
+			continue
+		}
+		existing[hashes[i]] = true
+		kept = append(kept, record)
+	}
+	skipped := int64(len(records) - len(kept))
+	if skipped == 0 {
+		return req, 0, nil
+	}
+	runlog.Printf(ctx, "Skipping %d of %d records already in %s (content hash)", skipped, len(records), req.TableName)
+
+	sampleData, err := json.Marshal(kept)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode deduplicated records: %w", err)
+	}
+	deduped := *req
+	deduped.SampleData = string(sampleData)
+	return &deduped, skipped, nil
+}
+
+// Returns which of the hashes rows of the table already carry in metadata['content_hash'].
+func (c *Client) existingHashes(ctx context.Context, tableName string, hashes []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for offset := 0; offset < len(hashes); offset += dedupLookupSize {
+		end := min(offset+dedupLookupSize, len(hashes))
+		var params paramList
+		placeholders := make([]string, 0, end-offset)
+		for _, hash := range hashes[offset:end] {
+			placeholders = append(placeholders, params.text(hash))
+		}
+		rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+			Statement: fmt.Sprintf(`
+				SELECT DISTINCT metadata['content_hash']
+				FROM %s.%s.%s
+				WHERE metadata['content_hash'] IN (%s)
+			`, c.catalog, c.schema, tableName, strings.Join(placeholders, ", ")),
+			Parameters: params.params,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up content hashes in %s: %w", tableName, err)
+		}
+		for _, row := range rows {
+			if len(row) > 0 {
+				existing[row[0]] = true
+			}
+		}
+	}
+	return existing, nil
+}
@@ -0,0 +1,213 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: TestConnection only says that SELECT 1 ran. When a load then fails, the
+//   cause is one of a handful of things further down the chain: the credentials, the
+//   warehouse, a missing catalog or schema, a table that was never created or a missing
+//   write grant. Diagnose walks that chain in order and reports each step, so one call
+//   (the diagnose command) shows where it breaks.
+
+//   Checks (in order):
+//   - auth: The workspace accepts the credentials (the principal is resolved)
+//   - warehouse: Its state, and SELECT 1 runs on it (like TestConnection)
+//   - catalog / schema: They exist (information_schema); a missing schema is a warning,
+//     ingest creates it when the principal may
+//   - table: Each given table exists; a missing one is a warning, ingest creates it
+//   - write: A probe table is created in the schema, written and dropped again
+//     (skipped in read-only mode)
+//   - A failed check skips the checks that depend on it
+
+// Outcomes of a diagnostic check.
+const (
+	DiagnosticPass = "PASS"
+	DiagnosticWarn = "WARN"
+	DiagnosticFail = "FAIL"
+	DiagnosticSkip = "SKIP"
+)
+
+// Prefix of the write probe's table, followed by a unique suffix.
+const diagnoseProbePrefix = "_blade_diagnose_"
+
+// One step of Diagnose.
+//   - Name: auth, warehouse, catalog, schema, table or write
+//   - Target: What was checked (principal, warehouse ID, catalog, table name, ...)
+//   - Remediation: What to do about a recognized failure (see Remediate)
+type DiagnosticCheck struct {
+	Name        string        `json:"name"`
+	Target      string        `json:"target,omitempty"`
+	Status      string        `json:"status"`
+	Detail      string        `json:"detail,omitempty"`
+	Remediation *Remediation  `json:"remediation,omitempty"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// Outcome of Diagnose.
+type DiagnosticReport struct {
+	Principal string            `json:"principal,omitempty"`
+	Warehouse string            `json:"warehouse"`
+	Catalog   string            `json:"catalog"`
+	Schema    string            `json:"schema"`
+	Checks    []DiagnosticCheck `json:"checks"`
+	Duration  time.Duration     `json:"duration"`
+}
+
+// Reports whether no check failed (warnings and skips aside).
+func (r *DiagnosticReport) OK() bool {
+	for _, check := range r.Checks {
+		if check.Status == DiagnosticFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Runs the connection checks (see Checks) against the client's namespace and the given tables.
+//   - Failures are part of the report, not an error: an error means the tables were invalid
+func (c *Client) Diagnose(ctx context.Context, tables []string) (*DiagnosticReport, error) {
+	for _, table := range tables {
+		if !tableNamePattern.MatchString(table) {
+			return nil, fmt.Errorf("invalid table name %q", table)
+		}
+	}
+	start := time.Now()
+	report := &DiagnosticReport{Warehouse: c.warehouseID, Catalog: c.catalog, Schema: c.schema}
+
+	// - run times a check and turns a failure into its status, detail and remediation
+	failed := false
+	run := func(name, target string, check func() (status, detail string, err error)) {
+		if failed {
+			report.Checks = append(report.Checks, DiagnosticCheck{Name: name, Target: target, Status: DiagnosticSkip, Detail: "an earlier check failed"})
+			return
+		}
+		began := time.Now()
+		status, detail, err := check()
+		result := DiagnosticCheck{Name: name, Target: target, Status: status, Detail: detail}
+		if err != nil {
+			result.Status, result.Detail = DiagnosticFail, err.Error()
+			if remedy, ok := Remediate(err); ok {
+				result.Remediation = &remedy
+			}
+		}
+		result.Duration = time.Since(began)
+		failed = result.Status == DiagnosticFail
+		report.Checks = append(report.Checks, result)
+	}
+	params := []sql.StatementParameterListItem{stringParam("catalog", c.catalog), stringParam("schema", c.schema)}
+	schemaExists := false
+
+	run("auth", c.workspace.Config.Host, func() (string, string, error) {
+		principal, err := c.CurrentPrincipal(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		report.Principal = principal
+		return DiagnosticPass, "authenticated as " + principal, nil
+	})
+	run("warehouse", c.warehouseID, func() (string, string, error) {
+		state, err := c.WarehouseState(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		began := time.Now()
+		if err := c.TestConnection(ctx); err != nil {
+			return "", "", fmt.Errorf("%s, but SELECT 1 failed: %w", state, err)
+		}
+		return DiagnosticPass, fmt.Sprintf("%s, SELECT 1 in %s", state, time.Since(began).Round(time.Millisecond)), nil
+	})
+	run("catalog", c.catalog, func() (string, string, error) {
+		rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+			Statement:  "SELECT 1 FROM system.information_schema.catalogs WHERE catalog_name = lower(:catalog)",
+			Parameters: params[:1],
+		})
+		if err != nil {
+			return "", "", err
+		}
+		if len(rows) == 0 {
+			return "", "", fmt.Errorf("catalog %s doesn't exist or isn't visible to %s (CATALOG_NOT_FOUND)", c.catalog, report.Principal)
+		}
+		return DiagnosticPass, "exists", nil
+	})
+	run("schema", c.catalog+"."+c.schema, func() (string, string, error) {
+		rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+			Statement:  "SELECT 1 FROM system.information_schema.schemata WHERE catalog_name = lower(:catalog) AND schema_name = lower(:schema)",
+			Parameters: params,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		if len(rows) == 0 {
+			return DiagnosticWarn, "doesn't exist yet; ingest creates it (needs CREATE SCHEMA on the catalog)", nil
+		}
+		schemaExists = true
+		return DiagnosticPass, "exists", nil
+	})
+
+	existing := map[string]bool{}
+	if !failed && schemaExists && len(tables) > 0 {
+		rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+			Statement:  "SELECT table_name FROM system.information_schema.tables WHERE table_catalog = lower(:catalog) AND table_schema = lower(:schema)",
+			Parameters: params,
+		})
+		if err != nil {
+			run("table", strings.Join(tables, ", "), func() (string, string, error) { return "", "", err })
+		}
+		for _, row := range rows {
+			if len(row) > 0 {
+				existing[strings.ToLower(row[0])] = true
+			}
+		}
+	}
+	for _, table := range tables {
+		run("table", table, func() (string, string, error) {
+			if existing[strings.ToLower(table)] {
+				return DiagnosticPass, "exists", nil
+			}
+			return DiagnosticWarn, "not created yet; ingest creates it", nil
+		})
+	}
+
+	run("write", c.catalog+"."+c.schema, func() (string, string, error) {
+		switch {
+		case c.readOnly:
+			return DiagnosticSkip, "read-only mode (BLADE_READ_ONLY)", nil
+		case !schemaExists:
+			return DiagnosticSkip, "the schema doesn't exist yet", nil
+		}
+		return c.writeProbe(ctx)
+	})
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// Creates a probe table in the schema, inserts a row and drops it again.
+func (c *Client) writeProbe(ctx context.Context) (string, string, error) {
+	probe := fmt.Sprintf("%s.%s.%s%x", c.catalog, c.schema, diagnoseProbePrefix, time.Now().UnixNano())
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("CREATE TABLE %s (probe INT)", probe),
+	}); err != nil {
+		return "", "", fmt.Errorf("failed to create a table in %s.%s: %w", c.catalog, c.schema, err)
+	}
+	// - The probe is dropped even when the insert fails; a failed drop is reported, the table stays behind
+	_, insertErr := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("INSERT INTO %s VALUES (1)", probe),
+	})
+	_, dropErr := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: "DROP TABLE IF EXISTS " + probe,
+	})
+	if insertErr != nil {
+		return "", "", fmt.Errorf("created %s but failed to insert into it: %w", probe, insertErr)
+	}
+	if dropErr != nil {
+		return DiagnosticWarn, fmt.Sprintf("created and wrote %s, but failed to drop it (drop it by hand): %v", probe, dropErr), nil
+	}
+	return DiagnosticPass, "created, wrote and dropped a probe table", nil
+}
@@ -0,0 +1,45 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Returns the user or service principal name the client is authenticated as.
+func (c *Client) CurrentPrincipal(ctx context.Context) (string, error) {
+	me, err := c.workspace.CurrentUser.Me(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve current user: %w", err)
+	}
+	return me.UserName, nil
+}
+
+// Returns the state of the configured SQL warehouse (RUNNING, STOPPED, ...).
+func (c *Client) WarehouseState(ctx context.Context) (string, error) {
+	warehouse, err := c.workspace.Warehouses.GetById(ctx, c.warehouseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get warehouse %s: %w", c.warehouseID, err)
+	}
+	return string(warehouse.State), nil
+}
+
+// Returns how many personal access tokens the principal owns and when the last of them
+// expires; the token in use is one of them, so it expires by then at the latest.
+// A zero time means at least one token never expires.
+func (c *Client) TokenExpiry(ctx context.Context) (time.Time, int, error) {
+	tokens, err := c.workspace.Tokens.ListAll(ctx)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+	var latest time.Time
+	for _, token := range tokens {
+		if token.ExpiryTime <= 0 {
+			return time.Time{}, len(tokens), nil
+		}
+		if expiry := time.UnixMilli(token.ExpiryTime); expiry.After(latest) {
+			latest = expiry
+		}
+	}
+	return latest, len(tokens), nil
+}
@@ -0,0 +1,55 @@
+package databricks
+
+import (
+	"context"
+
+	"databricks-blade-poc/internal/config"
+	"github.com/databricks/databricks-sdk-go"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Every statement the client runs (DDL, INSERT chunks, reads) used to go straight
+//   to the SDK, so exercising them needed a workspace or an HTTP fake of its API. They now
+//   go through a StatementExecutor, the SDK's statement execution service by default, which
+//   tests replace with a MockStatementExecutor (see mock.go) to run without network access.
+
+// Contract (a subset of the SDK's sql.StatementExecutionInterface, which satisfies it):
+// - ExecuteStatement: Submits a statement; the response may still be PENDING/RUNNING
+// - GetStatementByStatementId: Returns the current state of a submitted statement
+// - CancelExecution: Cancels a statement that is still executing
+type StatementExecutor interface {
+	ExecuteStatement(ctx context.Context, request sql.ExecuteStatementRequest) (*sql.StatementResponse, error)
+	GetStatementByStatementId(ctx context.Context, statementID string) (*sql.StatementResponse, error)
+	CancelExecution(ctx context.Context, request sql.CancelExecutionRequest) error
+}
+
+// Creates a client whose statements go to executor instead of the workspace.
+//   - No credentials are needed; cfg.DatabricksHost defaults to an unreachable host
+//   - Calls other than statements (warehouse state, query history, file uploads) still go
+//     to the host, so tests using this keep BLADE_STATEMENT_PROGRESS, BLADE_VOLUME_PATH and
+//     BLADE_TAG_WAREHOUSE off
+func NewClientWithExecutor(cfg *config.Config, executor StatementExecutor) (*Client, error) {
+	offline := *cfg
+	if offline.DatabricksHost == "" {
+		offline.DatabricksHost = "https://offline.invalid"
+	}
+	offline.RecordFile, offline.ReplayFile = "", ""
+	client, err := NewClientWithAuth(&offline, executorAuth{})
+	if err != nil {
+		return nil, err
+	}
+	client.statements = executor
+	return client, nil
+}
+
+// Placeholder credentials of a client whose statements never reach the workspace.
+type executorAuth struct{}
+
+func (executorAuth) Name() string {
+	return "executor"
+}
+
+func (executorAuth) Configure(sdkConfig *databricks.Config) error {
+	sdkConfig.AuthType, sdkConfig.Token = "pat", "executor"
+	return nil
+}
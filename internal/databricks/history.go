@@ -0,0 +1,81 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Table that receives one row per ingestion run (created on first use next to the BLADE tables).
+const RunHistoryTable = "blade_ingestion_history"
+
+// One run as recorded in the run history table.
+type RunHistoryEntry struct {
+	RunID        string
+	DataType     string
+	Format       string
+	Tenant       string
+	TableName    string
+	BatchID      string
+	Status       string
+	RowsIngested int64
+	Duration     time.Duration
+	Error        string
+	FinishedAt   time.Time
+}
+
+// Appends a run to the history table, creating the table if needed.
+func (c *Client) RecordRunHistory(ctx context.Context, entry RunHistoryEntry) error {
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, RunHistoryTable)
+
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				run_id STRING,
+				data_type STRING,
+				format STRING,
+				tenant STRING,
+				table_name STRING,
+				batch_id STRING,
+				status STRING,
+				rows_ingested BIGINT,
+				duration_ms BIGINT,
+				error STRING,
+				finished_at TIMESTAMP
+			)
+		`, fullName),
+	}); err != nil {
+		return fmt.Errorf("failed to create run history table %s: %w", fullName, err)
+	}
+	c.applyCostTags(ctx, "TABLE", fullName)
+
+	// Values are bound as named parameters; error messages can contain anything
+	_, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			INSERT INTO %s VALUES (
+				:run_id, :data_type, :format, :tenant, :table_name, :batch_id, :status,
+				CAST(:rows_ingested AS BIGINT), CAST(:duration_ms AS BIGINT), :error, CAST(:finished_at AS TIMESTAMP)
+			)
+		`, fullName),
+		Parameters: []sql.StatementParameterListItem{
+			stringParam("run_id", entry.RunID),
+			stringParam("data_type", entry.DataType),
+			stringParam("format", entry.Format),
+			stringParam("tenant", entry.Tenant),
+			stringParam("table_name", entry.TableName),
+			stringParam("batch_id", entry.BatchID),
+			stringParam("status", entry.Status),
+			stringParam("rows_ingested", strconv.FormatInt(entry.RowsIngested, 10)),
+			stringParam("duration_ms", strconv.FormatInt(entry.Duration.Milliseconds(), 10)),
+			stringParam("error", entry.Error),
+			stringParam("finished_at", entry.FinishedAt.UTC().Format("2006-01-02 15:04:05")),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record run %s in %s: %w", entry.RunID, fullName, err)
+	}
+	return nil
+}
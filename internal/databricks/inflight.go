@@ -0,0 +1,145 @@
+package databricks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Killing the process mid-ingestion left its statements executing in the
+//   warehouse: a statement still in its server-side wait was abandoned together with the
+//   HTTP call, before its ID was known. Statements are now submitted detached from
+//   cancellation, and the process's StatementTracker cancels whatever is still in flight
+//   before it exits.
+
+// Statements of the process that are still executing, for canceling them on shutdown.
+type StatementTracker struct {
+	mu          sync.Mutex
+	running     map[string]*Client // statement ID -> client that submitted it
+	submissions sync.WaitGroup     // submissions whose caller stopped waiting
+}
+
+type statementTrackerKey struct{}
+
+func NewStatementTracker() *StatementTracker {
+	return &StatementTracker{running: map[string]*Client{}}
+}
+
+// Returns ctx carrying tracker; statements executed with it are tracked until they finish.
+func WithStatementTracker(ctx context.Context, tracker *StatementTracker) context.Context {
+	return context.WithValue(ctx, statementTrackerKey{}, tracker)
+}
+
+func statementTrackerFrom(ctx context.Context) *StatementTracker {
+	tracker, _ := ctx.Value(statementTrackerKey{}).(*StatementTracker)
+	return tracker
+}
+
+func (t *StatementTracker) track(c *Client, statementID string) {
+	if t == nil || statementID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running[statementID] = c
+}
+
+func (t *StatementTracker) untrack(statementID string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.running, statementID)
+}
+
+// Counts a submission finishing in the background; the returned func marks it done.
+func (t *StatementTracker) submitting() func() {
+	if t == nil {
+		return func() {}
+	}
+	t.submissions.Add(1)
+	return t.submissions.Done
+}
+
+// Cancels every statement still in flight and returns their IDs.
+//   - Waits up to grace for abandoned submissions to return first: they cancel their
+//     statement themselves once its ID is known
+//   - Cancellation failures are only logged
+func (t *StatementTracker) Shutdown(ctx context.Context, grace time.Duration) []string {
+	returned := make(chan struct{})
+	go func() {
+		t.submissions.Wait()
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(grace):
+		runlog.Printf(ctx, "Gave up waiting for statement submissions after %s", grace)
+	}
+
+	t.mu.Lock()
+	running := t.running
+	t.running = map[string]*Client{}
+	t.mu.Unlock()
+	var canceled []string
+	for statementID, c := range running {
+		c.cancelStatement(ctx, statementID)
+		canceled = append(canceled, statementID)
+	}
+	sort.Strings(canceled)
+	return canceled
+}
+
+// Submits a statement without letting ctx abort the call, so its ID is never lost.
+//   - When ctx ends first, the caller gets ctx's error right away and the submission
+//     finishes in the background; a statement still executing when it returns is canceled
+//   - The call is still bounded by WaitTimeout (fitted to ctx's deadline) and BLADE_HTTP_TIMEOUT
+func (c *Client) submitStatement(ctx context.Context, req sql.ExecuteStatementRequest) (*sql.StatementResponse, error) {
+	type submission struct {
+		resp *sql.StatementResponse
+		err  error
+	}
+	var mu sync.Mutex
+	abandoned := false
+	submitted := make(chan submission, 1)
+	done := statementTrackerFrom(ctx).submitting()
+	go func() {
+		defer done()
+		resp, err := c.statements.ExecuteStatement(context.WithoutCancel(ctx), req)
+		mu.Lock()
+		defer mu.Unlock()
+		if !abandoned {
+			submitted <- submission{resp, err}
+			return
+		}
+		if err == nil && statementActive(resp) {
+			runlog.Printf(ctx, "Canceling statement %s, submitted before the run stopped", resp.StatementId)
+			c.cancelStatement(ctx, resp.StatementId)
+		}
+	}()
+
+	select {
+	case s := <-submitted:
+		return s.resp, s.err
+	case <-ctx.Done():
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	select {
+	case s := <-submitted:
+		return s.resp, s.err
+	default:
+		abandoned = true
+		return nil, ctx.Err()
+	}
+}
+
+// Reports whether a statement is still PENDING or RUNNING.
+func statementActive(resp *sql.StatementResponse) bool {
+	return resp.Status != nil && (resp.Status.State == sql.StatementStatePending || resp.Status.State == sql.StatementStateRunning)
+}
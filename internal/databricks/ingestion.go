@@ -4,24 +4,104 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"strconv"
 	"strings"
 	"time"
 	"github.com/databricks/databricks-sdk-go/service/sql"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/timeline"
 )
 
 
 func (c *Client) IngestBLADEData(ctx context.Context, req *IngestionRequest) (*IngestionResult, error) {
+	// - Time the workspace's rate limits cost the run is reported on the result (ThrottleTime)
+	ctx, meter := withThrottleMeter(ctx)
+	// - A Records source is closed however the load ends, so a producer blocked in
+	//   ChannelSource.Send is released even when no chunk was read
+	if req.Records != nil {
+		defer req.Records.Close()
+	}
+	// - The request's catalog/schema overrides (from its mapping) pick the namespace it loads into
+	if req.Catalog != "" || req.Schema != "" {
+		if err := req.Validate(); err != nil {
+			return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err}, fmt.Errorf("invalid ingestion request: %w", err)
+		}
+		c = c.InNamespace(req.Catalog, req.Schema)
+	}
+	result, err := c.ingestBLADEData(ctx, req)
+	if result != nil {
+		meter.report(ctx, result)
+	}
+	return result, err
+}
+
+// Routes the request by classification, or ingests it into its table.
+func (c *Client) ingestBLADEData(ctx context.Context, req *IngestionRequest) (*IngestionResult, error) {
+	// - With a classification routing policy (BLADE_CLASSIFICATION_ROUTES) the records are
+	//   split by classification_marking and each group is ingested into its own target
+	// - COPY INTO loads never hold the records client-side, so they can't be split and
+	//   are refused rather than written unrouted
+	// - record_stream loads are refused too; they also don't stage (the staging table
+	//   would hold the whole file before the commit, defeating the streaming)
+	if mode := req.Metadata["mode"]; len(c.classificationRoutes) > 0 && (mode == ModeCopyInto || mode == ModeRecordStream) {
+		err := fmt.Errorf("classification routing needs the records in the request; %s loads can't be routed", mode)
+		return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err}, err
+	}
+	// - Archive mode can't run with a dedup policy: records skipped as duplicates stay in
+	//   the older batch that held them, which archiving then moves out of the table
+	if c.archiveSuperseded && req.Dedup != nil && req.Metadata["mode"] != ModeCopyInto {
+		err := fmt.Errorf("BLADE_ARCHIVE_SUPERSEDED can't be used with the dedup policy of %s: the records it skips would be archived with the batches holding them; disable one of them", req.TableName)
+		return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err}, err
+	}
+	if req.Metadata["mode"] == ModeRecordStream && c.loadMode == LoadModeStaged {
+		err := fmt.Errorf("%s loads are inserted chunk by chunk and can't use the %s load mode", ModeRecordStream, LoadModeStaged)
+		return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err}, err
+	}
+	if len(c.classificationRoutes) > 0 && req.SampleData != "" {
+		// - Each route keeps its own batch, which the manifest finds by the route's source
+		if req.ResumeBatchID != "" {
+			err := fmt.Errorf("routed loads write one batch per route and can't resume batch %s; load the source again to resume each route's batch", req.ResumeBatchID)
+			return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err}, err
+		}
+		if err := req.Validate(); err != nil {
+			return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err}, fmt.Errorf("invalid ingestion request: %w", err)
+		}
+		return c.ingestRouted(ctx, req)
+	}
+	return c.ingest(ctx, req)
+}
+
+// Runs the ingestion pipeline for a request against the client's namespace.
+func (c *Client) ingestBatch(ctx context.Context, req *IngestionRequest) (*IngestionResult, error) {
 	// - Captures start time to measure total ingestion duration
   	// - Used in all return paths to provide accurate timing
 	start := time.Now() 
 
+	// - Rejects malformed requests before any SQL reaches the warehouse
+	if err := req.Validate(); err != nil {
+		return &IngestionResult{
+			TableName: req.TableName,
+			Status:    "failed",
+			Error:     err,
+			Duration:  time.Since(start),
+		}, fmt.Errorf("invalid ingestion request: %w", err)
+	}
+
 	// - Calls ensureTableExists() which:
     // - Creates catalog if missing (CREATE CATALOG IF NOT EXISTS blade_poc)
     // - Creates schema if missing (CREATE SCHEMA IF NOT EXISTS blade_poc.logistics)
     // - Creates table with standardized schema (item_id, item_type, classification_marking, etc.)
     // - Returns detailed failure result if table creation fails
-	if err := c.ensureTableExists(ctx, req); err != nil {
+	// - Each phase's statements are labeled in the run timeline (timeline.WithPhase)
+	// - A TTL policy also (re)creates the view over the table's unexpired rows
+	err := c.ensureTableExists(timeline.WithPhase(ctx, "create_table"), req)
+	if err == nil && req.TTL != nil {
+		err = c.ensureCurrentView(timeline.WithPhase(ctx, "create_table"), req)
+	}
+	if err != nil {
+		if budgetExceeded(ctx) {
+			return partialResult(ctx, req, start, "create_table", 0, "")
+		}
 		return &IngestionResult{
 			TableName: req.TableName,        
 			Status:    "failed",               
@@ -32,55 +112,366 @@ func (c *Client) IngestBLADEData(ctx context.Context, req *IngestionRequest) (*I
 
 	// - Checks two conditions for POC mode:
     // - SampleData field contains JSON data (from BLADE adapter)
-    // - Metadata explicitly marks this as "mock_data" mode
+    // - Metadata explicitly marks this as "mock_data" (or "live_api") mode
   	// - This is the main execution path for the current POC
-	if req.SampleData != "" && req.Metadata["mode"] == "mock_data" {
+	// - copy_into mode loads real BLADE files from SourcePath instead (see copyinto.go)
+	// - record_stream mode reads a local NDJSON file chunk by chunk (see stream.go)
+	copyInto := req.Metadata["mode"] == ModeCopyInto
+	stream := req.Metadata["mode"] == ModeRecordStream
+	if (req.SampleData != "" && recordMode(req)) || copyInto || stream {
+		// - batchID: Groups the rows of this load; generated by the BLADE_BATCH_ID strategy
+		//   (ULID by default, see batchid.go), unless the batch manifest assigned it
+		// - Shared by the insert and the post-load validations scoped to this batch
+		batchID := req.batchID
+		if batchID == "" {
+			batchID = c.newBatchID(req)
+		}
+
+		// - Record rules: records that break them fail the load, or are skipped or quarantined
+		//   before anything else runs, so dedup and the insert only see valid records; records
+		//   rejected while preparing the request (malformed CSV rows) are settled with them
+		// - The classification policy is checked with them (see classification.go)
+		// - Streams check each chunk as it is read (see stream.go)
+		var rejected []Rejection
+		if (req.Rules != nil || len(req.Rejects) > 0 || c.classification != nil) && req.SampleData != "" {
+			if budgetExceeded(ctx) {
+				return partialResult(ctx, req, start, "rules", 0, batchID)
+			}
+			checked, found, err := c.applyRecordRules(timeline.WithPhase(ctx, "rules"), req, batchID, 0)
+			if err != nil {
+				if budgetExceeded(ctx) {
+					return partialResult(ctx, req, start, "rules", 0, batchID)
+				}
+				return &IngestionResult{
+					TableName:    req.TableName,
+					Status:       "failed",
+					Error:        err,
+					Duration:     time.Since(start),
+					RowsRejected: int64(len(found)),
+					Rejections:   reportedRejections(found),
+				}, fmt.Errorf("failed record rules: %w", err)
+			}
+			req, rejected = checked, found
+		}
+
+		// - Dedup policy: records already in the table (by content hash) are dropped
+		//   before anything is written; the rest of the pipeline only sees the new ones
+		var skipped int64
+		if req.Dedup != nil && req.SampleData != "" {
+			if budgetExceeded(ctx) {
+				return partialResult(ctx, req, start, "dedup", 0, batchID)
+			}
+			deduped, n, err := c.dedupRecords(timeline.WithPhase(ctx, "dedup"), req)
+			if err != nil {
+				if budgetExceeded(ctx) {
+					return partialResult(ctx, req, start, "dedup", 0, batchID)
+				}
+				return &IngestionResult{
+					TableName: req.TableName,
+					Status:    "failed",
+					Error:     err,
+					Duration:  time.Since(start),
+				}, fmt.Errorf("failed to deduplicate records: %w", err)
+			}
+			req, skipped = deduped, n
+		}
+
 		// - Delegates actual insertion to insertMockData() helper function
+		// - "staged" load mode lands the rows in a staging table first and moves them
+		//   into the target with a single statement (all-or-nothing)
   		// - Returns failure result with timing if insertion fails
-		rowsInserted, err := c.insertMockData(ctx, req)
+		// - Run Budget (--max-runtime): every phase checks the context deadline first and
+		//   returns a partial result instead of starting work it can't finish
+		if budgetExceeded(ctx) {
+			return partialResult(ctx, req, start, "insert", 0, batchID)
+		}
+		// - Row count check: the table is counted before the insert and again after the load
+		//   (see rowcount.go)
+		rowsBefore, beforeErr := c.countRowsNow(timeline.WithPhase(ctx, "verification"), req.TableName)
+
+		// - chunks: One entry per INSERT statement (see insertMockData), kept on every result
+		// - COPY INTO is a single atomic statement, so it ignores the load mode and has no chunks
+		var rowsInserted int64
+		var chunks []InsertChunk
+		var copySource string
+		var streamed *streamLoad
+		var insertRejected []Rejection
+		var err error
+		if copyInto {
+			rowsInserted, copySource, err = c.copyIntoTable(timeline.WithPhase(ctx, "insert"), req, batchID)
+		} else if stream {
+			streamed, err = c.insertStream(ctx, req, batchID)
+			rowsInserted, chunks, skipped, rejected = streamed.rows, streamed.chunks, streamed.skipped, streamed.rejected
+		} else if c.loadMode == LoadModeStaged {
+			rowsInserted, chunks, insertRejected, err = c.insertStaged(timeline.WithPhase(ctx, "insert"), req, batchID)
+		} else {
+			rowsInserted, chunks, insertRejected, err = c.insertMockData(timeline.WithPhase(ctx, "insert"), req, req.TableName, batchID)
+		}
+		rejected = append(rejected, insertRejected...)
 		if err != nil {
+			if budgetExceeded(ctx) {
+				// - Direct mode keeps the chunks committed before the budget ran out
+				partial, err := partialResult(ctx, req, start, "insert", rowsInserted, batchID)
+				partial.Chunks = chunks
+				return partial, err
+			}
 			return &IngestionResult{
+				RowsIngested: rowsInserted,
 				TableName: req.TableName,
 				Status:    "failed",        
 				Error:     err,               
 				Duration:  time.Since(start), 
-			}, fmt.Errorf("failed to insert mock data: %w", err)
+				Chunks:    chunks,
+				RowsRejected: int64(len(rejected)),
+				Rejections: reportedRejections(rejected),
+			}, fmt.Errorf("failed to load %s: %w", ingestionType(req), err)
 		}
 
-		// - Tries to validate insertion by querying row count
-		// - Logs warning but doesn't fail if count query fails
-		// - Uses inserted count as fallback (current behavior)
-		_, err = c.getRowCount(ctx, req.TableName)
-		if err != nil {
-			log.Printf("Could not get row count from table, using inserted count: %v", err)
+		// - Records the warehouse refused have no row to key crew and child rows to
+		if len(insertRejected) > 0 {
+			if req, err = withoutRejected(req, insertRejected); err != nil {
+				return &IngestionResult{
+					RowsIngested: rowsInserted,
+					TableName:    req.TableName,
+					Status:       "failed",
+					Error:        err,
+					Duration:     time.Since(start),
+				}, err
+			}
 		}
 
+		// - Marking distribution of the rows loaded; COPY INTO loads, and resumed batches
+		//   whose committed chunks weren't read again, count their batch in the table; a
+		//   failed count is only logged
+		var markings map[string]int64
+		var markingsErr error
+		switch {
+		case copyInto || req.committedChunks != nil:
+			markings, markingsErr = c.batchMarkings(timeline.WithPhase(ctx, "verification"), req.TableName, batchID)
+		case stream:
+			markings = streamed.markings
+		case req.SampleData != "":
+			markings, markingsErr = sampleMarkings(req)
+		}
+		if markingsErr != nil {
+			runlog.Printf(ctx, "Could not count the markings of batch %s: %v", batchID, markingsErr)
+		}
+
+		// - Child tables and sample verification work on the request's records, so COPY INTO
+		//   loads skip them
+		// - Streams already wrote the child rows with each chunk
+		var childRows map[string]int64
+		if stream {
+			childRows = streamed.childRows
+		}
+
+		// - Mapping-declared child arrays (sortie crew included) become detail rows keyed back
+		//   to their parent record
+		if len(req.ChildTables) > 0 && req.SampleData != "" {
+			if budgetExceeded(ctx) {
+				return partialResult(ctx, req, start, "child_tables", rowsInserted, batchID)
+			}
+			childRows, err = c.insertChildTables(timeline.WithPhase(ctx, "child_tables"), req, batchID)
+			if err != nil {
+				if budgetExceeded(ctx) {
+					return partialResult(ctx, req, start, "child_tables", rowsInserted, batchID)
+				}
+				return &IngestionResult{
+					RowsIngested: rowsInserted,
+					TableName:    req.TableName,
+					Status:       "failed",
+					Error:        err,
+					Duration:     time.Since(start),
+				}, fmt.Errorf("failed to insert child tables: %w", err)
+			}
+		}
+
+		// - The batch is committed; verification and validations are skipped when out of budget
+		if budgetExceeded(ctx) {
+			return partialResult(ctx, req, start, "verification", rowsInserted, batchID)
+		}
+
+		// - Counts the table again and checks it grew by the rows just inserted
+		// - A mismatch warns, or fails the run with BLADE_ROW_COUNT_MISMATCH=fail (see below)
+		tableRows, countErr := c.countRowsNow(timeline.WithPhase(ctx, "verification"), req.TableName)
+		rowCount := checkRowCount(rowsBefore, tableRows, beforeErr, countErr, chunks, rowsInserted)
+
 		// - Constructs success result with:
 		// - Actual rows inserted count
 		// - Total execution time
 		// - Original request metadata preserved
-		// - Ingestion type marked as "mock_data_insert"
-		return &IngestionResult{
+		// - Ingestion type marked as "mock_data_insert" (or "copy_into" for file loads)
+		// - Run ID (when logging to a per-run stream) to find the run's log file
+		result := &IngestionResult{
 			RowsIngested: rowsInserted,  
+			RowsSkipped:  skipped,
+			RowsRejected: int64(len(rejected)),
+			Rejections:   reportedRejections(rejected),
 			Duration:     time.Since(start),  
 			TableName:    req.TableName,      
 			Status:       "completed",      
+			Chunks:       chunks,
+			RowCount:     rowCount,
+			Warnings:     append([]Warning(nil), req.Warnings...),
 			Metadata: map[string]interface{}{ 
 				"source_path":    req.SourcePath,    
 				"file_format":    req.FileFormat,      
 				"data_source":    req.DataSource,      
 				"blade_metadata": req.Metadata,      
-				"ingestion_type": "mock_data_insert",  
+				"ingestion_type": ingestionType(req),
+				"table_type":     tableType(req),
+				"batch_id":       batchID,
+				"batch_id_strategy": c.batchIDName,
+				"load_mode":      c.loadMode,
+				"raw_data_codec": c.rawDataCodecName,
 			},
-		}, nil 
+		}
+		if c.tenant != "" {
+			result.Metadata["tenant"] = c.tenant
+		}
+		if req.Catalog != "" || req.Schema != "" {
+			result.Metadata["namespace"] = c.catalog + "." + c.schema
+		}
+		if version := req.Metadata["source_version"]; version != "" {
+			result.Metadata["source_version"] = version
+		}
+		if url := req.Metadata["source_url"]; url != "" {
+			result.Metadata["source_url"] = url
+		}
+		if copyInto {
+			result.Metadata["copy_source"] = copySource
+		}
+		if snapshot := req.Metadata["snapshot_id"]; snapshot != "" {
+			result.Metadata["snapshot_id"] = snapshot
+		}
+		if stream {
+			result.Metadata["records_read"] = streamed.records
+		}
+		if childRows != nil {
+			result.Metadata["child_rows"] = childRows
+		}
+		if markings != nil {
+			result.Metadata["marking_distribution"] = markings
+			logMarkings(ctx, req.TableName, markings)
+		}
+		if run := runlog.FromContext(ctx); run != nil {
+			result.Metadata["run_id"] = run.ID
+			result.Metadata["log_path"] = run.Path
+		}
+
+		// Warnings:
+		// - Non-fatal conditions are collected on the result (see warnings.go); the request
+		//   carries those found while preparing the records
+		// - A table count of 0 means the warehouse returned no count, not an empty table
+		if beforeErr != nil || countErr != nil {
+			result.warn(ctx, WarnRowCount, "could not verify the row count of %s, using inserted count: %s", req.TableName, rowCount.Error)
+		} else if rowCount.Error == "" && !rowCount.Verified {
+			result.warn(ctx, WarnRowCount, "%s", rowCount.describe(req.TableName))
+		}
+		// - Snapshots report the rows they exported; COPY INTO skips files it loaded before
+		if expected, err := strconv.ParseInt(req.Metadata["expected_rows"], 10, 64); err == nil && expected > 0 && expected != rowsInserted {
+			result.warn(ctx, WarnRowCount, "source reports %d rows but %d were loaded", expected, rowsInserted)
+		}
+		if len(rejected) > 0 {
+			if req.Rules.Mode() == OnInvalidQuarantine {
+				result.warn(ctx, WarnRejected, "%d record(s) were rejected for %s and quarantined in %s", len(rejected), req.TableName, RejectTable+req.tableSuffix)
+			} else {
+				result.warn(ctx, WarnRejected, "%d record(s) were rejected for %s and skipped", len(rejected), req.TableName)
+			}
+		}
+		if c.rowCountMismatch == RowCountFail && rowCount.Error == "" && !rowCount.Verified {
+			err := fmt.Errorf("row count check failed: %s", rowCount.describe(req.TableName))
+			result.Status = "failed"
+			result.Error = err
+			result.Duration = time.Since(start)
+			return result, err
+		}
+		c.checkCoercion(timeline.WithPhase(ctx, "verification"), req, batchID, result)
+
+		// - Reads a random sample of the batch back and compares it with the source records
+		// - Mismatches are reported (and logged) but don't fail the run
+		// - A stream's sample comes from its first chunk
+		verifyReq := req
+		if stream {
+			verifyReq = &IngestionRequest{}
+			*verifyReq = *req
+			verifyReq.SampleData = streamed.sample
+		}
+		if c.verifySampleSize > 0 && verifyReq.SampleData != "" {
+			result.Verification = c.verifySample(timeline.WithPhase(ctx, "verification"), verifyReq, batchID)
+			if !result.Verification.Passed() {
+				result.Warnings = append(result.Warnings, Warning{Code: WarnVerification, Message: fmt.Sprintf(
+					"sample verification found %d missing record(s) and %d field mismatch(es)%s",
+					len(result.Verification.Missing), len(result.Verification.Mismatches), errorSuffix(result.Verification.Error))})
+			}
+		}
+
+		// - Runs the request's post-load validation SQL server-side against this batch
+		// - Every outcome is recorded in the result
+		// - Only failing "error" severity rules fail the run
+		result.Validations = c.runValidations(timeline.WithPhase(ctx, "validation"), req, batchID)
+		for _, validation := range result.Validations {
+			if !validation.Passed && validation.Severity == SeverityWarn {
+				result.Warnings = append(result.Warnings, Warning{Code: WarnValidation, Message: fmt.Sprintf(
+					"validation %s failed: %d violation(s)%s", validation.Name, validation.Violations, errorSuffix(validation.Error))})
+			}
+		}
+		if failed := failedValidations(result.Validations); len(failed) > 0 {
+			err := fmt.Errorf("post-load validation failed: %s", strings.Join(failed, ", "))
+			result.Status = "failed"
+			result.Error = err
+			result.Duration = time.Since(start)
+			return result, err
+		}
+
+		// - Archive mode: older batches of the same source are moved to {table}_archive
+		//   only after this batch passed validation, so a bad re-delivery never evicts good data
+		// - Archival problems are logged; the new batch is already committed
+		if c.archiveSuperseded {
+			archived, err := c.archiveSupersededBatches(timeline.WithPhase(ctx, "archive"), req, batchID)
+			if err != nil {
+				result.warn(ctx, WarnArchive, "could not archive superseded batches: %v", err)
+			}
+			result.Metadata["archived_rows"] = archived
+		}
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	// - Requests carrying neither mock records nor a copy_into source have nothing to load
+	return nil, fmt.Errorf("nothing to ingest: use mock_data or %s mode with sample data, or %s or %s mode with a source path", ModeLiveAPI, ModeCopyInto, ModeRecordStream)
+}
+
+// Metadata mode of records pulled from the BLADE REST API (BLADE_MODE=live): they load like
+// mock_data records, but their rows and result name the API as their source.
+const ModeLiveAPI = "live_api"
+
+// Reports whether a request carries its records in SampleData (mock_data and live_api modes).
+func recordMode(req *IngestionRequest) bool {
+	mode := req.Metadata["mode"]
+	return mode == "mock_data" || mode == ModeLiveAPI
+}
+
+// Names where a request's records came from, as stamped in each row's metadata source.
+func recordSource(req *IngestionRequest) string {
+	if req.Metadata["mode"] == ModeLiveAPI {
+		return "blade_api"
 	}
+	return "mock_blade"
+}
 
-	// - Currently only supports mock data mode
-  	// - Future enhancement would add real BLADE file processing here
-	return nil, fmt.Errorf("real BLADE ingestion not implemented - use mock data mode for POC")
+// Names how a request's rows are loaded, as reported in the result's ingestion_type.
+func ingestionType(req *IngestionRequest) string {
+	switch mode := req.Metadata["mode"]; mode {
+	case ModeCopyInto, ModeRecordStream:
+		return mode
+	case ModeLiveAPI:
+		return "blade_api_insert"
+	}
+	return "mock_data_insert"
 }
 
-func (c *Client) insertMockData(ctx context.Context, req *IngestionRequest) (int64, error) {
+func (c *Client) insertMockData(ctx context.Context, req *IngestionRequest, targetTable string, batchID string) (int64, []InsertChunk, []Rejection, error) {
 	var records []map[string]interface{} 
 	
 	// - Declares slice to hold parsed JSON records
@@ -88,47 +479,131 @@ func (c *Client) insertMockData(ctx context.Context, req *IngestionRequest) (int
 	// - Parses into []map[string]interface{} - array of flexible key-value maps
 	// - Returns immediately if JSON is malformed
 	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
-		return 0, fmt.Errorf("failed to parse sample data: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to parse sample data: %w", err)
+	}
+
+	// Chunking:
+	// - One INSERT per insertChunkSize records (BLADE_INSERT_CHUNK_SIZE, default 500) keeps
+	//   each statement under the warehouse's statement size limits; 0 sends a single INSERT
+	// - A failed chunk is recorded and the remaining chunks still run; the call fails
+	//   afterwards with every failed chunk named, and the rows of successful chunks counted
+	// - All chunks share batchID, so validations, verification and archival still see one batch
+	// - Tables that skip or quarantine invalid records retry a chunk the warehouse failed
+	//   one record at a time, rejecting only the records it refuses (see rejects.go)
+	// - A resumed batch skips the chunks an earlier attempt committed, counting their rows
+	//   (see manifest.go)
+	size := c.insertChunkSize
+	if size <= 0 || size > len(records) {
+		size = len(records)
 	}
+	runlog.Printf(ctx, "Preparing to insert %d records into %s.%s.%s", len(records), c.catalog, c.schema, targetTable)
 
+	var chunks []InsertChunk
+	var inserted int64
+	var failed []string
+	var rejected []Rejection
+	for offset := 0; offset < len(records); offset += size {
+		// - Out of run budget: stop between chunks; the committed chunks stay counted
+		if budgetExceeded(ctx) {
+			return inserted, chunks, rejected, fmt.Errorf("%w: stopped before insert chunk %d", stopReason(ctx), len(chunks)+1)
+		}
+		end := min(offset+size, len(records))
+		chunk := InsertChunk{Index: len(chunks) + 1, Offset: offset, Rows: int64(end - offset)}
+		if rows, ok := req.committedChunks[chunk.Index]; ok {
+			chunk.Rows, chunk.Resumed = rows, true
+			inserted += rows
+			chunks = append(chunks, chunk)
+			continue
+		}
+		started := time.Now()
+		statementID, err := c.insertRecords(ctx, req, targetTable, batchID, records[offset:end])
+		isolated := false
+		if err != nil && isolatesInsertFailure(ctx, req, err) {
+			var found []Rejection
+			chunk.Rows, found, err = c.isolateInsertFailures(ctx, req, targetTable, batchID, records[offset:end], offset, err)
+			chunk.Rejected, isolated = len(found), true
+			rejected = append(rejected, found...)
+		}
+		chunk.StatementID, chunk.Duration = statementID, time.Since(started)
+		if err != nil {
+			// - A retried chunk keeps the rows that went in on their own
+			if !isolated {
+				chunk.Rows = 0
+			}
+			chunk.Error = err.Error()
+			failed = append(failed, fmt.Sprintf("chunk %d (records %d-%d): %v", chunk.Index, offset+1, end, err))
+		}
+		inserted += chunk.Rows
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) > 1 {
+		runlog.Printf(ctx, "Inserted %d of %d records in %d chunks (%d failed)", inserted, len(records), len(chunks), len(failed))
+	}
+	if len(failed) > 0 {
+		return inserted, chunks, rejected, fmt.Errorf("%d of %d insert chunks failed: %s", len(failed), len(chunks), strings.Join(failed, "; "))
+	}
+	return inserted, chunks, rejected, nil
+}
+
+// Inserts one chunk of records with a single multi-row INSERT and returns its statement ID.
+func (c *Client) insertRecords(ctx context.Context, req *IngestionRequest, targetTable string, batchID string, records []map[string]interface{}) (string, error) {
 	// - values: Will hold SQL VALUES clauses for each record
-   	// - batchID: Unix timestamp to group related inserts (for tracking/debugging)
-    // - Logs insertion intent with full table path and record count
+	// - params: Every value is bound as a statement parameter, so record content
+	//   (IDs, timestamps, raw JSON) never becomes SQL text and can't break or inject
+	//   into the statement
 	var values []string
-	batchID := fmt.Sprintf("%d", time.Now().Unix())
-	log.Printf("Preparing to insert %d records into %s.%s.%s", len(records), c.catalog, c.schema, req.TableName)
+	var params paramList
+	// - Batch-level values are bound once and shared by every row
+	dataSource := params.text(req.DataSource)
+	// - source: "blade_api" for records pulled from the BLADE REST API, which also carry the
+	//   endpoint they came from as source_url; "mock_blade" otherwise
+	metadataPairs := fmt.Sprintf("'source', %s, 'batch_id', %s, 'data_type', %s, 'tenant', %s, 'source_path', %s, 'source_format', %s, 'source_version', %s",
+		params.text(recordSource(req)), params.text(batchID), params.text(req.Metadata["data_type"]), params.text(c.tenant), params.text(req.SourcePath),
+		params.text(req.Metadata["source_format"]), params.text(req.Metadata["source_version"]))
+	if url := req.Metadata["source_url"]; url != "" {
+		metadataPairs += ", 'source_url', " + params.text(url)
+	}
 	
 	for _, record := range records {
 		//  - Re-marshals the parsed record back to JSON string
 		//  - This preserves the original structure in raw_data column
-		//  - Escapes single quotes (' → '') for SQL safety
 		rawDataJSON, _ := json.Marshal(record) 
-		rawDataEscaped := strings.ReplaceAll(string(rawDataJSON), "'", "''")
+		rawData, err := c.rawDataCodec.Encode(rawDataJSON)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode raw_data (%s): %w", c.rawDataCodecName, err)
+		}
+
+		// - TTL policy: the row's expiry is the only per-record metadata entry (NULL when
+		//   the record lacks a parseable TTL field, so the row never expires)
+		// - Dedup policy: the record's content hash, looked up by later loads
+		metadata := "map(" + metadataPairs
+		if req.TTL != nil {
+			metadata += fmt.Sprintf(", 'expires_at', %s", params.bind(req.TTL.expiresAt(record), "STRING"))
+		}
+		if req.Dedup != nil {
+			metadata += fmt.Sprintf(", 'content_hash', %s", params.text(req.Dedup.hash(record)))
+		}
+		metadata += ")"
 		
 		//   Maps JSON fields to standardized table schema:
 		// 	- item_id, item_type, classification_marking, timestamp: Direct from JSON
+		// 	  (missing fields are NULL; timestamp is bound as a TIMESTAMP parameter)
 		// 	- data_source: From request (e.g., "BLADE_LOGISTICS")
-		// 	- raw_data: Complete escaped JSON record
+		// 	- raw_data: Complete JSON record, stored through the raw_data codec (BLADE_RAW_DATA_CODEC)
 		// 	- ingestion_timestamp: Current database time
-		// 	- metadata: Databricks MAP with batch tracking info
-		value := fmt.Sprintf(`(
-			'%s',
-			'%s', 
-			'%s',
-			TIMESTAMP '%s',
-			'%s',
-			'%s',
-			current_timestamp(),
-			map('source', 'mock_blade', 'batch_id', '%s', 'data_type', '%s')
-		)`,
-			record["item_id"],                  
-			record["item_type"],            
-			record["classification_marking"],  
-			record["timestamp"],   
-			req.DataSource,                     
-			rawDataEscaped,             
-			batchID,                        
-			req.Metadata["data_type"],  
+		// 	- metadata: Databricks MAP with batch tracking info (tenant, empty when unscoped,
+		// 	  source_path, which identifies re-deliveries of the same source, and the BLADE
+		// 	  source_format/source_version, empty when the export didn't declare one)
+		// 	- Typed columns: The mapping's columns, each read from its record field
+		value := fmt.Sprintf("(%s, %s, %s, %s, %s, %s, current_timestamp(), %s%s)",
+			params.bind(recordText(record, "item_id"), "STRING"),
+			params.bind(recordText(record, "item_type"), "STRING"),
+			params.bind(recordText(record, "classification_marking"), "STRING"),
+			params.bind(recordText(record, "timestamp"), "TIMESTAMP"),
+			dataSource,
+			params.text(rawData),
+			metadata,
+			typedColumnValues(&params, req.Columns, record),
 		)
 		values = append(values, value)
 	}
@@ -146,27 +621,28 @@ func (c *Client) insertMockData(ctx context.Context, req *IngestionRequest) (int
 			data_source,
 			raw_data,
 			ingestion_timestamp,
-			metadata
+			metadata%s
 		) VALUES %s
 	`, 
 		c.catalog,    
 		c.schema,   
-		req.TableName, 
+		targetTable, 
+		typedColumnNames(req.Columns),
 		strings.Join(values, ",\n")) 
 
 	// - Logs execution attempt
 	// - Calls Databricks SQL Execution API
 	// - Specifies warehouse, catalog, schema context
-	// - 30-second timeout for statement completion
-	log.Printf("Executing INSERT statement for %d records", len(records))
-	resp, err := c.workspace.StatementExecution.ExecuteStatement(
+	// - Default server-side wait, bounded by BLADE_DML_TIMEOUT
+	runlog.Printf(ctx, "Executing INSERT statement for %d records", len(records))
+	resp, err := c.executeStatement(
 		ctx,
 		sql.ExecuteStatementRequest{ 
 			Statement:   insertSQL,   
 			WarehouseId: c.warehouseID,  
 			Catalog:     c.catalog,     
 			Schema:      c.schema,       
-			WaitTimeout: "30s",   
+			Parameters:  params.params,
 		},
 	)
 
@@ -176,14 +652,39 @@ func (c *Client) insertMockData(ctx context.Context, req *IngestionRequest) (int
 	// - Returns count of records processed (assumes all succeeded)
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to insert mock data batch: %w", err)
+		// - A FAILED statement still has an ID worth reporting on the chunk
+		statementID := ""
+		if resp != nil {
+			statementID = resp.StatementId
+		}
+		return statementID, fmt.Errorf("failed to insert mock data batch: %w", err)
 	}
 
-	if resp.Status != nil && resp.Status.State == sql.StatementStatePending {
-		log.Printf("Data insertion pending")
+	runlog.Printf(ctx, "INSERT execution completed with status: %v", resp.Status.State)
+
+	return resp.StatementId, nil 
+}
+
+// Returns a record field as parameter text: strings as-is, other JSON values formatted, missing/null as "" (NULL).
+func recordText(record map[string]interface{}, key string) string {
+	switch value := record[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		// - JSON numbers decode as float64; 'f' keeps large IDs and epochs out of exponent form
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
 	}
-	
-	log.Printf("INSERT execution completed with status: %v", resp.Status.State)
+}
 
-	return int64(len(records)), nil 
-}
\ No newline at end of file
+func tableType(req *IngestionRequest) string {
+	// - Normalizes the request's table type for reporting
+	// - Empty means the default managed table
+	if strings.EqualFold(req.TableType, ExternalTable) {
+		return ExternalTable
+	}
+	return ManagedTable
+}
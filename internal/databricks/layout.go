@@ -0,0 +1,204 @@
+package databricks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//   Purpose: Large BLADE tables are read by time range and item type far more often than
+//   they are scanned whole. A mapping's Layout declares how the table's files are
+//   organized from the moment the table is created: Hive-style partitions, or liquid
+//   clustering keys that Databricks keeps up to date as rows are added.
+
+//   Notes:
+//   - Delta tables can't be partitioned by an expression, so a transform such as
+//     days(timestamp) becomes a generated column (timestamp_date DATE GENERATED ALWAYS AS
+//     (CAST(timestamp AS DATE))) the table is partitioned by; filters on timestamp still
+//     prune partitions, and inserts never name the generated column
+//   - The layout applies when the table is created; an existing table keeps its own
+//     (recreate it, or ALTER TABLE ... CLUSTER BY, to change it)
+//   - Partitioning and clustering exclude each other; a clustered table is optimized
+//     without ZORDER (see ZOrderColumns)
+
+// Physical layout of a table, applied when it is created.
+//   - PartitionBy: Columns, or years(col), months(col), days(col) or hours(col) of a
+//     TIMESTAMP or DATE column (one partition per value: keep the cardinality low)
+//   - ClusterBy: Liquid clustering keys, at most 4 columns
+type TableLayout struct {
+	PartitionBy []string `json:"partitionBy,omitempty"`
+	ClusterBy   []string `json:"clusterBy,omitempty"`
+}
+
+// Most clustering keys Databricks accepts.
+const maxClusterKeys = 4
+
+var partitionTransformPattern = regexp.MustCompile(`^(?i)(years|months|days|hours)\(\s*([A-Za-z_][A-Za-z0-9_]*)\s*\)$`)
+
+// Generated column a partition transform adds: its name suffix, type and expression.
+var partitionTransforms = map[string]struct{ suffix, sqlType, expression string }{
+	"years":  {"year", "INT", "YEAR(%s)"},
+	"months": {"month", "STRING", "DATE_FORMAT(%s, 'yyyy-MM')"},
+	"days":   {"date", "DATE", "CAST(%s AS DATE)"},
+	"hours":  {"hour", "STRING", "DATE_FORMAT(%s, 'yyyy-MM-dd-HH')"},
+}
+
+// A column computed from another one for a partition transform.
+type generatedColumn struct {
+	name, sqlType, expression string
+}
+
+// Returns the partition column a PartitionBy entry stands for, and the generated column
+// behind it (nil for a plain column).
+func partitionColumn(entry string) (string, *generatedColumn) {
+	entry = strings.TrimSpace(entry)
+	match := partitionTransformPattern.FindStringSubmatch(entry)
+	if match == nil {
+		return entry, nil
+	}
+	transform := partitionTransforms[strings.ToLower(match[1])]
+	column := &generatedColumn{
+		name:       match[2] + "_" + transform.suffix,
+		sqlType:    transform.sqlType,
+		expression: fmt.Sprintf(transform.expression, match[2]),
+	}
+	return column.name, column
+}
+
+// Reports whether the table uses liquid clustering.
+func (l *TableLayout) Clustered() bool {
+	return l != nil && len(l.ClusterBy) > 0
+}
+
+// Returns the columns the table is partitioned by (generated ones by their name).
+func (l *TableLayout) PartitionColumns() []string {
+	if l == nil {
+		return nil
+	}
+	columns := make([]string, 0, len(l.PartitionBy))
+	for _, entry := range l.PartitionBy {
+		name, _ := partitionColumn(entry)
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// Returns the columns of zorder OPTIMIZE may Z-order the table by: none for a clustered
+// table (clustering replaces Z-ordering), and never a partition column.
+func (l *TableLayout) ZOrderColumns(zorder []string) []string {
+	if l.Clustered() {
+		return nil
+	}
+	partitioned := make(map[string]bool)
+	for _, column := range l.PartitionColumns() {
+		partitioned[strings.ToLower(column)] = true
+	}
+	var columns []string
+	for _, column := range zorder {
+		if !partitioned[strings.ToLower(column)] {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// Returns the generated columns of the layout's partition transforms.
+func (l *TableLayout) generatedColumns() []generatedColumn {
+	if l == nil {
+		return nil
+	}
+	var columns []generatedColumn
+	for _, entry := range l.PartitionBy {
+		if _, generated := partitionColumn(entry); generated != nil {
+			columns = append(columns, *generated)
+		}
+	}
+	return columns
+}
+
+// Returns the column definitions appended to CREATE TABLE for the generated columns
+// (", name TYPE GENERATED ALWAYS AS (...)" each), and the PARTITIONED BY or CLUSTER BY clause.
+func (l *TableLayout) ddl() (columns, clause string) {
+	if l == nil {
+		return "", ""
+	}
+	var ddl strings.Builder
+	for _, col := range l.generatedColumns() {
+		fmt.Fprintf(&ddl, ",\n\t\t\t%s %s GENERATED ALWAYS AS (%s)", col.name, col.sqlType, col.expression)
+	}
+	switch {
+	case len(l.PartitionBy) > 0:
+		clause = "PARTITIONED BY (" + strings.Join(l.PartitionColumns(), ", ") + ")"
+	case len(l.ClusterBy) > 0:
+		clause = "CLUSTER BY (" + strings.Join(l.ClusterBy, ", ") + ")"
+	}
+	return ddl.String(), clause
+}
+
+// Checks the layout against the table's columns (the standard ones plus columns).
+//   - Partition and clustering columns must exist and hold scalars (not raw_data or metadata)
+//   - Transforms need a TIMESTAMP or DATE column; their generated column must not clash
+func (l TableLayout) Check(columns []TypedColumn) error {
+	if len(l.PartitionBy) > 0 && len(l.ClusterBy) > 0 {
+		return fmt.Errorf("layout sets both partitionBy and clusterBy: a table is either partitioned or clustered")
+	}
+	if len(l.ClusterBy) > maxClusterKeys {
+		return fmt.Errorf("layout clusters by %d columns: at most %d are allowed", len(l.ClusterBy), maxClusterKeys)
+	}
+
+	types := make(map[string]string, len(standardColumnTypes)+len(columns))
+	for name, sqlType := range standardColumnTypes {
+		types[name] = sqlType
+	}
+	for _, col := range columns {
+		sqlType, _ := col.SQLType()
+		types[strings.ToLower(col.Name)] = sqlType
+	}
+	layoutColumn := func(name, use string) error {
+		sqlType, exists := types[strings.ToLower(name)]
+		switch {
+		case !tableNamePattern.MatchString(name):
+			return fmt.Errorf("layout %s %q is not a column name", use, name)
+		case !exists:
+			return fmt.Errorf("layout %s %s is not a column of the table", use, name)
+		case strings.EqualFold(name, "raw_data"):
+			return fmt.Errorf("layout %s raw_data can't be used: it holds the whole record", use)
+		case strings.HasPrefix(sqlType, "MAP<") || strings.HasPrefix(sqlType, "ARRAY<"):
+			return fmt.Errorf("layout %s %s can't be used: it holds a %s", use, name, sqlType)
+		}
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range l.PartitionBy {
+		name, generated := partitionColumn(entry)
+		if generated != nil {
+			source := partitionTransformPattern.FindStringSubmatch(strings.TrimSpace(entry))[2]
+			if err := layoutColumn(source, "partition source"); err != nil {
+				return err
+			}
+			if sqlType := types[strings.ToLower(source)]; sqlType != "TIMESTAMP" && sqlType != "DATE" {
+				return fmt.Errorf("layout partition %s needs a TIMESTAMP or DATE column, %s is %s", entry, source, sqlType)
+			}
+			if _, clash := types[strings.ToLower(name)]; clash {
+				return fmt.Errorf("layout partition %s adds column %s, which the table already has", entry, name)
+			}
+		} else if err := layoutColumn(name, "partition column"); err != nil {
+			return err
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("layout partitions by %s twice", name)
+		}
+		seen[strings.ToLower(name)] = true
+	}
+	for _, name := range l.ClusterBy {
+		if err := layoutColumn(name, "clustering key"); err != nil {
+			return err
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("layout clusters by %s twice", name)
+		}
+		seen[strings.ToLower(name)] = true
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"databricks-blade-poc/internal/timeline"
+)
+
+//   Purpose: Sizing a warehouse for the real BLADE volumes needs numbers five mock records
+//   can't give. LoadTest pushes a large request through the normal ingestion path from
+//   several workers at once and measures throughput, statement latency and failures.
+
+//   Phases:
+//   - Warm-up: the first insert chunk is loaded alone, so table creation and a warehouse
+//     waking from auto-stop don't count against the measurement
+//   - Measured: the other records are split evenly over opts.Concurrency ingestions that
+//     run at the same time, each inserting insertChunkSize records per statement
+//   - Every statement of the measured phase is timed (see internal/timeline)
+
+// How a load test runs.
+//   - Concurrency: Ingestions running at the same time (default 1)
+type LoadTestOptions struct {
+	Concurrency int
+}
+
+// Outcome of a load test (durations serialize as nanoseconds, like IngestionResult.Duration).
+type LoadTestResult struct {
+	TableName        string         `json:"tableName"`
+	Records          int            `json:"records"`
+	BatchSize        int            `json:"batchSize"` // records per INSERT statement
+	Concurrency      int            `json:"concurrency"`
+	WarmupRows       int64          `json:"warmupRows"`
+	Warmup           time.Duration  `json:"warmup"`
+	RowsIngested     int64          `json:"rowsIngested"` // measured phase only
+	RowsFailed       int64          `json:"rowsFailed"`   // records of the measured phase neither inserted nor rejected
+	RowsRejected     int64          `json:"rowsRejected,omitempty"`
+	Duration         time.Duration  `json:"duration"`
+	RowsPerSecond    float64        `json:"rowsPerSecond"`
+	Ingestions       int            `json:"ingestions"`
+	FailedIngestions int            `json:"failedIngestions"`
+	Statements       []LatencyStats `json:"statements"` // per phase and kind, in order of first use
+	Errors           []string       `json:"errors,omitempty"`
+}
+
+// Latency of the statements of one phase and kind (e.g. insert/DML).
+type LatencyStats struct {
+	Phase  string        `json:"phase"`
+	Kind   string        `json:"kind"`
+	Count  int           `json:"count"`
+	Failed int           `json:"failed"`
+	P50    time.Duration `json:"p50"`
+	P90    time.Duration `json:"p90"`
+	P99    time.Duration `json:"p99"`
+	Max    time.Duration `json:"max"`
+}
+
+// Returns the share of the measured phase's statements that failed (0 to 1).
+func (r *LoadTestResult) StatementFailureRate() float64 {
+	total, failed := 0, 0
+	for _, stats := range r.Statements {
+		total += stats.Count
+		failed += stats.Failed
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(failed) / float64(total)
+}
+
+// Loads the records of req (SampleData) as a load test; see the phases above.
+//   - A failed warm-up stops the test; failed measured ingestions are counted, not returned
+func (c *Client) LoadTest(ctx context.Context, req *IngestionRequest, opts LoadTestOptions) (*LoadTestResult, error) {
+	var records []json.RawMessage
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return nil, fmt.Errorf("failed to parse load test records: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("load test needs at least one record")
+	}
+	concurrency := max(opts.Concurrency, 1)
+	batchSize := c.insertChunkSize
+	if batchSize <= 0 {
+		batchSize = len(records)
+	}
+	result := &LoadTestResult{TableName: req.TableName, Records: len(records), BatchSize: batchSize, Concurrency: concurrency}
+
+	warmup := min(batchSize, len(records))
+	started := time.Now()
+	warm, err := c.IngestBLADEData(ctx, loadTestShare(req, records[:warmup], "warmup"))
+	if err != nil {
+		return nil, fmt.Errorf("load test warm-up failed: %w", err)
+	}
+	result.Warmup, result.WarmupRows = time.Since(started), warm.RowsIngested
+
+	// Measured Phase:
+	// - Shares differ by at most one record; with fewer records than workers, fewer run
+	rest := records[warmup:]
+	workers := min(concurrency, len(rest))
+	tl := timeline.New()
+	measured := timeline.WithTimeline(ctx, tl)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	started = time.Now()
+	for w := 0; w < workers; w++ {
+		share := rest[w*len(rest)/workers : (w+1)*len(rest)/workers]
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			res, err := c.IngestBLADEData(measured, loadTestShare(req, share, fmt.Sprintf("worker-%d", w+1)))
+			mu.Lock()
+			defer mu.Unlock()
+			result.Ingestions++
+			inserted, rejected := int64(0), int64(0)
+			if res != nil {
+				inserted, rejected = res.RowsIngested, res.RowsRejected
+			}
+			result.RowsIngested += inserted
+			result.RowsRejected += rejected
+			result.RowsFailed += max(int64(len(share))-inserted-rejected, 0)
+			if err != nil {
+				result.FailedIngestions++
+				if len(result.Errors) < 5 {
+					result.Errors = append(result.Errors, err.Error())
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	result.Duration = time.Since(started)
+	if seconds := result.Duration.Seconds(); seconds > 0 {
+		result.RowsPerSecond = float64(result.RowsIngested) / seconds
+	}
+	result.Statements = latencyStats(tl.Spans())
+	return result, nil
+}
+
+// Returns a copy of req loading records, tagged with the load test share in its metadata.
+func loadTestShare(req *IngestionRequest, records []json.RawMessage, share string) *IngestionRequest {
+	data, _ := json.Marshal(records)
+	copied := *req
+	copied.SampleData = string(data)
+	copied.Warnings = nil
+	copied.Metadata = make(map[string]string, len(req.Metadata)+1)
+	for key, value := range req.Metadata {
+		copied.Metadata[key] = value
+	}
+	copied.Metadata["load_test_share"] = share
+	return &copied
+}
+
+// Groups spans by phase and kind and computes nearest-rank percentiles of their durations.
+func latencyStats(spans []timeline.Span) []LatencyStats {
+	var order []string
+	durations := map[string][]time.Duration{}
+	stats := map[string]*LatencyStats{}
+	for _, span := range spans {
+		key := span.Phase + "/" + span.Kind
+		if stats[key] == nil {
+			order = append(order, key)
+			stats[key] = &LatencyStats{Phase: span.Phase, Kind: span.Kind}
+		}
+		stats[key].Count++
+		if span.Error != "" {
+			stats[key].Failed++
+		}
+		durations[key] = append(durations[key], span.End-span.Start)
+	}
+	result := make([]LatencyStats, 0, len(order))
+	for _, key := range order {
+		values := durations[key]
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		rank := func(p float64) time.Duration {
+			index := int(math.Ceil(p*float64(len(values)))) - 1
+			return values[max(index, 0)]
+		}
+		s := stats[key]
+		s.P50, s.P90, s.P99, s.Max = rank(0.50), rank(0.90), rank(0.99), values[len(values)-1]
+		result = append(result, *s)
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Every chunked INSERT, dedup and archive DELETE leaves small files and
+//   tombstoned ones behind in a Delta table. OPTIMIZE compacts the small files (and
+//   Z-orders them so item_id lookups and time range filters skip most of them), VACUUM
+//   deletes the files no table version within the retention period still references.
+
+//   Notes:
+//   - Both run as their own commands (optimize, vacuum), not after each ingestion: they
+//     rewrite or delete files and take minutes on a large table
+//   - VACUUM ends time travel beyond the retention period, so restoring a batch with
+//     RESTORE ... VERSION AS OF is only possible within it
+
+// Columns the BLADE tables are Z-ordered by: point lookups by item_id, time range filters.
+var DefaultZOrder = []string{"item_id", "timestamp"}
+
+// Retention VACUUM keeps by default, and the shortest Delta accepts without turning off
+// its retention check (which a SQL warehouse can't do).
+const MinVacuumRetention = 7 * 24 * time.Hour
+
+// Outcome of OPTIMIZE on one table.
+//   - FilesAdded/FilesRemoved: From the statement's metrics (0 when it didn't return any)
+type OptimizeResult struct {
+	Table        string        `json:"table"`
+	ZOrderBy     []string      `json:"zorderBy,omitempty"`
+	FilesAdded   int64         `json:"filesAdded"`
+	FilesRemoved int64         `json:"filesRemoved"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// Outcome of VACUUM on one table.
+//   - Files: With DryRun, the files VACUUM would delete (Databricks lists up to 1000)
+type VacuumResult struct {
+	Table     string        `json:"table"`
+	Retention time.Duration `json:"retention"`
+	DryRun    bool          `json:"dryRun,omitempty"`
+	Files     []string      `json:"files,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Compacts a table of the configured schema, Z-ordering it by zorder (none: compaction only).
+func (c *Client) OptimizeTable(ctx context.Context, table string, zorder []string) (*OptimizeResult, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	for _, column := range zorder {
+		if !tableNamePattern.MatchString(column) {
+			return nil, fmt.Errorf("invalid Z-order column %q", column)
+		}
+	}
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table)
+	statement := "OPTIMIZE " + fullName
+	if len(zorder) > 0 {
+		statement += " ZORDER BY (" + strings.Join(zorder, ", ") + ")"
+	}
+
+	runlog.Printf(ctx, "Optimizing %s", fullName)
+	start := time.Now()
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{Statement: statement})
+	if err != nil {
+		return nil, fmt.Errorf("failed to optimize %s: %w", fullName, err)
+	}
+	result := &OptimizeResult{Table: table, ZOrderBy: zorder, Duration: time.Since(start)}
+
+	// - OPTIMIZE returns one row: the table path and a metrics struct, serialized as JSON
+	if len(rows) > 0 && len(rows[0]) > 1 {
+		var metrics struct {
+			NumFilesAdded   int64 `json:"numFilesAdded"`
+			NumFilesRemoved int64 `json:"numFilesRemoved"`
+		}
+		if json.Unmarshal([]byte(rows[0][1]), &metrics) == nil {
+			result.FilesAdded, result.FilesRemoved = metrics.NumFilesAdded, metrics.NumFilesRemoved
+		}
+	}
+	runlog.Printf(ctx, "Optimized %s: %d file(s) removed, %d added", fullName, result.FilesRemoved, result.FilesAdded)
+	return result, nil
+}
+
+// Deletes the files of a table of the configured schema that no version within retention references.
+//   - retention: At least MinVacuumRetention; rounded up to whole hours
+//   - dryRun: Lists the files instead of deleting them
+func (c *Client) VacuumTable(ctx context.Context, table string, retention time.Duration, dryRun bool) (*VacuumResult, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	if retention < MinVacuumRetention {
+		return nil, fmt.Errorf("VACUUM retention %s is below Delta's minimum of %s", retention, MinVacuumRetention)
+	}
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table)
+	statement := fmt.Sprintf("VACUUM %s RETAIN %d HOURS", fullName, int64(math.Ceil(retention.Hours())))
+	if dryRun {
+		statement += " DRY RUN"
+	}
+
+	runlog.Printf(ctx, "Vacuuming %s (retaining %s)", fullName, retention)
+	start := time.Now()
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{Statement: statement})
+	if err != nil {
+		return nil, fmt.Errorf("failed to vacuum %s: %w", fullName, err)
+	}
+	result := &VacuumResult{Table: table, Retention: retention, DryRun: dryRun, Duration: time.Since(start)}
+	if dryRun {
+		for _, row := range rows {
+			if len(row) > 0 {
+				result.Files = append(result.Files, row[0])
+			}
+		}
+		runlog.Printf(ctx, "VACUUM of %s would delete %d file(s)", fullName, len(result.Files))
+	}
+	return result, nil
+}
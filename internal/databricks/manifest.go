@@ -0,0 +1,379 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/timeline"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Re-running a load after a timeout or a crash used to insert its records a
+//   second time. With a batch manifest every load is recorded in blade_ingestion_batches
+//   under the hash of its source, so delivering the same source again is recognized: a
+//   load that completed is skipped, one that didn't is resumed under its batch ID.
+
+//   Decisions (BLADE_BATCH_MANIFEST=true, per target table):
+//   - load: The source hasn't been loaded into the table; a new batch is recorded
+//   - skip: The source was loaded and completed; nothing is written and the result
+//     names the batch that holds it
+//   - resume: An earlier load failed, stopped partway or never finished; the source is
+//     loaded again under the same batch ID, after clearing what the earlier load wrote, or
+//     only the insert chunks it didn't commit (see Checkpoints)
+//   - ResumeBatchID (ingest --resume) names the batch to resume instead of looking the
+//     source up; the batch must be in the manifest with the same source
+//   - Sources are hashed like the content batch ID strategy (data type, table, source
+//     path and version, and the records or file); Records streams have no hash and are
+//     always loaded
+//   - Two concurrent loads of the same source both see it unloaded; the manifest guards
+//     re-runs, not parallel ones
+
+//   Checkpoints:
+//   - A direct-mode load of records (mock data, record files, streams) records the insert
+//     chunks it committed (BLADE_INSERT_CHUNK_SIZE records each) when it ends, however it ends
+//   - Resuming it keeps those rows and only replays the other chunks; the crew and child
+//     tables, and the records the rules rejected, are rewritten with them
+//   - Chunks are matched by position, so the earlier load must have used the same chunk
+//     size; dedup, staged loads, COPY INTO and a chunk left half-written (some of its
+//     records inserted one by one before it failed) clear the whole batch instead
+
+// Table holding one row per batch when BLADE_BATCH_MANIFEST is on.
+const BatchManifestTable = "blade_ingestion_batches"
+
+// What the manifest decided for a load (BatchDecision.Action).
+const (
+	ManifestLoad   = "load"
+	ManifestSkip   = "skip"
+	ManifestResume = "resume"
+)
+
+// Manifest status of a batch that is being loaded, and of one RollbackBatch removed (a
+// later delivery of its source resumes it).
+const (
+	manifestRunning    = "running"
+	manifestRolledBack = "rolled_back"
+)
+
+// The manifest's decision for a load, reported on its result.
+//   - BatchID: The batch the load wrote to, or for a skip the batch already holding the source
+//   - PreviousStatus, PreviousRows: The earlier load of the source, for skip and resume
+//   - CommittedChunks: Insert chunks a resume kept from the earlier load (see Checkpoints)
+type BatchDecision struct {
+	Action          string `json:"action"`
+	SourceHash      string `json:"sourceHash,omitempty"`
+	BatchID         string `json:"batchId"`
+	PreviousStatus  string `json:"previousStatus,omitempty"`
+	PreviousRows    int64  `json:"previousRows,omitempty"`
+	CommittedChunks int    `json:"committedChunks,omitempty"`
+
+	// - checkpoint: Rows per committed chunk of the earlier load, by chunk index; nil when
+	//   it can't be resumed chunk by chunk
+	checkpoint map[int]int64
+}
+
+// Runs a load through the batch manifest: skips a source already loaded, resumes one
+// whose load didn't complete, and records the outcome.
+//   - Without a manifest, and for requests that fail validation, runs the pipeline as is
+//   - A manifest that can't be read or written before the load fails it, since the load
+//     could then repeat an earlier one; failing to record the outcome is a warning
+func (c *Client) ingest(ctx context.Context, req *IngestionRequest) (*IngestionResult, error) {
+	start := time.Now()
+	failed := func(err error) (*IngestionResult, error) {
+		return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err, Duration: time.Since(start)}, err
+	}
+	if req.ResumeBatchID != "" && !c.batchManifest {
+		return failed(fmt.Errorf("can't resume batch %s without the batch manifest (BLADE_BATCH_MANIFEST=true)", req.ResumeBatchID))
+	}
+	if !c.batchManifest || req.Validate() != nil {
+		return c.ingestBatch(ctx, req)
+	}
+	manifestCtx := timeline.WithPhase(ctx, "manifest")
+	decision, err := c.decideBatch(manifestCtx, req)
+	if err != nil {
+		return failed(err)
+	}
+
+	switch decision.Action {
+	case ManifestSkip:
+		runlog.Printf(ctx, "Skipping %s: its source was loaded by batch %s (%d rows)", req.TableName, decision.BatchID, decision.PreviousRows)
+		result := &IngestionResult{
+			TableName: req.TableName,
+			Status:    "completed",
+			Duration:  time.Since(start),
+			Warnings:  append([]Warning(nil), req.Warnings...),
+			Manifest:  decision,
+			Metadata: map[string]interface{}{
+				"data_source":    req.DataSource,
+				"blade_metadata": req.Metadata,
+				"ingestion_type": ingestionType(req),
+				"batch_id":       decision.BatchID,
+			},
+		}
+		if c.tenant != "" {
+			result.Metadata["tenant"] = c.tenant
+		}
+		return result, nil
+	case ManifestResume:
+		if !c.resumesChunks(req, decision) {
+			decision.checkpoint = nil
+		}
+		decision.CommittedChunks = len(decision.checkpoint)
+		runlog.Printf(ctx, "Resuming batch %s of %s (last %s, %d insert chunk(s) committed)", decision.BatchID, req.TableName, decision.PreviousStatus, decision.CommittedChunks)
+		if err := c.clearBatch(manifestCtx, req, decision); err != nil {
+			return failed(err)
+		}
+	default:
+		decision.BatchID = c.newBatchID(req)
+	}
+	if err := c.startManifestBatch(manifestCtx, req, decision); err != nil {
+		return failed(err)
+	}
+
+	batchReq := *req
+	batchReq.batchID, batchReq.committedChunks = decision.BatchID, decision.checkpoint
+	result, err := c.ingestBatch(ctx, &batchReq)
+	if result == nil {
+		result = &IngestionResult{TableName: req.TableName, Status: "failed", Error: err, Duration: time.Since(start)}
+	}
+	result.Manifest = decision
+	if recordErr := c.finishManifestBatch(manifestCtx, req, decision.BatchID, result, err); recordErr != nil {
+		result.warn(ctx, WarnManifest, "could not record batch %s in %s: %v", decision.BatchID, BatchManifestTable, recordErr)
+	}
+	return result, err
+}
+
+// Creates the manifest table (and its schema) if missing and decides what to do with the
+// request's source.
+func (c *Client) decideBatch(ctx context.Context, req *IngestionRequest) (*BatchDecision, error) {
+	if err := c.ensureCatalogAndSchema(ctx); err != nil {
+		return nil, err
+	}
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, BatchManifestTable)
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				batch_id STRING NOT NULL COMMENT 'metadata[''batch_id''] of the batch''s rows',
+				table_name STRING NOT NULL,
+				data_type STRING,
+				source_hash STRING COMMENT 'SHA-256 of the source (empty for Records streams)',
+				source_path STRING,
+				row_count BIGINT,
+				status STRING COMMENT 'running, completed, partial, failed or rolled_back',
+				attempts INT,
+				error STRING,
+				chunk_size INT COMMENT 'BLADE_INSERT_CHUNK_SIZE of the last attempt (NULL without a checkpoint)',
+				committed_chunks STRING COMMENT 'Insert chunks the last attempt committed, as index:rows pairs',
+				tenant STRING,
+				started_at TIMESTAMP,
+				finished_at TIMESTAMP
+			)
+		`, table),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create batch manifest %s: %w", table, err)
+	}
+	c.applyCostTags(ctx, "TABLE", table)
+
+	// - A named batch is looked up by its ID, other sources by their hash
+	decision := &BatchDecision{Action: ManifestLoad, SourceHash: sourceHash(req)}
+	if decision.SourceHash == "" && req.ResumeBatchID == "" {
+		return decision, nil
+	}
+	var params paramList
+	where := fmt.Sprintf("table_name = %s AND ", params.text(req.TableName))
+	if req.ResumeBatchID != "" {
+		where += fmt.Sprintf("batch_id = %s", params.text(req.ResumeBatchID))
+	} else {
+		where += fmt.Sprintf("source_hash = %s", params.text(decision.SourceHash))
+	}
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			SELECT batch_id, status, row_count, source_hash, chunk_size, committed_chunks FROM %s
+			WHERE %s
+			ORDER BY started_at DESC
+			LIMIT 1
+		`, table, where),
+		Parameters: params.params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch manifest %s: %w", table, err)
+	}
+	if len(rows) == 0 || len(rows[0]) < 6 {
+		if req.ResumeBatchID != "" {
+			return nil, fmt.Errorf("batch %s of %s isn't in the batch manifest %s", req.ResumeBatchID, req.TableName, table)
+		}
+		return decision, nil
+	}
+	row := rows[0]
+	if req.ResumeBatchID != "" && row[3] != decision.SourceHash {
+		return nil, fmt.Errorf("batch %s of %s was loaded from a different source: resume it with the source it was loaded from", req.ResumeBatchID, req.TableName)
+	}
+	decision.BatchID, decision.PreviousStatus = row[0], row[1]
+	decision.PreviousRows, _ = strconv.ParseInt(row[2], 10, 64)
+	if chunkSize, err := strconv.Atoi(row[4]); err == nil && chunkSize == c.insertChunkSize {
+		decision.checkpoint = parseCommittedChunks(row[5])
+	}
+	if decision.PreviousStatus == "completed" {
+		decision.Action = ManifestSkip
+	} else {
+		decision.Action = ManifestResume
+	}
+	return decision, nil
+}
+
+// Reports whether a resumed load can keep the chunks its earlier load committed: a direct-mode
+// load of records without dedup, whose earlier load recorded a checkpoint (see Checkpoints).
+func (c *Client) resumesChunks(req *IngestionRequest, decision *BatchDecision) bool {
+	return decision.checkpoint != nil && c.loadMode != LoadModeStaged && req.Dedup == nil &&
+		(recordMode(req) || req.Metadata["mode"] == ModeRecordStream)
+}
+
+// Deletes what an unfinished load of a batch left in the request's tables, so the resumed
+// load doesn't repeat it. Tables the load never created are left alone.
+//   - Without a checkpoint: the batch's rows in the table, its crew and child tables, and
+//     its rejected records
+//   - With one: the crew and child rows and the parse and rules rejections, which a record
+//     load rewrites in full after its insert; streams write them with each chunk, so the
+//     chunks they replay clear their own (see clearChunkRejects)
+func (c *Client) clearBatch(ctx context.Context, req *IngestionRequest, decision *BatchDecision) error {
+	type target struct{ table, condition string }
+	var targets []target
+	stream := req.Metadata["mode"] == ModeRecordStream
+	if decision.checkpoint == nil {
+		targets = append(targets, target{req.TableName, "metadata['batch_id'] = %s"}, target{RejectTable + req.tableSuffix, "batch_id = %s"})
+	} else if !stream {
+		targets = append(targets, target{RejectTable + req.tableSuffix, fmt.Sprintf("batch_id = %%s AND stage <> '%s'", RejectStageInsert)})
+	}
+	if decision.checkpoint == nil || !stream {
+		for _, child := range req.ChildTables {
+			targets = append(targets, target{child.Table, "batch_id = %s"})
+		}
+	}
+	for _, t := range targets {
+		var params paramList
+		table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, t.table)
+		_, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+			Statement:  fmt.Sprintf("DELETE FROM %s WHERE %s", table, fmt.Sprintf(t.condition, params.text(decision.BatchID))),
+			Parameters: params.params,
+		})
+		if remedy, ok := Remediate(err); ok && remedy.Code == RemedyTableNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to clear batch %s from %s: %w", decision.BatchID, table, err)
+		}
+	}
+	return nil
+}
+
+// Deletes the parse and rules rejections an earlier attempt of a batch left for the records
+// of a stream chunk it replays, from position from up to (not including) to; a negative to
+// leaves the range open, for the last chunk.
+func (c *Client) clearChunkRejects(ctx context.Context, req *IngestionRequest, batchID string, from, to int) error {
+	var params paramList
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, RejectTable+req.tableSuffix)
+	condition := fmt.Sprintf("batch_id = %s AND stage <> '%s' AND record_index >= %d", params.text(batchID), RejectStageInsert, from)
+	if to >= 0 {
+		condition += fmt.Sprintf(" AND record_index < %d", to)
+	}
+	_, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement:  fmt.Sprintf("DELETE FROM %s WHERE %s", table, condition),
+		Parameters: params.params,
+	})
+	if remedy, ok := Remediate(err); ok && remedy.Code == RemedyTableNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to clear earlier rejections of batch %s from %s: %w", batchID, table, err)
+	}
+	return nil
+}
+
+// Records a batch as running: a new row for a new batch, another attempt for a resumed one.
+func (c *Client) startManifestBatch(ctx context.Context, req *IngestionRequest, decision *BatchDecision) error {
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, BatchManifestTable)
+	var params paramList
+	var statement string
+	if decision.Action == ManifestLoad {
+		statement = fmt.Sprintf(`
+			INSERT INTO %s (batch_id, table_name, data_type, source_hash, source_path, row_count, status, attempts, error, tenant, started_at, finished_at)
+			VALUES (%s, %s, %s, %s, %s, 0, '%s', 1, NULL, %s, current_timestamp(), NULL)
+		`, table, params.text(decision.BatchID), params.text(req.TableName), params.text(req.Metadata["data_type"]),
+			params.text(decision.SourceHash), params.text(req.SourcePath), manifestRunning, params.bind(c.tenant, "STRING"))
+	} else {
+		statement = fmt.Sprintf(`
+			UPDATE %s SET status = '%s', attempts = attempts + 1, error = NULL, started_at = current_timestamp(), finished_at = NULL
+			WHERE batch_id = %s AND table_name = %s
+		`, table, manifestRunning, params.text(decision.BatchID), params.text(req.TableName))
+	}
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{Statement: statement, Parameters: params.params}); err != nil {
+		return fmt.Errorf("failed to record batch %s in %s: %w", decision.BatchID, table, err)
+	}
+	return nil
+}
+
+// Records how a batch's load ended, with the insert chunks it committed (see Checkpoints).
+func (c *Client) finishManifestBatch(ctx context.Context, req *IngestionRequest, batchID string, result *IngestionResult, loadErr error) error {
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, BatchManifestTable)
+	message := ""
+	if loadErr != nil {
+		message = loadErr.Error()
+	}
+	chunkSize, committed := "NULL", committedChunks(result.Chunks)
+	if committed != nil && c.loadMode != LoadModeStaged {
+		chunkSize = strconv.Itoa(c.insertChunkSize)
+	}
+	var params paramList
+	_, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			UPDATE %s SET status = %s, row_count = %d, error = %s, chunk_size = %s, committed_chunks = %s, finished_at = current_timestamp()
+			WHERE batch_id = %s AND table_name = %s
+		`, table, params.text(result.Status), result.RowsIngested, params.bind(message, "STRING"), chunkSize,
+			params.text(strings.Join(committed, ",")), params.text(batchID), params.text(req.TableName)),
+		Parameters: params.params,
+	})
+	return err
+}
+
+// Returns the committed chunks of a load as index:rows pairs, or nil when it has no chunks
+// or left one half-written (some rows in, then failed), which only clearing the batch undoes.
+func committedChunks(chunks []InsertChunk) []string {
+	if len(chunks) == 0 {
+		return nil
+	}
+	committed := []string{}
+	for _, chunk := range chunks {
+		if chunk.Error != "" && chunk.Rows > 0 {
+			return nil
+		}
+		if chunk.Error == "" {
+			committed = append(committed, fmt.Sprintf("%d:%d", chunk.Index, chunk.Rows))
+		}
+	}
+	return committed
+}
+
+// Parses the committed_chunks of a manifest row; an empty list is a checkpoint with no
+// chunk committed, and anything unreadable is no checkpoint (nil).
+func parseCommittedChunks(text string) map[int]int64 {
+	checkpoint := make(map[int]int64)
+	for _, pair := range strings.Split(text, ",") {
+		if pair == "" {
+			continue
+		}
+		index, rows, _ := strings.Cut(pair, ":")
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			return nil
+		}
+		n, err := strconv.ParseInt(rows, 10, 64)
+		if err != nil {
+			return nil
+		}
+		checkpoint[i] = n
+	}
+	return checkpoint
+}
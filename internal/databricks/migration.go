@@ -0,0 +1,159 @@
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Keeps existing tables in line with their mapping. CREATE TABLE IF NOT EXISTS
+//   leaves a table created from an older mapping untouched, so after it runs the table's
+//   columns are compared with the ones the mapping declares: missing columns are added,
+//   changed types are refused unless the table may be dropped and recreated.
+
+// Returned when a table's columns conflict with its mapping and it may not be recreated.
+var ErrIncompatibleSchema = errors.New("incompatible table schema")
+
+// Types of the standard columns, as in the CREATE TABLE statement.
+var standardColumnTypes = map[string]string{
+	"item_id":                "STRING",
+	"item_type":              "STRING",
+	"classification_marking": "STRING",
+	"timestamp":              "TIMESTAMP",
+	"data_source":            "STRING",
+	"raw_data":               "STRING",
+	"ingestion_timestamp":    "TIMESTAMP",
+	"metadata":               "MAP<STRING, STRING>",
+}
+
+// Differences between a table's columns and its mapping.
+//   - Add: Declared columns the table lacks; added with ALTER TABLE ADD COLUMNS
+//   - Incompatible: Columns whose type changed ("parts_cost: STRING -> DOUBLE"); only
+//     fixed by recreating the table
+//   - Extra: Table columns the mapping no longer declares; kept, and NULL in new loads
+type SchemaPlan struct {
+	Table        string        `json:"table"`
+	Add          []TypedColumn `json:"add,omitempty"`
+	Incompatible []string      `json:"incompatible,omitempty"`
+	Extra        []string      `json:"extra,omitempty"`
+}
+
+// Compares the request's table with the columns its mapping declares.
+//   - A table that doesn't exist (or isn't visible yet) yields an empty plan
+func (c *Client) PlanSchemaMigration(ctx context.Context, req *IngestionRequest) (*SchemaPlan, error) {
+	existing, err := c.DescribeColumns(ctx, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	plan := &SchemaPlan{Table: req.TableName}
+	if len(existing) == 0 {
+		return plan, nil
+	}
+
+	// Comparison:
+	// - Names are case-insensitive, as in Unity Catalog
+	// - Types are compared without case and spaces (information_schema reports
+	//   "map<string,string>" for MAP<STRING, STRING>)
+	current := make(map[string]string, len(existing))
+	for _, col := range existing {
+		current[strings.ToLower(col.Name)] = col.DataType
+	}
+	declared := make([]TypedColumn, 0, len(StandardColumns)+len(req.Columns))
+	for _, name := range StandardColumns {
+		declared = append(declared, TypedColumn{Name: name, Type: standardColumnTypes[name]})
+	}
+	declared = append(declared, req.Columns...)
+
+	wanted := make(map[string]bool, len(declared))
+	for _, col := range declared {
+		name := strings.ToLower(col.Name)
+		wanted[name] = true
+		sqlType := declaredType(col)
+		actual, exists := current[name]
+		switch {
+		case !exists:
+			plan.Add = append(plan.Add, col)
+		case normalizeColumnType(actual) != normalizeColumnType(sqlType):
+			plan.Incompatible = append(plan.Incompatible, fmt.Sprintf("%s: %s -> %s", col.Name, strings.ToUpper(actual), sqlType))
+		}
+	}
+	// - Generated partition columns are the table's own, never added to an existing table
+	for _, col := range req.Layout.generatedColumns() {
+		wanted[strings.ToLower(col.name)] = true
+	}
+	for _, col := range existing {
+		if !wanted[strings.ToLower(col.Name)] {
+			plan.Extra = append(plan.Extra, col.Name)
+		}
+	}
+	return plan, nil
+}
+
+func normalizeColumnType(sqlType string) string {
+	return strings.ReplaceAll(strings.ToUpper(sqlType), " ", "")
+}
+
+// Brings the request's existing table in line with its mapping (see PlanSchemaMigration).
+//   - Missing columns are added; existing rows read NULL for them
+//   - Changed types fail with ErrIncompatibleSchema unless req.ForceRecreate is set, in
+//     which case the table is dropped and recreated with createTableSQL (its rows are lost)
+//   - EXTERNAL tables are never dropped: their files would outlive the table and the
+//     recreated one would pick up the old schema again
+func (c *Client) migrateTable(ctx context.Context, req *IngestionRequest, createTableSQL string) error {
+	plan, err := c.PlanSchemaMigration(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to check the schema of table %s: %w", req.TableName, err)
+	}
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName)
+
+	if len(plan.Extra) > 0 {
+		runlog.Printf(ctx, "Table %s has columns its mapping no longer declares (kept, NULL in new loads): %s", table, strings.Join(plan.Extra, ", "))
+	}
+
+	if len(plan.Incompatible) > 0 {
+		changes := strings.Join(plan.Incompatible, "; ")
+		if !req.ForceRecreate {
+			return fmt.Errorf("%w: table %s no longer matches its mapping (%s); rerun with --force-recreate to drop and recreate it, losing its rows", ErrIncompatibleSchema, table, changes)
+		}
+		if strings.EqualFold(req.TableType, ExternalTable) {
+			return fmt.Errorf("%w: external table %s no longer matches its mapping (%s) and is not recreated automatically; drop it and move its files first", ErrIncompatibleSchema, table, changes)
+		}
+		runlog.Printf(ctx, "Recreating table %s for changed column types (%s)", table, changes)
+		if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{Statement: fmt.Sprintf("DROP TABLE IF EXISTS %s", table)}); err != nil {
+			return fmt.Errorf("failed to drop table %s: %w", table, err)
+		}
+		if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{Statement: createTableSQL}); err != nil {
+			return fmt.Errorf("failed to recreate table %s: %w", table, err)
+		}
+		c.forgetCostTags("TABLE", table)
+		return nil
+	}
+
+	if len(plan.Add) == 0 {
+		return nil
+	}
+	addColumns := strings.TrimPrefix(typedColumnsDDL(plan.Add), ",")
+	names := make([]string, len(plan.Add))
+	for i, col := range plan.Add {
+		names[i] = col.Name
+	}
+	runlog.Printf(ctx, "Adding columns to table %s: %s", table, strings.Join(names, ", "))
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("ALTER TABLE %s ADD COLUMNS (%s\n\t\t)", table, addColumns),
+	}); err != nil {
+		return fmt.Errorf("failed to add columns to table %s: %w", table, err)
+	}
+	return nil
+}
+
+// Returns the column's SQL type; the standard metadata column's MAP type passes through as declared.
+func declaredType(col TypedColumn) string {
+	if sqlType, err := col.SQLType(); err == nil {
+		return sqlType
+	}
+	return strings.ToUpper(col.Type)
+}
@@ -0,0 +1,106 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// A StatementExecutor that records every statement and answers it without a workspace.
+//   - Respond: Answers a submitted statement; nil answers each one SUCCEEDED without rows
+//   - Poll: Answers GetStatement for a statement answered PENDING or RUNNING; nil reports
+//     it SUCCEEDED
+//   - Statement IDs missing from an answer are filled in (mock-1, mock-2, ...)
+//
+// Safe for concurrent use; the zero value is ready to use.
+type MockStatementExecutor struct {
+	Respond func(req sql.ExecuteStatementRequest) (*sql.StatementResponse, error)
+	Poll    func(statementID string) (*sql.StatementResponse, error)
+
+	mu       sync.Mutex
+	requests []sql.ExecuteStatementRequest
+	canceled []string
+}
+
+func (m *MockStatementExecutor) ExecuteStatement(ctx context.Context, req sql.ExecuteStatementRequest) (*sql.StatementResponse, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, req)
+	statementID := fmt.Sprintf("mock-%d", len(m.requests))
+	m.mu.Unlock()
+
+	resp := MockSucceeded()
+	if m.Respond != nil {
+		var err error
+		if resp, err = m.Respond(req); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatementId == "" {
+		resp.StatementId = statementID
+	}
+	return resp, nil
+}
+
+func (m *MockStatementExecutor) GetStatementByStatementId(ctx context.Context, statementID string) (*sql.StatementResponse, error) {
+	resp := MockSucceeded()
+	if m.Poll != nil {
+		var err error
+		if resp, err = m.Poll(statementID); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatementId == "" {
+		resp.StatementId = statementID
+	}
+	return resp, nil
+}
+
+func (m *MockStatementExecutor) CancelExecution(ctx context.Context, req sql.CancelExecutionRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.canceled = append(m.canceled, req.StatementId)
+	return nil
+}
+
+// Returns the statements submitted so far, in order.
+func (m *MockStatementExecutor) Requests() []sql.ExecuteStatementRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]sql.ExecuteStatementRequest(nil), m.requests...)
+}
+
+// Returns the SQL text of the statements submitted so far, in order.
+func (m *MockStatementExecutor) Statements() []string {
+	requests := m.Requests()
+	statements := make([]string, len(requests))
+	for i, req := range requests {
+		statements[i] = req.Statement
+	}
+	return statements
+}
+
+// Returns the IDs of the statements the client canceled.
+func (m *MockStatementExecutor) Canceled() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.canceled...)
+}
+
+// Returns a SUCCEEDED statement response holding rows as its inline result.
+func MockSucceeded(rows ...[]string) *sql.StatementResponse {
+	resp := &sql.StatementResponse{Status: &sql.StatementStatus{State: sql.StatementStateSucceeded}}
+	if len(rows) > 0 {
+		resp.Result = &sql.ResultData{DataArray: rows, RowCount: int64(len(rows))}
+	}
+	return resp
+}
+
+// Returns a FAILED statement response carrying a Databricks error code and message.
+func MockFailed(code, message string) *sql.StatementResponse {
+	return &sql.StatementResponse{Status: &sql.StatementStatus{
+		State: sql.StatementStateFailed,
+		Error: &sql.ServiceError{ErrorCode: sql.ServiceErrorCode(code), Message: message},
+	}}
+}
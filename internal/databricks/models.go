@@ -2,28 +2,107 @@ package databricks
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
 // Encapsulates all parameters needed for a data ingestion operation.
 type IngestionRequest struct {
 	TableName     string            `json:"tableName"`
+	Catalog       string            `json:"catalog,omitempty"` // overrides the client's catalog for this load (see InNamespace)
+	Schema        string            `json:"schema,omitempty"`  // overrides the client's schema for this load (see InNamespace)
 	SourcePath    string            `json:"sourcePath"`
-	FileFormat    string            `json:"fileFormat"` // JSON or CSV
-	FormatOptions string            `json:"formatOptions"`
+	FileFormat    string            `json:"fileFormat"` // JSON or CSV (copy_into mode: JSON, CSV or PARQUET)
+	FormatOptions string            `json:"formatOptions"` // copy_into mode: COPY INTO FORMAT_OPTIONS, e.g. 'multiLine' = 'true'
 	DataSource    string            `json:"dataSource"`  // BLADE/ADVANA
 	SampleData    string            `json:"sampleData,omitempty"` // for PoC
 	Metadata      map[string]string `json:"metadata"`
+	TableType     string            `json:"tableType,omitempty"`   // MANAGED (default) or EXTERNAL
+	StoragePath   string            `json:"storagePath,omitempty"` // EXTERNAL only: relative to the configured external location, or a full URL
+	Validations   []ValidationRule  `json:"validations,omitempty"` // post-load checks run server-side after insert
+	ChildTables   []ChildTable      `json:"childTables,omitempty"` // one-to-many arrays materialized into their own tables
+	TTL           *TTLPolicy        `json:"ttl,omitempty"`         // per-row expiry in metadata['expires_at'] plus a view of unexpired rows
+	Columns       []TypedColumn     `json:"columns,omitempty"`     // typed columns filled from record fields, after the standard columns
+	Layout        *TableLayout      `json:"layout,omitempty"`      // partitioning or liquid clustering applied when the table is created (see layout.go)
+	ForceRecreate bool              `json:"forceRecreate,omitempty"` // drop and recreate the table when its column types no longer match the mapping
+	Dedup         *DedupPolicy      `json:"dedup,omitempty"`         // skip records whose content hash the table already holds
+	Warnings      []Warning         `json:"warnings,omitempty"`      // non-fatal conditions found while preparing the records, carried into the result
+	SourceColumns map[string]string `json:"sourceColumns,omitempty"` // copy_into mode: record field -> file column, for files that name fields differently (Advana snapshots)
+	Records       RecordSource      `json:"-"`                       // record_stream mode: records pulled chunk by chunk (see records.go); replaces the file:// SourcePath
+	Rules         *RecordRules      `json:"rules,omitempty"`         // per-record checks run before the records are loaded (see rules.go)
+	Rejects       []Rejection       `json:"-"`                       // records rejected while preparing the request (malformed CSV rows), settled by the Rules' mode (see rejects.go)
+	ResumeBatchID string            `json:"resumeBatchId,omitempty"` // batch manifest: resume this batch, replaying only the insert chunks it didn't commit (see manifest.go)
+
+	tableSuffix string // set on classification-routed copies; also applies to the reject table
+	batchID     string // set by the batch manifest for new and resumed batches (see manifest.go)
+	committedChunks map[int]int64 // set by the batch manifest on resumed batches: rows of the insert chunks already committed, by chunk index
 }
 
 // Contains the results and statistics from a completed ingestion operation.
 type IngestionResult struct {
 	RowsIngested int64 `json:"rowsIngested"`
+	RowsSkipped int64 `json:"rowsSkipped,omitempty"` // records dropped as duplicates by the dedup policy
+	RowsRejected int64 `json:"rowsRejected,omitempty"` // records that broke the table's rules (skipped or quarantined)
+	Rejections []Rejection `json:"rejections,omitempty"` // the first rejected records and why (see rules.go)
 	Duration time.Duration `json:"duration"`
 	TableName string `json:"tableName"`
 	Status string `json:"status"`
 	Error error `json:"error,omitempty"`
 	Metadata map[string]interface{} `json:"metadata"`
+	Validations []ValidationResult `json:"validations,omitempty"`
+	Verification *SampleVerification `json:"verification,omitempty"`
+	RowCount *RowCountCheck `json:"rowCount,omitempty"` // table counted before and after the load (see rowcount.go)
+	Chunks []InsertChunk `json:"chunks,omitempty"` // one per INSERT statement of the main table
+	Routes []RouteResult `json:"routes,omitempty"` // per-target results when routed by classification
+	Warnings []Warning `json:"warnings,omitempty"` // non-fatal conditions of the load (see warnings.go)
+	ThrottleTime time.Duration `json:"throttleTime,omitempty"` // time spent waiting out workspace rate limits (see throttle.go)
+	ThrottledRequests int `json:"throttledRequests,omitempty"` // API calls answered with 429 (or 503 + Retry-After)
+	Manifest *BatchDecision `json:"manifest,omitempty"` // whether the batch manifest loaded, skipped or resumed the source (see manifest.go)
+}
+
+// Outcome of one INSERT statement of a chunked load.
+//   - Offset: Position of the chunk's first record in the source (0-based)
+//   - Rows: Records committed by the chunk (0 when it failed, unless retried record by record)
+//   - Rejected: Records refused when the failed chunk was retried record by record (see rejects.go)
+//   - Resumed: Committed by an earlier attempt of the batch and not inserted again (see manifest.go)
+type InsertChunk struct {
+	Index       int           `json:"index"`
+	Offset      int           `json:"offset"`
+	Rows        int64         `json:"rows"`
+	Rejected    int           `json:"rejected,omitempty"`
+	StatementID string        `json:"statementId,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Error       string        `json:"error,omitempty"`
+	Resumed     bool          `json:"resumed,omitempty"`
+}
+
+// Validation rule severities: "error" fails the run, "warn" is only recorded.
+const (
+	SeverityError = "error"
+	SeverityWarn  = "warn"
+)
+
+// A post-load data quality check executed server-side against the ingested batch.
+//   - Condition: SQL predicate matching violating rows (e.g. "item_id IS NULL")
+//   - SQL: Custom query returning a single violation count; {table} expands to the
+//     three-part table name and :batch_id is bound to the current batch
+//   - Severity: "error" (default) or "warn"
+type ValidationRule struct {
+	Name      string `json:"name"`
+	Condition string `json:"condition,omitempty"`
+	SQL       string `json:"sql,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+}
+
+// Contains the outcome of a single post-load validation rule.
+type ValidationResult struct {
+	Name       string `json:"name"`
+	Severity   string `json:"severity"`
+	Passed     bool   `json:"passed"`
+	Violations int64  `json:"violations"`
+	Error      string `json:"error,omitempty"`
 }
 
 // Convenience method for serializing results to JSON.
@@ -35,9 +114,125 @@ func (r *IngestionResult) ToJSON() []byte {
 // Type-safe constants for BLADE data types.
 type BLADEDataType string
 
+// Unity Catalog table types a mapping can request.
+const (
+	ManagedTable  = "MANAGED"
+	ExternalTable = "EXTERNAL"
+)
+
 const (
 	MaintenanceData BLADEDataType = "maintenance"
 	SortieData BLADEDataType = "sortie"
 	DeploymentData BLADEDataType = "deployment"
 	LogisticsData BLADEDataType = "logistics"
-)
\ No newline at end of file
+)
+// Unity Catalog table names the client will interpolate into SQL: a plain identifier, no quoting or dots.
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,254}$`)
+
+// Checks the request before any SQL is issued so programmatic callers get a specific error.
+func (r *IngestionRequest) Validate() error {
+	// Table & Source:
+	// - TableName is interpolated into DDL/DML, so it must be a bare identifier
+	// - DataSource is written to every row and must be present
+	if !tableNamePattern.MatchString(r.TableName) {
+		return fmt.Errorf("invalid table name %q: use letters, digits and underscores, starting with a letter or underscore", r.TableName)
+	}
+	if strings.TrimSpace(r.DataSource) == "" {
+		return fmt.Errorf("data source is required")
+	}
+	// - Catalog/Schema overrides are interpolated like the table name
+	if r.Catalog != "" && !tableNamePattern.MatchString(r.Catalog) {
+		return fmt.Errorf("invalid catalog %q: use letters, digits and underscores, starting with a letter or underscore", r.Catalog)
+	}
+	if r.Schema != "" && !tableNamePattern.MatchString(r.Schema) {
+		return fmt.Errorf("invalid schema %q: use letters, digits and underscores, starting with a letter or underscore", r.Schema)
+	}
+	if r.TableType != "" && !strings.EqualFold(r.TableType, ManagedTable) && !strings.EqualFold(r.TableType, ExternalTable) {
+		return fmt.Errorf("invalid table type %q: use %s or %s", r.TableType, ManagedTable, ExternalTable)
+	}
+
+	// Metadata:
+	// - data_type is stamped on every row and used by downstream reporting
+	// - mock_data and live_api modes need SampleData to be a JSON array of records
+	// - copy_into mode needs a SourcePath and a FileFormat COPY INTO can read
+	// - record_stream mode needs Records or a local file:// SourcePath (read while loading)
+	if r.Metadata["data_type"] == "" {
+		return fmt.Errorf("metadata data_type is required")
+	}
+	if recordMode(r) && r.SampleData == "" {
+		return fmt.Errorf("sample data is required in %s mode", r.Metadata["mode"])
+	}
+	if r.Metadata["mode"] == ModeCopyInto {
+		if err := r.validateCopyInto(); err != nil {
+			return err
+		}
+	}
+	if r.Metadata["mode"] == ModeRecordStream && r.Records == nil && (!strings.HasPrefix(r.SourcePath, localFilePrefix) || streamPath(r) == "") {
+		return fmt.Errorf("%s mode needs Records or a %s source path", ModeRecordStream, localFilePrefix)
+	}
+	if r.SampleData != "" {
+		var records []map[string]interface{}
+		if err := json.Unmarshal([]byte(r.SampleData), &records); err != nil {
+			return fmt.Errorf("sample data must be a JSON array of records: %w", err)
+		}
+	}
+
+	// Validation Rules:
+	// - Each rule needs a name and exactly one of Condition or SQL
+	for _, rule := range r.Validations {
+		if rule.Name == "" {
+			return fmt.Errorf("validation rule without a name")
+		}
+		if (rule.Condition == "") == (rule.SQL == "") {
+			return fmt.Errorf("validation rule %s must set exactly one of condition or sql", rule.Name)
+		}
+		if severity := strings.ToLower(rule.Severity); severity != "" && severity != SeverityError && severity != SeverityWarn {
+			return fmt.Errorf("validation rule %s has invalid severity %q", rule.Name, rule.Severity)
+		}
+	}
+
+	// - Typed column names and fields are interpolated like the table name
+	if err := validateTypedColumns(r.TableName, r.Columns); err != nil {
+		return err
+	}
+
+	// - Layout columns are interpolated like the table name and must be columns of it
+	if r.Layout != nil {
+		if err := r.Layout.Check(r.Columns); err != nil {
+			return fmt.Errorf("table %s: %w", r.TableName, err)
+		}
+	}
+
+	// - The TTL field and view name are interpolated like the table name
+	if r.TTL != nil {
+		if err := r.TTL.validate(r.TableName); err != nil {
+			return err
+		}
+	}
+
+	if r.Dedup != nil {
+		if err := r.Dedup.validate(r.TableName); err != nil {
+			return err
+		}
+	}
+
+	if r.Rules != nil {
+		if err := r.Rules.validate(r.TableName); err != nil {
+			return err
+		}
+	}
+
+	// Child Tables:
+	// - Names and fields are interpolated into DDL/DML like the table name
+	seenChildren := make(map[string]bool)
+	for _, child := range r.ChildTables {
+		if err := child.validate(r.TableName); err != nil {
+			return err
+		}
+		if seenChildren[child.Table] {
+			return fmt.Errorf("duplicate child table %s", child.Table)
+		}
+		seenChildren[child.Table] = true
+	}
+	return nil
+}
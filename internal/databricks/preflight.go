@@ -0,0 +1,220 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// A single privilege the ingestion needs on one Unity Catalog securable.
+type PermissionCheck struct {
+	Securable   string `json:"securable"` // e.g. "SCHEMA blade_poc.logistics"
+	Privilege   string `json:"privilege"` // e.g. "CREATE TABLE"
+	Granted     bool   `json:"granted"`
+	Via         string `json:"via,omitempty"`         // how the privilege was found (owner, catalog grant, ...)
+	Remediation string `json:"remediation,omitempty"` // GRANT statement an admin can run when missing
+}
+
+// Contains the outcome of a permission pre-flight against the target catalog/schema/tables.
+type PreflightReport struct {
+	Principal string            `json:"principal"`
+	Catalog   string            `json:"catalog"`
+	Schema    string            `json:"schema"`
+	Checks    []PermissionCheck `json:"checks"`
+}
+
+// Returns only the checks whose privilege is not granted.
+func (r *PreflightReport) Missing() []PermissionCheck {
+	var missing []PermissionCheck
+	for _, check := range r.Checks {
+		if !check.Granted {
+			missing = append(missing, check)
+		}
+	}
+	return missing
+}
+
+// Verifies the current principal can USE the catalog/schema, CREATE TABLE, and
+// INSERT/SELECT on the given tables before any ingestion work starts.
+func (c *Client) Preflight(ctx context.Context, tables []string) (*PreflightReport, error) {
+	report := &PreflightReport{Catalog: c.catalog, Schema: c.schema}
+
+	// Principal Resolution:
+	// - current_user() is what every information_schema check below is compared against
+	// - Also doubles as a basic "can I run SQL at all" check
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{Statement: "SELECT current_user()"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve current user: %w", err)
+	}
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		report.Principal = rows[0][0]
+	}
+
+	params := []sql.StatementParameterListItem{
+		stringParam("catalog", c.catalog),
+		stringParam("schema", c.schema),
+	}
+	catalogSecurable := "CATALOG " + c.catalog
+	schemaSecurable := fmt.Sprintf("SCHEMA %s.%s", c.catalog, c.schema)
+
+	// Ownership & Existence:
+	// - Unity Catalog stores names in lowercase, so the configured names are lowered
+	//   before they are compared (as in Diagnose)
+	// - Owners implicitly hold every privilege on the object and its children
+	// - A missing catalog stops the pre-flight: nothing below can be evaluated
+	catalogOwner, catalogExists, err := c.ownedBy(ctx,
+		"SELECT catalog_owner FROM system.information_schema.catalogs WHERE catalog_name = lower(:catalog)", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect catalog %s: %w", c.catalog, err)
+	}
+	if !catalogExists {
+		report.Checks = append(report.Checks, PermissionCheck{
+			Securable:   "METASTORE",
+			Privilege:   "CREATE CATALOG",
+			Remediation: fmt.Sprintf("Catalog %s does not exist; create it or GRANT CREATE CATALOG ON METASTORE TO `%s`", c.catalog, report.Principal),
+		})
+		return report, nil
+	}
+
+	schemaOwner, schemaExists, err := c.ownedBy(ctx,
+		"SELECT schema_owner FROM system.information_schema.schemata WHERE catalog_name = lower(:catalog) AND schema_name = lower(:schema)", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect schema %s.%s: %w", c.catalog, c.schema, err)
+	}
+
+	grants, err := c.currentGrants(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Privilege Resolution Order:
+	// - Ownership of the object (or an ancestor)
+	// - ALL PRIVILEGES or the specific privilege on the object
+	// - Inherited grant on an ancestor (catalog → schema → table)
+	check := func(securable, privilege string, owner bool, levels ...string) PermissionCheck {
+		result := PermissionCheck{Securable: securable, Privilege: privilege}
+		if owner {
+			result.Granted, result.Via = true, "owner"
+			return result
+		}
+		for _, level := range levels {
+			if grants[level][privilege] || grants[level]["ALL PRIVILEGES"] {
+				result.Granted, result.Via = true, strings.ToLower(strings.SplitN(level, " ", 2)[0])+" grant"
+				return result
+			}
+		}
+		result.Remediation = fmt.Sprintf("GRANT %s ON %s TO `%s`", privilege, securable, report.Principal)
+		return result
+	}
+
+	report.Checks = append(report.Checks, check(catalogSecurable, "USE CATALOG", catalogOwner, "CATALOG"))
+	if !schemaExists {
+		report.Checks = append(report.Checks, check(catalogSecurable, "CREATE SCHEMA", catalogOwner, "CATALOG"))
+		// A schema created by this principal is owned by it, so nothing further can be missing.
+		return report, nil
+	}
+
+	schemaOwned := catalogOwner || schemaOwner
+	report.Checks = append(report.Checks,
+		check(schemaSecurable, "USE SCHEMA", schemaOwned, "SCHEMA", "CATALOG"),
+		check(schemaSecurable, "CREATE TABLE", schemaOwned, "SCHEMA", "CATALOG"),
+	)
+
+	tableOwners, err := c.tableOwnership(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Per-Table Checks:
+	// - Existing tables need MODIFY (INSERT) and SELECT for ingestion and verification
+	// - Tables that don't exist yet will be created (and owned) by this principal
+	for _, table := range tables {
+		owned, exists := tableOwners[strings.ToLower(table)]
+		if !exists {
+			continue
+		}
+		securable := fmt.Sprintf("TABLE %s.%s.%s", c.catalog, c.schema, table)
+		level := "TABLE " + strings.ToLower(table)
+		report.Checks = append(report.Checks,
+			check(securable, "MODIFY", schemaOwned || owned, level, "SCHEMA", "CATALOG"),
+			check(securable, "SELECT", schemaOwned || owned, level, "SCHEMA", "CATALOG"),
+		)
+	}
+
+	return report, nil
+}
+
+// Runs a single-column owner lookup and reports whether the current principal (or one of its groups) owns the object.
+func (c *Client) ownedBy(ctx context.Context, ownerSQL string, params []sql.StatementParameterListItem) (owned bool, exists bool, err error) {
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(
+			"SELECT owner = current_user() OR is_account_group_member(owner) FROM (%s) AS o(owner)", ownerSQL),
+		Parameters: params,
+	})
+	if err != nil {
+		return false, false, err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return false, false, nil
+	}
+	return rows[0][0] == "true", true, nil
+}
+
+// Collects every privilege granted to the current principal (directly or via groups),
+// keyed by level ("CATALOG", "SCHEMA", "TABLE <name>") and privilege type.
+func (c *Client) currentGrants(ctx context.Context, params []sql.StatementParameterListItem) (map[string]map[string]bool, error) {
+	principalFilter := "(grantee = current_user() OR is_account_group_member(grantee))"
+	grantsSQL := fmt.Sprintf(`
+		SELECT 'CATALOG', privilege_type FROM system.information_schema.catalog_privileges
+		WHERE catalog_name = lower(:catalog) AND %[1]s
+		UNION ALL
+		SELECT 'SCHEMA', privilege_type FROM system.information_schema.schema_privileges
+		WHERE catalog_name = lower(:catalog) AND schema_name = lower(:schema) AND %[1]s
+		UNION ALL
+		SELECT concat('TABLE ', table_name), privilege_type FROM system.information_schema.table_privileges
+		WHERE table_catalog = lower(:catalog) AND table_schema = lower(:schema) AND %[1]s
+	`, principalFilter)
+
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{Statement: grantsSQL, Parameters: params})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read privileges from information_schema: %w", err)
+	}
+
+	grants := make(map[string]map[string]bool)
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		if grants[row[0]] == nil {
+			grants[row[0]] = make(map[string]bool)
+		}
+		grants[row[0]][strings.ToUpper(row[1])] = true
+	}
+	return grants, nil
+}
+
+// Lists the tables in the target schema and whether the current principal owns each one.
+func (c *Client) tableOwnership(ctx context.Context, params []sql.StatementParameterListItem) (map[string]bool, error) {
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement: `
+			SELECT table_name, table_owner = current_user() OR is_account_group_member(table_owner)
+			FROM system.information_schema.tables
+			WHERE table_catalog = lower(:catalog) AND table_schema = lower(:schema)
+		`,
+		Parameters: params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables in %s.%s: %w", c.catalog, c.schema, err)
+	}
+
+	owners := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		owners[strings.ToLower(row[0])] = row[1] == "true"
+	}
+	return owners, nil
+}
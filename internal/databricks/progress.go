@@ -0,0 +1,120 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Shows whether a long statement (typically a big COPY INTO) is progressing
+//   or stuck. While awaitStatement polls a running statement, its live metrics are read
+//   from the query history API and logged next to the poll line.
+
+// Live metrics of a running statement as reported by the query history API.
+type StatementProgress struct {
+	Status         string `json:"status"` // QUEUED, RUNNING, FINISHED, ...
+	ReadBytes      int64  `json:"readBytes"`
+	ReadFiles      int64  `json:"readFiles"`
+	RowsRead       int64  `json:"rowsRead"`
+	WrittenBytes   int64  `json:"writtenBytes"`
+	RemainingTasks int64  `json:"remainingTasks"`
+	TaskTimeMs     int64  `json:"taskTimeMs"`
+}
+
+// Returns the one-line summary logged while the statement runs.
+func (p StatementProgress) String() string {
+	parts := []string{
+		fmt.Sprintf("%s read", formatBytes(p.ReadBytes)),
+		fmt.Sprintf("%d files", p.ReadFiles),
+		fmt.Sprintf("%d rows", p.RowsRead),
+	}
+	if p.WrittenBytes > 0 {
+		parts = append(parts, fmt.Sprintf("%s written", formatBytes(p.WrittenBytes)))
+	}
+	parts = append(parts, fmt.Sprintf("%d tasks remaining", p.RemainingTasks))
+	return p.Status + ": " + strings.Join(parts, ", ")
+}
+
+// Reads a statement's live metrics from the query history; nil when it isn't listed yet.
+func (c *Client) StatementProgress(ctx context.Context, statementID string) (*StatementProgress, error) {
+	resp, err := c.workspace.QueryHistory.List(ctx, sql.ListQueryHistoryRequest{
+		FilterBy:       &sql.QueryFilter{StatementIds: []string{statementID}},
+		IncludeMetrics: true,
+		MaxResults:     1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query history for statement %s: %w", statementID, err)
+	}
+	if len(resp.Res) == 0 {
+		return nil, nil
+	}
+	query := resp.Res[0]
+	progress := &StatementProgress{Status: string(query.Status)}
+	if metrics := query.Metrics; metrics != nil {
+		progress.ReadBytes = metrics.ReadBytes
+		progress.ReadFiles = metrics.ReadFilesCount
+		progress.RowsRead = metrics.RowsReadCount
+		progress.WrittenBytes = metrics.WriteRemoteBytes
+		progress.RemainingTasks = metrics.RemainingTaskCount
+		progress.TaskTimeMs = metrics.TaskTotalTimeMs
+	}
+	return progress, nil
+}
+
+// Progress last seen for one awaited statement.
+type progressTail struct {
+	last      *StatementProgress
+	unchanged int
+	reported  bool
+}
+
+// Number of unchanged polls after which a running statement is reported as possibly stuck.
+const stalledPolls = 5
+
+// Logs a running statement's progress when it changed, and once when it stopped changing.
+//   - Query history lags statement submission, so "not listed yet" and API errors are
+//     skipped (an error is logged once) and never interrupt the wait
+func (c *Client) tailProgress(ctx context.Context, statementID string, tail *progressTail) {
+	progress, err := c.StatementProgress(ctx, statementID)
+	if err != nil {
+		if !tail.reported {
+			tail.reported = true
+			runlog.Printf(ctx, "Statement %s progress unavailable: %v", statementID, err)
+		}
+		return
+	}
+	if progress == nil {
+		return
+	}
+	if tail.last != nil && *tail.last == *progress {
+		tail.unchanged++
+	} else {
+		tail.unchanged = 0
+	}
+	tail.last = progress
+
+	if tail.unchanged == stalledPolls {
+		runlog.Printf(ctx, "Statement %s progress %s (no change in %d polls, may be stuck or queued)", statementID, progress, stalledPolls)
+		return
+	}
+	if tail.unchanged == 0 {
+		runlog.Printf(ctx, "Statement %s progress %s", statementID, progress)
+	}
+}
+
+// Formats a byte count with binary units ("1.5 GiB").
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,114 @@
+package databricks
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Ad-hoc read-only queries against the BLADE tables (the query command).
+//   raw_data values come back as JSON whatever codec stored them, so nobody has to
+//   remember how a table was written to read it.
+
+// Rows returned by Query.
+//   - Truncated: More rows matched than the limit
+type QueryResult struct {
+	Columns   []string   `json:"columns"`
+	Rows      [][]string `json:"rows"`
+	Truncated bool       `json:"truncated,omitempty"`
+}
+
+// Output formats of WriteQueryResult (--format of the query and snapshot commands).
+const (
+	QueryFormatTable = "table"
+	QueryFormatJSON  = "json"
+	QueryFormatCSV   = "csv"
+)
+
+// Runs a single SELECT/DESCRIBE/SHOW statement and returns up to limit rows (0 = the API's inline limit).
+//   - Columns named raw_data are decoded with DecodeRawData
+func (c *Client) Query(ctx context.Context, statement string, limit int) (*QueryResult, error) {
+	if err := CheckReadOnly(statement); err != nil {
+		return nil, fmt.Errorf("query only runs read statements: %w", err)
+	}
+	return c.readQuery(ctx, sql.ExecuteStatementRequest{
+		Statement: statement,
+		RowLimit:  int64(limit),
+	})
+}
+
+// Runs a read statement built by the client and returns its rows, raw_data decoded.
+func (c *Client) readQuery(ctx context.Context, req sql.ExecuteStatementRequest) (*QueryResult, error) {
+	resp, err := c.executeStatement(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QueryResult{}
+	var rawDataColumns []int
+	if resp.Manifest != nil {
+		result.Truncated = resp.Manifest.Truncated
+		if resp.Manifest.Schema != nil {
+			for i, column := range resp.Manifest.Schema.Columns {
+				result.Columns = append(result.Columns, column.Name)
+				if column.Name == "raw_data" {
+					rawDataColumns = append(rawDataColumns, i)
+				}
+			}
+		}
+	}
+	if resp.Result != nil {
+		result.Rows = resp.Result.DataArray
+	}
+	for _, row := range result.Rows {
+		for _, i := range rawDataColumns {
+			if i >= len(row) {
+				continue
+			}
+			if row[i], err = DecodeRawData(row[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// Renders a query result as an aligned table (with a row count footer), indented JSON, or
+// CSV with a header row.
+func WriteQueryResult(w io.Writer, result *QueryResult, format string) error {
+	switch format {
+	case QueryFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case QueryFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(result.Columns); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(result.Rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(result.Columns, "\t"))
+	for _, row := range result.Rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "(%d rows", len(result.Rows))
+	if result.Truncated {
+		fmt.Fprintf(w, ", truncated; raise --limit")
+	}
+	fmt.Fprintln(w, ")")
+	return nil
+}
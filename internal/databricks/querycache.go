@@ -0,0 +1,193 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Verification statements (row counts, column descriptions) are often repeated
+//   within a run (schema migration, then the dictionary reporter) and by consecutive runs,
+//   each time waking the warehouse. A QueryCache carried by the run's context answers the
+//   repeats; any write to a table drops what was cached for it.
+
+// Results of cacheable statements, keyed by statement text and parameters.
+//   - In memory for one run, or loaded from / saved to a file and reused for ttl across
+//     runs (BLADE_QUERY_CACHE_TTL); writes by other processes are only noticed when entries expire
+//   - Safe for concurrent use
+type QueryCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]*queryCacheEntry
+	dirty   bool
+}
+
+type queryCacheEntry struct {
+	Table    string                 `json:"table"` // catalog.schema.table, lower case
+	Response *sql.StatementResponse `json:"response"`
+	StoredAt time.Time              `json:"storedAt"`
+}
+
+// Creates a cache for a single run.
+func NewQueryCache() *QueryCache {
+	return &QueryCache{entries: map[string]*queryCacheEntry{}}
+}
+
+// Opens the cache file at path, keeping entries younger than ttl (ttl 0 = a single-run cache, the file is not used).
+//   - A missing file starts an empty cache; Save writes it back
+func OpenQueryCache(path string, ttl time.Duration) (*QueryCache, error) {
+	cache := NewQueryCache()
+	if ttl <= 0 {
+		return cache, nil
+	}
+	cache.path, cache.ttl = path, ttl
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query cache %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse query cache %s: %w", path, err)
+	}
+	for key, entry := range cache.entries {
+		if entry == nil || entry.Response == nil || time.Since(entry.StoredAt) >= ttl {
+			delete(cache.entries, key)
+		}
+	}
+	return cache, nil
+}
+
+// Writes the cache back to its file if it changed (no-op for single-run caches).
+func (q *QueryCache) Save() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.path == "" || !q.dirty {
+		return nil
+	}
+	data, err := json.Marshal(q.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create query cache directory: %w", err)
+	}
+	// - A temp file of its own per save: parallel runs (ingest --all) share the cache file
+	temp, err := os.CreateTemp(filepath.Dir(q.path), filepath.Base(q.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write query cache %s: %w", q.path, err)
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write query cache %s: %w", q.path, err)
+	}
+	if err := os.Rename(temp.Name(), q.path); err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write query cache %s: %w", q.path, err)
+	}
+	q.dirty = false
+	return nil
+}
+
+func (q *QueryCache) get(key string) (*sql.StatementResponse, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entry, found := q.entries[key]
+	if !found || (q.ttl > 0 && time.Since(entry.StoredAt) >= q.ttl) {
+		return nil, false
+	}
+	return entry.Response, true
+}
+
+func (q *QueryCache) put(key, table string, resp *sql.StatementResponse) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.entries[key] = &queryCacheEntry{Table: strings.ToLower(table), Response: resp, StoredAt: time.Now().UTC()}
+	q.dirty = true
+}
+
+// Drops the entries of table ("" = every entry).
+func (q *QueryCache) invalidate(table string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	table = strings.ToLower(table)
+	for key, entry := range q.entries {
+		if table == "" || entry.Table == table {
+			delete(q.entries, key)
+			q.dirty = true
+		}
+	}
+}
+
+type queryCacheKey struct{}
+
+// Returns ctx carrying cache; statements run with it reuse cached verification results.
+func WithQueryCache(ctx context.Context, cache *QueryCache) context.Context {
+	return context.WithValue(ctx, queryCacheKey{}, cache)
+}
+
+func queryCacheFrom(ctx context.Context) *QueryCache {
+	cache, _ := ctx.Value(queryCacheKey{}).(*QueryCache)
+	return cache
+}
+
+// Runs a read statement about table (catalog.schema.table), answering it from the run's cache when possible.
+//   - Without a cache on ctx this is executeStatement
+func (c *Client) cachedStatement(ctx context.Context, table string, req sql.ExecuteStatementRequest) (*sql.StatementResponse, error) {
+	cache := queryCacheFrom(ctx)
+	if cache == nil {
+		return c.executeStatement(ctx, req)
+	}
+	identity, err := json.Marshal(struct {
+		Statement  string
+		Catalog    string
+		Schema     string
+		Parameters []sql.StatementParameterListItem
+	}{req.Statement, req.Catalog, req.Schema, req.Parameters})
+	if err != nil {
+		return c.executeStatement(ctx, req)
+	}
+	key := string(identity)
+	if resp, found := cache.get(key); found {
+		return resp, nil
+	}
+
+	resp, err := c.executeStatement(ctx, req)
+	if err == nil && resp.Status != nil && resp.Status.State == sql.StatementStateSucceeded {
+		cache.put(key, table, resp)
+	}
+	return resp, err
+}
+
+// Drops cached results a statement may have changed.
+//   - Statements naming a table (INSERT INTO, ALTER TABLE, COPY INTO, ...) drop that table's entries
+//   - CREATE/ALTER/COMMENT of a catalog or schema leave tables as they are; any other
+//     write (DROP SCHEMA, ...) drops everything
+func invalidateQueryCache(ctx context.Context, kind, label string) {
+	cache := queryCacheFrom(ctx)
+	if cache == nil || kind == "QUERY" {
+		return
+	}
+	words := strings.Fields(label)
+	switch {
+	case len(words) > 1:
+		cache.invalidate(words[1])
+	case len(words) == 1 && (words[0] == "CREATE" || words[0] == "ALTER" || words[0] == "COMMENT"):
+	default:
+		cache.invalidate("")
+	}
+}
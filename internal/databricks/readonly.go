@@ -0,0 +1,51 @@
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Returned for any write attempted through a read-only client (BLADE_READ_ONLY).
+var ErrReadOnly = errors.New("read-only mode")
+
+var (
+	// Leading keywords of the statements a read-only client may run
+	readOnlyKeywords = map[string]bool{"SELECT": true, "WITH": true, "DESCRIBE": true, "DESC": true, "SHOW": true, "EXPLAIN": true}
+
+	// Keywords that turn an allowed statement into a write (e.g. WITH ... INSERT)
+	writeKeywords = map[string]bool{
+		"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "CREATE": true,
+		"DROP": true, "ALTER": true, "TRUNCATE": true, "GRANT": true, "REVOKE": true,
+	}
+
+	sqlWord = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// Rejects anything but a single SELECT/DESCRIBE/SHOW-style statement (what a read-only client may run).
+//
+// The check fails closed: leading comments, multiple statements or a write keyword
+// anywhere outside a string literal all count as writes.
+func CheckReadOnly(statement string) error {
+	redacted := strings.TrimRight(RedactSQL(statement, 0), "; ")
+	if strings.Contains(redacted, ";") {
+		return fmt.Errorf("%w: multiple statements are not allowed", ErrReadOnly)
+	}
+	words := sqlWord.FindAllString(redacted, -1)
+	if len(words) == 0 || !strings.HasPrefix(strings.TrimLeft(redacted, "( "), words[0]) ||
+		!readOnlyKeywords[strings.ToUpper(words[0])] {
+		return fmt.Errorf("%w: only SELECT, DESCRIBE and SHOW statements are allowed", ErrReadOnly)
+	}
+	for _, word := range words[1:] {
+		if writeKeywords[strings.ToUpper(word)] {
+			return fmt.Errorf("%w: %s is not allowed", ErrReadOnly, strings.ToUpper(word))
+		}
+	}
+	return nil
+}
+
+// Reports whether the client refuses writes.
+func (c *Client) ReadOnly() bool {
+	return c.readOnly
+}
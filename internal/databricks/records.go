@@ -0,0 +1,134 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"databricks-blade-poc/blademap"
+)
+
+//   Purpose: SampleData hands the client a load's records as one JSON string, so whoever
+//   produces them has to hold the whole dataset in memory before the first INSERT. A
+//   RecordSource hands them over one at a time instead: the client pulls a chunk, loads
+//   it and only then pulls the next, so a producer is never more than a chunk (plus its
+//   own buffer) ahead of the warehouse.
+
+//   Sources:
+//   - NDJSON files: record_stream requests with a file:// SourcePath open one themselves,
+//     decompressing .gz files and zip archives while reading (blademap.OpenDataFile)
+//   - ChannelSource: records sent from another goroutine (providers, generators)
+//   - Any other implementation set in IngestionRequest.Records
+
+// Yields the records of a record_stream load, one JSON object at a time.
+//   - Next returns io.EOF after the last record, and any other error to stop the load
+//   - Close is called once the load stops reading, whether or not it reached the end
+//   - A source is read once; loading the same records again needs a new source
+type RecordSource interface {
+	Next(ctx context.Context) (json.RawMessage, error)
+	Close() error
+}
+
+// Returned by ChannelSource.Send once the load has stopped reading.
+var ErrSourceClosed = errors.New("record source closed")
+
+// Reads records from newline-delimited JSON.
+type ndjsonSource struct {
+	reader *blademap.NDJSONReader
+	closer io.Closer
+}
+
+// Returns a source reading newline-delimited JSON from r, which is closed with the source.
+func NewNDJSONSource(r io.ReadCloser) RecordSource {
+	return &ndjsonSource{reader: blademap.NewNDJSONReader(r), closer: r}
+}
+
+func (s *ndjsonSource) Next(ctx context.Context) (json.RawMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.reader.Next()
+}
+
+func (s *ndjsonSource) Close() error {
+	return s.closer.Close()
+}
+
+// Returns the source of a record_stream request: its Records, or else its NDJSON file.
+func openRecordSource(req *IngestionRequest) (RecordSource, string, error) {
+	if req.Records != nil {
+		return req.Records, "records", nil
+	}
+	path := streamPath(req)
+	file, _, err := blademap.OpenDataFile(path, "NDJSON")
+	if err != nil {
+		return nil, path, fmt.Errorf("failed to open record stream: %w", err)
+	}
+	return NewNDJSONSource(file), path, nil
+}
+
+// Feeds a load from another goroutine, like io.Pipe: Send blocks while the buffer is full,
+// so the producer runs at most buffer records ahead of the inserts.
+//   - The producer calls Finish once after its last Send, with the error that ended it (or nil)
+//   - Send fails with ErrSourceClosed once the load stopped reading, so the producer can quit
+type ChannelSource struct {
+	records chan json.RawMessage
+	closed  chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	err     error // the producer's, returned by Next after the buffered records
+}
+
+func NewChannelSource(buffer int) *ChannelSource {
+	return &ChannelSource{records: make(chan json.RawMessage, buffer), closed: make(chan struct{})}
+}
+
+// Hands one record to the load, waiting while the buffer is full.
+func (s *ChannelSource) Send(ctx context.Context, record json.RawMessage) error {
+	select {
+	case <-s.closed:
+		return ErrSourceClosed
+	default:
+	}
+	select {
+	case s.records <- record:
+		return nil
+	case <-s.closed:
+		return ErrSourceClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ends the records; a non-nil err fails the load once the buffered records are read.
+func (s *ChannelSource) Finish(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+	close(s.records)
+}
+
+func (s *ChannelSource) Next(ctx context.Context) (json.RawMessage, error) {
+	select {
+	case record, ok := <-s.records:
+		if ok {
+			return record, nil
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *ChannelSource) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
@@ -0,0 +1,191 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: A malformed line, a record that breaks its rules or one the warehouse refuses
+//   used to fail the whole load, or vanish from it. Rejected records are kept instead, so a
+//   data owner can see exactly what was turned away and why, fix the source and load it
+//   again. They land in one reject table per schema, next to the tables they were meant for.
+
+//   What Happens to Rejected Records (the table's RecordRules.OnInvalid):
+//   - fail (default, and for tables without rules): the load fails before the records are
+//     written, as it always did
+//   - skip: the other records are loaded, the rejected ones reported on the result
+//   - quarantine: as skip, and the rejected records are written to blade_ingest_rejects
+
+// Table holding the records turned away by loads in quarantine mode.
+const RejectTable = "blade_ingest_rejects"
+
+// Rejected records written per INSERT statement.
+const rejectInsertSize = 500
+
+// Where a record was rejected (Rejection.Stage).
+const (
+	RejectStageParse  = "parse"  // not a record: a malformed NDJSON line or CSV row
+	RejectStageRules  = "rules"  // broke the table's record rules
+	RejectStageInsert = "insert" // refused by the warehouse when inserted on its own
+)
+
+// A record turned away by a load.
+//   - Index: Position of the record among the records it was read with (0-based): the
+//     source for parse and rules rejections, the records left to insert (of the chunk, for
+//     streams) for insert ones
+//   - Line: Line of the source file, for parse rejections
+//   - Payload: The record as JSON, or the malformed text; written to the reject table
+type Rejection struct {
+	Index   int      `json:"index"`
+	Line    int      `json:"line,omitempty"`
+	Stage   string   `json:"stage"`
+	ItemID  string   `json:"itemId,omitempty"`
+	Reasons []string `json:"reasons"`
+	Payload string   `json:"-"`
+}
+
+// Applies the table's OnInvalid mode to rejected records: an error in fail mode, a log
+// line in skip mode, the reject table in quarantine mode.
+//   - total: Records the rejections came from, for the messages
+func (c *Client) settleRejections(ctx context.Context, req *IngestionRequest, batchID string, rejected []Rejection, total int) error {
+	switch req.Rules.Mode() {
+	case OnInvalidFail:
+		return fmt.Errorf("%d of %d records break the rules of %s: %s", len(rejected), total, req.TableName, describeRejections(rejected, 3))
+	case OnInvalidQuarantine:
+		return c.quarantineRecords(ctx, req, batchID, rejected)
+	}
+	runlog.Printf(ctx, "Skipping %d of %d records rejected for %s: %s", len(rejected), total, req.TableName, describeRejections(rejected, 3))
+	return nil
+}
+
+// Creates blade_ingest_rejects if missing and writes the rejected records to it.
+//   - raw_payload is the record as JSON (or the malformed text), whatever the raw_data
+//     codec, so it stays readable
+//   - Classification-routed copies write to the reject table of their route (table suffix)
+func (c *Client) quarantineRecords(ctx context.Context, req *IngestionRequest, batchID string, rejected []Rejection) error {
+	table := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, RejectTable+req.tableSuffix)
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				batch_id STRING NOT NULL COMMENT 'metadata[''batch_id''] of the load that rejected the record',
+				data_type STRING,
+				target_table STRING COMMENT 'Table the record was meant for',
+				stage STRING COMMENT 'parse, rules or insert',
+				record_index INT COMMENT 'Position of the record in what its stage read (0-based)',
+				source_line INT COMMENT 'Line of the source file, for parse rejections',
+				item_id STRING,
+				reason STRING,
+				raw_payload STRING COMMENT 'The record as JSON, or the malformed text',
+				source_file STRING,
+				tenant STRING,
+				rejected_at TIMESTAMP
+			)
+		`, table),
+	}); err != nil {
+		return fmt.Errorf("failed to create reject table %s: %w", table, err)
+	}
+	c.applyCostTags(ctx, "TABLE", table)
+
+	source := req.Metadata["source_file"]
+	if source == "" {
+		source = req.SourcePath
+	}
+	for offset := 0; offset < len(rejected); offset += rejectInsertSize {
+		end := min(offset+rejectInsertSize, len(rejected))
+		var params paramList
+		batch, dataType, target := params.text(batchID), params.text(req.Metadata["data_type"]), params.text(req.TableName)
+		file, tenant := params.bind(source, "STRING"), params.bind(c.tenant, "STRING")
+		values := make([]string, 0, end-offset)
+		for _, rejection := range rejected[offset:end] {
+			line := "NULL"
+			if rejection.Line > 0 {
+				line = fmt.Sprint(rejection.Line)
+			}
+			values = append(values, fmt.Sprintf("(%s, %s, %s, %s, %d, %s, %s, %s, %s, %s, %s, current_timestamp())",
+				batch, dataType, target, params.text(rejection.Stage), rejection.Index, line, params.bind(rejection.ItemID, "STRING"),
+				params.text(strings.Join(rejection.Reasons, "; ")), params.text(rejection.Payload), file, tenant))
+		}
+		if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+			Statement: fmt.Sprintf(`
+				INSERT INTO %s (batch_id, data_type, target_table, stage, record_index, source_line, item_id, reason, raw_payload, source_file, tenant, rejected_at)
+				VALUES %s
+			`, table, strings.Join(values, ",\n")),
+			Parameters: params.params,
+		}); err != nil {
+			return fmt.Errorf("failed to quarantine rejected records into %s: %w", table, err)
+		}
+	}
+	runlog.Printf(ctx, "Quarantined %d record(s) rejected for %s into %s", len(rejected), req.TableName, table)
+	return nil
+}
+
+// Reports whether a failed INSERT chunk is worth retrying record by record: the table
+// skips or quarantines invalid records, and the warehouse ran and failed the statement
+// (a transport error, a canceled statement or a stopped run say nothing about the records).
+func isolatesInsertFailure(ctx context.Context, req *IngestionRequest, err error) bool {
+	var statementErr *StatementError
+	return req.Rules.Mode() != OnInvalidFail && errors.As(err, &statementErr) &&
+		statementErr.State == string(sql.StatementStateFailed) && !budgetExceeded(ctx)
+}
+
+// Inserts the records of a failed chunk one at a time, so the records the warehouse
+// refuses (a timestamp that doesn't cast, ...) are rejected instead of failing the rest.
+//   - offset: Position of the chunk's first record among the records being inserted
+//   - Returns the rows inserted and the rejections, settled like other rejections
+//   - When no record goes in on its own the failure isn't the records' (a missing table,
+//     a lost grant), so nothing is rejected and cause is returned for the chunk
+func (c *Client) isolateInsertFailures(ctx context.Context, req *IngestionRequest, targetTable string, batchID string, records []map[string]interface{}, offset int, cause error) (int64, []Rejection, error) {
+	runlog.Printf(ctx, "Retrying the %d records of a failed INSERT one at a time", len(records))
+	var inserted int64
+	var rejected []Rejection
+	for i, record := range records {
+		if budgetExceeded(ctx) {
+			return inserted, rejected, fmt.Errorf("%w: stopped while retrying a failed insert chunk", stopReason(ctx))
+		}
+		if _, err := c.insertRecords(ctx, req, targetTable, batchID, records[i:i+1]); err != nil {
+			payload, _ := json.Marshal(record)
+			rejected = append(rejected, Rejection{Index: offset + i, Stage: RejectStageInsert, ItemID: recordText(record, "item_id"), Reasons: []string{err.Error()}, Payload: string(payload)})
+			continue
+		}
+		inserted++
+	}
+	if inserted == 0 {
+		return 0, nil, cause
+	}
+	if err := c.settleRejections(ctx, req, batchID, rejected, len(records)); err != nil {
+		return inserted, rejected, err
+	}
+	return inserted, rejected, nil
+}
+
+// Returns a copy of the request without the records rejected at insert, whose Index is
+// their position in its SampleData.
+func withoutRejected(req *IngestionRequest, rejected []Rejection) (*IngestionRequest, error) {
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return req, fmt.Errorf("failed to parse sample data: %w", err)
+	}
+	refused := make(map[int]bool, len(rejected))
+	for _, rejection := range rejected {
+		refused[rejection.Index] = true
+	}
+	kept := make([]map[string]interface{}, 0, len(records))
+	for i, record := range records {
+		if !refused[i] {
+			kept = append(kept, record)
+		}
+	}
+	sampleData, err := json.Marshal(kept)
+	if err != nil {
+		return req, fmt.Errorf("failed to encode inserted records: %w", err)
+	}
+	inserted := *req
+	inserted.SampleData = string(sampleData)
+	return &inserted, nil
+}
@@ -0,0 +1,143 @@
+package databricks
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/databricks/databricks-sdk-go/apierr"
+)
+
+//   Purpose: Demo users kept hitting the same few workspace failures (missing grants, a
+//   stopped warehouse, a misspelled catalog, oversized INSERTs) and asking what to do
+//   about each. Remediate recognizes them in an error and returns the fix, which the
+//   CLI prints and the REST API returns next to the error.
+
+// Remediation codes, stable for automation to match on.
+const (
+	RemedyStatementTooLarge    = "statement_too_large"
+	RemedyRateLimited          = "rate_limited"
+	RemedyUnauthenticated      = "unauthenticated"
+	RemedyPermissionDenied     = "permission_denied"
+	RemedyWarehouseUnavailable = "warehouse_unavailable"
+	RemedyCatalogNotFound      = "catalog_not_found"
+	RemedySchemaNotFound       = "schema_not_found"
+	RemedyTableNotFound        = "table_not_found"
+)
+
+// What to do about a recognized failure.
+type Remediation struct {
+	Code string `json:"code"`
+	Hint string `json:"hint"`
+}
+
+// A statement the warehouse reported as FAILED or CANCELED.
+//   - Code: The SQL error code (e.g. PERMISSION_DENIED), when the API gave one
+type StatementError struct {
+	State   string
+	Code    string
+	Message string
+}
+
+func (e *StatementError) Error() string {
+	if e.Code == "" && e.Message == "" {
+		return "statement " + e.State
+	}
+	return "statement " + e.State + ": " + e.Code + ": " + e.Message
+}
+
+// What a failure is recognized by, upper-cased.
+type failureSignals struct {
+	status int    // HTTP status of an API error (0 = none)
+	codes  string // SQL and API error codes in the chain
+	text   string // the whole error message
+}
+
+// Classification:
+//   - Rules are tried in order; the first match wins
+//   - Error codes are matched where the API sets them, the message text otherwise
+//     (SQL errors name their condition in brackets, e.g. [TABLE_OR_VIEW_NOT_FOUND])
+var remediationRules = []struct {
+	code    string
+	hint    string
+	matches func(f failureSignals) bool
+}{
+	{RemedyStatementTooLarge,
+		"lower BLADE_INSERT_CHUNK_SIZE so each INSERT stays under the warehouse's statement size limit (16 MiB), or load large extracts as files with ingest --source",
+		func(f failureSignals) bool {
+			return f.status == http.StatusRequestEntityTooLarge || strings.Contains(f.text, "STATEMENT_TOO_LARGE") ||
+				(strings.Contains(f.text, "STATEMENT") && (strings.Contains(f.text, "TOO LARGE") || strings.Contains(f.text, "SIZE EXCEEDS")))
+		}},
+	{RemedyRateLimited,
+		"the workspace is rate-limiting API calls; retry later, raise BLADE_THROTTLE_RETRIES or lower BLADE_SERVE_WORKERS",
+		func(f failureSignals) bool {
+			return f.status == http.StatusTooManyRequests || containsAny(f.codes, "TOO_MANY_REQUESTS", "REQUEST_LIMIT_EXCEEDED")
+		}},
+	{RemedyUnauthenticated,
+		"the workspace rejected the credentials; check DATABRICKS_TOKEN (or DATABRICKS_CLIENT_ID/DATABRICKS_CLIENT_SECRET for oauth-m2m) in .env and run doctor to see the token's expiry",
+		func(f failureSignals) bool {
+			return f.status == http.StatusUnauthorized || containsAny(f.codes, "UNAUTHENTICATED", "INVALID_TOKEN") || strings.Contains(f.text, "TOKEN IS EXPIRED")
+		}},
+	{RemedyWarehouseUnavailable,
+		"start the SQL warehouse DATABRICKS_WAREHOUSE_ID in the workspace (or fix the ID) and rerun, or set BLADE_WAREHOUSE_AUTO_START=true to have it started; doctor shows the warehouse's state",
+		func(f failureSignals) bool {
+			return strings.Contains(f.codes, "WAREHOUSE_STOPPED") || (strings.Contains(f.text, "WAREHOUSE") &&
+				containsAny(f.text, "STOPPED", "STOPPING", "NOT RUNNING", "DELETED", "DOES NOT EXIST", "NOT FOUND"))
+		}},
+	{RemedyPermissionDenied,
+		"the principal lacks a Unity Catalog privilege; run preflight to list the missing grants and have a workspace admin apply them",
+		func(f failureSignals) bool {
+			return f.status == http.StatusForbidden || containsAny(f.codes, "PERMISSION_DENIED", "INSUFFICIENT_PERMISSIONS") ||
+				containsAny(f.text, "PERMISSION_DENIED", "INSUFFICIENT_PERMISSIONS")
+		}},
+	{RemedyCatalogNotFound,
+		"the catalog doesn't exist or isn't visible to the principal; check DATABRICKS_CATALOG (and BLADE_TENANT), or have an admin create it or grant CREATE CATALOG",
+		func(f failureSignals) bool {
+			return containsAny(f.text, "CATALOG_NOT_FOUND", "NO_SUCH_CATALOG")
+		}},
+	{RemedySchemaNotFound,
+		"the schema doesn't exist or isn't visible to the principal; check DATABRICKS_SCHEMA (and BLADE_TENANT), or grant CREATE SCHEMA on the catalog so ingest can create it",
+		func(f failureSignals) bool {
+			return containsAny(f.text, "SCHEMA_NOT_FOUND", "NO_SUCH_SCHEMA")
+		}},
+	{RemedyTableNotFound,
+		"the table doesn't exist yet; ingest the data type first (ingest creates its table) or check the table name",
+		func(f failureSignals) bool {
+			return containsAny(f.text, "TABLE_OR_VIEW_NOT_FOUND", "NO_SUCH_TABLE")
+		}},
+}
+
+// Returns the remediation of a recognized workspace failure in err.
+func Remediate(err error) (Remediation, bool) {
+	if err == nil {
+		return Remediation{}, false
+	}
+	signals := failureSignals{text: strings.ToUpper(err.Error())}
+	var codes []string
+	var statementErr *StatementError
+	if errors.As(err, &statementErr) {
+		codes = append(codes, statementErr.Code)
+	}
+	var apiErr *apierr.APIError
+	if errors.As(err, &apiErr) {
+		signals.status = apiErr.StatusCode
+		codes = append(codes, apiErr.ErrorCode)
+	}
+	signals.codes = strings.ToUpper(strings.Join(codes, " "))
+
+	for _, rule := range remediationRules {
+		if rule.matches(signals) {
+			return Remediation{Code: rule.code, Hint: rule.hint}, true
+		}
+	}
+	return Remediation{}, false
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,337 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: A bad load (wrong file, bad mock data pushed to a shared catalog) has to be
+//   undone without touching the batches loaded before or after it. Every row a load writes
+//   carries its batch ID, so rolling the batch back deletes exactly those rows: from the
+//   data type's table, its crew and child tables, and the reject table.
+
+//   Modes:
+//   - delete (default): DELETE ... WHERE metadata['batch_id'] = :batch_id per table; the
+//     batch's versions stay in the table history (see TableHistory)
+//   - restore: RESTORE TABLE ... TO VERSION AS OF the version before the batch's first write,
+//     for the main table; refused unless every later version only wrote the batch's own rows
+//     (RESTORE would otherwise undo other loads as well). Crew, child and reject rows are
+//     still deleted, they aren't written in the same versions
+
+//   Archive Mode (BLADE_ARCHIVE_SUPERSEDED):
+//   - The rows a batch superseded were moved to {table}_archive when it loaded; rolling the
+//     batch back moves them back into the table (superseded_by = the batch), so the source's
+//     previous delivery is current again
+
+//   Notes:
+//   - With the batch manifest (BLADE_BATCH_MANIFEST) the batch is marked rolled_back, so
+//     delivering its source again loads it again (as a resume) instead of skipping it
+//   - Tables that were never created are skipped
+//   - Classification-routed copies (BLADE_CLASSIFICATION_ROUTES) live in other tables or
+//     schemas and aren't covered
+
+// Latest versions of the main table searched for the batch's first write with Restore.
+const rollbackHistoryLimit = 100
+
+// How RollbackBatch undoes a batch.
+//   - Restore: RESTORE the main table instead of deleting its rows (see Modes)
+//   - DryRun: Count the rows that would be removed, change nothing
+type RollbackOptions struct {
+	Restore bool
+	DryRun  bool
+}
+
+// Rows a rollback removed (or, in a dry run, would remove) from one table.
+//   - Restored: Rows the batch superseded, moved back from {table}_archive (main table only)
+//   - Skipped: The table doesn't exist
+type RollbackTable struct {
+	Table    string `json:"table"`
+	Rows     int64  `json:"rows"`
+	Restored int64  `json:"restored,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"`
+}
+
+// Outcome of a rollback.
+//   - RestoredVersion: With Restore, the version the main table was (or would be) restored to
+//   - RowsRemoved: Sum over Tables
+//   - RowsRestored: Superseded rows moved back from the archive (see Archive Mode)
+type RollbackResult struct {
+	BatchID         string          `json:"batchId"`
+	DryRun          bool            `json:"dryRun,omitempty"`
+	RestoredVersion *int64          `json:"restoredVersion,omitempty"`
+	Tables          []RollbackTable `json:"tables"`
+	RowsRemoved     int64           `json:"rowsRemoved"`
+	RowsRestored    int64           `json:"rowsRestored,omitempty"`
+	Duration        time.Duration   `json:"duration"`
+}
+
+// Removes a batch's rows from a data type's tables of the configured schema.
+//   - tables: The data type's main table first (batch in metadata['batch_id']), then its
+//     crew and child tables (batch in batch_id); the reject table is added
+func (c *Client) RollbackBatch(ctx context.Context, batchID string, tables []string, opts RollbackOptions) (*RollbackResult, error) {
+	if batchID == "" {
+		return nil, fmt.Errorf("no batch ID to roll back")
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables to roll back batch %s from", batchID)
+	}
+	for _, table := range tables {
+		if !tableNamePattern.MatchString(table) {
+			return nil, fmt.Errorf("invalid table name %q", table)
+		}
+	}
+	start := time.Now()
+	result := &RollbackResult{BatchID: batchID, DryRun: opts.DryRun}
+
+	tables = append(append([]string(nil), tables...), RejectTable)
+	for i, table := range tables {
+		condition := "batch_id = :batch_id"
+		if i == 0 {
+			condition = "metadata['batch_id'] = :batch_id"
+		}
+		var removed RollbackTable
+		var err error
+		if i == 0 && opts.Restore {
+			removed, result.RestoredVersion, err = c.restoreBeforeBatch(ctx, table, batchID, opts.DryRun)
+		} else {
+			removed, err = c.deleteBatchRows(ctx, table, condition, batchID, opts.DryRun)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// - Only after the batch's own rows are gone, so the restored ones aren't removed with them
+		// - Not with Restore: a batch that archived rows deleted them in a later version, for
+		//   which RESTORE is refused
+		if i == 0 && !opts.Restore && !removed.Skipped {
+			if removed.Restored, err = c.restoreSuperseded(ctx, table, batchID, opts.DryRun); err != nil {
+				return nil, err
+			}
+		}
+		result.Tables = append(result.Tables, removed)
+		result.RowsRemoved += removed.Rows
+		result.RowsRestored += removed.Restored
+	}
+
+	if c.batchManifest && !opts.DryRun {
+		if err := c.markRolledBack(ctx, tables[0], batchID); err != nil {
+			return nil, err
+		}
+	}
+	result.Duration = time.Since(start)
+	if opts.DryRun {
+		runlog.Printf(ctx, "Rolling back batch %s would remove %d row(s) and restore %d archived row(s)", batchID, result.RowsRemoved, result.RowsRestored)
+	} else {
+		runlog.Printf(ctx, "Rolled back batch %s: %d row(s) removed, %d archived row(s) restored", batchID, result.RowsRemoved, result.RowsRestored)
+	}
+	return result, nil
+}
+
+// Counts, then deletes, a batch's rows in one table.
+func (c *Client) deleteBatchRows(ctx context.Context, table, condition, batchID string, dryRun bool) (RollbackTable, error) {
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table)
+	params := []sql.StatementParameterListItem{stringParam("batch_id", batchID)}
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement:  fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", fullName, condition),
+		Parameters: params,
+	})
+	if remedy, ok := Remediate(err); ok && remedy.Code == RemedyTableNotFound {
+		return RollbackTable{Table: table, Skipped: true}, nil
+	}
+	if err != nil {
+		return RollbackTable{}, fmt.Errorf("failed to count the rows of batch %s in %s: %w", batchID, fullName, err)
+	}
+	removed := RollbackTable{Table: table}
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		removed.Rows, _ = strconv.ParseInt(rows[0][0], 10, 64)
+	}
+	if dryRun || removed.Rows == 0 {
+		return removed, nil
+	}
+
+	rows, err = c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement:  fmt.Sprintf("DELETE FROM %s WHERE %s", fullName, condition),
+		Parameters: params,
+	})
+	if err != nil {
+		return RollbackTable{}, fmt.Errorf("failed to delete batch %s from %s: %w", batchID, fullName, err)
+	}
+	// - DELETE reports num_affected_rows as its single result row where the backend has it
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		if n, err := strconv.ParseInt(rows[0][0], 10, 64); err == nil {
+			removed.Rows = n
+		}
+	}
+	runlog.Printf(ctx, "Deleted %d row(s) of batch %s from %s", removed.Rows, batchID, fullName)
+	return removed, nil
+}
+
+// Moves the rows a batch superseded from {table}_archive back into the table and returns
+// how many there are (in a dry run, would be).
+//   - Columns both tables have are copied by name; the audit columns stay behind
+//   - Delta can't commit both tables in one transaction, so the copy skips batches the table
+//     already holds; a rollback interrupted between the two statements is completed by
+//     running it again
+//   - An archive that was never created has nothing to restore
+func (c *Client) restoreSuperseded(ctx context.Context, table, batchID string, dryRun bool) (int64, error) {
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table)
+	archive := fullName + archiveTableSuffix
+	params := []sql.StatementParameterListItem{stringParam("batch_id", batchID)}
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement:  fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE superseded_by = :batch_id", archive),
+		Parameters: params,
+	})
+	if remedy, ok := Remediate(err); ok && remedy.Code == RemedyTableNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count the rows batch %s superseded in %s: %w", batchID, archive, err)
+	}
+	var restored int64
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		restored, _ = strconv.ParseInt(rows[0][0], 10, 64)
+	}
+	if dryRun || restored == 0 {
+		return restored, nil
+	}
+
+	archived, err := c.DescribeColumns(ctx, table+archiveTableSuffix)
+	if err != nil {
+		return 0, err
+	}
+	current, err := c.DescribeColumns(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+	inTable := make(map[string]bool, len(current))
+	for _, col := range current {
+		inTable[strings.ToLower(col.Name)] = true
+	}
+	var names []string
+	for _, col := range archived {
+		if inTable[strings.ToLower(col.Name)] {
+			names = append(names, col.Name)
+		}
+	}
+	if len(names) == 0 {
+		return 0, fmt.Errorf("failed to restore the rows batch %s superseded: %s and %s share no columns", batchID, archive, fullName)
+	}
+	columns := strings.Join(names, ", ")
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			INSERT INTO %[1]s (%[3]s)
+			SELECT %[3]s FROM %[2]s
+			WHERE superseded_by = :batch_id
+			AND metadata['batch_id'] NOT IN (SELECT DISTINCT metadata['batch_id'] FROM %[1]s WHERE metadata['batch_id'] IS NOT NULL)
+		`, fullName, archive, columns),
+		Parameters: params,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to restore the rows batch %s superseded into %s: %w", batchID, fullName, err)
+	}
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement:  fmt.Sprintf("DELETE FROM %s WHERE superseded_by = :batch_id", archive),
+		Parameters: params,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to delete the restored rows from %s: %w", archive, err)
+	}
+	runlog.Printf(ctx, "Restored %d row(s) batch %s superseded from %s into %s", restored, batchID, archive, fullName)
+	return restored, nil
+}
+
+// Restores the main table to the version before the batch's first write, after checking
+// that no later version changed other batches' rows.
+func (c *Client) restoreBeforeBatch(ctx context.Context, table, batchID string, dryRun bool) (RollbackTable, *int64, error) {
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table)
+	versions, err := c.TableHistory(ctx, table, rollbackHistoryLimit, true)
+	if remedy, ok := Remediate(err); ok && remedy.Code == RemedyTableNotFound {
+		return RollbackTable{Table: table, Skipped: true}, nil, nil
+	}
+	if err != nil {
+		return RollbackTable{}, nil, err
+	}
+
+	// History: newest first; the batch's first write is the oldest version adding it
+	first := -1
+	for i, version := range versions {
+		for _, batch := range version.BatchesAdded {
+			if batch == batchID {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return RollbackTable{}, nil, fmt.Errorf("batch %s isn't in the latest %d versions of %s: roll it back without --restore", batchID, rollbackHistoryLimit, fullName)
+	}
+	if versions[first].Version == 0 {
+		return RollbackTable{}, nil, fmt.Errorf("batch %s created %s: roll it back without --restore", batchID, fullName)
+	}
+	for _, version := range versions[:first] {
+		changed := len(version.BatchesRemoved) > 0
+		for _, batch := range version.BatchesAdded {
+			changed = changed || batch != batchID
+		}
+		if changed {
+			return RollbackTable{}, nil, fmt.Errorf("version %d of %s changed other batches (%s): RESTORE would undo it too; roll back without --restore", version.Version, fullName, version.Operation)
+		}
+	}
+	target := versions[first].Version - 1
+
+	// - Versions that added no batch can still have written other batches' rows (a
+	//   concurrent load's later chunks): the other rows must be those of the target version
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			SELECT count_if(metadata['batch_id'] = :batch_id), count_if(metadata['batch_id'] IS NULL OR metadata['batch_id'] <> :batch_id),
+				(SELECT COUNT(*) FROM %[1]s VERSION AS OF %[2]d)
+			FROM %[1]s
+		`, fullName, target),
+		Parameters: []sql.StatementParameterListItem{stringParam("batch_id", batchID)},
+	})
+	if err != nil {
+		return RollbackTable{}, nil, fmt.Errorf("failed to compare %s with version %d: %w", fullName, target, err)
+	}
+	if len(rows) == 0 || len(rows[0]) < 3 {
+		return RollbackTable{}, nil, fmt.Errorf("failed to compare %s with version %d: no result", fullName, target)
+	}
+	counts := make([]int64, 3)
+	for i := range counts {
+		counts[i], _ = strconv.ParseInt(rows[0][i], 10, 64)
+	}
+	if counts[1] != counts[2] {
+		return RollbackTable{}, nil, fmt.Errorf("%s holds %d row(s) of other batches, version %d held %d: RESTORE would change them; roll back without --restore", fullName, counts[1], target, counts[2])
+	}
+
+	removed := RollbackTable{Table: table, Rows: counts[0]}
+	if dryRun {
+		return removed, &target, nil
+	}
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("RESTORE TABLE %s TO VERSION AS OF %d", fullName, target),
+	}); err != nil {
+		return RollbackTable{}, nil, fmt.Errorf("failed to restore %s to version %d: %w", fullName, target, err)
+	}
+	runlog.Printf(ctx, "Restored %s to version %d, removing %d row(s) of batch %s", fullName, target, removed.Rows, batchID)
+	return removed, &target, nil
+}
+
+// Marks a batch rolled back in the batch manifest; a manifest that doesn't exist has nothing to mark.
+func (c *Client) markRolledBack(ctx context.Context, table, batchID string) error {
+	manifest := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, BatchManifestTable)
+	var params paramList
+	_, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("UPDATE %s SET status = '%s', finished_at = current_timestamp() WHERE batch_id = %s AND table_name = %s",
+			manifest, manifestRolledBack, params.text(batchID), params.text(table)),
+		Parameters: params.params,
+	})
+	if remedy, ok := Remediate(err); ok && remedy.Code == RemedyTableNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark batch %s rolled back in %s: %w", batchID, manifest, err)
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/timeline"
+)
+
+//   Purpose: Data owners require mixed-classification extracts to be physically
+//   separated (e.g. CUI never lands next to UNCLASSIFIED rows). With a routing policy
+//   every record goes to the schema or table chosen by its classification_marking.
+
+//   Policy Format (BLADE_CLASSIFICATION_ROUTES):
+//   - Comma-separated MARKING=schema:NAME or MARKING=table:SUFFIX entries, e.g.
+//     "CUI=schema:logistics_cui, UNCLASSIFIED=schema:logistics, *=table:_unmarked"
+//   - A marking also matches its "//"-qualified forms ("CUI" matches "CUI//SP-PRVCY");
+//     the longest matching marking wins, "*" catches everything else
+//   - Without "*", records whose marking matches no route fail the run before any insert
+
+// Catch-all marking of a routing policy.
+const RouteAnyMarking = "*"
+
+// Where records carrying a classification marking are written.
+//   - Schema: Schema the records go to (same catalog and table name)
+//   - TableSuffix: Appended to the table name (and its child tables) in the same schema
+type ClassificationRoute struct {
+	Marking     string `json:"marking"`
+	Schema      string `json:"schema,omitempty"`
+	TableSuffix string `json:"tableSuffix,omitempty"`
+}
+
+// Outcome of the records sent to one route.
+type RouteResult struct {
+	Marking string           `json:"marking"`
+	Table   string           `json:"table"` // three-part name the records went to
+	Records int              `json:"records"`
+	Result  *IngestionResult `json:"result"`
+}
+
+var tableSuffixPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// Parses a BLADE_CLASSIFICATION_ROUTES policy; an empty policy disables routing.
+func ParseClassificationRoutes(policy string) ([]ClassificationRoute, error) {
+	var routes []ClassificationRoute
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(policy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		marking, target, found := strings.Cut(entry, "=")
+		kind, name, hasKind := strings.Cut(strings.TrimSpace(target), ":")
+		marking, name = normalizeMarking(marking), strings.TrimSpace(name)
+		if !found || !hasKind || marking == "" || name == "" {
+			return nil, fmt.Errorf("invalid classification route %q: use MARKING=schema:NAME or MARKING=table:SUFFIX", entry)
+		}
+		if seen[marking] {
+			return nil, fmt.Errorf("classification %s is routed twice", marking)
+		}
+		seen[marking] = true
+
+		route := ClassificationRoute{Marking: marking}
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "schema":
+			if !tableNamePattern.MatchString(name) {
+				return nil, fmt.Errorf("invalid schema %q in classification route %s", name, marking)
+			}
+			route.Schema = name
+		case "table":
+			if !tableSuffixPattern.MatchString(name) {
+				return nil, fmt.Errorf("invalid table suffix %q in classification route %s", name, marking)
+			}
+			route.TableSuffix = name
+		default:
+			return nil, fmt.Errorf("invalid classification route %q: target must be schema:NAME or table:SUFFIX", entry)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// Trims and upper-cases a marking so "cui " and "CUI" route alike.
+func normalizeMarking(marking string) string {
+	return strings.ToUpper(strings.TrimSpace(marking))
+}
+
+// Returns the route for a record's marking, or false when none matches.
+func matchRoute(routes []ClassificationRoute, marking string) (ClassificationRoute, bool) {
+	marking = normalizeMarking(marking)
+	var best ClassificationRoute
+	found := false
+	for _, route := range routes {
+		if route.Marking == RouteAnyMarking {
+			if !found {
+				best, found = route, true
+			}
+			continue
+		}
+		if marking != route.Marking && !strings.HasPrefix(marking, route.Marking+"//") {
+			continue
+		}
+		if !found || best.Marking == RouteAnyMarking || len(route.Marking) > len(best.Marking) {
+			best, found = route, true
+		}
+	}
+	return best, found
+}
+
+// Splits the request's records by route and ingests each group into its own schema or table.
+func (c *Client) ingestRouted(ctx context.Context, req *IngestionRequest) (*IngestionResult, error) {
+	start := time.Now()
+	failed := func(err error) (*IngestionResult, error) {
+		return &IngestionResult{TableName: req.TableName, Status: "failed", Error: err, Duration: time.Since(start)}, err
+	}
+
+	// Partition:
+	// - Every record is assigned before anything is written, so an unroutable
+	//   marking fails the run without a partial load
+	// - Groups keep the order in which their route first appears in the source
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return failed(fmt.Errorf("failed to parse sample data: %w", err))
+	}
+	if strings.Contains(req.StoragePath, "://") {
+		return failed(fmt.Errorf("table %s has a fixed storage URL and can't be routed by classification", req.TableName))
+	}
+	type group struct {
+		route   ClassificationRoute
+		records []map[string]interface{}
+	}
+	var groups []*group
+	byMarking := make(map[string]*group)
+	unroutable := make(map[string]bool)
+	for _, record := range records {
+		marking := recordText(record, "classification_marking")
+		route, ok := matchRoute(c.classificationRoutes, marking)
+		if !ok {
+			unroutable[marking] = true
+			continue
+		}
+		g := byMarking[route.Marking]
+		if g == nil {
+			g = &group{route: route}
+			byMarking[route.Marking] = g
+			groups = append(groups, g)
+		}
+		g.records = append(g.records, record)
+	}
+	// - The classification ceiling is checked across routes first, so a record above it
+	//   fails the run before any route writes
+	if err := c.classification.checkCeiling(records, req.TableName); err != nil {
+		return failed(err)
+	}
+	if len(unroutable) > 0 {
+		markings := make([]string, 0, len(unroutable))
+		for marking := range unroutable {
+			markings = append(markings, marking)
+		}
+		sort.Strings(markings)
+		return failed(fmt.Errorf("no classification route for marking(s) %q", markings))
+	}
+
+	// Ingestion:
+	// - Each group runs the full pipeline (table creation, insert, children, validation)
+	//   against its target; a failed route doesn't stop the others
+	// - Routed clients share the workspace connection, like tenant-scoped ones
+	result := &IngestionResult{
+		TableName: req.TableName,
+		Status:    "completed",
+		Metadata:  map[string]interface{}{"ingestion_type": "classification_routed", "data_source": req.DataSource, "blade_metadata": req.Metadata},
+		Warnings:  append([]Warning(nil), req.Warnings...),
+	}
+	var errs []error
+	markings := make(map[string]int64)
+	loaded := make(map[string]bool)
+	var archived int64
+	for _, g := range groups {
+		routed, routedReq := c.routeTo(g.route, req)
+		data, err := json.Marshal(g.records)
+		if err != nil {
+			return failed(fmt.Errorf("failed to encode records for route %s: %w", g.route.Marking, err))
+		}
+		routedReq.SampleData = string(data)
+
+		target := fmt.Sprintf("%s.%s.%s", routed.catalog, routed.schema, routedReq.TableName)
+		loaded[target] = true
+		runlog.Printf(ctx, "Routing %d %s record(s) to %s", len(g.records), g.route.Marking, target)
+		routeResult, err := routed.ingest(ctx, routedReq)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("route %s (%s): %w", g.route.Marking, target, err))
+		}
+		result.Routes = append(result.Routes, RouteResult{Marking: g.route.Marking, Table: target, Records: len(g.records), Result: routeResult})
+		if routeResult == nil {
+			result.Status = "failed"
+			continue
+		}
+
+		// Aggregate: rows, chunks and validations of every route; the worst status wins
+		result.RowsIngested += routeResult.RowsIngested
+		result.RowsSkipped += routeResult.RowsSkipped
+		result.RowsRejected += routeResult.RowsRejected
+		result.Rejections = reportedRejections(append(result.Rejections, routeResult.Rejections...))
+		result.Chunks = append(result.Chunks, routeResult.Chunks...)
+		result.Validations = append(result.Validations, routeResult.Validations...)
+		result.Warnings = append(result.Warnings, routeResult.Warnings...)
+		if rows, ok := routeResult.Metadata["archived_rows"].(int64); ok {
+			archived += rows
+		}
+		if routeMarkings, ok := routeResult.Metadata["marking_distribution"].(map[string]int64); ok {
+			for marking, rows := range routeMarkings {
+				markings[marking] += rows
+			}
+		}
+		if routeResult.Status == "failed" || (routeResult.Status == "partial" && result.Status == "completed") {
+			result.Status = routeResult.Status
+		}
+	}
+	if len(markings) > 0 {
+		result.Metadata["marking_distribution"] = markings
+	}
+
+	// Archive Mode:
+	// - Every route archives its own target's older batches of the source (see ingestBatch)
+	// - Targets of routes without records in this delivery still hold them: once every
+	//   route loaded, they are archived too, superseded by the first route's batch
+	if c.archiveSuperseded {
+		if result.Status == "completed" && len(errs) == 0 && len(result.Routes) > 0 {
+			supersededBy, _ := result.Routes[0].Result.Metadata["batch_id"].(string)
+			for _, route := range c.classificationRoutes {
+				routed, routedReq := c.routeTo(route, req)
+				target := fmt.Sprintf("%s.%s.%s", routed.catalog, routed.schema, routedReq.TableName)
+				if loaded[target] {
+					continue
+				}
+				loaded[target] = true
+				rows, err := routed.archiveIdleRoute(timeline.WithPhase(ctx, "archive"), routedReq, supersededBy)
+				if err != nil {
+					result.warn(ctx, WarnArchive, "could not archive superseded batches of route %s: %v", route.Marking, err)
+				}
+				archived += rows
+			}
+		}
+		result.Metadata["archived_rows"] = archived
+	}
+	if c.tenant != "" {
+		result.Metadata["tenant"] = c.tenant
+	}
+	if run := runlog.FromContext(ctx); run != nil {
+		result.Metadata["run_id"] = run.ID
+		result.Metadata["log_path"] = run.Path
+	}
+	result.Duration = time.Since(start)
+	if err := errors.Join(errs...); err != nil {
+		result.Error = err
+		return result, err
+	}
+	return result, nil
+}
+
+// Archives the source's batches in a route's target that got no records from this delivery;
+// a target that was never created has nothing to archive.
+func (c *Client) archiveIdleRoute(ctx context.Context, req *IngestionRequest, batchID string) (int64, error) {
+	existing, err := c.DescribeColumns(ctx, req.TableName)
+	if err != nil || len(existing) == 0 {
+		return 0, err
+	}
+	return c.archiveSupersededBatches(ctx, req, batchID)
+}
+
+// Returns the client and request copies that write to route's target.
+func (c *Client) routeTo(route ClassificationRoute, req *IngestionRequest) (*Client, *IngestionRequest) {
+	routed := *c
+	routed.classificationRoutes = nil
+	routedReq := *req
+	routedReq.Warnings = nil // preparation warnings are reported once, on the combined result
+	routedReq.Metadata = make(map[string]string, len(req.Metadata)+1)
+	for key, value := range req.Metadata {
+		routedReq.Metadata[key] = value
+	}
+	routedReq.Metadata["classification_route"] = route.Marking
+
+	// Storage: EXTERNAL tables of different routes never share a location
+	if route.Schema != "" {
+		routed.schema = route.Schema
+		if c.externalLocation != "" {
+			routed.externalLocation = strings.TrimRight(c.externalLocation, "/") + "/" + route.Schema
+		}
+	} else {
+		routedReq.tableSuffix = route.TableSuffix
+		routedReq.TableName = req.TableName + route.TableSuffix
+		routedReq.ChildTables = make([]ChildTable, len(req.ChildTables))
+		for i, child := range req.ChildTables {
+			child.Table += route.TableSuffix
+			routedReq.ChildTables[i] = child
+		}
+		if req.StoragePath != "" {
+			routedReq.StoragePath = strings.TrimRight(req.StoragePath, "/") + route.TableSuffix
+		}
+		if req.TTL != nil && req.TTL.View != "" {
+			ttl := *req.TTL
+			ttl.View += route.TableSuffix
+			routedReq.TTL = &ttl
+		}
+	}
+	return &routed, &routedReq
+}
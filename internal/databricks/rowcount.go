@@ -0,0 +1,94 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+//   Purpose: The table was counted after every load, but the count was only compared with
+//   the rows inserted, and only when it came back smaller. A load now counts its table
+//   before and after writing, so a load whose rows didn't all land (or that landed more
+//   than it sent) is flagged on its result, and can fail the run.
+
+//   Check (every load that writes its table: mock data, record files, streams, COPY INTO):
+//   - Expected: the table grew by exactly the rows the load inserted; chunks a resumed
+//     batch kept from an earlier attempt were counted before and aren't expected again
+//   - A count that fails leaves the check unverified, with a row_count warning; a count the
+//     warehouse doesn't return leaves it unverified quietly, as before
+//   - A concurrent write to the table shows up as a mismatch: the counts can't tell it apart
+//   - BLADE_ROW_COUNT_MISMATCH: "warn" (default) raises a row_count warning on a mismatch,
+//     "fail" also fails the run; its rows stay committed, like a failed validation
+
+// What a row count check that doesn't add up does (BLADE_ROW_COUNT_MISMATCH).
+const (
+	RowCountWarn = "warn"
+	RowCountFail = "fail"
+)
+
+// Outcome of a load's row count check.
+//   - Before, After: Rows in the table before the insert and after the load
+//   - Inserted: Rows the load inserted (RowsIngested, less the rows of resumed chunks)
+//   - Discrepancy: After - Before - Inserted; negative when rows are missing
+//   - Error: Why the table couldn't be counted (the check is then unverified)
+type RowCountCheck struct {
+	Before      int64  `json:"before"`
+	After       int64  `json:"after"`
+	Inserted    int64  `json:"inserted"`
+	Verified    bool   `json:"verified"`
+	Discrepancy int64  `json:"discrepancy,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Returns the BLADE_ROW_COUNT_MISMATCH mode, RowCountWarn when empty.
+func rowCountMismatchMode(mode string) (string, error) {
+	switch mode = strings.ToLower(mode); mode {
+	case "", RowCountWarn:
+		return RowCountWarn, nil
+	case RowCountFail:
+		return RowCountFail, nil
+	}
+	return "", fmt.Errorf("unsupported row count mismatch mode %q (supported: %s, %s)", mode, RowCountWarn, RowCountFail)
+}
+
+// Counts a table's rows for the check, bypassing the run's query cache, whose entries a
+// write by another process doesn't drop.
+func (c *Client) countRowsNow(ctx context.Context, table string) (int64, error) {
+	if cache := queryCacheFrom(ctx); cache != nil {
+		cache.invalidate(fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, table))
+	}
+	return c.getRowCount(ctx, table)
+}
+
+// Compares the table's growth with the rows the load inserted.
+//   - beforeErr, afterErr: Errors of the two counts; a count of 0 with no error means the
+//     warehouse returned no count, and is only trusted before a table's first load
+func checkRowCount(before, after int64, beforeErr, afterErr error, chunks []InsertChunk, rowsInserted int64) *RowCountCheck {
+	check := &RowCountCheck{Before: before, After: after, Inserted: rowsInserted}
+	for _, chunk := range chunks {
+		if chunk.Resumed {
+			check.Inserted -= chunk.Rows
+		}
+	}
+	switch {
+	case beforeErr != nil:
+		check.Error = fmt.Sprintf("counting the table before the insert: %v", beforeErr)
+	case afterErr != nil:
+		check.Error = fmt.Sprintf("counting the table after the load: %v", afterErr)
+	case after == 0 && check.Inserted > 0:
+		check.Error = "the warehouse returned no row count"
+	default:
+		check.Discrepancy = after - before - check.Inserted
+		check.Verified = check.Discrepancy == 0
+	}
+	return check
+}
+
+// Describes a mismatch ("table grew by 3 rows (5 → 8) but 4 were inserted: 1 row missing").
+func (r *RowCountCheck) describe(table string) string {
+	detail := fmt.Sprintf("%d more than inserted", r.Discrepancy)
+	if r.Discrepancy < 0 {
+		detail = fmt.Sprintf("%d missing", -r.Discrepancy)
+	}
+	return fmt.Sprintf("table %s grew by %d rows (%d → %d) but %d were inserted: %s", table, r.After-r.Before, r.Before, r.After, r.Inserted, detail)
+}
@@ -0,0 +1,282 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+//   Purpose: Post-load validations only count bad rows once they are in the table. Record
+//   rules check each record before anything is written, so a record missing its item_id or
+//   carrying an unknown marking never reaches the table, and what happens to it is the
+//   data type's choice: fail the load, skip the record, or quarantine it to a reject table.
+
+//   Rules (all optional, checked on every record):
+//   - Required: Fields that must be present and non-empty
+//   - Patterns: Field → regular expression its value must match (RE2, unanchored unless
+//     the pattern says ^...$); a missing field is left to Required
+//   - Allowed: Field → the only values it may hold (exact match)
+//   - Timestamps: Field → window its RFC 3339 timestamp or YYYY-MM-DD date must fall in
+//   - Applies to record loads (mock data, record files, streams); COPY INTO loads are
+//     checked after the fact by the validations instead
+
+// What happens to records that break their table's rules (RecordRules.OnInvalid).
+const (
+	OnInvalidFail       = "fail"       // fail the load before anything is written (default)
+	OnInvalidSkip       = "skip"       // load the valid records, report the rest on the result
+	OnInvalidQuarantine = "quarantine" // load the valid records, write the rest to blade_ingest_rejects
+)
+
+// Rejections listed on a result; RowsRejected still counts them all.
+const maxReportedRejections = 50
+
+// Per-record rules of a table, checked before its records are loaded.
+//   - OnInvalid: OnInvalidFail (default), OnInvalidSkip or OnInvalidQuarantine
+type RecordRules struct {
+	Required   []string              `json:"required,omitempty"`
+	Patterns   map[string]string     `json:"patterns,omitempty"`
+	Allowed    map[string][]string   `json:"allowed,omitempty"`
+	Timestamps map[string]TimeWindow `json:"timestamps,omitempty"`
+	OnInvalid  string                `json:"onInvalid,omitempty"`
+}
+
+// Window a timestamp field must fall in; each bound is optional.
+//   - MaxAge, MaxAhead: How far before / after the load it may be, a Go duration ("72h")
+//     or whole days ("1095d")
+//   - NotBefore, NotAfter: Fixed bounds, RFC 3339 timestamps or YYYY-MM-DD dates
+type TimeWindow struct {
+	MaxAge    string `json:"maxAge,omitempty"`
+	MaxAhead  string `json:"maxAhead,omitempty"`
+	NotBefore string `json:"notBefore,omitempty"`
+	NotAfter  string `json:"notAfter,omitempty"`
+}
+
+// Rules every built-in data type starts from: an item_id and item_type on each record,
+// and a timestamp no more than a day ahead of the load. Records that break them, or
+// that can't be parsed or inserted, are quarantined rather than failing the load.
+func DefaultRecordRules() *RecordRules {
+	return &RecordRules{
+		Required:   []string{"item_id", "item_type"},
+		Timestamps: map[string]TimeWindow{"timestamp": {MaxAhead: "1d"}},
+		OnInvalid:  OnInvalidQuarantine,
+	}
+}
+
+// Returns the OnInvalid mode, OnInvalidFail when unset or without rules.
+func (r *RecordRules) Mode() string {
+	if r == nil || r.OnInvalid == "" {
+		return OnInvalidFail
+	}
+	return strings.ToLower(r.OnInvalid)
+}
+
+// Checks the rules of the table they are declared on.
+func (r RecordRules) validate(table string) error {
+	switch r.Mode() {
+	case OnInvalidFail, OnInvalidSkip, OnInvalidQuarantine:
+	default:
+		return fmt.Errorf("table %s has invalid onInvalid %q: use %s, %s or %s", table, r.OnInvalid, OnInvalidFail, OnInvalidSkip, OnInvalidQuarantine)
+	}
+	return r.Check()
+}
+
+// Returns an error for each rule that can't be applied: an empty field name, a pattern
+// that doesn't compile, an empty allowed list or a bound that doesn't parse.
+func (r RecordRules) Check() error {
+	var errs []error
+	for _, field := range r.Required {
+		if strings.TrimSpace(field) == "" {
+			errs = append(errs, fmt.Errorf("rules require an empty field name"))
+		}
+	}
+	for _, field := range sortedKeys(r.Patterns) {
+		if _, err := regexp.Compile(r.Patterns[field]); err != nil {
+			errs = append(errs, fmt.Errorf("rule pattern for %s: %w", field, err))
+		}
+	}
+	for _, field := range sortedKeys(r.Allowed) {
+		if len(r.Allowed[field]) == 0 {
+			errs = append(errs, fmt.Errorf("rule for %s allows no values", field))
+		}
+	}
+	for _, field := range sortedKeys(r.Timestamps) {
+		window := r.Timestamps[field]
+		for _, bound := range []string{window.MaxAge, window.MaxAhead} {
+			if d, err := parseDays(bound); bound != "" && (err != nil || d <= 0) {
+				errs = append(errs, fmt.Errorf("timestamp rule for %s: invalid duration %q, use a positive duration such as 72h or 30d", field, bound))
+			}
+		}
+		for _, bound := range []string{window.NotBefore, window.NotAfter} {
+			if _, err := parseRecordTime(bound); bound != "" && err != nil {
+				errs = append(errs, fmt.Errorf("timestamp rule for %s: invalid bound %q, use an RFC 3339 timestamp or YYYY-MM-DD date", field, bound))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Compiles the Patterns; the rules are validated on the request, so they compile.
+func (r RecordRules) compilePatterns() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp, len(r.Patterns))
+	for field, pattern := range r.Patterns {
+		patterns[field] = regexp.MustCompile(pattern)
+	}
+	return patterns
+}
+
+// Returns why a record breaks the rules, in a stable order (nil when it doesn't).
+//   - patterns: The compiled Patterns (compilePatterns)
+//   - now: The load's start, which MaxAge and MaxAhead count from
+func (r RecordRules) check(record map[string]interface{}, patterns map[string]*regexp.Regexp, now time.Time) []string {
+	var reasons []string
+	for _, field := range r.Required {
+		if strings.TrimSpace(recordText(record, field)) == "" {
+			reasons = append(reasons, fmt.Sprintf("%s is required", field))
+		}
+	}
+	for _, field := range sortedKeys(r.Patterns) {
+		value := recordText(record, field)
+		if value == "" {
+			continue
+		}
+		if !patterns[field].MatchString(value) {
+			reasons = append(reasons, fmt.Sprintf("%s %q doesn't match %s", field, value, r.Patterns[field]))
+		}
+	}
+	for _, field := range sortedKeys(r.Allowed) {
+		value := recordText(record, field)
+		if value != "" && !containsValue(r.Allowed[field], value) {
+			reasons = append(reasons, fmt.Sprintf("%s %q isn't allowed", field, value))
+		}
+	}
+	for _, field := range sortedKeys(r.Timestamps) {
+		value := recordText(record, field)
+		if value == "" {
+			continue
+		}
+		if reason := r.Timestamps[field].check(field, value, now); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+// Returns why a timestamp falls outside the window, or "".
+func (w TimeWindow) check(field, value string, now time.Time) string {
+	t, err := parseRecordTime(value)
+	if err != nil {
+		return fmt.Sprintf("%s %q is not a timestamp", field, value)
+	}
+	if age, _ := parseDays(w.MaxAge); w.MaxAge != "" && t.Before(now.Add(-age)) {
+		return fmt.Sprintf("%s %s is older than %s", field, value, w.MaxAge)
+	}
+	if ahead, _ := parseDays(w.MaxAhead); w.MaxAhead != "" && t.After(now.Add(ahead)) {
+		return fmt.Sprintf("%s %s is more than %s ahead", field, value, w.MaxAhead)
+	}
+	if bound, err := parseRecordTime(w.NotBefore); err == nil && t.Before(bound) {
+		return fmt.Sprintf("%s %s is before %s", field, value, w.NotBefore)
+	}
+	if bound, err := parseRecordTime(w.NotAfter); err == nil && t.After(bound) {
+		return fmt.Sprintf("%s %s is after %s", field, value, w.NotAfter)
+	}
+	return ""
+}
+
+// Checks the request's records against its rules and the client's classification policy.
+//   - Returns a copy of the request holding only the valid records, like dedup, and the
+//     rejected ones; offset is the position of the first record in the source
+//   - Records rejected while preparing the request (Rejects, e.g. malformed CSV rows) are
+//     settled along with the ones that break the rules (see settleRejections)
+//   - A record above the classification ceiling fails the load whatever the rules' mode
+func (c *Client) applyRecordRules(ctx context.Context, req *IngestionRequest, batchID string, offset int) (*IngestionRequest, []Rejection, error) {
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse sample data: %w", err)
+	}
+	if err := c.classification.checkCeiling(records, req.TableName); err != nil {
+		return nil, nil, err
+	}
+	rejected := append([]Rejection(nil), req.Rejects...)
+	kept := records
+	if req.Rules != nil || c.classification != nil {
+		rules := req.Rules
+		if rules == nil {
+			rules = &RecordRules{}
+		}
+		patterns, now := rules.compilePatterns(), time.Now()
+		kept = make([]map[string]interface{}, 0, len(records))
+		for i, record := range records {
+			reasons := rules.check(record, patterns, now)
+			if marking := recordText(record, "classification_marking"); !c.classification.allows(marking) {
+				reasons = append(reasons, fmt.Sprintf("classification_marking %q isn't allowed here", marking))
+			}
+			if len(reasons) == 0 {
+				kept = append(kept, record)
+				continue
+			}
+			payload, _ := json.Marshal(record)
+			rejected = append(rejected, Rejection{Index: offset + i, Stage: RejectStageRules, ItemID: recordText(record, "item_id"), Reasons: reasons, Payload: string(payload)})
+		}
+	}
+	if len(rejected) == 0 {
+		return req, nil, nil
+	}
+	if err := c.settleRejections(ctx, req, batchID, rejected, len(records)+len(req.Rejects)); err != nil {
+		return nil, rejected, err
+	}
+
+	sampleData, err := json.Marshal(kept)
+	if err != nil {
+		return nil, rejected, fmt.Errorf("failed to encode valid records: %w", err)
+	}
+	checked := *req
+	checked.SampleData, checked.Rejects = string(sampleData), nil
+	return &checked, rejected, nil
+}
+
+// Describes the first n rejections ("record 3 (F16-001): item_type is required; ...").
+func describeRejections(rejected []Rejection, n int) string {
+	parts := make([]string, 0, n+1)
+	for _, rejection := range rejected[:min(n, len(rejected))] {
+		name := fmt.Sprintf("record %d", rejection.Index+1)
+		if rejection.Line > 0 {
+			name = fmt.Sprintf("line %d", rejection.Line)
+		}
+		if rejection.ItemID != "" {
+			name += fmt.Sprintf(" (%s)", rejection.ItemID)
+		}
+		parts = append(parts, name+": "+strings.Join(rejection.Reasons, ", "))
+	}
+	if len(rejected) > n {
+		parts = append(parts, fmt.Sprintf("and %d more", len(rejected)-n))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Caps the rejections listed on a result at maxReportedRejections.
+func reportedRejections(rejected []Rejection) []Rejection {
+	return rejected[:min(len(rejected), maxReportedRejections)]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsValue(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
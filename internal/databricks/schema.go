@@ -0,0 +1,137 @@
+package databricks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//   Purpose: Gives each data type real columns next to the standard BLADE columns, so
+//   analysts can query parts_required or labor hours directly instead of digging
+//   through the raw_data JSON. raw_data still keeps the complete record.
+
+// Columns every BLADE table has, in table order; typed columns follow them.
+var StandardColumns = []string{"item_id", "item_type", "classification_marking", "timestamp", "data_source", "raw_data", "ingestion_timestamp", "metadata"}
+
+// A typed column filled from one field of each record.
+//   - Name: Column name; also the record field read unless Field is set
+//   - Type: A scalar SQL type (STRING, INT, BIGINT, DOUBLE, BOOLEAN, DATE, TIMESTAMP,
+//     DECIMAL(p,s), ...) or ARRAY<scalar>
+//   - Comment: Column comment shown in Catalog Explorer
+//   - Missing fields and values that don't convert to the type are NULL
+type TypedColumn struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Field   string `json:"field,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+var (
+	scalarTypePattern = `(?:STRING|TINYINT|SMALLINT|INT|BIGINT|FLOAT|DOUBLE|BOOLEAN|DATE|TIMESTAMP|DECIMAL\(\d{1,2}, ?\d{1,2}\))`
+	columnTypePattern = regexp.MustCompile(`^(?:` + scalarTypePattern + `|ARRAY<` + scalarTypePattern + `>)$`)
+)
+
+// Returns the column's normalized SQL type, or an error if it isn't supported.
+func (col TypedColumn) SQLType() (string, error) {
+	sqlType := strings.ToUpper(strings.TrimSpace(col.Type))
+	if !columnTypePattern.MatchString(sqlType) {
+		return "", fmt.Errorf("column %s has unsupported type %q: use a scalar type or ARRAY<scalar>", col.Name, col.Type)
+	}
+	return sqlType, nil
+}
+
+// Returns the record field the column is read from.
+func (col TypedColumn) field() string {
+	if col.Field != "" {
+		return col.Field
+	}
+	return col.Name
+}
+
+// Reports whether the column holds an array (bound as JSON and parsed server-side).
+func (col TypedColumn) isArray() bool {
+	sqlType, _ := col.SQLType()
+	return strings.HasPrefix(sqlType, "ARRAY<")
+}
+
+// Checks the column names, fields and types of a table's typed columns.
+func validateTypedColumns(table string, columns []TypedColumn) error {
+	seen := make(map[string]bool)
+	for _, col := range columns {
+		name := strings.ToLower(col.Name)
+		if !tableNamePattern.MatchString(col.Name) || !tableNamePattern.MatchString(col.field()) {
+			return fmt.Errorf("table %s has invalid column %q (field %q)", table, col.Name, col.field())
+		}
+		for _, standard := range StandardColumns {
+			if name == standard {
+				return fmt.Errorf("table %s column %s clashes with a standard column", table, col.Name)
+			}
+		}
+		if seen[name] {
+			return fmt.Errorf("table %s declares column %s twice", table, col.Name)
+		}
+		seen[name] = true
+		if _, err := col.SQLType(); err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// Returns the column definitions appended to CREATE TABLE (", name TYPE COMMENT '...'" each).
+func typedColumnsDDL(columns []TypedColumn) string {
+	var ddl strings.Builder
+	for _, col := range columns {
+		fmt.Fprintf(&ddl, ",\n\t\t\t%s %s", col.Name, declaredType(col))
+		if col.Comment != "" {
+			fmt.Fprintf(&ddl, " COMMENT %s", sqlString(col.Comment))
+		}
+	}
+	return ddl.String()
+}
+
+// Returns the typed column names appended to an INSERT column list (", a, b").
+func typedColumnNames(columns []TypedColumn) string {
+	var names strings.Builder
+	for _, col := range columns {
+		names.WriteString(", " + col.Name)
+	}
+	return names.String()
+}
+
+// Binds the record's value for each typed column and returns the VALUES expressions (", :p1, ...").
+//   - Scalars are bound with the column type; try_cast keeps a bad value from failing the chunk
+//   - Arrays are bound as JSON text and parsed with from_json
+func typedColumnValues(params *paramList, columns []TypedColumn, record map[string]interface{}) string {
+	var values strings.Builder
+	for _, col := range columns {
+		sqlType, _ := col.SQLType()
+		if col.isArray() {
+			raw := ""
+			if value, ok := record[col.field()]; ok && value != nil {
+				encoded, _ := json.Marshal(value)
+				raw = string(encoded)
+			}
+			fmt.Fprintf(&values, ", from_json(%s, '%s')", params.bind(raw, "STRING"), sqlType)
+			continue
+		}
+		fmt.Fprintf(&values, ", try_cast(%s AS %s)", params.bind(recordText(record, col.field()), "STRING"), sqlType)
+	}
+	return values.String()
+}
+
+// Returns the COPY INTO select expressions for the typed columns (", CAST(`field` AS TYPE) AS name").
+//   - CSV files hold arrays as ";"-separated text, the same layout the mock CSVs use
+func typedColumnSelect(columns []TypedColumn, fileFormat string, sourceColumns map[string]string) string {
+	var selects strings.Builder
+	for _, col := range columns {
+		sqlType, _ := col.SQLType()
+		source := fileColumn(sourceColumns, col.field())
+		if col.isArray() && strings.EqualFold(fileFormat, "CSV") {
+			source = fmt.Sprintf("split(%s, ';')", source)
+		}
+		fmt.Fprintf(&selects, ",\n\t\t\t\ttry_cast(%s AS %s) AS %s", source, sqlType, col.Name)
+	}
+	return selects.String()
+}
@@ -0,0 +1,122 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Table property that carries example questions for Genie space curation.
+const exampleQuestionsProperty = "genie.example_questions"
+
+// Column tag that carries comma-separated synonyms for natural-language querying.
+const synonymsTag = "synonyms"
+
+// Natural-language metadata for one column of a BLADE table.
+type ColumnSemantics struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Synonyms    []string `json:"synonyms,omitempty"`
+}
+
+// Natural-language metadata for a BLADE table, formatted for Databricks Genie spaces.
+//   - Description: Becomes the table comment
+//   - Columns: Column comments plus a "synonyms" tag per column
+//   - ExampleQuestions: Stored as a table property and listed in the table comment
+type TableSemantics struct {
+	Description      string            `json:"description"`
+	Columns          []ColumnSemantics `json:"columns,omitempty"`
+	ExampleQuestions []string          `json:"exampleQuestions,omitempty"`
+}
+
+// Returns descriptions and synonyms for the standardized columns every BLADE table shares.
+func DefaultColumnSemantics() []ColumnSemantics {
+	return []ColumnSemantics{
+		{Name: "item_id", Description: "Unique identifier of the BLADE record", Synonyms: []string{"record id", "item number"}},
+		{Name: "item_type", Description: "Kind of logistics item or event the record describes", Synonyms: []string{"category", "record type"}},
+		{Name: "classification_marking", Description: "Security classification marking of the record", Synonyms: []string{"classification", "marking"}},
+		{Name: "timestamp", Description: "When the event occurred in BLADE", Synonyms: []string{"event time", "date"}},
+		{Name: "data_source", Description: "Upstream system the record was ingested from", Synonyms: []string{"source", "origin"}},
+		{Name: "raw_data", Description: "Complete source record as JSON; use get_json_object to read type-specific fields", Synonyms: []string{"payload", "details"}},
+		{Name: "ingestion_timestamp", Description: "When the record was loaded into Databricks", Synonyms: []string{"load time", "loaded at"}},
+		{Name: "metadata", Description: "Ingestion metadata such as batch_id and data_type", Synonyms: []string{"batch info"}},
+	}
+}
+
+// Applies table/column comments, synonym tags and example questions to an existing table.
+func (c *Client) SeedSemantics(ctx context.Context, tableName string, semantics TableSemantics) error {
+	fullName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, tableName)
+
+	// Statement Order:
+	// - Table comment first so a partially seeded table still has its description
+	// - Column comments and synonym tags next
+	// - Example questions last (table property)
+	for _, statement := range semanticStatements(fullName, semantics) {
+		if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{Statement: statement}); err != nil {
+			return fmt.Errorf("failed to seed semantics for %s: %w", fullName, err)
+		}
+	}
+	runlog.Printf(ctx, "Seeded semantic metadata for %s (%d columns, %d example questions)",
+		fullName, len(semantics.Columns), len(semantics.ExampleQuestions))
+	return nil
+}
+
+// Builds the DDL statements that attach the semantic metadata to fullName.
+func semanticStatements(fullName string, semantics TableSemantics) []string {
+	var statements []string
+
+	// Genie reads the table comment, so example questions are repeated there
+	comment := semantics.Description
+	if len(semantics.ExampleQuestions) > 0 {
+		comment += "\nExample questions:\n- " + strings.Join(semantics.ExampleQuestions, "\n- ")
+	}
+	if comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s", fullName, sqlString(comment)))
+	}
+
+	for _, column := range semantics.Columns {
+		if column.Description != "" {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s COMMENT %s",
+				fullName, column.Name, sqlString(column.Description)))
+		}
+		if len(column.Synonyms) > 0 {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET TAGS (%s = %s)",
+				fullName, column.Name, sqlString(synonymsTag), sqlString(strings.Join(column.Synonyms, ", "))))
+		}
+	}
+
+	if len(semantics.ExampleQuestions) > 0 {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s SET TBLPROPERTIES (%s = %s)",
+			fullName, sqlString(exampleQuestionsProperty), sqlString(strings.Join(semantics.ExampleQuestions, " | "))))
+	}
+	return statements
+}
+
+// Quotes s as a SQL string literal (DDL comments and tags cannot take parameter markers).
+func sqlString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// Returns the " COMMENT '...'" clause of a standard column in CREATE TABLE, from
+// DefaultColumnSemantics (empty for a column it doesn't describe).
+func standardColumnComment(name string) string {
+	for _, column := range DefaultColumnSemantics() {
+		if column.Name == name && column.Description != "" {
+			return " COMMENT " + sqlString(column.Description)
+		}
+	}
+	return ""
+}
+
+// Returns the COMMENT clause of a request's table in CREATE TABLE: the mapping's
+// description, carried in the request metadata (empty without one).
+func tableCommentClause(req *IngestionRequest) string {
+	if description := strings.TrimSpace(req.Metadata["description"]); description != "" {
+		return "COMMENT " + sqlString(description)
+	}
+	return ""
+}
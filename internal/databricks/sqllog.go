@@ -0,0 +1,81 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+var (
+	sqlStringLiteral = regexp.MustCompile(`'(?:[^'\\]|\\.)*'`)
+	sqlWhitespace    = regexp.MustCompile(`\s+`)
+)
+
+// Replaces every string literal with '?' and collapses whitespace, so statements can be
+// logged without record content; maxChars > 0 truncates the result.
+func RedactSQL(statement string, maxChars int) string {
+	redacted := sqlStringLiteral.ReplaceAllString(statement, "'?'")
+	redacted = strings.TrimSpace(sqlWhitespace.ReplaceAllString(redacted, " "))
+	if maxChars > 0 && len(redacted) > maxChars {
+		return fmt.Sprintf("%s... (%d more chars)", redacted[:maxChars], len(redacted)-maxChars)
+	}
+	return redacted
+}
+
+// Debug logger for submitted statements (BLADE_SQL_DEBUG).
+//   - Statements are redacted and truncated to maxChars
+//   - At most perSecond statements are logged per second; the rest are counted
+//     and reported on the next line that gets through
+type sqlLogger struct {
+	maxChars  int
+	perSecond int
+
+	mu          sync.Mutex
+	windowStart time.Time
+	logged      int
+	suppressed  int
+}
+
+func newSQLLogger(maxChars, perSecond int) *sqlLogger {
+	if perSecond <= 0 {
+		perSecond = 5
+	}
+	return &sqlLogger{maxChars: maxChars, perSecond: perSecond}
+}
+
+func (l *sqlLogger) log(ctx context.Context, req sql.ExecuteStatementRequest) {
+	l.mu.Lock()
+	now := time.Now()
+	if now.Sub(l.windowStart) >= time.Second {
+		l.windowStart, l.logged = now, 0
+	}
+	if l.logged >= l.perSecond {
+		l.suppressed++
+		l.mu.Unlock()
+		return
+	}
+	l.logged++
+	suppressed := l.suppressed
+	l.suppressed = 0
+	l.mu.Unlock()
+
+	// Parameters are listed by name only; their values are record content too
+	var names []string
+	for _, param := range req.Parameters {
+		names = append(names, ":"+param.Name)
+	}
+	line := "SQL: " + RedactSQL(req.Statement, l.maxChars)
+	if len(names) > 0 {
+		line += " [params " + strings.Join(names, ", ") + "]"
+	}
+	if suppressed > 0 {
+		line += fmt.Sprintf(" (%d statement(s) not logged)", suppressed)
+	}
+	runlog.Printf(ctx, "%s", line)
+}
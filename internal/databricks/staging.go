@@ -0,0 +1,61 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Load modes: "direct" inserts straight into the target, "staged" commits the whole run with one statement.
+const (
+	LoadModeDirect = "direct"
+	LoadModeStaged = "staged"
+)
+
+// Name of the per-batch staging table; unique per batch so concurrent runs never share one.
+func stagingTableName(table, batchID string) string {
+	return fmt.Sprintf("%s__staging_%s", table, batchID)
+}
+
+// Loads the batch into a staging table, then moves it into the target with a single INSERT ... SELECT.
+func (c *Client) insertStaged(ctx context.Context, req *IngestionRequest, batchID string) (int64, []InsertChunk, []Rejection, error) {
+	target := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName)
+	staging := stagingTableName(req.TableName, batchID)
+	stagingFull := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, staging)
+
+	// Staging Table:
+	// - Same columns as the target (CREATE TABLE ... LIKE), always managed
+	// - Dropped when the run ends; a process killed mid-run leaves only the
+	//   staging table behind, never a partially loaded target
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("CREATE TABLE %s LIKE %s", stagingFull, target),
+	}); err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to create staging table %s: %w", stagingFull, err)
+	}
+	defer func() {
+		// Cleanup still runs when the run budget has expired
+		if _, err := c.executeStatement(context.WithoutCancel(ctx), sql.ExecuteStatementRequest{
+			Statement: fmt.Sprintf("DROP TABLE IF EXISTS %s", stagingFull),
+		}); err != nil {
+			runlog.Printf(ctx, "Could not drop staging table %s: %v", stagingFull, err)
+		}
+	}()
+
+	// - A failed chunk fails the run before the commit, so the target sees none of the batch
+	rows, chunks, rejected, err := c.insertMockData(ctx, req, staging, batchID)
+	if err != nil {
+		return 0, chunks, rejected, err
+	}
+
+	// Commit:
+	// - A single Delta INSERT is atomic: the target sees every staged row or none
+	runlog.Printf(ctx, "Committing %d staged records from %s into %s", rows, stagingFull, target)
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", target, stagingFull),
+	}); err != nil {
+		return 0, chunks, rejected, fmt.Errorf("failed to commit staged batch into %s: %w", target, err)
+	}
+	return rows, chunks, rejected, nil
+}
@@ -0,0 +1,378 @@
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/timeline"
+	"github.com/databricks/databricks-sdk-go/apierr"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Codes a submission that raced a warehouse auto-stop fails with: the statement's
+// ServiceErrorCode, or the API's error_code when the submission itself is refused.
+// None is specific to auto-stop, so they only count while the warehouse reports
+// STOPPING or STOPPED (see IsWarehouseStoppingError).
+var warehouseStoppingCodes = map[string]bool{
+	string(sql.ServiceErrorCodeTemporarilyUnavailable): true,
+	string(sql.ServiceErrorCodeAborted):                true,
+	string(sql.ServiceErrorCodeCancelled):              true,
+	"INVALID_STATE":                                    true, // apierr.ErrInvalidState
+	"RESOURCE_CONFLICT":                                true, // apierr.ErrResourceConflict (409)
+}
+
+// Message fragments of the same race, only used when the warehouse's state can't be read.
+var warehouseStoppingSignatures = []string{
+	"warehouse is stopping",
+	"is in stopping state",
+	"state: stopping",
+	"warehouse was stopped",
+	"endpoint is stopping",
+}
+
+// Poll interval used when the configuration doesn't set one.
+const defaultStatementPollInterval = 2 * time.Second
+
+// Server-side wait of a statement before the client switches to polling it.
+const defaultWaitTimeout = "30s"
+
+// Runs a single statement against the configured SQL warehouse.
+func (c *Client) executeStatement(ctx context.Context, req sql.ExecuteStatementRequest) (*sql.StatementResponse, error) {
+	// Request Defaults:
+	// - WarehouseId: Always the client's warehouse unless the caller overrides it
+	// - WaitTimeout: defaultWaitTimeout, shortened (with server-side cancel) when the
+	//   run budget or the statement's own limit ends sooner
+	if req.WarehouseId == "" {
+		req.WarehouseId = c.warehouseID
+	}
+	if req.WaitTimeout == "" {
+		req.WaitTimeout = defaultWaitTimeout
+	}
+
+	// Statement Limit:
+	// - DDL and DML statements get their own deadline (BLADE_DDL_TIMEOUT / BLADE_DML_TIMEOUT)
+	//   on top of the run's; past it the statement is canceled like at the end of the budget
+	kind, label := describeStatement(req.Statement)
+	parent := ctx
+	limit, setting := c.statementLimit(kind)
+	if limit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limit)
+		defer cancel()
+	}
+	fitToBudget(ctx, &req)
+
+	// Read-Only Mode:
+	// - Enforced here, below every caller, so no code path can write by accident
+	if c.readOnly {
+		if err := CheckReadOnly(req.Statement); err != nil {
+			return nil, err
+		}
+	}
+	if c.sqlLog != nil {
+		c.sqlLog.log(ctx, req)
+	}
+
+	// Timeline:
+	// - One span per statement (retries included) in the run's timeline, if it has one
+	// - Writes also drop what the run's query cache holds for the table they touch
+	end := timeline.Begin(ctx, kind, label)
+	resp, err := c.executeWithRetry(ctx, req)
+	statementID := ""
+	if resp != nil {
+		statementID = resp.StatementId
+	}
+	end(statementID, err)
+	invalidateQueryCache(ctx, kind, label)
+	if err != nil {
+		err = explainThrottling(err)
+		if limit > 0 && parent.Err() == nil && errors.Is(err, context.DeadlineExceeded) && !deadlineFrom(ctx, parent) {
+			err = fmt.Errorf("%s statement did not finish within %s (%s): %w", kind, limit, setting, err)
+		}
+	}
+	return resp, err
+}
+
+// Returns ctx's error once it's done, or when the SDK's rate limiter refused a call that
+// would end after ctx's deadline (the deadline is as good as reached); nil otherwise.
+func deadlineReached(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if _, ok := ctx.Deadline(); ok && strings.Contains(err.Error(), "would exceed context deadline") {
+		return fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+	return nil
+}
+
+// Reports whether ctx's deadline is parent's, i.e. a limit added to ctx isn't what ran out.
+func deadlineFrom(ctx, parent context.Context) bool {
+	deadline, _ := ctx.Deadline()
+	parentDeadline, ok := parent.Deadline()
+	return ok && !parentDeadline.After(deadline)
+}
+
+// Returns the limit of a statement kind and the setting it comes from (0 = none).
+func (c *Client) statementLimit(kind string) (time.Duration, string) {
+	switch kind {
+	case "DDL":
+		return c.ddlTimeout, "BLADE_DDL_TIMEOUT"
+	case "DML":
+		return c.dmlTimeout, "BLADE_DML_TIMEOUT"
+	}
+	return 0, ""
+}
+
+// Submits the statement, resubmitting it when it raced a warehouse auto-stop.
+func (c *Client) executeWithRetry(ctx context.Context, req sql.ExecuteStatementRequest) (*sql.StatementResponse, error) {
+	// Auto-Stop Race:
+	// - A statement submitted while the warehouse is auto-stopping fails with a
+	//   recognizable error instead of waiting for the warehouse to come back
+	// - Wait for the stop to finish, then resubmit so auto-start kicks in
+	// - Any other error (or exhausting the attempts) is returned unchanged
+	for attempt := 1; ; attempt++ {
+		resp, err := c.executeStatementOnce(ctx, req)
+		if err == nil || attempt > c.retryAttempts || !c.racedAutoStop(ctx, req.WarehouseId, err) {
+			return resp, err
+		}
+
+		runlog.Printf(ctx, "Warehouse %s was auto-stopping (attempt %d/%d), retrying after restart: %v",
+			req.WarehouseId, attempt, c.retryAttempts, err)
+		if waitErr := c.waitForWarehouseStop(ctx, req.WarehouseId); waitErr != nil {
+			return resp, err
+		}
+	}
+}
+
+func (c *Client) executeStatementOnce(ctx context.Context, req sql.ExecuteStatementRequest) (*sql.StatementResponse, error) {
+	submitted := time.Now()
+	resp, err := c.submitStatement(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// - The statement is tracked while it runs, so shutdown can cancel it (see inflight.go)
+	tracker := statementTrackerFrom(ctx)
+	tracker.track(c, resp.StatementId)
+	defer tracker.untrack(resp.StatementId)
+
+	// - WaitTimeout only bounds the server-side wait; a statement still running
+	//   afterwards is followed until it finishes instead of being reported as done
+	resp, err = c.awaitStatement(ctx, resp, submitted)
+	if err != nil {
+		return resp, err
+	}
+
+	// Failed Statements:
+	// - The API reports SQL errors (syntax, permissions, missing objects) as a
+	//   FAILED state on an otherwise successful HTTP response
+	// - Surfacing them as errors keeps callers from reading an empty result
+	if resp.Status != nil && (resp.Status.State == sql.StatementStateFailed || resp.Status.State == sql.StatementStateCanceled) {
+		statementErr := &StatementError{State: string(resp.Status.State)}
+		if resp.Status.Error != nil {
+			statementErr.Code, statementErr.Message = string(resp.Status.Error.ErrorCode), resp.Status.Error.Message
+		}
+		return resp, statementErr
+	}
+
+	return resp, nil
+}
+
+// Follows a statement that is still PENDING/RUNNING via GetStatement until it succeeds, fails or is canceled.
+//   - Polls every statementPollInterval (BLADE_STATEMENT_POLL_INTERVAL)
+//   - Gives up after statementTimeout from submission (BLADE_STATEMENT_TIMEOUT), or when ctx
+//     ends (run budget), and cancels the statement so it doesn't keep running unobserved
+//   - Logs live progress (bytes, files, rows, tasks) from the query history while it runs,
+//     unless BLADE_STATEMENT_PROGRESS is false (see progress.go)
+func (c *Client) awaitStatement(ctx context.Context, resp *sql.StatementResponse, submitted time.Time) (*sql.StatementResponse, error) {
+	interval := c.statementPollInterval
+	if interval <= 0 {
+		interval = defaultStatementPollInterval
+	}
+	var tail progressTail
+	for statementActive(resp) {
+		if c.statementTimeout > 0 && time.Since(submitted) >= c.statementTimeout {
+			c.cancelStatement(ctx, resp.StatementId)
+			return resp, fmt.Errorf("statement %s still %s after %s, canceled", resp.StatementId, resp.Status.State, c.statementTimeout)
+		}
+		runlog.Printf(ctx, "Statement %s is %s, checking again in %s", resp.StatementId, resp.Status.State, interval)
+		if c.statementProgress {
+			c.tailProgress(ctx, resp.StatementId, &tail)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.cancelStatement(ctx, resp.StatementId)
+			return resp, ctx.Err()
+		case <-time.After(interval):
+		}
+		next, err := c.statements.GetStatementByStatementId(ctx, resp.StatementId)
+		if err != nil {
+			if reached := deadlineReached(ctx, err); reached != nil {
+				c.cancelStatement(ctx, resp.StatementId)
+				return resp, reached
+			}
+			return resp, fmt.Errorf("failed to poll statement %s: %w", resp.StatementId, err)
+		}
+		resp = next
+	}
+	return resp, nil
+}
+
+// Cancels a statement the client stopped waiting for; failures are only logged.
+func (c *Client) cancelStatement(ctx context.Context, statementID string) {
+	// Cancellation still goes out when ctx itself is what ran out
+	if err := c.statements.CancelExecution(context.WithoutCancel(ctx), sql.CancelExecutionRequest{
+		StatementId: statementID,
+	}); err != nil {
+		runlog.Printf(ctx, "Could not cancel statement %s: %v", statementID, err)
+	}
+}
+
+// Classifies a statement for the run timeline: its kind (DDL, DML, QUERY) and a short
+// label made of the leading keyword and the first qualified name, without any literals.
+func describeStatement(statement string) (kind, label string) {
+	words := strings.Fields(RedactSQL(statement, 0))
+	if len(words) == 0 {
+		return "OTHER", ""
+	}
+	keyword := strings.ToUpper(words[0])
+	switch keyword {
+	case "CREATE", "ALTER", "DROP", "COMMENT", "SET":
+		kind = "DDL"
+	case "INSERT", "DELETE", "MERGE", "UPDATE", "COPY":
+		kind = "DML"
+	case "SELECT", "WITH", "DESCRIBE", "SHOW":
+		kind = "QUERY"
+	default:
+		kind = "OTHER"
+	}
+	label = keyword
+	for _, word := range words[1:] {
+		if strings.Count(word, ".") == 2 && !strings.ContainsAny(word, "'(") {
+			label += " " + strings.TrimRight(word, ",;")
+			break
+		}
+	}
+	return kind, label
+}
+
+// Reports whether a failed statement raced an auto-stop, asking the warehouse for its
+// state when the error could be one.
+func (c *Client) racedAutoStop(ctx context.Context, warehouseID string, err error) bool {
+	if !warehouseStoppingCodes[statementErrorCode(err)] && !matchesStoppingSignature(err) {
+		return false
+	}
+	var state sql.State
+	if warehouse, getErr := c.workspace.Warehouses.GetById(ctx, warehouseID); getErr == nil {
+		state = warehouse.State
+	}
+	return IsWarehouseStoppingError(err, state)
+}
+
+// Reports whether err is a statement failure caused by an auto-stop, given the state the
+// warehouse reports afterwards:
+//   - STOPPING / STOPPED: err carries one of warehouseStoppingCodes (or a stopping message)
+//   - Unknown state (""): only a stopping message counts
+//   - Any other state: never; the warehouse is up, so retrying won't help
+func IsWarehouseStoppingError(err error, state sql.State) bool {
+	if err == nil {
+		return false
+	}
+	switch state {
+	case sql.StateStopping, sql.StateStopped:
+		return warehouseStoppingCodes[statementErrorCode(err)] || matchesStoppingSignature(err)
+	case "":
+		return matchesStoppingSignature(err)
+	}
+	return false
+}
+
+// Returns the error code of a failed statement (its ServiceErrorCode) or refused API call
+// (error_code), upper-cased; "" for other errors.
+func statementErrorCode(err error) string {
+	var statementErr *StatementError
+	if errors.As(err, &statementErr) && statementErr.Code != "" {
+		return strings.ToUpper(statementErr.Code)
+	}
+	var apiErr *apierr.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusConflict && apiErr.ErrorCode == "" {
+			return "RESOURCE_CONFLICT"
+		}
+		return strings.ToUpper(apiErr.ErrorCode)
+	}
+	return ""
+}
+
+func matchesStoppingSignature(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, signature := range warehouseStoppingSignatures {
+		if strings.Contains(message, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// Blocks until the warehouse has left the STOPPING state (or the retry delay elapses).
+func (c *Client) waitForWarehouseStop(ctx context.Context, warehouseID string) error {
+	deadline := time.Now().Add(c.retryDelay)
+	for {
+		warehouse, err := c.workspace.Warehouses.GetById(ctx, warehouseID)
+		if err == nil && warehouse.State != sql.StateStopping {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// Runs a query and returns its inline result rows (empty when the query returned nothing).
+func (c *Client) queryRows(ctx context.Context, req sql.ExecuteStatementRequest) ([][]string, error) {
+	resp, err := c.executeStatement(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, nil
+	}
+	return resp.Result.DataArray, nil
+}
+
+// Collects positional-style named parameters (:p0, :p1, ...) for statements with many
+// values, such as multi-row INSERTs, so record content never becomes SQL text.
+type paramList struct {
+	params []sql.StatementParameterListItem
+}
+
+// Adds a parameter of the given SQL type and returns its marker; an empty value binds NULL.
+func (p *paramList) bind(value, sqlType string) string {
+	name := fmt.Sprintf("p%d", len(p.params))
+	p.params = append(p.params, sql.StatementParameterListItem{Name: name, Value: value, Type: sqlType})
+	return ":" + name
+}
+
+// Adds a STRING parameter that keeps an empty value as an empty string rather than NULL.
+func (p *paramList) text(value string) string {
+	marker := p.bind(value, "STRING")
+	p.params[len(p.params)-1].ForceSendFields = []string{"Value"}
+	return marker
+}
+
+// Builds a named STRING parameter for use with :name markers in a statement.
+func stringParam(name, value string) sql.StatementParameterListItem {
+	return sql.StatementParameterListItem{Name: name, Value: value, Type: "STRING"}
+}
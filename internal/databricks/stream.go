@@ -0,0 +1,243 @@
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/timeline"
+)
+
+//   Purpose: Mock and dropped-in files used to travel in SampleData as one JSON string,
+//   so a multi-gigabyte file had to fit in memory several times over. A record_stream
+//   request pulls its records from a RecordSource instead (its Records, or the local
+//   newline-delimited JSON file it names), loading them one chunk at a time.
+
+//   Per Chunk (BLADE_INSERT_CHUNK_SIZE records, 500 when it's 0):
+//   - Record rules, dedup, the INSERT, sortie crew and child tables run on the chunk, in
+//     that order; in fail mode a chunk with an invalid record stops the load, leaving the
+//     chunks before it committed
+//   - Dedup sees earlier chunks in the table, so repeats across chunks are dropped too
+//   - A failed INSERT is recorded on its chunk and the stream goes on, like chunked inserts;
+//     a failed dedup, crew or child table insert stops the load
+//   - A line that isn't a JSON object stops the load, unless the table skips or quarantines
+//     invalid records: then it is rejected with the chunk it was read in (see rejects.go)
+//   - Sample verification draws from the first chunk
+//   - A resumed batch reads the chunks an earlier attempt committed without loading them
+//     again, and clears the rejections the others left before replaying them (see manifest.go)
+
+// Request mode that inserts records pulled chunk by chunk from the request's Records, or
+// from the local NDJSON file its SourcePath names ("file://..."), instead of SampleData.
+const ModeRecordStream = "record_stream"
+
+// Records per chunk of a stream when BLADE_INSERT_CHUNK_SIZE is 0 (one INSERT per load).
+const defaultStreamChunkSize = 500
+
+// Prefix of the local file paths streamed by record_stream requests.
+const localFilePrefix = "file://"
+
+// Returns the local path of a record_stream request's file.
+func streamPath(req *IngestionRequest) string {
+	return strings.TrimPrefix(req.SourcePath, localFilePrefix)
+}
+
+// What a streamed load wrote.
+//   - sample: The first chunk as a JSON array, for sample verification
+//   - markings: Rows inserted per classification marking
+type streamLoad struct {
+	records   int64
+	rows      int64
+	skipped   int64
+	childRows map[string]int64
+	rejected  []Rejection
+	chunks    []InsertChunk
+	sample    string
+	markings  map[string]int64
+}
+
+// Streams the request's records into its table, one chunk at a time.
+//   - The next chunk is only pulled once the previous one is loaded (backpressure)
+func (c *Client) insertStream(ctx context.Context, req *IngestionRequest, batchID string) (*streamLoad, error) {
+	load := &streamLoad{markings: make(map[string]int64)}
+	source, name, err := openRecordSource(req)
+	if err != nil {
+		return load, err
+	}
+	defer source.Close()
+
+	size := c.insertChunkSize
+	if size <= 0 {
+		size = defaultStreamChunkSize
+	}
+	runlog.Printf(ctx, "Streaming %s into %s.%s.%s in chunks of %d records", name, c.catalog, c.schema, req.TableName, size)
+
+	var failed []string
+	for offset := 0; ; {
+		sampleData, count, malformed, err := readStreamChunk(ctx, source, size, offset, req.Rules.Mode() != OnInvalidFail)
+		if err != nil {
+			return load, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if count == 0 && len(malformed) == 0 {
+			break
+		}
+		// - Out of run budget (or interrupted): stop between chunks; committed chunks stay counted
+		if budgetExceeded(ctx) {
+			return load, fmt.Errorf("%w: stopped before insert chunk %d", stopReason(ctx), len(load.chunks)+1)
+		}
+		chunkReq := *req
+		chunkReq.SampleData, chunkReq.Rejects = sampleData, malformed
+		if load.sample == "" && count > 0 {
+			load.sample = sampleData
+		}
+		load.records += int64(count)
+
+		index := len(load.chunks) + 1
+		if rows, ok := req.committedChunks[index]; ok {
+			load.rows += rows
+			load.chunks = append(load.chunks, InsertChunk{Index: index, Offset: offset, Rows: rows, Resumed: true})
+			offset += count
+			continue
+		}
+		if req.committedChunks != nil {
+			to := offset + count
+			if count < size {
+				to = -1
+			}
+			if err := c.clearChunkRejects(timeline.WithPhase(ctx, "manifest"), req, batchID, offset, to); err != nil {
+				return load, err
+			}
+		}
+
+		chunk, err := c.loadStreamChunk(ctx, &chunkReq, batchID, offset, load)
+		chunk.Index, chunk.Offset = index, offset
+		// - A chunk that stopped after its INSERT (crew, child tables) isn't committed
+		if err != nil && chunk.Error == "" {
+			chunk.Error = err.Error()
+		}
+		load.chunks = append(load.chunks, chunk)
+		if err != nil {
+			return load, err
+		}
+		if chunk.Error != "" {
+			failed = append(failed, fmt.Sprintf("chunk %d (records %d-%d): %s", chunk.Index, offset+1, offset+count, chunk.Error))
+		}
+		offset += count
+	}
+
+	runlog.Printf(ctx, "Streamed %d records in %d chunks: %d inserted, %d skipped (%d chunks failed)",
+		load.records, len(load.chunks), load.rows, load.skipped, len(failed))
+	if len(failed) > 0 {
+		return load, fmt.Errorf("%d of %d insert chunks failed: %s", len(failed), len(load.chunks), strings.Join(failed, "; "))
+	}
+	return load, nil
+}
+
+// Reads up to size records and returns them as a JSON array, with their count (0 at the end).
+//   - keepMalformed: Lines that aren't JSON objects are returned as parse rejections
+//     (positioned from offset) instead of failing the read
+func readStreamChunk(ctx context.Context, source RecordSource, size int, offset int, keepMalformed bool) (string, int, []Rejection, error) {
+	var chunk bytes.Buffer
+	chunk.WriteByte('[')
+	count := 0
+	var malformed []Rejection
+	for count < size {
+		record, err := source.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var lineErr *blademap.LineError
+		if keepMalformed && errors.As(err, &lineErr) {
+			malformed = append(malformed, Rejection{Index: offset + count, Line: lineErr.Line, Stage: RejectStageParse, Reasons: []string{"not a JSON object"}, Payload: lineErr.Text})
+			continue
+		}
+		if err != nil {
+			return "", 0, nil, err
+		}
+		if count > 0 {
+			chunk.WriteByte(',')
+		}
+		chunk.Write(record)
+		count++
+	}
+	chunk.WriteByte(']')
+	return chunk.String(), count, malformed, nil
+}
+
+// Loads one chunk: record rules, dedup, INSERT, crew and child tables, adding what it wrote to load.
+//   - offset: Position of the chunk's first record in the source
+//   - An INSERT failure is returned on the chunk; other failures as the error
+func (c *Client) loadStreamChunk(ctx context.Context, req *IngestionRequest, batchID string, offset int, load *streamLoad) (InsertChunk, error) {
+	var chunk InsertChunk
+	if req.Rules != nil || len(req.Rejects) > 0 || c.classification != nil {
+		checked, rejected, err := c.applyRecordRules(timeline.WithPhase(ctx, "rules"), req, batchID, offset)
+		load.rejected = append(load.rejected, rejected...)
+		if err != nil {
+			return chunk, fmt.Errorf("failed record rules: %w", err)
+		}
+		req = checked
+	}
+	if req.Dedup != nil {
+		deduped, skipped, err := c.dedupRecords(timeline.WithPhase(ctx, "dedup"), req)
+		if err != nil {
+			return chunk, fmt.Errorf("failed to deduplicate records: %w", err)
+		}
+		req = deduped
+		load.skipped += skipped
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		return chunk, fmt.Errorf("failed to parse records: %w", err)
+	}
+	if len(records) == 0 {
+		return chunk, nil
+	}
+
+	started := time.Now()
+	insertCtx := timeline.WithPhase(ctx, "insert")
+	statementID, err := c.insertRecords(insertCtx, req, req.TableName, batchID, records)
+	chunk.Rows = int64(len(records))
+	// - A chunk the warehouse failed may be retried record by record (see rejects.go); crew
+	//   and child rows then only follow the records that went in
+	isolated := false
+	var refused []Rejection
+	if err != nil && isolatesInsertFailure(ctx, req, err) {
+		chunk.Rows, refused, err = c.isolateInsertFailures(insertCtx, req, req.TableName, batchID, records, 0, err)
+		chunk.Rejected, isolated = len(refused), true
+		load.rejected = append(load.rejected, refused...)
+		if err == nil {
+			req, err = withoutRejected(req, refused)
+		}
+	}
+	chunk.StatementID, chunk.Duration = statementID, time.Since(started)
+	if err != nil && !isolated {
+		chunk.Rows = 0
+	}
+	load.rows += chunk.Rows
+	if err != nil {
+		chunk.Error = err.Error()
+		return chunk, nil
+	}
+	countMarkings(load.markings, records, refused)
+
+	// - Child rows (sortie crew included) key back to the chunk's rows, so they follow its INSERT
+	if len(req.ChildTables) > 0 {
+		childRows, err := c.insertChildTables(timeline.WithPhase(ctx, "child_tables"), req, batchID)
+		if err != nil {
+			return chunk, fmt.Errorf("failed to insert child tables: %w", err)
+		}
+		if load.childRows == nil {
+			load.childRows = make(map[string]int64, len(childRows))
+		}
+		for table, rows := range childRows {
+			load.childRows[table] += rows
+		}
+	}
+	return chunk, nil
+}
@@ -0,0 +1,199 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: FinOps attributes workspace spend by tag. Every catalog, schema and table
+//   this integration creates carries the cost-attribution tags from config (project,
+//   owner, environment), and the SQL warehouse can carry them too, so its spend shows
+//   up under the project without anyone tagging objects by hand.
+
+//   Data Tags:
+//   - Each data type's table also carries tags describing its data, so it can be found
+//     and governed by them in Catalog Explorer: source_system and data_type from the
+//     request metadata, and classification (see tableClassification)
+
+//   Failure Handling:
+//   - Tagging needs APPLY TAG on the object (warehouse: CAN MANAGE); a failure is logged
+//     and doesn't fail the load, the object is tagged again by the next run
+//   - Each object is tagged once per process, not on every load
+
+// A cost-attribution tag.
+type costTag struct {
+	key   string
+	value string
+}
+
+// Returns the configured cost tags in a fixed order; empty values are left out.
+func costTagsFromConfig(cfg *config.Config) []costTag {
+	var tags []costTag
+	for _, tag := range []costTag{
+		{"project", cfg.CostProject},
+		{"owner", cfg.CostOwner},
+		{"environment", cfg.CostEnvironment},
+	} {
+		if value := strings.TrimSpace(tag.value); value != "" {
+			tags = append(tags, costTag{tag.key, value})
+		}
+	}
+	return tags
+}
+
+// Tags a Unity Catalog object ("CATALOG", "SCHEMA" or "TABLE" and its full name) with the cost tags.
+func (c *Client) applyCostTags(ctx context.Context, securable, name string) {
+	if len(c.costTags) == 0 {
+		return
+	}
+	object := securable + " " + name
+	if _, done := c.tagged.LoadOrStore(object, true); done {
+		return
+	}
+	pairs := make([]string, len(c.costTags))
+	for i, tag := range c.costTags {
+		pairs[i] = fmt.Sprintf("%s = %s", sqlString(tag.key), sqlString(tag.value))
+	}
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("ALTER %s SET TAGS (%s)", object, strings.Join(pairs, ", ")),
+	}); err != nil {
+		c.tagged.Delete(object)
+		runlog.Printf(ctx, "Could not apply cost tags to %s: %v", strings.ToLower(object), err)
+	}
+}
+
+// Tags a request's table with its data tags (see Data Tags).
+func (c *Client) applyTableTags(ctx context.Context, req *IngestionRequest) {
+	var tags []costTag
+	for _, tag := range []costTag{
+		{"source_system", req.Metadata["source_system"]},
+		{"data_type", req.Metadata["data_type"]},
+		{"classification", c.tableClassification(req)},
+	} {
+		if value := strings.TrimSpace(tag.value); value != "" {
+			tags = append(tags, costTag{tag.key, value})
+		}
+	}
+	if len(tags) == 0 {
+		return
+	}
+	name := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName)
+	if _, done := c.tagged.LoadOrStore("DATA TABLE "+name, true); done {
+		return
+	}
+	pairs := make([]string, len(tags))
+	for i, tag := range tags {
+		pairs[i] = fmt.Sprintf("%s = %s", sqlString(tag.key), sqlString(tag.value))
+	}
+	if _, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("ALTER TABLE %s SET TAGS (%s)", name, strings.Join(pairs, ", ")),
+	}); err != nil {
+		c.tagged.Delete("DATA TABLE " + name)
+		runlog.Printf(ctx, "Could not apply data tags to table %s: %v", name, err)
+	}
+}
+
+// Returns the classification a request's table is tagged with: the marking its
+// classification route takes (not the "*" catch-all), else the ceiling of the
+// classification policy, the highest marking the table may hold (empty for neither).
+func (c *Client) tableClassification(req *IngestionRequest) string {
+	if marking := req.Metadata["classification_route"]; marking != "" && marking != RouteAnyMarking {
+		return marking
+	}
+	if c.classification != nil {
+		return c.classification.Ceiling
+	}
+	return ""
+}
+
+// Forgets that a table was tagged, after it was dropped and recreated.
+func (c *Client) forgetCostTags(securable, name string) {
+	c.tagged.Delete(securable + " " + name)
+	c.tagged.Delete("DATA " + securable + " " + name)
+}
+
+// Adds the cost tags to the SQL warehouse's custom tags (BLADE_TAG_WAREHOUSE).
+//   - Editing a warehouse rewrites its whole definition, so it is read first and only
+//     edited when a tag is missing or different; other custom tags are kept
+func (c *Client) applyWarehouseCostTags(ctx context.Context) {
+	if !c.tagWarehouse || len(c.costTags) == 0 {
+		return
+	}
+	if _, done := c.tagged.LoadOrStore("WAREHOUSE "+c.warehouseID, true); done {
+		return
+	}
+	if err := c.tagWarehouseOnce(ctx); err != nil {
+		c.tagged.Delete("WAREHOUSE " + c.warehouseID)
+		runlog.Printf(ctx, "Could not apply cost tags to warehouse %s: %v", c.warehouseID, err)
+	}
+}
+
+func (c *Client) tagWarehouseOnce(ctx context.Context) error {
+	warehouse, err := c.workspace.Warehouses.GetById(ctx, c.warehouseID)
+	if err != nil {
+		return err
+	}
+	var existing []sql.EndpointTagPair
+	if warehouse.Tags != nil {
+		existing = warehouse.Tags.CustomTags
+	}
+	merged, changed := mergeWarehouseTags(existing, c.costTags)
+	if !changed {
+		return nil
+	}
+	warehouseType := sql.EditWarehouseRequestWarehouseType(warehouse.WarehouseType)
+	if warehouse.WarehouseType == sql.GetWarehouseResponseWarehouseTypeTypeUnspecified {
+		warehouseType = ""
+	}
+
+	// - Fields the API treats as unset when zero are sent explicitly, so the edit keeps them
+	_, err = c.workspace.Warehouses.Edit(ctx, sql.EditWarehouseRequest{
+		Id:                      warehouse.Id,
+		Name:                    warehouse.Name,
+		AutoStopMins:            warehouse.AutoStopMins,
+		Channel:                 warehouse.Channel,
+		ClusterSize:             warehouse.ClusterSize,
+		CreatorName:             warehouse.CreatorName,
+		EnablePhoton:            warehouse.EnablePhoton,
+		EnableServerlessCompute: warehouse.EnableServerlessCompute,
+		InstanceProfileArn:      warehouse.InstanceProfileArn,
+		MaxNumClusters:          warehouse.MaxNumClusters,
+		MinNumClusters:          warehouse.MinNumClusters,
+		SpotInstancePolicy:      warehouse.SpotInstancePolicy,
+		WarehouseType:           warehouseType,
+		Tags:                    &sql.EndpointTags{CustomTags: merged},
+		ForceSendFields:         []string{"AutoStopMins", "EnablePhoton", "EnableServerlessCompute"},
+	})
+	if err != nil {
+		return err
+	}
+	runlog.Printf(ctx, "Applied cost tags to warehouse %s", c.warehouseID)
+	return nil
+}
+
+// Returns the warehouse's custom tags with the cost tags set, and whether anything changed.
+func mergeWarehouseTags(existing []sql.EndpointTagPair, tags []costTag) ([]sql.EndpointTagPair, bool) {
+	merged := append([]sql.EndpointTagPair(nil), existing...)
+	changed := false
+	for _, tag := range tags {
+		found := false
+		for i := range merged {
+			if merged[i].Key == tag.key {
+				found = true
+				if merged[i].Value != tag.value {
+					merged[i].Value, changed = tag.value, true
+				}
+			}
+		}
+		if !found {
+			merged = append(merged, sql.EndpointTagPair{Key: tag.key, Value: tag.value})
+			changed = true
+		}
+	}
+	return merged, changed
+}
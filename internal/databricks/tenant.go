@@ -0,0 +1,101 @@
+package databricks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Tenant isolation modes: which part of the Unity Catalog namespace carries the tenant.
+const (
+	TenantIsolationSchema  = "schema"  // {catalog}.{schema}_{tenant}.{table}
+	TenantIsolationCatalog = "catalog" // {catalog}_{tenant}.{schema}.{table}
+)
+
+// Tenant IDs become part of catalog/schema identifiers, so only lowercase identifier characters are allowed.
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// Checks that tenant can be embedded in Unity Catalog identifiers and storage paths.
+func ValidateTenant(tenant string) error {
+	if !tenantIDPattern.MatchString(tenant) {
+		return fmt.Errorf("invalid tenant %q: use 1-32 lowercase letters, digits or underscores", tenant)
+	}
+	return nil
+}
+
+// Returns a copy of the client whose catalog, schema and storage are scoped to tenant.
+//   - isolation: "schema" (default) suffixes the schema, "catalog" suffixes the catalog
+//   - EXTERNAL table locations get a per-tenant subdirectory
+//   - Every ingested row is tagged with the tenant in its metadata map
+//   - Schemas of classification routes are suffixed like the default schema
+//
+// The copy shares the underlying workspace connection, so scoping is cheap per request.
+func (c *Client) ForTenant(tenant, isolation string) (*Client, error) {
+	if err := ValidateTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	scoped := *c
+	scoped.tenant = tenant
+	switch strings.ToLower(isolation) {
+	case "", TenantIsolationSchema:
+		scoped.schema = c.schema + "_" + tenant
+		scoped.tenantIsolation = TenantIsolationSchema
+	case TenantIsolationCatalog:
+		scoped.catalog = c.catalog + "_" + tenant
+		scoped.tenantIsolation = TenantIsolationCatalog
+	default:
+		return nil, fmt.Errorf("unsupported tenant isolation %q (supported: %s, %s)", isolation, TenantIsolationSchema, TenantIsolationCatalog)
+	}
+	if c.externalLocation != "" {
+		scoped.externalLocation = strings.TrimRight(c.externalLocation, "/") + "/" + tenant
+	}
+
+	// - Classification routes to other schemas stay inside the tenant's namespace
+	if scoped.schema != c.schema && len(c.classificationRoutes) > 0 {
+		scoped.classificationRoutes = make([]ClassificationRoute, len(c.classificationRoutes))
+		for i, route := range c.classificationRoutes {
+			if route.Schema != "" {
+				route.Schema += "_" + tenant
+			}
+			scoped.classificationRoutes[i] = route
+		}
+	}
+	return &scoped, nil
+}
+
+// Returns a copy of the client that reads and writes catalog and schema instead of its
+// own; an empty one keeps the client's.
+//   - Per-data-type overrides (the mapping's catalog/schema) resolve through it at ingestion time
+//   - A tenant-scoped client suffixes an overridden catalog or schema like ForTenant does
+//     its own, so an override never leaves the tenant's namespace
+//   - Classification routes to other schemas use the overridden catalog
+func (c *Client) InNamespace(catalog, schema string) *Client {
+	if catalog == "" && schema == "" {
+		return c
+	}
+	scoped := *c
+	if catalog != "" {
+		scoped.catalog = catalog
+		if c.tenant != "" && c.tenantIsolation == TenantIsolationCatalog {
+			scoped.catalog += "_" + c.tenant
+		}
+	}
+	if schema != "" {
+		scoped.schema = schema
+		if c.tenant != "" && c.tenantIsolation == TenantIsolationSchema {
+			scoped.schema += "_" + c.tenant
+		}
+	}
+	return &scoped
+}
+
+// Reports the catalog and schema this client reads and writes.
+func (c *Client) Namespace() (catalog, schema string) {
+	return c.catalog, c.schema
+}
+
+// Returns the tenant the client is scoped to (empty when unscoped).
+func (c *Client) Tenant() string {
+	return c.tenant
+}
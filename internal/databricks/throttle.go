@@ -0,0 +1,216 @@
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/apierr"
+)
+
+//   Purpose: Workspace rate limiting (HTTP 429) used to be retried by the SDK with its
+//   generic backoff, ignoring how long the workspace asked to wait, and the time lost
+//   was invisible. The throttle transport waits exactly as long as the workspace asks
+//   and every ingestion reports the time it spent throttled.
+
+// Retries of one API call the transport handles itself before leaving it to the SDK.
+const defaultThrottleRetries = 5
+
+// Wait between throttled attempts when the workspace doesn't say how long (doubled per attempt).
+const throttleBackoff = time.Second
+
+// Throttling seen by the API calls of one ingestion.
+type throttleMeter struct {
+	mu        sync.Mutex
+	waited    time.Duration
+	responses int
+}
+
+type throttleMeterKey struct{}
+
+// Returns ctx carrying a new meter for the API calls made with it.
+func withThrottleMeter(ctx context.Context) (context.Context, *throttleMeter) {
+	meter := &throttleMeter{}
+	return context.WithValue(ctx, throttleMeterKey{}, meter), meter
+}
+
+func throttleMeterFrom(ctx context.Context) *throttleMeter {
+	meter, _ := ctx.Value(throttleMeterKey{}).(*throttleMeter)
+	return meter
+}
+
+func (m *throttleMeter) add(waited time.Duration, responses int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.waited += waited
+	m.responses += responses
+}
+
+// Copies the meter onto the result, with a warning when the run was throttled.
+func (m *throttleMeter) report(ctx context.Context, result *IngestionResult) {
+	m.mu.Lock()
+	waited, responses := m.waited, m.responses
+	m.mu.Unlock()
+	result.ThrottleTime, result.ThrottledRequests = waited, responses
+	if responses > 0 {
+		result.warn(ctx, WarnThrottled, "the workspace rate-limited %d API call(s); %s spent waiting", responses, waited.Round(time.Millisecond))
+	}
+}
+
+// Retries rate-limited API calls after the delay the workspace asks for.
+//   - 429s, and 503s that carry Retry-After, are throttled responses
+//   - Delay: Retry-After (seconds or HTTP date), else a RetryInfo error detail, else backoff
+//   - The delay also holds back every other call of the process (the limit is per workspace)
+//   - Waits longer than maxWait (kept below BLADE_HTTP_TIMEOUT, which bounds one call) and
+//     calls whose body can't be resent are handed back to the SDK's own retries
+type throttleTransport struct {
+	next    http.RoundTripper
+	retries int
+	maxWait time.Duration
+
+	mu        sync.Mutex
+	notBefore time.Time
+}
+
+func newThrottleTransport(next http.RoundTripper, retries int, httpTimeout time.Duration) *throttleTransport {
+	if retries <= 0 {
+		retries = defaultThrottleRetries
+	}
+	maxWait := httpTimeout / 2
+	if maxWait <= 0 {
+		maxWait = 30 * time.Second
+	}
+	return &throttleTransport{next: next, retries: retries, maxWait: maxWait}
+}
+
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	meter := throttleMeterFrom(req.Context())
+	for attempt := 0; ; attempt++ {
+		if err := t.waitTurn(req.Context(), meter); err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || !isThrottled(resp) {
+			return resp, err
+		}
+
+		delay, known := retryDelay(resp)
+		if !known {
+			delay = throttleBackoff << attempt
+		}
+		meter.add(0, 1)
+		t.holdUntil(time.Now().Add(delay))
+		if attempt >= t.retries || delay > t.maxWait || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		req = retry
+	}
+}
+
+// Blocks until the workspace's requested delay has passed (at most maxWait), counting the wait.
+func (t *throttleTransport) waitTurn(ctx context.Context, meter *throttleMeter) error {
+	t.mu.Lock()
+	wait := time.Until(t.notBefore)
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	if wait > t.maxWait {
+		wait = t.maxWait
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	started := time.Now()
+	select {
+	case <-ctx.Done():
+		meter.add(time.Since(started), 0)
+		return ctx.Err()
+	case <-timer.C:
+		meter.add(time.Since(started), 0)
+		return nil
+	}
+}
+
+func (t *throttleTransport) holdUntil(until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.notBefore) {
+		t.notBefore = until
+	}
+}
+
+func isThrottled(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != "")
+}
+
+// Returns the delay a throttled response asks for; the body is left readable.
+func retryDelay(resp *http.Response) (time.Duration, bool) {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(time.Until(at), 0), true
+		}
+	}
+	if resp.Body == nil {
+		return 0, false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+	// - Databricks error details: {"details": [{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retry_delay": "2s"}]}
+	var apiError struct {
+		Details []struct {
+			Type       string `json:"@type"`
+			RetryDelay string `json:"retry_delay"`
+		} `json:"details"`
+	}
+	if json.Unmarshal(body, &apiError) != nil {
+		return 0, false
+	}
+	for _, detail := range apiError.Details {
+		if detail.Type != "type.googleapis.com/google.rpc.RetryInfo" {
+			continue
+		}
+		if delay, err := time.ParseDuration(detail.RetryDelay); err == nil && delay >= 0 {
+			return delay, true
+		}
+	}
+	return 0, false
+}
+
+// Names workspace rate limiting in an error the SDK gave up retrying, with the delay the workspace asked for.
+func explainThrottling(err error) error {
+	var apiErr *apierr.APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsTooManyRequests() {
+		return err
+	}
+	if info := apiErr.ErrorDetails().RetryInfo; info != nil {
+		return fmt.Errorf("workspace rate limit exceeded (retry after %s): %w", info.RetryDelay, err)
+	}
+	return fmt.Errorf("workspace rate limit exceeded: %w", err)
+}
@@ -0,0 +1,254 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Every INSERT, COPY INTO, dedup or archive DELETE of a load commits a new
+//   Delta table version. Auditing a load means reading those versions back: the table's
+//   history (who committed what, and how many rows it wrote or deleted), which batches
+//   each version added or removed, and the table as it was before or after a version.
+
+//   Notes:
+//   - Versions older than the VACUUM retention (BLADE_VACUUM_RETENTION) can still be
+//     listed by the history, but their files are gone, so they can't be queried
+//   - Timestamps are UTC; TIMESTAMP AS OF picks the last version committed at or before it
+
+// A point of a table's history: a version, or the version current at a timestamp.
+//   - Timestamp: When set, wins over Version
+type TimeTravel struct {
+	Version   int64     `json:"version"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// Parses a version ("12" or "v12") or a timestamp (RFC 3339, "YYYY-MM-DD HH:MM:SS" or
+// "YYYY-MM-DD", UTC).
+func ParseTimeTravel(spec string) (TimeTravel, error) {
+	spec = strings.TrimSpace(spec)
+	if version, err := strconv.ParseInt(strings.TrimPrefix(spec, "v"), 10, 64); err == nil && version >= 0 {
+		return TimeTravel{Version: version}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if timestamp, err := time.Parse(layout, spec); err == nil {
+			return TimeTravel{Timestamp: timestamp.UTC()}, nil
+		}
+	}
+	return TimeTravel{}, fmt.Errorf("invalid point in time %q: use a version (12) or a timestamp (2024-06-01T10:00:00Z, 2024-06-01)", spec)
+}
+
+// Returns the time travel clause appended to the table name.
+func (t TimeTravel) clause() string {
+	if !t.Timestamp.IsZero() {
+		return fmt.Sprintf("TIMESTAMP AS OF '%s'", t.Timestamp.UTC().Format("2006-01-02 15:04:05.000"))
+	}
+	return fmt.Sprintf("VERSION AS OF %d", t.Version)
+}
+
+func (t TimeTravel) String() string {
+	if !t.Timestamp.IsZero() {
+		return t.Timestamp.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("v%d", t.Version)
+}
+
+// One version of a table's history (DESCRIBE HISTORY).
+//   - Parameters/Metrics: The operation's parameters and metrics (e.g. numOutputRows)
+//   - BatchesAdded/BatchesRemoved: Batches (metadata['batch_id']) the version added or
+//     removed; only filled in by TableHistory with batches
+type TableVersion struct {
+	Version        int64             `json:"version"`
+	Timestamp      string            `json:"timestamp"`
+	User           string            `json:"user,omitempty"`
+	Operation      string            `json:"operation"`
+	Parameters     map[string]string `json:"parameters,omitempty"`
+	Metrics        map[string]string `json:"metrics,omitempty"`
+	BatchesAdded   []string          `json:"batchesAdded,omitempty"`
+	BatchesRemoved []string          `json:"batchesRemoved,omitempty"`
+}
+
+// Operations that change a table's rows; the others (OPTIMIZE, VACUUM, ALTER, ...) only
+// rewrite files or change the schema and properties.
+var dataOperations = map[string]bool{
+	"WRITE": true, "COPY INTO": true, "DELETE": true, "MERGE": true, "UPDATE": true,
+	"TRUNCATE": true, "RESTORE": true, "CREATE TABLE AS SELECT": true, "REPLACE TABLE AS SELECT": true,
+}
+
+// Reports whether the version changed the table's rows.
+func (v TableVersion) ChangesData() bool {
+	return dataOperations[strings.ToUpper(v.Operation)]
+}
+
+// Returns the rows the version inserted, from its metrics.
+func (v TableVersion) RowsWritten() int64 {
+	return v.metric("numOutputRows", "numTargetRowsInserted")
+}
+
+// Returns the rows the version deleted, from its metrics.
+func (v TableVersion) RowsDeleted() int64 {
+	return v.metric("numDeletedRows", "numTargetRowsDeleted")
+}
+
+// Returns the first of the metrics the version has (0 for none).
+func (v TableVersion) metric(names ...string) int64 {
+	for _, name := range names {
+		if value, ok := v.Metrics[name]; ok {
+			n, _ := strconv.ParseInt(value, 10, 64)
+			return n
+		}
+	}
+	return 0
+}
+
+// Lists the latest limit versions of a table, newest first (limit 0 = all of them).
+//   - table: "table", "schema.table" or "catalog.schema.table"
+//   - batches: Also work out the batches each version that changed rows added or removed
+//     (reads every such version and the one before it, so keep limit small on large tables)
+func (c *Client) TableHistory(ctx context.Context, table string, limit int, batches bool) ([]TableVersion, error) {
+	if err := checkTableReference(table); err != nil {
+		return nil, err
+	}
+	fullName := c.qualifiedTable(table)
+	statement := "DESCRIBE HISTORY " + fullName
+	if limit > 0 {
+		statement += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	resp, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{Statement: statement})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the history of %s: %w", fullName, err)
+	}
+
+	// - Columns are looked up by name; the history has grown columns over Delta releases
+	columns := map[string]int{}
+	if resp.Manifest != nil && resp.Manifest.Schema != nil {
+		for i, column := range resp.Manifest.Schema.Columns {
+			columns[column.Name] = i
+		}
+	}
+	var rows [][]string
+	if resp.Result != nil {
+		rows = resp.Result.DataArray
+	}
+	value := func(row []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	versions := make([]TableVersion, 0, len(rows))
+	for _, row := range rows {
+		version, err := strconv.ParseInt(value(row, "version"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the history of %s: version %q", fullName, value(row, "version"))
+		}
+		entry := TableVersion{
+			Version:   version,
+			Timestamp: value(row, "timestamp"),
+			User:      value(row, "userName"),
+			Operation: value(row, "operation"),
+		}
+		// - Map columns arrive as JSON objects
+		_ = json.Unmarshal([]byte(value(row, "operationParameters")), &entry.Parameters)
+		_ = json.Unmarshal([]byte(value(row, "operationMetrics")), &entry.Metrics)
+		versions = append(versions, entry)
+	}
+
+	if batches {
+		if err := c.historyBatches(ctx, fullName, versions); err != nil {
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
+// Fills in the batches each version that changed rows added and removed, comparing the
+// batch IDs it holds with those of the version before it, in a single statement.
+func (c *Client) historyBatches(ctx context.Context, fullName string, versions []TableVersion) error {
+	read := map[int64]bool{}
+	for _, version := range versions {
+		if version.ChangesData() {
+			read[version.Version] = true
+			if version.Version > 0 {
+				read[version.Version-1] = true
+			}
+		}
+	}
+	if len(read) == 0 {
+		return nil
+	}
+	snapshots := make([]int64, 0, len(read))
+	for version := range read {
+		snapshots = append(snapshots, version)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i] < snapshots[j] })
+	selects := make([]string, len(snapshots))
+	for i, version := range snapshots {
+		selects[i] = fmt.Sprintf("SELECT DISTINCT %d AS version, metadata['batch_id'] AS batch_id FROM %s VERSION AS OF %d WHERE metadata['batch_id'] IS NOT NULL", version, fullName, version)
+	}
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{Statement: strings.Join(selects, "\nUNION ALL\n")})
+	if err != nil {
+		return fmt.Errorf("failed to read the batches of %s's versions (versions older than the VACUUM retention can't be read; lower the limit): %w", fullName, err)
+	}
+
+	held := map[int64]map[string]bool{}
+	for _, version := range snapshots {
+		held[version] = map[string]bool{}
+	}
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		if version, err := strconv.ParseInt(row[0], 10, 64); err == nil && held[version] != nil {
+			held[version][row[1]] = true
+		}
+	}
+	for i := range versions {
+		version := &versions[i]
+		if !version.ChangesData() {
+			continue
+		}
+		before := held[version.Version-1] // nil for version 0
+		for batch := range held[version.Version] {
+			if !before[batch] {
+				version.BatchesAdded = append(version.BatchesAdded, batch)
+			}
+		}
+		for batch := range before {
+			if !held[version.Version][batch] {
+				version.BatchesRemoved = append(version.BatchesRemoved, batch)
+			}
+		}
+		sort.Strings(version.BatchesAdded)
+		sort.Strings(version.BatchesRemoved)
+	}
+	return nil
+}
+
+// Returns up to limit rows of a table as it was at asOf (0 = the API's inline limit).
+//   - batchID: Only the rows of this batch (metadata['batch_id']); empty for all rows
+//   - raw_data is decoded like Query does
+func (c *Client) QueryAsOf(ctx context.Context, table string, asOf TimeTravel, batchID string, limit int) (*QueryResult, error) {
+	if err := checkTableReference(table); err != nil {
+		return nil, err
+	}
+	fullName := c.qualifiedTable(table)
+	param := stringParam("batch_id", batchID)
+	param.ForceSendFields = []string{"Value"}
+	result, err := c.readQuery(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf("SELECT * FROM %s %s WHERE (:batch_id = '' OR metadata['batch_id'] = :batch_id)",
+			fullName, asOf.clause()),
+		Parameters: []sql.StatementParameterListItem{param},
+		RowLimit:   int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s as of %s: %w", fullName, asOf, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,42 @@
+package databricks
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Builds the pooled HTTP transport shared by every request the client (and its tenant copies) makes.
+//
+// The Statement Execution API is sessionless: each statement names its warehouse,
+// catalog and schema, so there is no server-side session to reuse. What each run's
+// many small DDL/verification statements can reuse is the TLS connection, which the
+// default transport keeps only two of per host.
+func newHTTPTransport(maxIdleConns int) *http.Transport {
+	if maxIdleConns <= 0 {
+		maxIdleConns = 16
+	}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConns,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// Converts BLADE_HTTP_TIMEOUT to the SDK's HTTPTimeoutSeconds, rounding up.
+//   - The SDK takes whole seconds and treats 0 as its 60s default, so truncating a
+//     sub-second timeout would silently lengthen it
+func httpTimeoutSeconds(timeout time.Duration) int {
+	if timeout <= 0 {
+		return 0
+	}
+	return int((timeout + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,125 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Some BLADE records only matter for a while (a sortie schedule is stale a
+//   month after it was flown). A TTL policy stamps each row's expiry into
+//   metadata['expires_at'] and keeps a view over the rows that haven't expired, which
+//   is what "current operational picture" queries read. Expired rows are never deleted.
+
+// Row-level time-to-live derived from a record field.
+//   - Field: Record field the expiry counts from (RFC 3339 timestamp or YYYY-MM-DD date)
+//   - After: Time until expiry, a Go duration ("720h") or whole days ("30d")
+//   - View: View over the unexpired rows (default {table}_current)
+type TTLPolicy struct {
+	Field string `json:"field"`
+	After string `json:"after"`
+	View  string `json:"view,omitempty"`
+}
+
+// Layout of metadata['expires_at'], the form Databricks casts to and from TIMESTAMP (UTC).
+const expiresAtLayout = "2006-01-02 15:04:05"
+
+// Parses After; days are accepted on top of time.ParseDuration units.
+func (p TTLPolicy) Duration() (time.Duration, error) {
+	ttl, err := parseDays(p.After)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid TTL %q: use a positive duration such as 720h or 30d", p.After)
+	}
+	return ttl, nil
+}
+
+// Parses a Go duration ("720h") or whole days ("30d").
+func parseDays(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(strings.TrimSpace(value), "d"); found {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(strings.TrimSpace(value))
+}
+
+// Parses a record's time field: an RFC 3339 timestamp or a YYYY-MM-DD date.
+func parseRecordTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// Returns the name of the view over unexpired rows of table.
+func (p TTLPolicy) viewName(table string) string {
+	if p.View != "" {
+		return p.View
+	}
+	return table + "_current"
+}
+
+// Checks the policy of the table it is declared on.
+func (p TTLPolicy) validate(table string) error {
+	if !tableNamePattern.MatchString(p.Field) {
+		return fmt.Errorf("TTL field %q of table %s must be a plain field name", p.Field, table)
+	}
+	if _, err := p.Duration(); err != nil {
+		return fmt.Errorf("table %s: %w", table, err)
+	}
+	if view := p.viewName(table); !tableNamePattern.MatchString(view) || view == table {
+		return fmt.Errorf("invalid TTL view name %q for table %s", view, table)
+	}
+	return nil
+}
+
+// Returns the record's expiry in expiresAtLayout, or "" when its TTL field is missing or unparseable.
+func (p TTLPolicy) expiresAt(record map[string]interface{}) string {
+	value := recordText(record, p.Field)
+	if value == "" {
+		return ""
+	}
+	start, err := parseRecordTime(value)
+	if err != nil {
+		return ""
+	}
+	ttl, err := p.Duration()
+	if err != nil {
+		return ""
+	}
+	return start.Add(ttl).UTC().Format(expiresAtLayout)
+}
+
+// Returns the SQL expression computing expires_at server-side (COPY INTO loads).
+func (p TTLPolicy) expiresAtSQL() string {
+	ttl, _ := p.Duration()
+	return fmt.Sprintf("CAST(timestampadd(SECOND, %d, CAST(%s AS TIMESTAMP)) AS STRING)", int64(ttl.Seconds()), p.Field)
+}
+
+// Creates or replaces the view over the table's unexpired rows.
+//   - raw_data is decoded, so the view shows JSON whatever the raw_data codec
+//   - Rows without expires_at (loaded before the policy, or without the TTL field) count as current
+func (c *Client) ensureCurrentView(ctx context.Context, req *IngestionRequest) error {
+	view := req.TTL.viewName(req.TableName)
+	viewSQL := fmt.Sprintf(`
+		CREATE OR REPLACE VIEW %s.%s.%s AS
+		SELECT * FROM %s
+		WHERE metadata['expires_at'] IS NULL
+			OR CAST(metadata['expires_at'] AS TIMESTAMP) > current_timestamp()
+	`, c.catalog, c.schema, view, c.decodedSource(fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName)))
+	runlog.Printf(ctx, "Creating view %s.%s.%s over unexpired rows of %s", c.catalog, c.schema, view, req.TableName)
+	_, err := c.executeStatement(ctx, sql.ExecuteStatementRequest{
+		Statement:   viewSQL,
+		WarehouseId: c.warehouseID,
+		Catalog:     c.catalog,
+		Schema:      c.schema,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create view %s: %w", view, err)
+	}
+	return nil
+}
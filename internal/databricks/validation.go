@@ -0,0 +1,108 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// Rules applied to every BLADE table unless a mapping overrides them.
+func DefaultValidationRules() []ValidationRule {
+	return []ValidationRule{
+		{
+			Name:      "no_null_item_id",
+			Condition: "item_id IS NULL OR trim(item_id) = ''",
+			Severity:  SeverityError,
+		},
+		{
+			Name:      "timestamp_within_3_years",
+			Condition: "timestamp IS NULL OR timestamp < current_timestamp() - INTERVAL 3 YEARS OR timestamp > current_timestamp() + INTERVAL 1 DAY",
+			Severity:  SeverityWarn,
+		},
+	}
+}
+
+func (c *Client) runValidations(ctx context.Context, req *IngestionRequest, batchID string) []ValidationResult {
+	fullTableName := fmt.Sprintf("%s.%s.%s", c.catalog, c.schema, req.TableName)
+	results := make([]ValidationResult, 0, len(req.Validations))
+
+	for _, rule := range req.Validations {
+		severity := strings.ToLower(rule.Severity)
+		if severity == "" {
+			severity = SeverityError
+		}
+		result := ValidationResult{Name: rule.Name, Severity: severity}
+
+		// Query Construction:
+		// - Condition rules count violating rows in this batch only; they see raw_data as
+		//   JSON whatever the raw_data codec
+		// - Custom SQL rules are used as written with {table} expanded
+		var validationSQL string
+		switch {
+		case rule.SQL != "":
+			validationSQL = strings.ReplaceAll(rule.SQL, "{table}", fullTableName)
+		case rule.Condition != "":
+			validationSQL = fmt.Sprintf(
+				"SELECT COUNT(*) FROM %s WHERE metadata['batch_id'] = :batch_id AND (%s)",
+				c.decodedSource(fullTableName), rule.Condition)
+		default:
+			result.Error = "rule has neither condition nor sql"
+			results = append(results, result)
+			continue
+		}
+
+		var params []sql.StatementParameterListItem
+		if strings.Contains(validationSQL, ":batch_id") {
+			params = append(params, stringParam("batch_id", batchID))
+		}
+
+		rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+			Statement:  validationSQL,
+			Catalog:    c.catalog,
+			Schema:     c.schema,
+			Parameters: params,
+		})
+
+		// Outcome:
+		// - Query errors count as failures so a broken rule can't silently pass
+		// - Violations > 0 fails the rule
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case len(rows) == 0 || len(rows[0]) == 0:
+			result.Error = "validation query returned no rows"
+		default:
+			count, parseErr := strconv.ParseInt(rows[0][0], 10, 64)
+			if parseErr != nil {
+				result.Error = fmt.Sprintf("validation query returned non-numeric value %q", rows[0][0])
+			} else {
+				result.Violations = count
+				result.Passed = count == 0
+			}
+		}
+
+		if result.Passed {
+			runlog.Printf(ctx, "Validation %s passed", rule.Name)
+		} else {
+			runlog.Printf(ctx, "Validation %s (%s) failed: %d violation(s) %s", rule.Name, severity, result.Violations, result.Error)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Names of error-severity rules that did not pass.
+func failedValidations(results []ValidationResult) []string {
+	var failed []string
+	for _, result := range results {
+		if !result.Passed && result.Severity == SeverityError {
+			failed = append(failed, result.Name)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,151 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+// A single field whose stored value differs from the source record.
+type FieldMismatch struct {
+	ItemID string `json:"itemId"`
+	Field  string `json:"field"`
+	Source string `json:"source"`
+	Stored string `json:"stored"`
+}
+
+// Contains the outcome of reading back a random sample of the ingested batch.
+type SampleVerification struct {
+	Sampled    int             `json:"sampled"`
+	Missing    []string        `json:"missing,omitempty"` // sampled item IDs not found in the batch
+	Mismatches []FieldMismatch `json:"mismatches,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// Reports whether every sampled record was read back unchanged.
+func (v *SampleVerification) Passed() bool {
+	return v.Error == "" && len(v.Missing) == 0 && len(v.Mismatches) == 0
+}
+
+// Reads back up to c.verifySampleSize random records of the batch and compares them field by field with the source.
+func (c *Client) verifySample(ctx context.Context, req *IngestionRequest, batchID string) *SampleVerification {
+	verification := &SampleVerification{}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(req.SampleData), &records); err != nil {
+		verification.Error = fmt.Sprintf("failed to parse sample data: %v", err)
+		return verification
+	}
+
+	// Sample Selection:
+	// - Records are shuffled and the first N with a unique item_id are taken
+	// - item_id is the only key the read-back can match rows on
+	bySource := make(map[string]map[string]interface{})
+	for _, i := range rand.Perm(len(records)) {
+		if len(bySource) == c.verifySampleSize {
+			break
+		}
+		id, ok := records[i]["item_id"].(string)
+		if !ok || id == "" {
+			continue
+		}
+		bySource[id] = records[i]
+	}
+	if len(bySource) == 0 {
+		return verification
+	}
+	verification.Sampled = len(bySource)
+
+	params := []sql.StatementParameterListItem{stringParam("batch_id", batchID)}
+	var markers []string
+	for id := range bySource {
+		name := fmt.Sprintf("id%d", len(markers))
+		markers = append(markers, ":"+name)
+		params = append(params, stringParam(name, id))
+	}
+
+	// Read-Back Query:
+	// - Scoped to this batch so older loads of the same items don't match
+	// - The timestamp is read as epoch seconds to avoid session time zone formatting
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			SELECT item_id, item_type, classification_marking, CAST(unix_timestamp(timestamp) AS STRING), data_source, %s
+			FROM %s.%s.%s
+			WHERE metadata['batch_id'] = :batch_id AND item_id IN (%s)
+		`, c.rawDataCodec.DecodeSQL("raw_data"), c.catalog, c.schema, req.TableName, strings.Join(markers, ", ")),
+		Parameters: params,
+	})
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+
+	found := make(map[string]bool)
+	for _, row := range rows {
+		if len(row) < 6 || found[row[0]] {
+			continue
+		}
+		source, ok := bySource[row[0]]
+		if !ok {
+			continue
+		}
+		found[row[0]] = true
+		verification.Mismatches = append(verification.Mismatches, compareRecord(row[0], source, req.DataSource, row)...)
+	}
+	for id := range bySource {
+		if !found[id] {
+			verification.Missing = append(verification.Missing, id)
+		}
+	}
+
+	runlog.Printf(ctx, "Verified %d sampled record(s) in %s: %d missing, %d field mismatch(es)",
+		verification.Sampled, req.TableName, len(verification.Missing), len(verification.Mismatches))
+	return verification
+}
+
+// Compares one stored row (item_id, item_type, classification_marking, epoch timestamp, data_source, raw_data) with its source record.
+func compareRecord(itemID string, source map[string]interface{}, dataSource string, row []string) []FieldMismatch {
+	var mismatches []FieldMismatch
+	mismatch := func(field, want, got string) {
+		if want != got {
+			mismatches = append(mismatches, FieldMismatch{ItemID: itemID, Field: field, Source: want, Stored: got})
+		}
+	}
+
+	mismatch("item_type", fmt.Sprint(source["item_type"]), row[1])
+	mismatch("classification_marking", fmt.Sprint(source["classification_marking"]), row[2])
+	mismatch("data_source", dataSource, row[4])
+
+	sourceTime := fmt.Sprint(source["timestamp"])
+	if parsed, err := time.Parse(time.RFC3339, sourceTime); err == nil {
+		mismatch("timestamp", strconv.FormatInt(parsed.Unix(), 10), row[3])
+	} else {
+		mismatch("timestamp", sourceTime, row[3])
+	}
+
+	// raw_data must round-trip to the same JSON document (key order is irrelevant)
+	var stored map[string]interface{}
+	if err := json.Unmarshal([]byte(row[5]), &stored); err != nil {
+		mismatch("raw_data", "valid JSON", row[5])
+		return mismatches
+	}
+	for key, want := range source {
+		if got, ok := stored[key]; !ok || !reflect.DeepEqual(want, got) {
+			mismatch("raw_data."+key, fmt.Sprint(want), fmt.Sprint(got))
+		}
+	}
+	for key, got := range stored {
+		if _, ok := source[key]; !ok {
+			mismatch("raw_data."+key, "<absent>", fmt.Sprint(got))
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,165 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: The warehouse ID is an opaque 16-character string people copy from the
+//   Connection details tab; the warehouse's name is what they know it by. With
+//   DATABRICKS_WAREHOUSE_NAME the client lists the workspace's warehouses, picks the one
+//   of that name and uses its ID; list-warehouses shows the candidates.
+
+//   Matching:
+//   - Exact name first, then case-insensitive; two warehouses matching equally is an
+//     error listing their IDs (set DATABRICKS_WAREHOUSE_ID to pick one)
+//   - DATABRICKS_WAREHOUSE_ID, when set, wins and the name isn't looked up
+
+//   Cache:
+//   - Resolved IDs are kept in a JSON file (warehouses.json in BLADE_STATE_DIR) by host and
+//     name, so later runs don't list the warehouses again
+//   - A cached ID is checked with one GetById: a warehouse that was deleted or renamed
+//     is looked up again
+
+// A SQL warehouse of the workspace, as list-warehouses shows it.
+type Warehouse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	Size       string `json:"size"`
+	Type       string `json:"type"`
+	Serverless bool   `json:"serverless"`
+	Creator    string `json:"creator,omitempty"`
+}
+
+// Returns the workspace's SQL warehouses the principal can see, by name.
+func (c *Client) ListWarehouses(ctx context.Context) ([]Warehouse, error) {
+	infos, err := c.workspace.Warehouses.ListAll(ctx, sql.ListWarehousesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouses: %w", err)
+	}
+	warehouses := make([]Warehouse, len(infos))
+	for i, info := range infos {
+		warehouses[i] = Warehouse{
+			ID:         info.Id,
+			Name:       info.Name,
+			State:      string(info.State),
+			Size:       info.ClusterSize,
+			Type:       string(info.WarehouseType),
+			Serverless: info.EnableServerlessCompute,
+			Creator:    info.CreatorName,
+		}
+	}
+	sort.Slice(warehouses, func(i, j int) bool {
+		return strings.ToLower(warehouses[i].Name) < strings.ToLower(warehouses[j].Name)
+	})
+	return warehouses, nil
+}
+
+// Sets the client's warehouse to the one named name (see Matching), using and updating
+// the cache at cachePath ("" = no cache).
+func (c *Client) ResolveWarehouse(ctx context.Context, name, cachePath string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("no warehouse name to resolve")
+	}
+	key := strings.TrimRight(c.workspace.Config.Host, "/") + " " + name
+	cache := readWarehouseCache(cachePath)
+	if id := cache[key]; id != "" {
+		if warehouse, err := c.workspace.Warehouses.GetById(ctx, id); err == nil && warehouse.Name == name {
+			c.warehouseID = id
+			return nil
+		}
+		runlog.Printf(ctx, "Cached warehouse %s is no longer %q, looking it up again", id, name)
+	}
+
+	warehouses, err := c.ListWarehouses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve DATABRICKS_WAREHOUSE_NAME %q: %w", name, err)
+	}
+	match, err := matchWarehouse(warehouses, name)
+	if err != nil {
+		return err
+	}
+	c.warehouseID = match.ID
+	runlog.Printf(ctx, "Resolved warehouse %q to %s", name, match.ID)
+
+	if cachePath != "" {
+		cache[key] = match.ID
+		if err := writeWarehouseCache(cachePath, cache); err != nil {
+			runlog.Printf(ctx, "Could not cache the ID of warehouse %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// Returns the warehouse named name: the exact match, else the only case-insensitive one.
+func matchWarehouse(warehouses []Warehouse, name string) (Warehouse, error) {
+	var exact, folded []Warehouse
+	for _, warehouse := range warehouses {
+		switch {
+		case warehouse.Name == name:
+			exact = append(exact, warehouse)
+		case strings.EqualFold(warehouse.Name, name):
+			folded = append(folded, warehouse)
+		}
+	}
+	candidates := exact
+	if len(candidates) == 0 {
+		candidates = folded
+	}
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 0:
+		names := make([]string, len(warehouses))
+		for i, warehouse := range warehouses {
+			names[i] = fmt.Sprintf("%q", warehouse.Name)
+		}
+		if len(names) == 0 {
+			return Warehouse{}, fmt.Errorf("no warehouse named %q: the principal can't see any warehouse (it needs CAN USE on one)", name)
+		}
+		return Warehouse{}, fmt.Errorf("no warehouse named %q (DATABRICKS_WAREHOUSE_NAME); visible warehouses: %s", name, strings.Join(names, ", "))
+	default:
+		ids := make([]string, len(candidates))
+		for i, warehouse := range candidates {
+			ids[i] = warehouse.ID
+		}
+		return Warehouse{}, fmt.Errorf("%d warehouses are named %q (%s): set DATABRICKS_WAREHOUSE_ID to the one to use", len(candidates), name, strings.Join(ids, ", "))
+	}
+}
+
+// Reads the resolved warehouse IDs; a missing or unreadable cache is empty.
+func readWarehouseCache(path string) map[string]string {
+	cache := map[string]string{}
+	if path == "" {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]string{}
+	}
+	return cache
+}
+
+func writeWarehouseCache(path string, cache map[string]string) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,88 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"databricks-blade-poc/internal/runlog"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+)
+
+//   Purpose: Non-fatal conditions of a load (fields dropped from ragged CSV rows, values
+//   that didn't convert to a typed column, a table holding fewer rows than were just
+//   inserted, ...) used to exist only as log lines. They are also collected on the
+//   result, so automation can act on them without parsing logs.
+
+// Warning codes, stable for automation to match on.
+const (
+	WarnSkippedFields = "skipped_fields" // source values dropped while preparing the records
+	WarnCSVType       = "csv_type"       // CSV values that didn't convert to their column's hinted type (kept as text)
+	WarnCoercion      = "coercion"       // typed column values that didn't convert and were stored as NULL
+	WarnRowCount      = "row_count"      // table row count unavailable or below the rows just inserted
+	WarnVerification  = "verification"   // sampled records missing or changed when read back
+	WarnValidation    = "validation"     // failed "warn" severity validation rules
+	WarnArchive       = "archive"        // superseded batches could not be archived
+	WarnThrottled     = "throttled"      // API calls were rate-limited by the workspace
+	WarnRejected      = "rejected"       // records that broke the table's rules were skipped or quarantined
+	WarnManifest      = "manifest"       // the batch's outcome could not be recorded in the batch manifest
+)
+
+// A non-fatal condition of a load.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Logs a warning and adds it to the result.
+func (r *IngestionResult) warn(ctx context.Context, code string, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	runlog.Printf(ctx, "Warning (%s): %s", code, message)
+	r.Warnings = append(r.Warnings, Warning{Code: code, Message: message})
+}
+
+// Counts, per typed column, the batch's rows whose record had a value that didn't convert (try_cast NULL).
+//   - raw_data keeps every record, so a NULL column with a non-null source field is a failed conversion
+//   - Failing to count is itself only worth a warning
+func (c *Client) checkCoercion(ctx context.Context, req *IngestionRequest, batchID string, result *IngestionResult) {
+	if len(req.Columns) == 0 {
+		return
+	}
+	var params paramList
+	counts := make([]string, len(req.Columns))
+	for i, col := range req.Columns {
+		counts[i] = fmt.Sprintf("count_if(%s IS NULL AND get_json_object(%s, %s) IS NOT NULL)", col.Name, c.rawDataCodec.DecodeSQL("raw_data"), params.text("$."+col.field()))
+	}
+	rows, err := c.queryRows(ctx, sql.ExecuteStatementRequest{
+		Statement: fmt.Sprintf(`
+			SELECT %s
+			FROM %s.%s.%s
+			WHERE metadata['batch_id'] = %s
+		`, strings.Join(counts, ", "), c.catalog, c.schema, req.TableName, params.text(batchID)),
+		Parameters: params.params,
+	})
+	if err != nil {
+		result.warn(ctx, WarnCoercion, "could not check typed column conversions: %v", err)
+		return
+	}
+	if len(rows) == 0 {
+		return
+	}
+	for i, col := range req.Columns {
+		if i >= len(rows[0]) {
+			break
+		}
+		if failed, _ := strconv.ParseInt(rows[0][i], 10, 64); failed > 0 {
+			result.warn(ctx, WarnCoercion, "%d value(s) of field %s didn't convert to %s and were stored as NULL in column %s", failed, col.field(), declaredType(col), col.Name)
+		}
+	}
+}
+
+// Returns ": err" for a non-empty error text, to end a warning message with.
+func errorSuffix(err string) string {
+	if err == "" {
+		return ""
+	}
+	return ": " + err
+}
@@ -0,0 +1,129 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/config"
+	"databricks-blade-poc/internal/databricks"
+)
+
+//   Purpose: Supplies the records the ingestion engine loads. BLADE is one provider;
+//   other logistics feeds (e.g. ADVANA) plug in by registering their own, so commands
+//   and the engine never depend on a particular source.
+
+//   Contract:
+//   - Name: The provider identifier selected via BLADE_DATA_PROVIDER (e.g. "blade")
+//   - ListTypes: The data types the provider can load, in sorted order
+//   - FetchRecords: Loads one data type in the given format ("" = provider default) as a
+//     ready-to-ingest request; errors for unknown types or formats. Providers that fetch
+//     over the network stop when ctx is done. Feeds too large to
+//     hold in memory return a record_stream request whose Records the client pulls
+//     chunk by chunk (databricks.RecordSource) instead of filling SampleData
+//   - DescribeSchema: The tables and semantics a data type is loaded into
+type Provider interface {
+	Name() string
+	ListTypes() []string
+	FetchRecords(ctx context.Context, dataType string, format string) (*databricks.IngestionRequest, error)
+	DescribeSchema(dataType string) (Schema, error)
+}
+
+// Optional: providers that can also load real files with COPY INTO (ingest --source).
+type FileLoader interface {
+	FetchFiles(dataType string, format string, sourcePath string) (*databricks.IngestionRequest, error)
+}
+
+// Optional: providers that can insert the records of a single local JSON, CSV or NDJSON file (watch).
+type RecordFileLoader interface {
+	FetchRecordFile(dataType string, filePath string) (*databricks.IngestionRequest, error)
+}
+
+// Optional: providers that read CSV extracts and can report how their columns are typed (csv-types).
+type CSVDescriber interface {
+	DescribeCSV(dataType string, filePath string) ([]blademap.ColumnType, error)
+}
+
+// Optional: providers that can load Advana-exported Parquet snapshots (ingest --advana-snapshot).
+type SnapshotLoader interface {
+	FetchSnapshot(dataType string, snapshotDir string) (*databricks.IngestionRequest, error)
+}
+
+// Optional: providers that can synthesize realistic records of a data type.
+//   - GenerateMock: Writes them as the provider's data files (generate-mock); returns the files
+//   - MockRequest: Returns them as a ready-to-ingest request, without files (loadtest)
+type MockGenerator interface {
+	GenerateMock(dataType string, opts MockOptions) ([]string, error)
+	MockRequest(dataType string, opts MockOptions) (*databricks.IngestionRequest, error)
+}
+
+// What generate-mock writes.
+//   - Count: Records per data type
+//   - Seed: Seeds the generator; the same seed, start and count give the same files
+//   - Start: Timestamp of the first record; the others follow over the next 30 days
+//   - Formats: JSON, CSV and/or NDJSON
+//   - Dir: Data path the files go under ("" = the provider's own)
+//   - Overwrite: Replace existing files instead of failing
+type MockOptions struct {
+	Count     int
+	Seed      int64
+	Start     time.Time
+	Formats   []string
+	Dir       string
+	Overwrite bool
+}
+
+// Where and how a data type lands in Databricks.
+//   - Catalog/Schema: Namespace override for the data type's tables (empty: the configured one)
+//   - Tables: Every table a load writes, the main table first
+//   - Semantics: Column descriptions and example questions for Genie spaces
+type Schema struct {
+	DataType    string
+	TableName   string
+	Catalog     string
+	Schema      string
+	Description string
+	Tables      []string
+	Semantics   databricks.TableSemantics
+	Layout      *databricks.TableLayout // nil: neither partitioned nor clustered
+}
+
+// Builds a Provider from the application configuration.
+type Factory func(cfg *config.Config) (Provider, error)
+
+// Provider used when BLADE_DATA_PROVIDER is not set.
+const DefaultProvider = "blade"
+
+var factories = map[string]Factory{}
+
+// Makes a provider selectable via BLADE_DATA_PROVIDER. Later registrations replace earlier ones.
+func Register(name string, factory Factory) {
+	factories[strings.ToLower(name)] = factory
+}
+
+// Returns the registered provider names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolves the provider selected by cfg.DataProvider (default: "blade").
+func NewProvider(cfg *config.Config) (Provider, error) {
+	name := strings.ToLower(cfg.DataProvider)
+	if name == "" {
+		name = DefaultProvider
+	}
+
+	factory, exists := factories[name]
+	if !exists {
+		return nil, fmt.Errorf("unsupported BLADE_DATA_PROVIDER %q (supported: %s)", cfg.DataProvider, strings.Join(Names(), ", "))
+	}
+	return factory(cfg)
+}
@@ -0,0 +1,69 @@
+package datasource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//   Purpose: Switches individual data types off (e.g. deployment data embargoed for a
+//   quarter) without editing the mapping set. Disabled types are skipped with their
+//   reason by ingest --all and flagged in API listings; ingesting one directly fails.
+
+// Disabled data types and why, parsed from BLADE_DISABLED_DATA_TYPES.
+type Toggles map[string]string
+
+// Parses "dataType[=reason], ..." (e.g. "deployment=embargoed this quarter, sortie").
+func ParseToggles(spec string) (Toggles, error) {
+	toggles := Toggles{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		dataType, reason, _ := strings.Cut(entry, "=")
+		dataType, reason = strings.TrimSpace(dataType), strings.TrimSpace(reason)
+		if dataType == "" {
+			return nil, fmt.Errorf("invalid disabled data type %q: use dataType or dataType=reason", entry)
+		}
+		if _, seen := toggles[dataType]; seen {
+			return nil, fmt.Errorf("data type %s is disabled twice", dataType)
+		}
+		toggles[dataType] = reason
+	}
+	return toggles, nil
+}
+
+// Reports whether dataType is disabled and the reason given for it (may be empty).
+func (t Toggles) Disabled(dataType string) (string, bool) {
+	reason, disabled := t[dataType]
+	return reason, disabled
+}
+
+// Returns the error for an attempt to load a disabled data type, or nil if it is enabled.
+func (t Toggles) Check(dataType string) error {
+	reason, disabled := t.Disabled(dataType)
+	if !disabled {
+		return nil
+	}
+	if reason == "" {
+		return fmt.Errorf("data type %s is disabled (BLADE_DISABLED_DATA_TYPES)", dataType)
+	}
+	return fmt.Errorf("data type %s is disabled (BLADE_DISABLED_DATA_TYPES): %s", dataType, reason)
+}
+
+// Returns the disabled data types the provider doesn't know, usually a typo in the setting.
+func (t Toggles) Unknown(source Provider) []string {
+	known := make(map[string]bool)
+	for _, dataType := range source.ListTypes() {
+		known[dataType] = true
+	}
+	var unknown []string
+	for dataType := range t {
+		if !known[dataType] {
+			unknown = append(unknown, dataType)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,198 @@
+package dictionary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/databricks"
+)
+
+//   Purpose: Documents what an ingestion run created for the data governance team.
+//   A dictionary combines the table's columns (with their Unity Catalog comments),
+//   the mapping's semantic descriptions and the source fields inferred from the data.
+
+// A source record field with the JSON type inferred from the loaded records.
+type Field struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"` // string, timestamp, number, boolean, array, object or mixed
+	Example string `json:"example,omitempty"`
+}
+
+// A documented column of a target table.
+type Column struct {
+	Name        string   `json:"name"`
+	DataType    string   `json:"dataType,omitempty"`
+	Nullable    bool     `json:"nullable"`
+	Description string   `json:"description,omitempty"` // column comment, falling back to the mapping's semantics
+	Synonyms    []string `json:"synonyms,omitempty"`
+}
+
+// The data dictionary of one target table.
+type Table struct {
+	Name         string    `json:"name"` // catalog.schema.table
+	Description  string    `json:"description,omitempty"`
+	Columns      []Column  `json:"columns"`
+	SourceFields []Field   `json:"sourceFields,omitempty"` // fields of the raw_data JSON
+	RunID        string    `json:"runId,omitempty"`
+	GeneratedAt  time.Time `json:"generatedAt"`
+}
+
+// Infers the type of every field across the JSON records in sampleData, sorted by name.
+func InferFields(sampleData string) []Field {
+	var records []map[string]interface{}
+	if json.Unmarshal([]byte(sampleData), &records) != nil {
+		return nil
+	}
+
+	byName := make(map[string]*Field)
+	for _, record := range records {
+		for name, value := range record {
+			if value == nil {
+				if byName[name] == nil {
+					byName[name] = &Field{Name: name}
+				}
+				continue
+			}
+			kind := jsonType(value)
+			field := byName[name]
+			switch {
+			case field == nil:
+				byName[name] = &Field{Name: name, Type: kind, Example: example(value)}
+			case field.Type == "":
+				field.Type, field.Example = kind, example(value)
+			case field.Type != kind:
+				field.Type = "mixed"
+			}
+		}
+	}
+
+	fields := make([]Field, 0, len(byName))
+	for _, field := range byName {
+		if field.Type == "" {
+			field.Type = "null"
+		}
+		fields = append(fields, *field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		if _, err := time.Parse(time.RFC3339, value); err == nil {
+			return "timestamp"
+		}
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Short example value; nested values are summarized rather than dumped.
+func example(value interface{}) string {
+	switch value := value.(type) {
+	case []interface{}:
+		return fmt.Sprintf("%d item(s)", len(value))
+	case map[string]interface{}:
+		return fmt.Sprintf("%d field(s)", len(value))
+	}
+	text := fmt.Sprint(value)
+	if len(text) > 40 {
+		text = text[:40] + "..."
+	}
+	return text
+}
+
+// Builds a table's dictionary from its Unity Catalog columns and the mapping's semantics.
+//
+// Columns come from the warehouse when available; otherwise the semantic column list
+// is used so a dictionary can still be produced offline.
+func Build(name string, columns []databricks.ColumnInfo, semantics databricks.TableSemantics, fields []Field) *Table {
+	table := &Table{Name: name, Description: semantics.Description, SourceFields: fields, GeneratedAt: time.Now().UTC()}
+	bySemantics := make(map[string]databricks.ColumnSemantics, len(semantics.Columns))
+	for _, column := range semantics.Columns {
+		bySemantics[column.Name] = column
+	}
+
+	if len(columns) == 0 {
+		for _, column := range semantics.Columns {
+			columns = append(columns, databricks.ColumnInfo{Name: column.Name, Nullable: true})
+		}
+	}
+	for _, column := range columns {
+		documented := Column{Name: column.Name, DataType: column.DataType, Nullable: column.Nullable, Description: column.Comment}
+		if meaning, exists := bySemantics[column.Name]; exists {
+			if documented.Description == "" {
+				documented.Description = meaning.Description
+			}
+			documented.Synonyms = meaning.Synonyms
+		}
+		table.Columns = append(table.Columns, documented)
+	}
+	return table
+}
+
+// Writes the dictionary as a Markdown document.
+func WriteMarkdown(w io.Writer, t *Table) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Data Dictionary: %s\n\n", t.Name)
+	if t.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", t.Description)
+	}
+	fmt.Fprintf(&b, "_Generated %s", t.GeneratedAt.Format(time.RFC3339))
+	if t.RunID != "" {
+		fmt.Fprintf(&b, " after run %s", t.RunID)
+	}
+	b.WriteString("._\n\n## Columns\n\n| Column | Type | Nullable | Description | Synonyms |\n|---|---|---|---|---|\n")
+	for _, column := range t.Columns {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", cell(column.Name), cell(column.DataType),
+			yesNo(column.Nullable), cell(column.Description), cell(strings.Join(column.Synonyms, ", ")))
+	}
+	if len(t.SourceFields) > 0 {
+		b.WriteString("\n## Source Fields (raw_data)\n\n| Field | Inferred Type | Example |\n|---|---|---|\n")
+		for _, field := range t.SourceFields {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", cell(field.Name), cell(field.Type), cell(field.Example))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Writes the dictionary as CSV: one row per column and per source field.
+func WriteCSV(w io.Writer, t *Table) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"table", "kind", "name", "type", "nullable", "description", "synonyms"})
+	for _, column := range t.Columns {
+		out.Write([]string{t.Name, "column", column.Name, column.DataType, yesNo(column.Nullable),
+			column.Description, strings.Join(column.Synonyms, ";")})
+	}
+	for _, field := range t.SourceFields {
+		out.Write([]string{t.Name, "source_field", field.Name, field.Type, "", "", ""})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// Escapes a Markdown table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,182 @@
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+//   Purpose: Accreditation requires proof that the binary and the mapping config it
+//   loads are the released, unmodified artifacts before the tool runs on a production
+//   host. A release ships a manifest of SHA-256 checksums signed with the release key
+//   (ed25519); the matching public key is embedded in the binary at build time, so a
+//   manifest can't be regenerated for a tampered binary without the private key.
+
+// Release Build:
+//   - go build -ldflags "-X databricks-blade-poc/internal/integrity.PublicKey=<base64 key>" ./cmd
+//   - sign-release --key release.pem <binary> <mappings file>... writes the manifest and
+//     its detached signature (<manifest>.sig) and prints the public key to embed
+var PublicKey string
+
+// Checksums of a release's artifacts, keyed by file name (e.g. "blade-cli", "mappings.yaml").
+type Manifest struct {
+	Version string            `json:"version,omitempty"`
+	Files   map[string]string `json:"files"` // SHA-256 hex
+}
+
+// Outcome of checking one artifact against the manifest.
+//   - Status: "ok", "mismatch" (contents changed), "unlisted" (not part of the release)
+//     or "unreadable"
+type Check struct {
+	Artifact string `json:"artifact"`
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Reports whether the artifact matched its released checksum.
+func (c Check) Passed() bool {
+	return c.Status == "ok"
+}
+
+// Returns the hex SHA-256 of a file's contents.
+func SHA256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Builds the manifest of the given files, each listed under its base name.
+func NewManifest(version string, paths []string) (*Manifest, error) {
+	manifest := &Manifest{Version: version, Files: make(map[string]string, len(paths))}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if _, duplicate := manifest.Files[name]; duplicate {
+			return nil, fmt.Errorf("two release artifacts are named %s", name)
+		}
+		sum, err := SHA256File(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", path, err)
+		}
+		manifest.Files[name] = sum
+	}
+	return manifest, nil
+}
+
+// Reads the release signing key: a PEM "PRIVATE KEY" (PKCS #8) ed25519 key, as written by
+// "openssl genpkey -algorithm ed25519".
+func ReadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an ed25519 key", path)
+	}
+	return key, nil
+}
+
+// Returns the base64 public half of a signing key, the value to embed in PublicKey.
+func EncodePublicKey(key ed25519.PrivateKey) string {
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+// Writes the manifest to path and its ed25519 signature (base64) to path + ".sig".
+func (m *Manifest) WriteSigned(path string, key ed25519.PrivateKey) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", path, err)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	if err := os.WriteFile(path+".sig", []byte(signature+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest signature: %w", err)
+	}
+	return nil
+}
+
+// Reads a manifest and checks its signature (path + ".sig") against the base64 ed25519 publicKey.
+//   - An unsigned manifest, a bad signature or a binary built without a key all fail:
+//     an unverifiable manifest proves nothing
+func LoadManifest(path, publicKey string) (*Manifest, error) {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if publicKey == "" || err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("this binary has no valid embedded release key (built without -ldflags -X ...integrity.PublicKey)")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release manifest: %w", err)
+	}
+	encoded, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release manifest signature: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(trimNewline(encoded)))
+	if err != nil || !ed25519.Verify(key, data, signature) {
+		return nil, fmt.Errorf("release manifest %s is not signed by the release key", path)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid release manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+func trimNewline(data []byte) []byte {
+	for len(data) > 0 && (data[len(data)-1] == '\n' || data[len(data)-1] == '\r') {
+		data = data[:len(data)-1]
+	}
+	return data
+}
+
+// Checks artifacts (path of each file the process runs or loads) against the manifest, in path order.
+//   - Artifacts are looked up by base name; one the release doesn't list fails as "unlisted"
+func (m *Manifest) Verify(paths []string) []Check {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	checks := make([]Check, 0, len(sorted))
+	for _, path := range sorted {
+		check := Check{Artifact: filepath.Base(path), Path: path}
+		want, listed := m.Files[check.Artifact]
+		sum, err := SHA256File(path)
+		switch {
+		case !listed:
+			check.Status, check.Detail = "unlisted", "not part of the signed release"
+		case err != nil:
+			check.Status, check.Detail = "unreadable", err.Error()
+		case sum != want:
+			check.Status, check.Detail = "mismatch", fmt.Sprintf("sha256 %s, release has %s", sum, want)
+		default:
+			check.Status = "ok"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
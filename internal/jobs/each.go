@@ -0,0 +1,103 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/runstore"
+)
+
+// Runs one data type to completion (ingest --all); record is nil when the run failed before it was recorded.
+type EachFunc func(ctx context.Context, dataType string) (*runstore.Record, error)
+
+// Outcome of one data type in RunEach.
+//   - Skipped: Why the data type wasn't run ("" = it ran)
+type Outcome struct {
+	DataType string
+	Record   *runstore.Record
+	Err      error
+	Skipped  string
+}
+
+// Runs run once per data type, on up to workers goroutines (1 = one after another).
+//   - skip: Returns the reason a data type isn't run (e.g. disabled); nil runs them all
+//   - A failing data type doesn't stop the others
+//   - Outcomes are returned in the order of dataTypes, whatever order the runs finished in
+func RunEach(ctx context.Context, dataTypes []string, workers int, skip func(dataType string) (string, bool), run EachFunc) []Outcome {
+	if workers <= 0 {
+		workers = 1
+	}
+	outcomes := make([]Outcome, len(dataTypes))
+	pending := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				record, err := run(ctx, dataTypes[i])
+				// - Each index is written by exactly one worker, so no lock is needed
+				outcomes[i] = Outcome{DataType: dataTypes[i], Record: record, Err: err}
+			}
+		}()
+	}
+	for i, dataType := range dataTypes {
+		if skip != nil {
+			if reason, skipped := skip(dataType); skipped {
+				outcomes[i] = Outcome{DataType: dataType, Skipped: reason}
+				continue
+			}
+		}
+		pending <- i
+	}
+	close(pending)
+	wg.Wait()
+	return outcomes
+}
+
+// Rows ingested by all runs, including the rows committed by failed and partial ones.
+func TotalRows(outcomes []Outcome) int64 {
+	var total int64
+	for _, outcome := range outcomes {
+		if outcome.Record != nil && outcome.Record.Result != nil {
+			total += outcome.Record.Result.RowsIngested
+		}
+	}
+	return total
+}
+
+// Data types whose run failed, in order.
+func Failed(outcomes []Outcome) []string {
+	var failed []string
+	for _, outcome := range outcomes {
+		if outcome.Err != nil {
+			failed = append(failed, outcome.DataType)
+		}
+	}
+	return failed
+}
+
+// Summarizes the outcome for the ingest --all table.
+func (o Outcome) Summary() string {
+	if o.Skipped != "" {
+		return "skipped (" + o.Skipped + ")"
+	}
+	if o.Err != nil {
+		summary := "failed: " + o.Err.Error()
+		if o.Record != nil && o.Record.Status == runstore.StatusPartial {
+			summary = "partial: " + o.Err.Error()
+		}
+		if remedy, ok := databricks.Remediate(o.Err); ok {
+			summary += "\n" + strings.Repeat(" ", 15) + "how to fix: " + remedy.Hint
+		}
+		return summary
+	}
+	if o.Record == nil || o.Record.Result == nil {
+		return "completed"
+	}
+	return fmt.Sprintf("completed: %d rows in %s (run %s)", o.Record.Result.RowsIngested, o.Record.Result.Duration.Round(time.Millisecond), o.Record.ID)
+}
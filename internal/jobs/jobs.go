@@ -0,0 +1,197 @@
+// Package jobs runs accepted ingestions asynchronously: a fixed pool of worker goroutines
+// takes jobs from a bounded queue, and every state change is persisted in the run store,
+// so callers return immediately and look jobs up by ID later.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/runstore"
+)
+
+// Returned by Submit when every worker is busy and the queue is full.
+var ErrQueueFull = errors.New("ingestion queue is full")
+
+// Returned by Submit once Close has been called.
+var ErrClosed = errors.New("job manager is shut down")
+
+// Defaults used when Options leaves the pool or queue size at 0.
+const (
+	DefaultWorkers   = 2
+	DefaultQueueSize = 100
+)
+
+// Loads one job; record carries its ID, data type, format and tenant.
+//   - Called on a worker goroutine; the result is stored on the record either way
+type RunFunc func(ctx context.Context, record *runstore.Record, req *databricks.IngestionRequest) (*databricks.IngestionResult, error)
+
+// Pool and queue sizes (0 = DefaultWorkers / DefaultQueueSize).
+type Options struct {
+	Workers   int
+	QueueSize int
+}
+
+type job struct {
+	record *runstore.Record
+	req    *databricks.IngestionRequest
+}
+
+// Runs submitted jobs on a worker pool and records their state (queued, running,
+// completed, failed, partial) in the run store.
+type Manager struct {
+	store   *runstore.Store
+	run     RunFunc
+	queue   chan job
+	workers sync.WaitGroup
+	ctx     context.Context // jobs run under it; cancelled by Close
+	cancel  context.CancelCauseFunc
+
+	mu     sync.RWMutex // guards closed against sends on the closed queue
+	closed bool
+}
+
+// Starts the worker pool.
+func NewManager(store *runstore.Store, run RunFunc, opts Options) *Manager {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	m := &Manager{store: store, run: run, queue: make(chan job, opts.QueueSize), ctx: ctx, cancel: cancel}
+	for i := 0; i < opts.Workers; i++ {
+		m.workers.Add(1)
+		go func() {
+			defer m.workers.Done()
+			for next := range m.queue {
+				m.execute(next.record, next.req)
+			}
+		}()
+	}
+	return m
+}
+
+// Persists the record as queued and hands the job to the pool; returns without waiting for it.
+//   - ErrQueueFull: The job won't run; the caller can retry later
+func (m *Manager) Submit(record *runstore.Record, req *databricks.IngestionRequest) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrClosed
+	}
+	if len(m.queue) == cap(m.queue) {
+		return ErrQueueFull
+	}
+
+	record.Status = runstore.StatusQueued
+	if record.SubmittedAt.IsZero() {
+		record.SubmittedAt = time.Now().UTC()
+	}
+	if err := m.store.Save(record); err != nil {
+		return err
+	}
+	queued := *record
+	select {
+	case m.queue <- job{record: &queued, req: req}:
+		return nil
+	default:
+		record.Status, record.Error = runstore.StatusFailed, ErrQueueFull.Error()
+		m.store.Save(record)
+		return ErrQueueFull
+	}
+}
+
+// Returns a job's current state.
+func (m *Manager) Get(id string) (*runstore.Record, bool, error) {
+	return m.store.Get(id)
+}
+
+// Marks jobs left queued or running in store by a previous process as failed, since
+// their requests were lost with it. Call before starting a Manager; returns how many were marked.
+func Recover(store *runstore.Store) (int, error) {
+	recovered := 0
+	for _, status := range []string{runstore.StatusQueued, runstore.StatusRunning} {
+		pageToken := ""
+		for {
+			records, next, err := store.List(runstore.Filter{Status: status}, pageToken, runstore.MaxPageSize)
+			if err != nil {
+				return recovered, err
+			}
+			for _, record := range records {
+				finished := time.Now().UTC()
+				record.Status, record.FinishedAt = runstore.StatusFailed, &finished
+				record.Error = fmt.Sprintf("interrupted: the server stopped while the ingestion was %s", status)
+				if err := store.Save(record); err != nil {
+					return recovered, err
+				}
+				recovered++
+			}
+			if next == "" {
+				break
+			}
+			pageToken = next
+		}
+	}
+	return recovered, nil
+}
+
+// Stops accepting jobs and waits for the queued and running ones to finish, or until
+// ctx is done; then interrupts those still running (they end as partial, with the rows
+// committed so far).
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		m.cancel(nil)
+		return nil
+	case <-ctx.Done():
+		m.cancel(databricks.ErrInterrupted)
+		<-done
+		return fmt.Errorf("running ingestions were cancelled: %w", ctx.Err())
+	}
+}
+
+// Runs one job and records its outcome.
+func (m *Manager) execute(record *runstore.Record, req *databricks.IngestionRequest) {
+	started := time.Now().UTC()
+	record.Status, record.StartedAt = runstore.StatusRunning, &started
+	if err := m.store.Save(record); err != nil {
+		runlog.Printf(m.ctx, "Could not record run %s: %v", record.ID, err)
+	}
+
+	result, err := m.run(m.ctx, record, req)
+	finished := time.Now().UTC()
+	record.Result, record.FinishedAt = result, &finished
+	record.Status = runstore.StatusCompleted
+	if err != nil {
+		record.Status = runstore.StatusFailed
+		if databricks.IsPartial(err) {
+			record.Status = runstore.StatusPartial
+		}
+		record.Error = err.Error()
+		if remedy, ok := databricks.Remediate(err); ok {
+			record.Remediation = &remedy
+		}
+	}
+	if err := m.store.Save(record); err != nil {
+		runlog.Printf(m.ctx, "Could not record run %s: %v", record.ID, err)
+	}
+}
@@ -0,0 +1,180 @@
+package lineage
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+//   Purpose: Describes BLADE → Databricks flows as OpenLineage run events, including
+//   column-level lineage, so enterprise lineage tooling can display them without
+//   reverse-engineering SQL.
+
+// Identifies this tool as the producer of the events.
+const Producer = "https://github.com/cdschexnide/final-databricks-poc"
+
+const (
+	schemaURL             = "https://openlineage.io/spec/2-0-2/OpenLineage.json#/definitions/RunEvent"
+	schemaFacetURL        = "https://openlineage.io/spec/facets/1-1-1/SchemaDatasetFacet.json"
+	columnLineageFacetURL = "https://openlineage.io/spec/facets/1-0-1/ColumnLineageDatasetFacet.json"
+)
+
+// OpenLineage event types used here.
+const (
+	EventComplete = "COMPLETE"
+	EventFail     = "FAIL"
+)
+
+// An OpenLineage RunEvent.
+type RunEvent struct {
+	EventType string    `json:"eventType"`
+	EventTime time.Time `json:"eventTime"`
+	Run       Run       `json:"run"`
+	Job       Job       `json:"job"`
+	Inputs    []Dataset `json:"inputs"`
+	Outputs   []Dataset `json:"outputs"`
+	Producer  string    `json:"producer"`
+	SchemaURL string    `json:"schemaURL"`
+}
+
+type Run struct {
+	RunID string `json:"runId"`
+}
+
+type Job struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type Dataset struct {
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name"`
+	Facets    map[string]interface{} `json:"facets,omitempty"`
+}
+
+// Columns of the standardized BLADE table and the source field each one copies (empty: generated).
+var targetColumns = []struct {
+	name, typ, sourceField string
+}{
+	{"item_id", "STRING", "item_id"},
+	{"item_type", "STRING", "item_type"},
+	{"classification_marking", "STRING", "classification_marking"},
+	{"timestamp", "TIMESTAMP", "timestamp"},
+	{"data_source", "STRING", ""},
+	{"raw_data", "STRING", "*"}, // every source field
+	{"ingestion_timestamp", "TIMESTAMP", ""},
+	{"metadata", "MAP<STRING, STRING>", ""},
+}
+
+// What one ingestion run read and wrote.
+//   - RunID: OpenLineage expects a UUID; callers pass a stable UUID derived from the run ID
+//   - SourceNamespace/SourceName: The BLADE source (e.g. "blade://BLADE_LOGISTICS", "mock://maintenance")
+//   - TargetNamespace/TargetName: The workspace and three-part table name
+//   - SourceFields: Field names present in the source records
+type Flow struct {
+	RunID           string
+	JobName         string
+	SourceNamespace string
+	SourceName      string
+	SourceFields    []string
+	TargetNamespace string
+	TargetName      string
+	Failed          bool
+	EventTime       time.Time
+}
+
+// Builds the COMPLETE (or FAIL) event for a flow, with schema and column lineage facets on the output.
+func NewRunEvent(flow Flow) *RunEvent {
+	inputField := func(field string) map[string]string {
+		return map[string]string{"namespace": flow.SourceNamespace, "name": flow.SourceName, "field": field}
+	}
+	hasField := make(map[string]bool, len(flow.SourceFields))
+	for _, field := range flow.SourceFields {
+		hasField[field] = true
+	}
+
+	// Column Lineage:
+	// - Standard columns are direct copies (IDENTITY) of the same-named source field
+	// - raw_data holds the whole record, so every source field feeds it
+	// - Generated columns (data_source, ingestion_timestamp, metadata) have no inputs
+	var schemaFields []map[string]string
+	columnFields := make(map[string]interface{})
+	for _, column := range targetColumns {
+		schemaFields = append(schemaFields, map[string]string{"name": column.name, "type": column.typ})
+
+		var inputs []map[string]string
+		transformation := "IDENTITY"
+		switch {
+		case column.sourceField == "*":
+			for _, field := range flow.SourceFields {
+				inputs = append(inputs, inputField(field))
+			}
+			transformation = "serialized as JSON"
+		case hasField[column.sourceField]:
+			inputs = append(inputs, inputField(column.sourceField))
+		}
+		if len(inputs) > 0 {
+			columnFields[column.name] = map[string]interface{}{
+				"inputFields":               inputs,
+				"transformationType":        "DIRECT",
+				"transformationDescription": transformation,
+			}
+		}
+	}
+
+	eventType := EventComplete
+	if flow.Failed {
+		eventType = EventFail
+	}
+	return &RunEvent{
+		EventType: eventType,
+		EventTime: flow.EventTime.UTC(),
+		Run:       Run{RunID: flow.RunID},
+		Job:       Job{Namespace: flow.TargetNamespace, Name: flow.JobName},
+		Inputs:    []Dataset{{Namespace: flow.SourceNamespace, Name: flow.SourceName}},
+		Outputs: []Dataset{{
+			Namespace: flow.TargetNamespace,
+			Name:      flow.TargetName,
+			Facets: map[string]interface{}{
+				"schema": map[string]interface{}{
+					"_producer": Producer, "_schemaURL": schemaFacetURL, "fields": schemaFields,
+				},
+				"columnLineage": map[string]interface{}{
+					"_producer": Producer, "_schemaURL": columnLineageFacetURL, "fields": columnFields,
+				},
+			},
+		}},
+		Producer:  Producer,
+		SchemaURL: schemaURL,
+	}
+}
+
+// Returns the sorted union of field names across a JSON array of records.
+func SourceFields(sampleData string) []string {
+	var records []map[string]json.RawMessage
+	if json.Unmarshal([]byte(sampleData), &records) != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var fields []string
+	for _, record := range records {
+		for field := range record {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Derives a stable name-based (version 5) UUID from a run ID, since OpenLineage run IDs must be UUIDs.
+func RunUUID(runID string) string {
+	sum := sha1.Sum([]byte(Producer + "/" + runID))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
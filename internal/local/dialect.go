@@ -0,0 +1,556 @@
+package local
+
+import (
+	"context"
+	dbsql "database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//   Translation of the Databricks SQL the client writes into SQLite:
+//   - String literals are masked first, so rewrites never touch record content
+//   - catalog.schema.table becomes "catalog.schema.table"; X.information_schema.columns
+//     the emulated "information_schema.columns"
+//   - Types: STRING, TIMESTAMP, DATE, BOOLEAN, BINARY, MAP<..>, ARRAY<..>, STRUCT<..> are TEXT,
+//     integer types INTEGER, DOUBLE/FLOAT/DECIMAL REAL
+//   - try_cast(x AS T), count_if(x), IF(c, a, b), a <=> b, map['key'], QUALIFY and
+//     current_timestamp() +/- INTERVAL n UNIT are rewritten; map(), from_json(),
+//     get_json_object(), unix_timestamp(), split(), current_user() and friends are Go
+//     functions (see functions.go)
+
+// Statements to run, and the changes they make to information_schema.columns.
+type plan struct {
+	statements []string
+
+	create     *tableDef // columns of a created table, recorded unless it existed before
+	add        *tableDef // columns added to a table
+	drop       *tableName
+	dropSchema *tableName // catalog and schema of a dropped schema (table empty)
+	comment    *tableDef  // a column's new comment
+}
+
+type tableName struct {
+	catalog, schema, table string
+}
+
+// The SQLite identifier of the table.
+func (t tableName) quoted() string {
+	return `"` + strings.Join(nonEmpty(t.catalog, t.schema, t.table), ".") + `"`
+}
+
+type tableDef struct {
+	name    tableName
+	columns []columnDef
+	like    *tableName // CREATE TABLE ... LIKE: the table whose columns are copied
+}
+
+type columnDef struct {
+	name, dataType, comment string
+	notNull                 bool
+}
+
+var (
+	literalPattern   = regexp.MustCompile(`'(?:[^'\\]|''|\\.)*'`)
+	placeholderRegex = regexp.MustCompile("\x01(\\d+)\x02")
+
+	// Qualified names, not inside backquotes or after another dot
+	threePartPattern  = regexp.MustCompile("(^|[^\\w.\"`\x01])([A-Za-z_]\\w*)\\.([A-Za-z_]\\w*)\\.([A-Za-z_]\\w*)\\b")
+	infoSchemaPattern = regexp.MustCompile(`(?i)\b(?:[A-Za-z_]\w*\.)?information_schema\.columns\b`)
+	mapAccessPattern  = regexp.MustCompile("([A-Za-z_]\\w*|`[^`]+`)\\[(\x01\\d+\x02)\\]")
+	intervalPattern   = regexp.MustCompile(`(?i)\b(current_timestamp|now)\(\)\s*([+-])\s*INTERVAL\s+'?(\d+)'?\s+([A-Za-z]+?)S?\b`)
+	currentPattern    = regexp.MustCompile(`(?i)\b(current_timestamp|now)\(\)|\bcurrent_timestamp\b`)
+	currentDate       = regexp.MustCompile(`(?i)\bcurrent_date\(\)`)
+	createTable       = regexp.MustCompile(`(?is)^CREATE\s+(OR\s+REPLACE\s+)?TABLE\s+(IF\s+NOT\s+EXISTS\s+)?(\S+)\s*(.*)$`)
+	createView        = regexp.MustCompile(`(?is)^CREATE\s+OR\s+REPLACE\s+(?:TEMP\w*\s+)?VIEW\s+(\S+)\s+(.*)$`)
+	addColumns        = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(\S+)\s+ADD\s+COLUMNS?\s*\((.*)\)$`)
+	columnComment     = regexp.MustCompile("(?is)^ALTER\\s+TABLE\\s+(\\S+)\\s+(?:ALTER|CHANGE)\\s+COLUMN\\s+(`[^`]+`|\\w+)\\s+COMMENT\\s+(\x01\\d+\x02)$")
+	dropTable         = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(IF\s+EXISTS\s+)?(\S+)$`)
+	dropSchema        = regexp.MustCompile(`(?is)^DROP\s+(?:SCHEMA|DATABASE)\s+(?:IF\s+EXISTS\s+)?(\S+)`)
+	truncateTable     = regexp.MustCompile(`(?is)^TRUNCATE\s+TABLE\s+(\S+)$`)
+	qualifyPattern    = regexp.MustCompile(`(?i)\bQUALIFY\b`)
+	selectFrom        = regexp.MustCompile(`(?is)^(\s*SELECT\s+)(.*?)(\s+FROM\s.*)$`)
+)
+
+// Translates one Databricks statement.
+func translate(statement string) (*plan, error) {
+	masked, literals := maskLiterals(strings.TrimRight(strings.TrimSpace(statement), "; \t\r\n"))
+	words := strings.Fields(strings.ToUpper(masked))
+	if len(words) == 0 {
+		return &plan{}, nil
+	}
+	upper := " " + strings.Join(words, " ") + " "
+
+	switch {
+	// - Namespaces, governance and maintenance have nothing to emulate
+	case words[0] == "CREATE" && len(words) > 1 && (words[1] == "CATALOG" || words[1] == "SCHEMA" || words[1] == "DATABASE"),
+		words[0] == "ALTER" && len(words) > 1 && (words[1] == "CATALOG" || words[1] == "SCHEMA" || words[1] == "DATABASE"),
+		words[0] == "ALTER" && (strings.Contains(upper, " SET TAGS ") || strings.Contains(upper, " UNSET TAGS ") || strings.Contains(upper, " TBLPROPERTIES ")),
+		words[0] == "COMMENT", words[0] == "USE", words[0] == "GRANT", words[0] == "REVOKE",
+		words[0] == "OPTIMIZE", words[0] == "ANALYZE", words[0] == "VACUUM", words[0] == "REFRESH":
+		return &plan{}, nil
+
+	case words[0] == "COPY", words[0] == "MERGE":
+		return nil, fmt.Errorf("%s is not supported by the local backend", words[0]+" "+words[1])
+	}
+
+	if match := columnComment.FindStringSubmatch(masked); match != nil {
+		name := parseName(match[1])
+		return &plan{comment: &tableDef{name: name, columns: []columnDef{{name: unquoteIdentifier(match[2]), comment: unquoteLiteral(unmask(match[3], literals))}}}}, nil
+	}
+	if match := addColumns.FindStringSubmatch(masked); match != nil {
+		def := &tableDef{name: parseName(match[1])}
+		var statements []string
+		for _, column := range splitTopLevel(match[2]) {
+			parsed, ok := parseColumn(column, literals)
+			if !ok {
+				return nil, fmt.Errorf("can't parse column definition %q", unmask(column, literals))
+			}
+			def.columns = append(def.columns, parsed)
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", def.name.quoted(), quoteIdentifier(parsed.name), sqliteType(parsed.dataType)))
+		}
+		return &plan{statements: statements, add: def}, nil
+	}
+	if match := createTable.FindStringSubmatch(masked); match != nil && strings.HasPrefix(strings.TrimSpace(match[4]), "(") {
+		return translateCreateTable(match[1] != "", match[2] != "", parseName(match[3]), match[4], literals)
+	}
+	if match := createTable.FindStringSubmatch(masked); match != nil && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(match[4])), "LIKE ") {
+		// - CREATE TABLE x LIKE y copies the columns (and their recorded types) of y
+		source := parseName(strings.Fields(match[4])[1])
+		target := parseName(match[3])
+		return &plan{
+			statements: []string{fmt.Sprintf("CREATE TABLE %s%s AS SELECT * FROM %s WHERE 0", ifNotExists(match[2] != ""), target.quoted(), source.quoted())},
+			create:     &tableDef{name: target, like: &source},
+		}, nil
+	}
+	if match := createView.FindStringSubmatch(masked); match != nil {
+		view := parseName(match[1])
+		return &plan{statements: []string{
+			"DROP VIEW IF EXISTS " + view.quoted(),
+			"CREATE VIEW " + view.quoted() + " " + unmask(rewriteExpressions(match[2]), literals),
+		}}, nil
+	}
+	if match := dropTable.FindStringSubmatch(masked); match != nil {
+		name := parseName(match[2])
+		return &plan{statements: []string{"DROP TABLE " + ifExists(match[1] != "") + name.quoted()}, drop: &name}, nil
+	}
+	if match := dropSchema.FindStringSubmatch(masked); match != nil {
+		parts := strings.Split(match[1], ".")
+		name := tableName{schema: unquoteIdentifier(parts[len(parts)-1])}
+		if len(parts) > 1 {
+			name.catalog = unquoteIdentifier(parts[0])
+		}
+		return &plan{dropSchema: &name}, nil
+	}
+	if match := truncateTable.FindStringSubmatch(masked); match != nil {
+		return &plan{statements: []string{"DELETE FROM " + parseName(match[1]).quoted()}}, nil
+	}
+	return &plan{statements: []string{unmask(rewriteExpressions(masked), literals)}}, nil
+}
+
+// Translates CREATE TABLE name (columns) [USING ..., COMMENT ..., TBLPROPERTIES ...].
+func translateCreateTable(replace, guarded bool, name tableName, rest string, literals []string) (*plan, error) {
+	end := matchingParen(rest, strings.Index(rest, "("))
+	if end < 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in CREATE TABLE %s", name.quoted())
+	}
+	def := &tableDef{name: name}
+	var columns []string
+	for _, column := range splitTopLevel(rest[strings.Index(rest, "(")+1 : end]) {
+		first := strings.ToUpper(strings.Fields(column + " x")[0])
+		if first == "CONSTRAINT" || first == "PRIMARY" || first == "FOREIGN" {
+			continue
+		}
+		parsed, ok := parseColumn(column, literals)
+		if !ok {
+			return nil, fmt.Errorf("can't parse column definition %q", unmask(column, literals))
+		}
+		def.columns = append(def.columns, parsed)
+		sqlite := quoteIdentifier(parsed.name) + " " + sqliteType(parsed.dataType)
+		if parsed.notNull {
+			sqlite += " NOT NULL"
+		}
+		columns = append(columns, sqlite)
+	}
+	var statements []string
+	if replace {
+		statements = append(statements, "DROP TABLE IF EXISTS "+name.quoted())
+	}
+	statements = append(statements, fmt.Sprintf("CREATE TABLE %s%s (%s)", ifNotExists(guarded && !replace), name.quoted(), strings.Join(columns, ", ")))
+	p := &plan{statements: statements, create: def}
+	if replace {
+		p.drop = &name
+	}
+	return p, nil
+}
+
+// Parses "name TYPE [NOT NULL] [COMMENT 'text'] [...]".
+func parseColumn(definition string, literals []string) (columnDef, bool) {
+	definition = strings.TrimSpace(definition)
+	var name string
+	if strings.HasPrefix(definition, "`") {
+		end := strings.Index(definition[1:], "`")
+		if end < 0 {
+			return columnDef{}, false
+		}
+		name, definition = definition[1:end+1], strings.TrimSpace(definition[end+2:])
+	} else {
+		fields := strings.Fields(definition)
+		if len(fields) < 2 {
+			return columnDef{}, false
+		}
+		name, definition = fields[0], strings.TrimSpace(definition[len(fields[0]):])
+	}
+
+	// - The type runs to the first top-level space (MAP<STRING, STRING> and DECIMAL(10, 2)
+	//   contain spaces)
+	depth, end := 0, len(definition)
+	for i, r := range definition {
+		switch r {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ' ', '\t', '\n', '\r':
+			if depth == 0 && end == len(definition) {
+				end = i
+			}
+		}
+	}
+	column := columnDef{name: name, dataType: definition[:end]}
+	modifiers := " " + strings.Join(strings.Fields(definition[end:]), " ")
+	column.notNull = strings.Contains(strings.ToUpper(modifiers), " NOT NULL")
+	if index := strings.Index(strings.ToUpper(modifiers), " COMMENT "); index >= 0 {
+		if match := placeholderRegex.FindString(modifiers[index:]); match != "" {
+			column.comment = unquoteLiteral(unmask(match, literals))
+		}
+	}
+	return column, column.dataType != ""
+}
+
+// Returns the SQLite column type (and so affinity) for a Databricks type.
+func sqliteType(dataType string) string {
+	upper := strings.ToUpper(strings.TrimSpace(dataType))
+	switch {
+	case strings.HasPrefix(upper, "INT"), strings.HasPrefix(upper, "BIGINT"), strings.HasPrefix(upper, "SMALLINT"),
+		strings.HasPrefix(upper, "TINYINT"), strings.HasPrefix(upper, "LONG"), strings.HasPrefix(upper, "SHORT"), strings.HasPrefix(upper, "BYTE"):
+		return "INTEGER"
+	case strings.HasPrefix(upper, "DOUBLE"), strings.HasPrefix(upper, "FLOAT"), strings.HasPrefix(upper, "REAL"), strings.HasPrefix(upper, "DECIMAL"), strings.HasPrefix(upper, "NUMERIC"):
+		return "REAL"
+	}
+	return "TEXT"
+}
+
+// Rewrites the names, types and functions of a query or DML statement (literals masked).
+func rewriteExpressions(masked string) string {
+	masked = infoSchemaPattern.ReplaceAllString(masked, `"information_schema.columns"`)
+	masked = threePartPattern.ReplaceAllString(masked, `$1"$2.$3.$4"`)
+	masked = mapAccessPattern.ReplaceAllString(masked, `json_extract($1, '$$.' || $2)`)
+
+	masked = rewriteCalls(masked, "try_cast", func(args string) string {
+		value, dataType := splitCast(args)
+		return fmt.Sprintf("try_cast(%s, '%s')", value, strings.ToUpper(dataType))
+	})
+	masked = rewriteCalls(masked, "cast", func(args string) string {
+		value, dataType := splitCast(args)
+		return fmt.Sprintf("CAST(%s AS %s)", value, sqliteType(dataType))
+	})
+	masked = rewriteCalls(masked, "count_if", func(args string) string {
+		return fmt.Sprintf("COALESCE(SUM(CASE WHEN %s THEN 1 ELSE 0 END), 0)", args)
+	})
+	masked = rewriteCalls(masked, "if", func(args string) string {
+		return "iif(" + args + ")"
+	})
+	masked = strings.ReplaceAll(masked, "<=>", " IS ")
+	masked = rewriteQualify(masked)
+
+	masked = intervalPattern.ReplaceAllStringFunc(masked, func(interval string) string {
+		match := intervalPattern.FindStringSubmatch(interval)
+		return fmt.Sprintf("datetime('now', '%s%s %ss')", match[2], match[3], strings.ToLower(match[4]))
+	})
+	masked = currentPattern.ReplaceAllString(masked, "datetime('now')")
+	masked = currentDate.ReplaceAllString(masked, "date('now')")
+	return masked
+}
+
+// Moves each QUALIFY condition into a subquery SQLite can filter on:
+// SELECT cols FROM src QUALIFY cond becomes SELECT cols FROM (SELECT *, cond AS q FROM src) WHERE q.
+func rewriteQualify(masked string) string {
+	for {
+		loc := qualifyPattern.FindStringIndex(masked)
+		if loc == nil {
+			return masked
+		}
+		// - The query is the innermost parenthesized one around QUALIFY, or the statement
+		start, end := 0, len(masked)
+		for i, depth := loc[0]-1, 0; i >= 0; i-- {
+			if masked[i] == ')' {
+				depth++
+			} else if masked[i] == '(' {
+				if depth == 0 {
+					start = i + 1
+					break
+				}
+				depth--
+			}
+		}
+		for i, depth := loc[1], 0; i < len(masked); i++ {
+			if masked[i] == '(' {
+				depth++
+			} else if masked[i] == ')' {
+				if depth == 0 {
+					end = i
+					break
+				}
+				depth--
+			}
+		}
+		match := selectFrom.FindStringSubmatch(masked[start:loc[0]])
+		if match == nil {
+			return masked
+		}
+		rewritten := fmt.Sprintf("%s%s FROM (SELECT *, (%s) AS blade_qualify%s) WHERE blade_qualify",
+			match[1], match[2], strings.TrimSpace(masked[loc[1]:end]), match[3])
+		masked = masked[:start] + rewritten + masked[end:]
+	}
+}
+
+// Replaces every call name(args) with rewrite(args), innermost calls first.
+func rewriteCalls(masked, name string, rewrite func(args string) string) string {
+	pattern := regexp.MustCompile(`(?i)(^|[^\w.])` + name + `\s*\(`)
+	var out strings.Builder
+	for {
+		loc := pattern.FindStringSubmatchIndex(masked)
+		if loc == nil {
+			out.WriteString(masked)
+			return out.String()
+		}
+		start, open := loc[3], loc[1]-1
+		end := matchingParen(masked, open)
+		if end < 0 {
+			out.WriteString(masked)
+			return out.String()
+		}
+		out.WriteString(masked[:start])
+		out.WriteString(rewrite(rewriteCalls(masked[open+1:end], name, rewrite)))
+		masked = masked[end+1:]
+	}
+}
+
+// Splits "value AS TYPE" at its last top-level AS.
+func splitCast(args string) (value, dataType string) {
+	upper := strings.ToUpper(args)
+	depth := 0
+	for i := len(args) - 1; i >= 3; i-- {
+		switch args[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+		}
+		if depth == 0 && upper[i-3:i+1] == " AS " {
+			return strings.TrimSpace(args[:i-3]), strings.TrimSpace(args[i+1:])
+		}
+	}
+	return args, "STRING"
+}
+
+// Returns the index of the parenthesis closing the one at open, or -1.
+func matchingParen(s string, open int) int {
+	if open < 0 {
+		return -1
+	}
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Splits a list at the commas outside parentheses and angle brackets.
+func splitTopLevel(list string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '(', '<':
+			depth++
+		case ')', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(list[start:]) != "" {
+		parts = append(parts, list[start:])
+	}
+	return parts
+}
+
+// Replaces string literals by placeholders (\x01N\x02) and returns them.
+func maskLiterals(statement string) (string, []string) {
+	var literals []string
+	masked := literalPattern.ReplaceAllStringFunc(statement, func(literal string) string {
+		literals = append(literals, literal)
+		return fmt.Sprintf("\x01%d\x02", len(literals)-1)
+	})
+	return masked, literals
+}
+
+func unmask(masked string, literals []string) string {
+	return placeholderRegex.ReplaceAllStringFunc(masked, func(placeholder string) string {
+		index, _ := strconv.Atoi(placeholderRegex.FindStringSubmatch(placeholder)[1])
+		return literals[index]
+	})
+}
+
+func unquoteLiteral(literal string) string {
+	literal = strings.TrimSuffix(strings.TrimPrefix(literal, "'"), "'")
+	return strings.NewReplacer("''", "'", `\'`, "'", `\\`, `\`).Replace(literal)
+}
+
+func unquoteIdentifier(identifier string) string {
+	return strings.Trim(identifier, "`\"")
+}
+
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// Splits a one- to three-part name.
+func parseName(name string) tableName {
+	parts := strings.Split(name, ".")
+	for i := range parts {
+		parts[i] = unquoteIdentifier(parts[i])
+	}
+	switch len(parts) {
+	case 1:
+		return tableName{table: parts[0]}
+	case 2:
+		return tableName{schema: parts[0], table: parts[1]}
+	}
+	return tableName{catalog: parts[0], schema: parts[1], table: strings.Join(parts[2:], ".")}
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, value := range values {
+		if value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func ifNotExists(set bool) string {
+	if set {
+		return "IF NOT EXISTS "
+	}
+	return ""
+}
+
+func ifExists(set bool) string {
+	if set {
+		return "IF EXISTS "
+	}
+	return ""
+}
+
+// Applies the statement's changes to information_schema.columns (inside its transaction).
+func (p *plan) updateCatalog(ctx context.Context, tx *dbsql.Tx) error {
+	const columns = `"information_schema.columns"`
+	where := func(name tableName) (string, []interface{}) {
+		return "table_catalog = ? AND table_schema = ? AND table_name = ?", []interface{}{name.catalog, name.schema, name.table}
+	}
+	if p.drop != nil {
+		clause, args := where(*p.drop)
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+columns+" WHERE "+clause, args...); err != nil {
+			return err
+		}
+	}
+	if p.dropSchema != nil {
+		rows, err := tx.QueryContext(ctx, "SELECT DISTINCT table_name FROM "+columns+" WHERE table_catalog = ? AND table_schema = ?", p.dropSchema.catalog, p.dropSchema.schema)
+		if err != nil {
+			return err
+		}
+		var tables []string
+		for rows.Next() {
+			var table string
+			rows.Scan(&table)
+			tables = append(tables, table)
+		}
+		rows.Close()
+		for _, table := range tables {
+			name := tableName{catalog: p.dropSchema.catalog, schema: p.dropSchema.schema, table: table}
+			if _, err := tx.ExecContext(ctx, "DROP TABLE IF EXISTS "+name.quoted()); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+columns+" WHERE table_catalog = ? AND table_schema = ?", p.dropSchema.catalog, p.dropSchema.schema); err != nil {
+			return err
+		}
+	}
+
+	for _, def := range []*tableDef{p.create, p.add} {
+		if def == nil {
+			continue
+		}
+		clause, args := where(def.name)
+		var next int
+		if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(ordinal_position), 0) FROM "+columns+" WHERE "+clause, args...).Scan(&next); err != nil {
+			return err
+		}
+		if def == p.create && next > 0 {
+			// - CREATE TABLE IF NOT EXISTS of a table that exists changes nothing
+			continue
+		}
+		if def.like != nil {
+			sourceClause, sourceArgs := where(*def.like)
+			if _, err := tx.ExecContext(ctx, "INSERT INTO "+columns+" SELECT ?, ?, ?, column_name, full_data_type, is_nullable, comment, ordinal_position FROM "+columns+" WHERE "+sourceClause,
+				append([]interface{}{def.name.catalog, def.name.schema, def.name.table}, sourceArgs...)...); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, column := range def.columns {
+			next++
+			nullable := "YES"
+			if column.notNull {
+				nullable = "NO"
+			}
+			var comment interface{}
+			if column.comment != "" {
+				comment = column.comment
+			}
+			if _, err := tx.ExecContext(ctx, "INSERT INTO "+columns+" VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				def.name.catalog, def.name.schema, def.name.table, column.name, fullDataType(column.dataType), nullable, comment, next); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.comment != nil {
+		clause, args := where(p.comment.name)
+		column := p.comment.columns[0]
+		if _, err := tx.ExecContext(ctx, "UPDATE "+columns+" SET comment = ? WHERE "+clause+" AND column_name = ?", append(append([]interface{}{column.comment}, args...), column.name)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Spells a DDL type the way information_schema.columns reports it (map<string,string>).
+func fullDataType(dataType string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(dataType, ", ", ",")), ""))
+}
@@ -0,0 +1,296 @@
+package local
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// Layout of the timestamps the local backend stores (UTC), the one SQLite's datetime() uses.
+const timestampLayout = "2006-01-02 15:04:05"
+
+// Registers the Databricks functions SQLite lacks.
+func registerFunctions() error {
+	functions := []struct {
+		name  string
+		nArgs int32
+		fn    func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error)
+	}{
+		{"map", -1, mapFunction},
+		{"array", -1, arrayFunction},
+		{"from_json", 2, fromJSON},
+		{"to_json", 1, toJSON},
+		{"get_json_object", 2, getJSONObject},
+		{"unix_timestamp", 1, unixTimestamp},
+		{"to_timestamp", 1, toTimestamp},
+		{"try_cast", 2, tryCast},
+		{"size", 1, size},
+		{"nvl", 2, nvl},
+		{"split", 2, split},
+		{"array_contains", 2, arrayContains},
+		{"current_user", 0, currentUser},
+	}
+	for _, function := range functions {
+		if err := sqlite.RegisterDeterministicScalarFunction(function.name, function.nArgs, function.fn); err != nil {
+			return fmt.Errorf("%s: %w", function.name, err)
+		}
+	}
+	return nil
+}
+
+// map(k1, v1, k2, v2, ...) as a JSON object; NULL values are left out.
+func mapFunction(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("map expects key/value pairs, got %d arguments", len(args))
+	}
+	entries := make(map[string]string, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		if args[i+1] != nil {
+			entries[text(args[i])] = text(args[i+1])
+		}
+	}
+	data, err := json.Marshal(entries)
+	return string(data), err
+}
+
+// array(v1, v2, ...) as a JSON array.
+func arrayFunction(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg != nil {
+			values[i] = text(arg)
+		}
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+// from_json(value, schema): arrays and maps are stored as their JSON, so only invalid JSON
+// (NULL on a SQL warehouse too) needs handling.
+func fromJSON(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	return validJSON(args[0]), nil
+}
+
+// to_json(value): maps and arrays already are JSON.
+func toJSON(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	return validJSON(args[0]), nil
+}
+
+func validJSON(value driver.Value) driver.Value {
+	if value == nil || !json.Valid([]byte(text(value))) {
+		return nil
+	}
+	return text(value)
+}
+
+// get_json_object(json, '$.a.b[0]'): scalars as text, objects and arrays as JSON, NULL
+// when the path is missing or the JSON is invalid.
+func getJSONObject(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	var value interface{}
+	if json.Unmarshal([]byte(text(args[0])), &value) != nil {
+		return nil, nil
+	}
+	path := strings.TrimPrefix(text(args[1]), "$")
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "."):
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, nil
+			}
+			value, path = object[path[:end]], path[end:]
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, nil
+			}
+			key := strings.Trim(path[1:end], `'"`)
+			path = path[end+1:]
+			if index, err := strconv.Atoi(key); err == nil {
+				array, ok := value.([]interface{})
+				if !ok || index < 0 || index >= len(array) {
+					return nil, nil
+				}
+				value = array[index]
+				continue
+			}
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, nil
+			}
+			value = object[key]
+		default:
+			return nil, nil
+		}
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// unix_timestamp(ts): seconds since the epoch of a stored timestamp.
+func unixTimestamp(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	normalized, ok := normalizeTimestamp(text(args[0]))
+	if !ok {
+		return nil, nil
+	}
+	parsed, _ := time.Parse(timestampLayout, normalized)
+	return parsed.Unix(), nil
+}
+
+func toTimestamp(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	if normalized, ok := normalizeTimestamp(text(args[0])); ok {
+		return normalized, nil
+	}
+	return nil, nil
+}
+
+// try_cast(value, 'TYPE') (rewritten from try_cast(value AS TYPE)): NULL when the value
+// doesn't convert.
+func tryCast(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	value := strings.TrimSpace(text(args[0]))
+	dataType := strings.ToUpper(text(args[1]))
+	switch sqliteType(dataType) {
+	case "INTEGER":
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number, nil
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil && number == float64(int64(number)) {
+			return int64(number), nil
+		}
+		return nil, nil
+	case "REAL":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number, nil
+		}
+		return nil, nil
+	}
+	switch {
+	case strings.HasPrefix(dataType, "TIMESTAMP"), dataType == "DATE":
+		normalized, ok := normalizeTimestamp(value)
+		if !ok {
+			return nil, nil
+		}
+		if dataType == "DATE" {
+			return normalized[:10], nil
+		}
+		return normalized, nil
+	case dataType == "BOOLEAN":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(parsed), nil
+		}
+		return nil, nil
+	}
+	return text(args[0]), nil
+}
+
+// size(array or map): number of elements, -1 for NULL like Databricks.
+func size(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil {
+		return int64(-1), nil
+	}
+	var value interface{}
+	if json.Unmarshal([]byte(text(args[0])), &value) != nil {
+		return nil, nil
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		return int64(len(v)), nil
+	case map[string]interface{}:
+		return int64(len(v)), nil
+	}
+	return nil, nil
+}
+
+// split(text, separator) as a JSON array; the separator is taken literally, not as a regex.
+func split(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(strings.Split(text(args[0]), text(args[1])))
+	return string(data), err
+}
+
+func arrayContains(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var values []interface{}
+	if args[0] == nil || json.Unmarshal([]byte(text(args[0])), &values) != nil {
+		return nil, nil
+	}
+	for _, value := range values {
+		if fmt.Sprint(value) == text(args[1]) {
+			return int64(1), nil
+		}
+	}
+	return int64(0), nil
+}
+
+func nvl(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] != nil {
+		return args[0], nil
+	}
+	return args[1], nil
+}
+
+// Converts ISO 8601 / RFC 3339 text, "YYYY-MM-DD[ HH:MM:SS]" or epoch seconds to the stored
+// UTC layout.
+func normalizeTimestamp(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Unix(int64(seconds), 0).UTC().Format(timestampLayout), true
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05Z07:00", "2006-01-02"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC().Format(timestampLayout), true
+		}
+	}
+	return "", false
+}
+
+func text(value driver.Value) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	}
+	return formatValue(value)
+}
+
+// current_user(): there is no workspace principal, so the local OS user stands in for it.
+func currentUser(_ *sqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username, nil
+	}
+	return os.Getenv("USER"), nil
+}
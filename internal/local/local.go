@@ -0,0 +1,257 @@
+package local
+
+import (
+	"context"
+	dbsql "database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/databricks/databricks-sdk-go/service/sql"
+	_ "modernc.org/sqlite"
+)
+
+//   Purpose: Trying the adapter → ingest → verify flow needed a Databricks workspace, a
+//   warehouse and credentials, which developers without an account and CI don't have.
+//   The local backend (--backend local, BLADE_BACKEND=local) runs the client's statements
+//   against an embedded SQLite database instead, so the whole flow works offline.
+
+//   Emulation (see dialect.go):
+//   - A table catalog.schema.table is the SQLite table "catalog.schema.table"; catalogs and
+//     schemas need no objects, so CREATE CATALOG/SCHEMA succeed without doing anything
+//   - Tags, table properties and table comments are accepted and ignored, and so are
+//     layouts: PARTITIONED BY and CLUSTER BY are dropped, generated columns are plain
+//     columns that stay NULL, OPTIMIZE and VACUUM do nothing
+//   - information_schema.columns is kept up to date by CREATE TABLE and ADD COLUMNS, with
+//     the Databricks types of the DDL, so schema drift is detected like in a workspace
+//   - Timestamps are stored as UTC "YYYY-MM-DD HH:MM:SS" text, maps and arrays as JSON
+//   - Statements the emulation can't run (COPY INTO, MERGE, zstd_decompress) fail like a
+//     SQL error would; Volumes don't exist, so BLADE_VOLUME_PATH is ignored and
+//     BLADE_RAW_DATA_CODEC=zstd records can't be read back
+//   - Every statement finishes before ExecuteStatement returns; nothing is ever polled
+
+// Database used when BLADE_LOCAL_DB isn't set, inside BLADE_STATE_DIR.
+const DefaultDatabase = "local.db"
+
+// Runs Databricks statements against a SQLite database; implements databricks.StatementExecutor.
+type Executor struct {
+	db   *dbsql.DB
+	path string
+	ids  atomic.Int64
+}
+
+var registerOnce sync.Once
+
+// Opens (or creates) the SQLite database at path; ":memory:" keeps it in memory.
+func Open(path string) (*Executor, error) {
+	var err error
+	registerOnce.Do(func() { err = registerFunctions() })
+	if err != nil {
+		return nil, fmt.Errorf("failed to register SQL functions: %w", err)
+	}
+	if path != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create the directory of %s: %w", path, err)
+		}
+	}
+	db, err := dbsql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open local database %s: %w", path, err)
+	}
+	// - One connection: SQLite has a single writer, and an in-memory database lives
+	//   as long as its connection
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS "information_schema.columns" (
+		table_catalog TEXT, table_schema TEXT, table_name TEXT, column_name TEXT,
+		full_data_type TEXT, is_nullable TEXT, comment TEXT, ordinal_position INTEGER
+	)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize local database %s: %w", path, err)
+	}
+	return &Executor{db: db, path: path}, nil
+}
+
+// Returns the database file (or ":memory:").
+func (e *Executor) Path() string {
+	return e.path
+}
+
+func (e *Executor) Close() error {
+	return e.db.Close()
+}
+
+func (e *Executor) ExecuteStatement(ctx context.Context, req sql.ExecuteStatementRequest) (*sql.StatementResponse, error) {
+	resp := &sql.StatementResponse{StatementId: fmt.Sprintf("local-%d", e.ids.Add(1))}
+	result, err := e.run(ctx, req)
+	if err != nil {
+		resp.Status = &sql.StatementStatus{State: sql.StatementStateFailed, Error: &sql.ServiceError{
+			ErrorCode: sql.ServiceErrorCodeBadRequest,
+			Message:   describeError(err),
+		}}
+		return resp, nil
+	}
+	resp.Status = &sql.StatementStatus{State: sql.StatementStateSucceeded}
+	resp.Manifest, resp.Result = result.manifest, result.data
+	return resp, nil
+}
+
+// Statements finish inside ExecuteStatement, so a poll only repeats that they did.
+func (e *Executor) GetStatementByStatementId(ctx context.Context, statementID string) (*sql.StatementResponse, error) {
+	return &sql.StatementResponse{StatementId: statementID, Status: &sql.StatementStatus{State: sql.StatementStateSucceeded}}, nil
+}
+
+func (e *Executor) CancelExecution(ctx context.Context, req sql.CancelExecutionRequest) error {
+	return nil
+}
+
+type result struct {
+	manifest *sql.ResultManifest
+	data     *sql.ResultData
+}
+
+// Translates and runs a statement in one transaction, returning the rows of its last part.
+func (e *Executor) run(ctx context.Context, req sql.ExecuteStatementRequest) (*result, error) {
+	plan, err := translate(req.Statement)
+	if err != nil {
+		return nil, err
+	}
+	args := bindParameters(req.Parameters)
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	res := &result{}
+	for i, statement := range plan.statements {
+		if i < len(plan.statements)-1 {
+			if _, err := tx.ExecContext(ctx, statement, args...); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rows, err := tx.QueryContext(ctx, statement, args...)
+		if err != nil {
+			return nil, err
+		}
+		if res, err = readRows(rows, req.RowLimit); err != nil {
+			return nil, err
+		}
+	}
+	if err := plan.updateCatalog(ctx, tx); err != nil {
+		return nil, err
+	}
+	return res, tx.Commit()
+}
+
+// Binds the statement's named parameters; a STRING without a value is NULL unless it was
+// sent as an explicit empty string, like on a SQL warehouse.
+func bindParameters(params []sql.StatementParameterListItem) []interface{} {
+	args := make([]interface{}, 0, len(params))
+	for _, param := range params {
+		var value interface{} = param.Value
+		switch {
+		case param.Value == "" && !forced(param.ForceSendFields, "Value"):
+			value = nil
+		case strings.EqualFold(param.Type, "TIMESTAMP"):
+			if normalized, ok := normalizeTimestamp(param.Value); ok {
+				value = normalized
+			}
+		}
+		args = append(args, dbsql.Named(param.Name, value))
+	}
+	return args
+}
+
+func forced(fields []string, name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads the rows as strings (NULL as ""), with a manifest naming the columns.
+//   - limit: At most this many rows (0 = all); one more is read to tell whether the
+//     result was truncated, as the manifest of a SQL warehouse reports
+func readRows(rows *dbsql.Rows, limit int64) (*result, error) {
+	defer rows.Close()
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	res := &result{manifest: &sql.ResultManifest{Schema: &sql.ResultSchema{ColumnCount: len(columns)}}}
+	for i, column := range columns {
+		typeText := strings.ToUpper(column.DatabaseTypeName())
+		if typeText == "" || typeText == "TEXT" {
+			typeText = "STRING"
+		}
+		res.manifest.Schema.Columns = append(res.manifest.Schema.Columns, sql.ColumnInfo{
+			Name: column.Name(), Position: i, TypeText: typeText, TypeName: sql.ColumnInfoTypeName(typeText),
+		})
+	}
+
+	var data [][]string
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if limit > 0 && int64(len(data)) == limit {
+			res.manifest.Truncated = true
+			break
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(values))
+		for i, value := range values {
+			row[i] = formatValue(value)
+		}
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	res.manifest.TotalRowCount = int64(len(data))
+	if len(data) > 0 {
+		res.data = &sql.ResultData{DataArray: data, RowCount: int64(len(data))}
+	}
+	return res, nil
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+var missingTablePattern = regexp.MustCompile(`no such table: (\S+)`)
+
+// Words SQLite errors the way a SQL warehouse would, so remediation hints still match.
+func describeError(err error) string {
+	message := err.Error()
+	if match := missingTablePattern.FindStringSubmatch(message); match != nil {
+		return fmt.Sprintf("[TABLE_OR_VIEW_NOT_FOUND] The table or view %s cannot be found (local backend: %s)", strings.Trim(match[1], `"`), message)
+	}
+	return "local backend: " + message
+}
@@ -0,0 +1,128 @@
+package quota
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Configurable limits applied per tenant and data type; zero means unlimited.
+type Limits struct {
+	RunsPerHour int
+	RowsPerDay  int64
+}
+
+// Returned when a run would exceed a quota; RetryAfter is when enough usage has aged out.
+type ExceededError struct {
+	Tenant     string
+	DataType   string
+	Limit      string // "runs per hour" or "rows per day"
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	tenant := e.Tenant
+	if tenant == "" {
+		tenant = "default"
+	}
+	return fmt.Sprintf("quota exceeded for tenant %s, data type %s: %s (retry after %s)",
+		tenant, e.DataType, e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+// One accepted run and the rows it was allowed to load.
+type usage struct {
+	at   time.Time
+	rows int64
+}
+
+// Enforces Limits over rolling windows, tracked separately for every tenant/data type pair.
+// Safe for concurrent use by the server's job workers.
+type Limiter struct {
+	limits Limits
+	now    func() time.Time
+
+	mu    sync.Mutex
+	usage map[string][]usage
+}
+
+// Creates a limiter; now may be nil to use the wall clock (tests pass a fake clock).
+func NewLimiter(limits Limits, now func() time.Time) *Limiter {
+	if now == nil {
+		now = time.Now
+	}
+	return &Limiter{limits: limits, now: now, usage: make(map[string][]usage)}
+}
+
+// Records a run of rows for tenant/dataType, or returns an *ExceededError without recording anything.
+func (l *Limiter) Acquire(tenant, dataType string, rows int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := tenant + "/" + dataType
+	now := l.now()
+
+	// Rolling Windows:
+	// - Entries older than a day can no longer count against either limit
+	// - Runs count over the last hour, rows over the last day
+	var kept []usage
+	for _, u := range l.usage[key] {
+		if now.Sub(u.at) < 24*time.Hour {
+			kept = append(kept, u)
+		}
+	}
+	l.usage[key] = kept
+
+	if l.limits.RunsPerHour > 0 {
+		var recent []usage
+		for _, u := range kept {
+			if now.Sub(u.at) < time.Hour {
+				recent = append(recent, u)
+			}
+		}
+		if len(recent) >= l.limits.RunsPerHour {
+			// The oldest run in the window has to age out before another fits
+			oldest := recent[len(recent)-l.limits.RunsPerHour]
+			return &ExceededError{Tenant: tenant, DataType: dataType, Limit: "runs per hour",
+				RetryAfter: oldest.at.Add(time.Hour).Sub(now)}
+		}
+	}
+
+	if l.limits.RowsPerDay > 0 {
+		var total int64
+		for _, u := range kept {
+			total += u.rows
+		}
+		if total+rows > l.limits.RowsPerDay {
+			// Walk forward until enough rows have aged out to fit this run
+			retryAfter := 24 * time.Hour
+			excess := total + rows - l.limits.RowsPerDay
+			for _, u := range kept {
+				excess -= u.rows
+				if excess <= 0 {
+					retryAfter = u.at.Add(24 * time.Hour).Sub(now)
+					break
+				}
+			}
+			return &ExceededError{Tenant: tenant, DataType: dataType, Limit: "rows per day", RetryAfter: retryAfter}
+		}
+	}
+
+	l.usage[key] = append(kept, usage{at: now, rows: rows})
+	return nil
+}
+
+// Gives back a run Acquire recorded for tenant/dataType that never started (e.g. its
+// queue was full), so a retry isn't refused for it. Removes the newest matching entry.
+func (l *Limiter) Release(tenant, dataType string, rows int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := tenant + "/" + dataType
+	entries := l.usage[key]
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].rows == rows {
+			l.usage[key] = append(entries[:i:i], entries[i+1:]...)
+			return
+		}
+	}
+}
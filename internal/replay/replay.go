@@ -0,0 +1,175 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+//   Purpose: Captures the Databricks API traffic of a real run (BLADE_RECORD) and
+//   serves it back later (BLADE_REPLAY), so demos and regression tests can run the
+//   full CLI flow on a disconnected network without a workspace.
+
+//   Cassette Format:
+//   - JSON lines, one Interaction per API call, in the order they were made
+//   - Credentials are never written: request headers aren't recorded at all
+//   - Request/response bodies are kept verbatim, so a cassette contains the ingested data
+
+// A single recorded API call.
+type Interaction struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Query        string `json:"query,omitempty"`
+	RequestBody  string `json:"requestBody,omitempty"`
+	Status       int    `json:"status"`
+	ContentType  string `json:"contentType,omitempty"`
+	ResponseBody string `json:"responseBody"`
+}
+
+// Forwards every request to the wrapped transport and appends the exchange to a cassette.
+type Recorder struct {
+	next http.RoundTripper
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Opens (truncating) the cassette at path and records the calls made through next.
+func NewRecorder(path string, next http.RoundTripper) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette %s: %w", path, err)
+	}
+	return &Recorder{next: next, file: file}, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		requestBody = body
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		// Transport errors aren't recorded; replay has nothing meaningful to return for them
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	line, err := json.Marshal(Interaction{
+		Method:       req.Method,
+		Path:         req.URL.Path,
+		Query:        req.URL.RawQuery,
+		RequestBody:  string(requestBody),
+		Status:       resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: string(responseBody),
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to record interaction: %w", err)
+	}
+	return resp, nil
+}
+
+// Serves recorded responses instead of calling the workspace.
+//
+// Requests are matched by method and path, in recorded order: the n-th
+// POST /api/2.0/sql/statements/ gets the n-th recorded response for it. Bodies
+// aren't compared because batch IDs and timestamps differ on every run.
+type Replayer struct {
+	mu      sync.Mutex
+	pending map[string][]Interaction
+}
+
+// Loads the cassette at path.
+func NewReplayer(path string) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette %s: %w", path, err)
+	}
+	defer file.Close()
+
+	replayer := &Replayer{pending: make(map[string][]Interaction)}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s line %d: %w", path, line, err)
+		}
+		key := interaction.Method + " " + interaction.Path
+		replayer.pending[key] = append(replayer.pending[key], interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s: %w", path, err)
+	}
+	return replayer, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	key := req.Method + " " + req.URL.Path
+	r.mu.Lock()
+	queue := r.pending[key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("replay: no recorded response left for %s", key)
+	}
+	interaction := queue[0]
+	r.pending[key] = queue[1:]
+	r.mu.Unlock()
+
+	header := make(http.Header)
+	if interaction.ContentType != "" {
+		header.Set("Content-Type", interaction.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(interaction.ResponseBody))),
+		ContentLength: int64(len(interaction.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// Number of recorded interactions not yet replayed.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	remaining := 0
+	for _, queue := range r.pending {
+		remaining += len(queue)
+	}
+	return remaining
+}
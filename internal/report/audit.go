@@ -0,0 +1,66 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+
+	"databricks-blade-poc/internal/databricks"
+)
+
+func init() {
+	Register("audit", func(opts Options) (Reporter, error) {
+		if opts.Audit == nil {
+			return nil, fmt.Errorf("audit reporter needs a Databricks connection")
+		}
+		return &auditReporter{audit: opts.Audit, operator: localOperator()}, nil
+	})
+}
+
+// Appends one row per run to the Databricks audit table.
+type auditReporter struct {
+	audit    AuditWriter
+	operator string
+}
+
+func (a *auditReporter) Name() string { return "audit" }
+
+func (a *auditReporter) Report(ctx context.Context, r *Report) error {
+	host, _ := os.Hostname()
+	record := databricks.AuditRecord{
+		RunID:      r.RunID,
+		DataType:   r.DataType,
+		Format:     r.Format,
+		Tenant:     r.Tenant,
+		Operator:   a.operator,
+		Host:       host,
+		SourcePath: r.SourcePath,
+		SourceHash: r.SourceHash,
+		Status:     r.Status(),
+		Error:      r.Error,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+	}
+	if record.StartedAt.IsZero() {
+		record.StartedAt = r.FinishedAt
+	}
+	if result := r.Result; result != nil {
+		record.TableName = result.TableName
+		record.RowsIngested = result.RowsIngested
+		record.RowsRejected = result.RowsRejected
+		record.Duration = result.Duration
+		if batchID, ok := result.Metadata["batch_id"].(string); ok {
+			record.BatchID = batchID
+		}
+	}
+	return a.audit.RecordAudit(ctx, record)
+}
+
+// The OS user running the tool ($USER when it can't be looked up).
+func localOperator() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/databricks"
+)
+
+func init() {
+	Register("console", func(opts Options) (Reporter, error) {
+		out := opts.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		return &consoleReporter{out: out}, nil
+	})
+}
+
+// Prints the results banner.
+type consoleReporter struct {
+	out io.Writer
+}
+
+func (c *consoleReporter) Name() string { return "console" }
+
+func (c *consoleReporter) Report(ctx context.Context, r *Report) error {
+	// Formatted Output Design:
+	// - Header/Footer: 50-character equals sign borders
+	// - Separator: Dashed line under title
+	// - Key Metrics: Table name, status, row count, timing
+	// - Source Indicator: Clearly marks mock data, files and the live BLADE API
+	var b strings.Builder
+	b.WriteString("\n" + strings.Repeat("=", 50) + "\n")
+	b.WriteString("BLADE INGESTION RESULTS")
+	b.WriteString("\n" + strings.Repeat("-", 50) + "\n")
+	if result := r.Result; result != nil {
+		fmt.Fprintf(&b, "Table: %s\n", result.TableName)
+		fmt.Fprintf(&b, "Status: %s\n", result.Status)
+		fmt.Fprintf(&b, "Rows Ingested: %d\n", result.RowsIngested)
+		if result.RowsSkipped > 0 {
+			fmt.Fprintf(&b, "Duplicates Skipped: %d\n", result.RowsSkipped)
+		}
+		if result.RowsRejected > 0 {
+			fmt.Fprintf(&b, "Records Rejected: %d\n", result.RowsRejected)
+		}
+		if manifest := result.Manifest; manifest != nil && manifest.Action != databricks.ManifestLoad {
+			fmt.Fprintf(&b, "Batch Manifest: %s batch %s (was %s", manifest.Action, manifest.BatchID, manifest.PreviousStatus)
+			if manifest.CommittedChunks > 0 {
+				fmt.Fprintf(&b, ", kept %d committed chunk(s)", manifest.CommittedChunks)
+			}
+			b.WriteString(")\n")
+		}
+		fmt.Fprintf(&b, "Duration: %s\n", result.Duration)
+		if result.ThrottledRequests > 0 {
+			fmt.Fprintf(&b, "Throttled: %s (%d rate-limited API call(s))\n", result.ThrottleTime.Round(time.Millisecond), result.ThrottledRequests)
+		}
+	} else {
+		fmt.Fprintf(&b, "Status: %s\n", r.Status())
+	}
+	fmt.Fprintf(&b, "Run ID: %s (log: %s)\n", r.RunID, r.LogPath)
+	if r.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", r.Error)
+	}
+	if result := r.Result; result != nil {
+		for _, route := range result.Routes {
+			status := "failed"
+			if route.Result != nil {
+				status = route.Result.Status
+			}
+			fmt.Fprintf(&b, "Route %s -> %s: %d record(s), %s\n", route.Marking, route.Table, route.Records, status)
+		}
+		if len(result.Chunks) > 1 {
+			failed := 0
+			for _, chunk := range result.Chunks {
+				if chunk.Error != "" {
+					failed++
+				}
+			}
+			fmt.Fprintf(&b, "Insert Chunks: %d (%d failed)\n", len(result.Chunks), failed)
+			for _, chunk := range result.Chunks {
+				if chunk.Error != "" {
+					fmt.Fprintf(&b, "  chunk %d (from record %d): %s\n", chunk.Index, chunk.Offset+1, chunk.Error)
+				}
+			}
+		}
+		for _, validation := range result.Validations {
+			status := "PASS"
+			if !validation.Passed {
+				status = "FAIL"
+			}
+			fmt.Fprintf(&b, "Validation [%s] %s (%s): %d violation(s)\n", status, validation.Name, validation.Severity, validation.Violations)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(&b, "Warning [%s] %s\n", warning.Code, warning.Message)
+		}
+		if check := result.RowCount; check != nil && check.Error == "" {
+			status := "verified"
+			if !check.Verified {
+				status = fmt.Sprintf("MISMATCH (%+d)", check.Discrepancy)
+			}
+			fmt.Fprintf(&b, "Row Count: %s, %d -> %d with %d inserted\n", status, check.Before, check.After, check.Inserted)
+		}
+		if v := result.Verification; v != nil {
+			fmt.Fprintf(&b, "Sample Verification: %d sampled, %d missing, %d mismatch(es)\n", v.Sampled, len(v.Missing), len(v.Mismatches))
+			for _, m := range v.Mismatches {
+				fmt.Fprintf(&b, "  %s %s: source %q, stored %q\n", m.ItemID, m.Field, m.Source, m.Stored)
+			}
+			if v.Error != "" {
+				fmt.Fprintf(&b, "  verification error: %s\n", v.Error)
+			}
+		}
+	}
+	// - COPY INTO runs name the Volume path they loaded instead, live mode runs the API endpoint
+	if source, ok := copySource(r); ok {
+		fmt.Fprintf(&b, "Source: BLADE files (%s)", source)
+	} else if r.SourceURL != "" {
+		fmt.Fprintf(&b, "Source: BLADE API (%s)", r.SourceURL)
+	} else {
+		b.WriteString("Source: BLADE (mock)")
+	}
+	b.WriteString("\n" + strings.Repeat("=", 50) + "\n")
+
+	_, err := io.WriteString(c.out, b.String())
+	return err
+}
+
+// Returns the Volume path a COPY INTO run loaded, if the run was one.
+func copySource(r *Report) (string, bool) {
+	if r.Result == nil {
+		return "", false
+	}
+	source, ok := r.Result.Metadata["copy_source"].(string)
+	return source, ok && source != ""
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/dictionary"
+)
+
+func init() {
+	Register("dictionary", func(opts Options) (Reporter, error) {
+		return &dictionaryReporter{dir: filepath.Join(reportDir(opts), "dictionary"), columns: opts.Columns}, nil
+	})
+}
+
+// Reads table columns and comments from the warehouse; implemented by *databricks.Client.
+type ColumnDescriber interface {
+	DescribeColumns(ctx context.Context, tableName string) ([]databricks.ColumnInfo, error)
+}
+
+// Rewrites {dir}/dictionary/{table}.md and .csv for every table the run wrote,
+// so the files always describe the tables as of the latest run.
+type dictionaryReporter struct {
+	dir     string
+	columns ColumnDescriber // nil: document from the mapping's semantics only
+}
+
+func (d *dictionaryReporter) Name() string { return "dictionary" }
+
+func (d *dictionaryReporter) Report(ctx context.Context, r *Report) error {
+	if len(r.Tables) == 0 {
+		return fmt.Errorf("run %s has no target tables", r.RunID)
+	}
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create dictionary directory %s: %w", d.dir, err)
+	}
+
+	// Namespace:
+	// - Tables are documented under the run's catalog.schema (taken from TargetTable)
+	// - Semantics and source fields describe the main table only; child tables get their columns
+	namespace := ""
+	if i := strings.LastIndex(r.TargetTable, "."); i >= 0 {
+		namespace = r.TargetTable[:i+1]
+	}
+	var errs []error
+	for i, tableName := range r.Tables {
+		var columns []databricks.ColumnInfo
+		if d.columns != nil {
+			described, err := d.columns.DescribeColumns(ctx, tableName)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			columns = described
+		}
+		var semantics databricks.TableSemantics
+		var fields []dictionary.Field
+		if i == 0 {
+			if r.Semantics != nil {
+				semantics = *r.Semantics
+			}
+			fields = r.SourceSchema
+		}
+		table := dictionary.Build(namespace+tableName, columns, semantics, fields)
+		table.RunID = r.RunID
+
+		for ext, write := range map[string]func(io.Writer, *dictionary.Table) error{
+			".md":  dictionary.WriteMarkdown,
+			".csv": dictionary.WriteCSV,
+		} {
+			if err := writeDictionary(filepath.Join(d.dir, tableName+ext), table, write); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func writeDictionary(path string, table *dictionary.Table, write func(io.Writer, *dictionary.Table) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create data dictionary %s: %w", path, err)
+	}
+	defer file.Close()
+	if err := write(file, table); err != nil {
+		return fmt.Errorf("failed to write data dictionary %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats Encode writes a report in.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+func init() {
+	Register("json", func(opts Options) (Reporter, error) {
+		return &fileReporter{name: "json", dir: reportDir(opts), ext: ".json", write: writeJSON}, nil
+	})
+	Register("html", func(opts Options) (Reporter, error) {
+		return &fileReporter{name: "html", dir: reportDir(opts), ext: ".html", write: writeHTML}, nil
+	})
+}
+
+func reportDir(opts Options) string {
+	if opts.Dir == "" {
+		return "reports"
+	}
+	return opts.Dir
+}
+
+// Writes one file per run to {dir}/{runID}{ext}.
+type fileReporter struct {
+	name  string
+	dir   string
+	ext   string
+	write func(f *os.File, r *Report) error
+}
+
+func (f *fileReporter) Name() string { return f.name }
+
+func (f *fileReporter) Report(ctx context.Context, r *Report) error {
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create report directory %s: %w", f.dir, err)
+	}
+	path := filepath.Join(f.dir, r.RunID+f.ext)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report %s: %w", path, err)
+	}
+	defer file.Close()
+	if err := f.write(file, r); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", path, err)
+	}
+	return nil
+}
+
+// Serializes the report; IngestionResult.Error is an interface, so it is dropped in favor of Report.Error.
+func marshalReport(r *Report) ([]byte, error) {
+	out := *r
+	if r.Result != nil {
+		result := *r.Result
+		if result.Error != nil && out.Error == "" {
+			out.Error = result.Error.Error()
+		}
+		result.Error = nil
+		out.Result = &result
+	}
+	return json.MarshalIndent(&out, "", "  ")
+}
+
+// Writes the report to w as JSON or YAML, with the fields of the json reporter's files.
+//   - YAML keeps the JSON field names and their order (durations stay in nanoseconds)
+func Encode(w io.Writer, r *Report, format string) error {
+	data, err := marshalReport(r)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatJSON:
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case FormatYAML:
+		// - JSON is YAML, so the document parses as is; its flow style and quoting are
+		//   cleared so it is written as block YAML
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		blockStyle(&doc)
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&doc); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+	return fmt.Errorf("unsupported report format %q (supported: %s, %s)", format, FormatJSON, FormatYAML)
+}
+
+// Clears the styles of a node and its children; the encoder still quotes strings that
+// would read as another type ("123", "true").
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func writeJSON(f *os.File, r *Report) error {
+	data, err := marshalReport(r)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>BLADE ingestion {{.RunID}}</title>
+<style>body{font-family:sans-serif;margin:2em}table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:4px 8px;text-align:left}.FAIL{color:#b00}.PASS{color:#070}</style>
+</head>
+<body>
+<h1>BLADE Ingestion Results</h1>
+<table>
+<tr><th>Run ID</th><td>{{.RunID}}</td></tr>
+<tr><th>Data Type</th><td>{{.DataType}} ({{.Format}})</td></tr>
+{{with .Tenant}}<tr><th>Tenant</th><td>{{.}}</td></tr>{{end}}
+<tr><th>Status</th><td>{{.Status}}</td></tr>
+{{with .Result}}<tr><th>Table</th><td>{{.TableName}}</td></tr>
+<tr><th>Rows Ingested</th><td>{{.RowsIngested}}</td></tr>
+{{with .RowsSkipped}}<tr><th>Duplicates Skipped</th><td>{{.}}</td></tr>{{end}}
+{{with .RowsRejected}}<tr><th>Records Rejected</th><td>{{.}}</td></tr>{{end}}
+{{with .Manifest}}<tr><th>Batch Manifest</th><td>{{.Action}} batch {{.BatchID}}{{with .PreviousStatus}} (was {{.}}){{end}}{{with .CommittedChunks}}, kept {{.}} committed chunk(s){{end}}</td></tr>{{end}}
+{{with .RowCount}}{{if not .Error}}<tr><th>Row Count</th>{{if .Verified}}<td class="PASS">verified{{else}}<td class="FAIL">mismatch ({{.Discrepancy}}){{end}}, {{.Before}} &rarr; {{.After}} with {{.Inserted}} inserted</td></tr>{{end}}{{end}}
+<tr><th>Duration</th><td>{{.Duration}}</td></tr>
+{{with .ThrottledRequests}}<tr><th>Throttled</th><td>{{$.Result.ThrottleTime}} ({{.}} rate-limited API calls)</td></tr>{{end}}{{end}}
+<tr><th>Finished</th><td>{{.FinishedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+{{with .Error}}<tr><th>Error</th><td class="FAIL">{{.}}</td></tr>{{end}}
+</table>
+{{with .Result}}{{if .Validations}}
+<h2>Validations</h2>
+<table>
+<tr><th>Rule</th><th>Severity</th><th>Result</th><th>Violations</th></tr>
+{{range .Validations}}<tr><td>{{.Name}}</td><td>{{.Severity}}</td>{{if .Passed}}<td class="PASS">PASS</td>{{else}}<td class="FAIL">FAIL</td>{{end}}<td>{{.Violations}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Warnings}}
+<h2>Warnings</h2>
+<table>
+<tr><th>Code</th><th>Message</th></tr>
+{{range .Warnings}}<tr><td>{{.Code}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Verification}}
+<h2>Sample Verification</h2>
+<p>{{.Sampled}} sampled, {{len .Missing}} missing, {{len .Mismatches}} mismatch(es)</p>
+{{if .Mismatches}}<table>
+<tr><th>Item</th><th>Field</th><th>Source</th><th>Stored</th></tr>
+{{range .Mismatches}}<tr><td>{{.ItemID}}</td><td>{{.Field}}</td><td>{{.Source}}</td><td>{{.Stored}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+func writeHTML(f *os.File, r *Report) error {
+	return htmlTemplate.Execute(f, r)
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"context"
+	"fmt"
+
+	"databricks-blade-poc/internal/databricks"
+)
+
+func init() {
+	Register("history", func(opts Options) (Reporter, error) {
+		if opts.History == nil {
+			return nil, fmt.Errorf("history reporter needs a Databricks connection")
+		}
+		return &historyReporter{history: opts.History}, nil
+	})
+}
+
+// Appends one row per run to the Databricks run history table.
+type historyReporter struct {
+	history HistoryWriter
+}
+
+func (h *historyReporter) Name() string { return "history" }
+
+func (h *historyReporter) Report(ctx context.Context, r *Report) error {
+	entry := databricks.RunHistoryEntry{
+		RunID:      r.RunID,
+		DataType:   r.DataType,
+		Format:     r.Format,
+		Tenant:     r.Tenant,
+		Status:     r.Status(),
+		Error:      r.Error,
+		FinishedAt: r.FinishedAt,
+	}
+	if result := r.Result; result != nil {
+		entry.TableName = result.TableName
+		entry.RowsIngested = result.RowsIngested
+		entry.Duration = result.Duration
+		if batchID, ok := result.Metadata["batch_id"].(string); ok {
+			entry.BatchID = batchID
+		}
+	}
+	return h.history.RecordRunHistory(ctx, entry)
+}
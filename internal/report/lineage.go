@@ -0,0 +1,79 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"databricks-blade-poc/internal/lineage"
+)
+
+func init() {
+	Register("lineage", func(opts Options) (Reporter, error) {
+		return &lineageReporter{
+			url:       opts.LineageURL,
+			dir:       reportDir(opts),
+			namespace: opts.Namespace,
+			client:    &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	})
+}
+
+// Emits an OpenLineage run event with column-level lineage for each run.
+//   - POSTed to the configured endpoint, or written to {dir}/{runID}.lineage.json
+type lineageReporter struct {
+	url       string
+	dir       string
+	namespace string
+	client    *http.Client
+}
+
+func (l *lineageReporter) Name() string { return "lineage" }
+
+func (l *lineageReporter) Report(ctx context.Context, r *Report) error {
+	if r.TargetTable == "" {
+		return fmt.Errorf("run %s has no target table", r.RunID)
+	}
+	event := lineage.NewRunEvent(lineage.Flow{
+		RunID:           lineage.RunUUID(r.RunID),
+		JobName:         "blade_ingest." + r.DataType,
+		SourceNamespace: "blade",
+		SourceName:      r.SourcePath,
+		SourceFields:    r.SourceFields,
+		TargetNamespace: l.namespace,
+		TargetName:      r.TargetTable,
+		Failed:          r.Status() == "failed",
+		EventTime:       r.FinishedAt,
+	})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if l.url == "" {
+		if err := os.MkdirAll(l.dir, 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(l.dir, r.RunID+".lineage.json"), payload, 0o644)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post lineage event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("lineage endpoint returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/dictionary"
+	"databricks-blade-poc/internal/timeline"
+)
+
+//   Purpose: Delivers the outcome of an ingestion run to one or more destinations
+//   (console, JSON/HTML files, webhook, history and audit tables, lineage, data
+//   dictionary, statement timeline) selected via BLADE_REPORTERS.
+
+// Everything a reporter knows about a finished run.
+type Report struct {
+	RunID        string                      `json:"runId"`
+	DataType     string                      `json:"dataType"`
+	Format       string                      `json:"format"`
+	Tenant       string                      `json:"tenant,omitempty"`
+	LogPath      string                      `json:"logPath,omitempty"`
+	SourcePath   string                      `json:"sourcePath,omitempty"`
+	SourceURL    string                      `json:"sourceUrl,omitempty"`  // BLADE API endpoint of a live mode run
+	SourceHash   string                      `json:"sourceHash,omitempty"` // SHA-256 of the source (see databricks.SourceFileHash)
+	SourceFields []string                    `json:"-"`
+	TargetTable  string                      `json:"targetTable,omitempty"` // catalog.schema.table
+	Tables       []string                    `json:"-"`                     // every table the run writes, main table first
+	Semantics    *databricks.TableSemantics  `json:"-"`                     // mapping descriptions for the data dictionary
+	SourceSchema []dictionary.Field          `json:"-"`                     // source fields with inferred types
+	Timeline     *timeline.Export            `json:"-"`                     // statement start/end times of the run
+	StartedAt    time.Time                   `json:"startedAt"`
+	FinishedAt   time.Time                   `json:"finishedAt"`
+	Error        string                      `json:"error,omitempty"` // set when the run failed
+	Result       *databricks.IngestionResult `json:"result,omitempty"`
+}
+
+// Status of the run: the result's status, or "failed" when there is no result.
+func (r *Report) Status() string {
+	if r.Result != nil && r.Result.Status != "" {
+		return r.Result.Status
+	}
+	if r.Error != "" {
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Contract:
+// - Name: The identifier used in BLADE_REPORTERS (e.g. "console")
+// - Report: Delivers one run's report; errors are surfaced but never undo the run
+type Reporter interface {
+	Name() string
+	Report(ctx context.Context, r *Report) error
+}
+
+// Writes run history rows; implemented by *databricks.Client.
+type HistoryWriter interface {
+	RecordRunHistory(ctx context.Context, entry databricks.RunHistoryEntry) error
+}
+
+// Writes audit rows; implemented by *databricks.Client.
+type AuditWriter interface {
+	RecordAudit(ctx context.Context, record databricks.AuditRecord) error
+}
+
+// Settings shared by the reporter factories.
+type Options struct {
+	Out        io.Writer       // console output
+	Dir        string          // JSON/HTML report files are written here as {runID}.json / {runID}.html
+	WebhookURL string          // webhook reporter target
+	LineageURL string          // OpenLineage endpoint (e.g. http://marquez:5000/api/v1/lineage); events go to Dir when empty
+	Namespace  string          // OpenLineage namespace for the workspace (the Databricks host)
+	History    HistoryWriter   // history table reporter (nil when not connected)
+	Audit      AuditWriter     // audit table reporter (nil when not connected)
+	Columns    ColumnDescriber // data dictionary column comments (nil when not connected)
+	Gantt      bool            // timeline reporter also prints an ASCII Gantt chart to Out
+}
+
+// Builds a Reporter from the shared options.
+type Factory func(opts Options) (Reporter, error)
+
+// Reporter used when BLADE_REPORTERS is not set.
+const DefaultReporters = "console"
+
+var factories = map[string]Factory{}
+
+// Makes a reporter selectable via BLADE_REPORTERS. Later registrations replace earlier ones.
+func Register(name string, factory Factory) {
+	factories[strings.ToLower(name)] = factory
+}
+
+// Returns the registered reporter names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Builds the reporters named in a comma-separated list (e.g. "console,json,webhook").
+func New(list string, opts Options) ([]Reporter, error) {
+	if strings.TrimSpace(list) == "" {
+		list = DefaultReporters
+	}
+
+	var reporters []Reporter
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		factory, exists := factories[name]
+		if !exists {
+			return nil, fmt.Errorf("unsupported reporter %q (supported: %s)", name, strings.Join(Names(), ", "))
+		}
+		reporter, err := factory(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure %s reporter: %w", name, err)
+		}
+		reporters = append(reporters, reporter)
+	}
+	return reporters, nil
+}
+
+// Sends the report to every reporter; one failing reporter doesn't stop the others.
+func Publish(ctx context.Context, reporters []Reporter, r *Report) error {
+	var errs []error
+	for _, reporter := range reporters {
+		if err := reporter.Report(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("%s reporter: %w", reporter.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"databricks-blade-poc/internal/timeline"
+)
+
+func init() {
+	Register("timeline", func(opts Options) (Reporter, error) {
+		reporter := &timelineReporter{dir: reportDir(opts)}
+		if opts.Gantt {
+			reporter.gantt = opts.Out
+			if reporter.gantt == nil {
+				reporter.gantt = os.Stdout
+			}
+		}
+		return reporter, nil
+	})
+}
+
+// Width of the ASCII Gantt chart in columns.
+const ganttWidth = 60
+
+// Writes the run's statement timeline to {dir}/{runID}.timeline.json and, when
+// BLADE_TIMELINE_GANTT is set, prints it as an ASCII Gantt chart.
+type timelineReporter struct {
+	dir   string
+	gantt io.Writer // nil: no chart
+}
+
+func (t *timelineReporter) Name() string { return "timeline" }
+
+func (t *timelineReporter) Report(ctx context.Context, r *Report) error {
+	if r.Timeline == nil {
+		return fmt.Errorf("run %s has no timeline", r.RunID)
+	}
+	data, err := json.MarshalIndent(r.Timeline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create report directory %s: %w", t.dir, err)
+	}
+	path := filepath.Join(t.dir, r.RunID+".timeline.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write timeline %s: %w", path, err)
+	}
+	if t.gantt != nil {
+		fmt.Fprintf(t.gantt, "\nStatement timeline (run %s):\n", r.RunID)
+		return timeline.WriteGantt(t.gantt, *r.Timeline, ganttWidth)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func init() {
+	Register("webhook", func(opts Options) (Reporter, error) {
+		if opts.WebhookURL == "" {
+			return nil, fmt.Errorf("BLADE_REPORT_WEBHOOK_URL is not set")
+		}
+		return &webhookReporter{url: opts.WebhookURL, client: &http.Client{Timeout: 10 * time.Second}}, nil
+	})
+}
+
+// POSTs the JSON report to a URL (e.g. a chat or incident webhook).
+type webhookReporter struct {
+	url    string
+	client *http.Client
+}
+
+func (w *webhookReporter) Name() string { return "webhook" }
+
+func (w *webhookReporter) Report(ctx context.Context, r *Report) error {
+	payload, err := marshalReport(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package runlog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+//   Purpose: Gives every ingestion run its own log stream so one failed run can be
+//   troubleshot without grepping a shared log.
+
+//   Behavior:
+//   - Each run writes to {logDir}/{runID}.log
+//   - Lines are tagged with the run ID and optionally mirrored (e.g. to stderr)
+//   - The Run travels in the context, so deep code paths log to the right run
+type Run struct {
+	ID     string
+	Path   string
+	file   *os.File
+	logger *log.Logger
+}
+
+type contextKey struct{}
+
+var runIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Generates a sortable, collision-resistant run identifier (e.g. "20240115T103000-9f86d081").
+func NewRunID() string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Opens {logDir}/{runID}.log and returns a Run whose logger writes there and to mirror (if non-nil).
+func Start(logDir, runID string, mirror io.Writer) (*Run, error) {
+	if !runIDPattern.MatchString(runID) {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+	if err := os.MkdirAll(logDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory %s: %w", logDir, err)
+	}
+
+	path := filepath.Join(logDir, runID+".log")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run log %s: %w", path, err)
+	}
+
+	var out io.Writer = file
+	if mirror != nil {
+		out = io.MultiWriter(file, mirror)
+	}
+
+	return &Run{
+		ID:     runID,
+		Path:   path,
+		file:   file,
+		logger: log.New(out, fmt.Sprintf("[run %s] ", runID), log.LstdFlags),
+	}, nil
+}
+
+func (r *Run) Printf(format string, args ...interface{}) {
+	r.logger.Printf(format, args...)
+}
+
+func (r *Run) Close() error {
+	return r.file.Close()
+}
+
+// Attaches a run to the context so Printf calls further down log to its stream.
+func WithRun(ctx context.Context, run *Run) context.Context {
+	return context.WithValue(ctx, contextKey{}, run)
+}
+
+// Returns the run attached to ctx, or nil when none is.
+func FromContext(ctx context.Context) *Run {
+	run, _ := ctx.Value(contextKey{}).(*Run)
+	return run
+}
+
+// Logs to the context's run stream, falling back to the standard logger.
+func Printf(ctx context.Context, format string, args ...interface{}) {
+	if run := FromContext(ctx); run != nil {
+		run.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// Reads back the log of a run, complete or so far (status --log and GET /ingestions/{id}/log).
+func ReadLog(logDir, runID string) ([]byte, error) {
+	if !runIDPattern.MatchString(runID) {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+	return os.ReadFile(filepath.Join(logDir, runID+".log"))
+}
@@ -0,0 +1,224 @@
+package runstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"databricks-blade-poc/internal/databricks"
+)
+
+//   Purpose: Local state store for ingestion runs, so past runs can be listed and
+//   filtered (CLI history, REST /ingestions) without scraping log files.
+
+//   Behavior:
+//   - One JSON document per run at {dir}/{id}.json, rewritten on every status change
+//   - Writes go through a temp file + rename so readers never see a torn record
+//   - The directory is BLADE_STATE_DIR; the other state files (query cache, watch ledger,
+//     warehouse names) live in its cache subdirectory (see CachePath), and List skips any
+//     that are still next to the runs
+
+// Run states, shared with the REST API's Ingestion schema.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusPartial   = "partial" // stopped by the run budget (--max-runtime)
+)
+
+// A persisted ingestion run; the JSON shape matches the API's Ingestion schema.
+type Record struct {
+	ID          string                      `json:"id"`
+	DataType    string                      `json:"dataType"`
+	Format      string                      `json:"format"`
+	Tenant      string                      `json:"tenant,omitempty"`
+	Status      string                      `json:"status"`
+	SubmittedAt time.Time                   `json:"submittedAt"`
+	StartedAt   *time.Time                  `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time                  `json:"finishedAt,omitempty"`
+	Error       string                      `json:"error,omitempty"`
+	Remediation *databricks.Remediation     `json:"remediation,omitempty"` // fix for a recognized workspace failure
+	Result      *databricks.IngestionResult `json:"result,omitempty"`
+}
+
+// Narrows List results; zero values match everything. Since/Until bound SubmittedAt.
+type Filter struct {
+	DataType string
+	Status   string
+	Tenant   string
+	Since    time.Time
+	Until    time.Time
+}
+
+func (f Filter) matches(r *Record) bool {
+	return (f.DataType == "" || r.DataType == f.DataType) &&
+		(f.Status == "" || r.Status == f.Status) &&
+		(f.Tenant == "" || r.Tenant == f.Tenant) &&
+		(f.Since.IsZero() || !r.SubmittedAt.Before(f.Since)) &&
+		(f.Until.IsZero() || r.SubmittedAt.Before(f.Until))
+}
+
+// Default and maximum page sizes for List.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// File-backed run store; safe for concurrent use within one process.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// Opens (creating if needed) the run store under dir.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create run store %s: %w", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Subdirectory of the state directory holding the state files that aren't runs.
+const CacheDir = "cache"
+
+// Returns the path of a cache or ledger file of the state directory dir ({dir}/cache/{name}).
+//   - A file an older version left at {dir}/{name} is moved there on first use
+func CachePath(dir, name string) string {
+	path := filepath.Join(dir, CacheDir, name)
+	legacy := filepath.Join(dir, name)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(legacy); err == nil && os.MkdirAll(filepath.Dir(path), 0o755) == nil {
+			os.Rename(legacy, path)
+		}
+	}
+	return path
+}
+
+// Inserts or replaces the record with the same ID.
+func (s *Store) Save(record *Record) error {
+	if !idPattern.MatchString(record.ID) {
+		return fmt.Errorf("invalid run ID %q", record.ID)
+	}
+	// IngestionResult.Error is an interface and doesn't serialize; the message lives in Record.Error
+	stored := *record
+	if stored.Result != nil {
+		result := *stored.Result
+		if result.Error != nil && stored.Error == "" {
+			stored.Error = result.Error.Error()
+		}
+		result.Error = nil
+		stored.Result = &result
+	}
+
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run %s: %w", record.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := filepath.Join(s.dir, record.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write run %s: %w", record.ID, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write run %s: %w", record.ID, err)
+	}
+	return nil
+}
+
+// Loads one run; the bool is false when no run has that ID.
+func (s *Store) Get(id string) (*Record, bool, error) {
+	if !idPattern.MatchString(id) {
+		return nil, false, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(filepath.Join(s.dir, id+".json"))
+}
+
+// Returns one page of runs matching filter, newest first, and the token for the next page ("" on the last page).
+//   - pageToken: "" for the first page, otherwise a value returned by a previous call
+//   - limit: <= 0 uses DefaultPageSize, capped at MaxPageSize
+func (s *Store) List(filter Filter, pageToken string, limit int) ([]*Record, string, error) {
+	offset := 0
+	if pageToken != "" {
+		parsed, err := strconv.Atoi(pageToken)
+		if err != nil || parsed < 0 {
+			return nil, "", fmt.Errorf("invalid page token %q", pageToken)
+		}
+		offset = parsed
+	}
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	s.mu.Lock()
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		s.mu.Unlock()
+		return nil, "", err
+	}
+	var matched []*Record
+	for _, path := range paths {
+		record, found, err := s.read(path)
+		if err != nil {
+			s.mu.Unlock()
+			return nil, "", err
+		}
+		// - Other state files share the directory (query cache, watch ledger, ...); a run is
+		//   stored under its own ID
+		if found && record.ID+".json" == filepath.Base(path) && filter.matches(record) {
+			matched = append(matched, record)
+		}
+	}
+	s.mu.Unlock()
+
+	// Newest first; the ID breaks ties so pages are stable between calls
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].SubmittedAt.Equal(matched[j].SubmittedAt) {
+			return matched[i].SubmittedAt.After(matched[j].SubmittedAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	if offset >= len(matched) {
+		return nil, "", nil
+	}
+	end := offset + limit
+	if end >= len(matched) {
+		return matched[offset:], "", nil
+	}
+	return matched[offset:end], strconv.Itoa(end), nil
+}
+
+func (s *Store) read(path string) (*Record, bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read run %s: %w", path, err)
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, false, fmt.Errorf("corrupt run record %s: %w", strings.TrimSuffix(filepath.Base(path), ".json"), err)
+	}
+	return &record, true, nil
+}
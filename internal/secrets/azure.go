@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+//   Azure Key Vault (azure-kv://<vault>/<secret>[/<version>]):
+//   - <vault> is the vault's name (<vault>.vault.azure.net) or its full host name for
+//     other clouds, e.g. azure-kv://blade.vault.usgovcloudapi.net/databricks-token
+//   - Token: a service principal's client credentials when AZURE_TENANT_ID,
+//     AZURE_CLIENT_ID and AZURE_CLIENT_SECRET are set (AZURE_AUTHORITY_HOST, default
+//     https://login.microsoftonline.com), otherwise the VM's managed identity
+//     (AZURE_CLIENT_ID picks a user-assigned one)
+//   - BLADE_SECRETS_AZURE_ENDPOINT replaces https://<vault host>, e.g. for a private endpoint
+
+// Key Vault REST API version.
+const keyVaultAPIVersion = "7.4"
+
+// Instance metadata endpoint issuing managed identity tokens.
+const managedIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+func init() {
+	Register(azureBackend{})
+}
+
+type azureBackend struct{}
+
+func (azureBackend) Scheme() string {
+	return "azure-kv"
+}
+
+func (azureBackend) Fetch(ctx context.Context, name string) (string, error) {
+	vault, secret, ok := strings.Cut(name, "/")
+	if !ok || vault == "" || secret == "" {
+		return "", fmt.Errorf("use azure-kv://<vault>/<secret>[/<version>]")
+	}
+	if !strings.Contains(vault, ".") {
+		vault += ".vault.azure.net"
+	}
+	// - Tokens are issued for the vault service of the vault's cloud (https://vault.azure.net)
+	resource := "https://" + vault[strings.Index(vault, ".")+1:]
+	endpoint := os.Getenv("BLADE_SECRETS_AZURE_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://" + vault
+	}
+
+	token, err := azureToken(ctx, resource)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an Azure token for %s: %w", resource, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/secrets/%s?api-version=%s", strings.TrimRight(endpoint, "/"), secret, keyVaultAPIVersion), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := decodeResponse(resp, &body); err != nil {
+		return "", err
+	}
+	return body.Value, nil
+}
+
+// Returns an access token for resource: client credentials when configured, otherwise
+// the managed identity.
+func azureToken(ctx context.Context, resource string) (string, error) {
+	tenant, clientID, clientSecret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET")
+	var req *http.Request
+	var err error
+	if tenant != "" && clientID != "" && clientSecret != "" {
+		authority := strings.TrimRight(os.Getenv("AZURE_AUTHORITY_HOST"), "/")
+		if authority == "" {
+			authority = "https://login.microsoftonline.com"
+		}
+		form := url.Values{"grant_type": {"client_credentials"}, "client_id": {clientID}, "client_secret": {clientSecret}, "scope": {resource + "/.default"}}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/oauth2/v2.0/token", authority, url.PathEscape(tenant)), strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		query := url.Values{"api-version": {"2018-02-01"}, "resource": {resource}}
+		if clientID != "" {
+			query.Set("client_id", clientID)
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, managedIdentityEndpoint+"?"+query.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata", "true")
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil && req.Header.Get("Metadata") == "true" {
+		return "", fmt.Errorf("no managed identity answered (off Azure, set AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET): %w", err)
+	}
+	if err != nil {
+		return "", err
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := decodeResponse(resp, &body); err != nil {
+		return "", err
+	}
+	return body.AccessToken, nil
+}
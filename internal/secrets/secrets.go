@@ -0,0 +1,167 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//   Purpose: On shared hosts a .env holding DATABRICKS_TOKEN is a credential on disk for
+//   anyone who can read it. A credential setting may instead hold a reference to a secret
+//   store, resolved when the client authenticates, so only the reference is written down.
+
+//   References (any credential setting: DATABRICKS_TOKEN, DATABRICKS_CLIENT_ID,
+//   DATABRICKS_CLIENT_SECRET, ARM_CLIENT_SECRET, or a profile's token, clientId,
+//   clientSecret or azureClientSecret):
+//   - aws-sm://<secret name or ARN>[#key]: AWS Secrets Manager (see aws.go)
+//   - azure-kv://<vault>/<secret>[/<version>]: Azure Key Vault (see azure.go)
+//   - vault://<API path>[#key]: HashiCorp Vault, KV v1 or v2 (see vault.go)
+//   - #key picks one field of a JSON object secret; without it an object holding a single
+//     field resolves to that field, one with several is refused, and any other secret is
+//     used whole
+//   - Anything else is a plain value and returned unchanged
+
+// Contract:
+// - Scheme: The reference prefix the backend resolves, without "://" (e.g. "vault")
+// - Fetch: Returns the secret named by the reference's remainder (before any #key)
+type Backend interface {
+	Scheme() string
+	Fetch(ctx context.Context, name string) (string, error)
+}
+
+// How long a secret store may take to answer.
+const fetchTimeout = 30 * time.Second
+
+var (
+	backends = map[string]Backend{}
+
+	// Each reference is fetched once per process, however many times it's resolved.
+	cacheMu sync.Mutex
+	cache   = map[string]string{}
+
+	httpClient = &http.Client{Timeout: fetchTimeout}
+)
+
+// Makes a backend resolve references with its scheme. Later registrations replace earlier ones.
+func Register(backend Backend) {
+	backends[backend.Scheme()] = backend
+}
+
+// Returns the registered schemes in sorted order.
+func Schemes() []string {
+	schemes := make([]string, 0, len(backends))
+	for scheme := range backends {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Reports whether a value is a secret store reference rather than a plain value.
+func IsReference(value string) bool {
+	scheme, _, ok := strings.Cut(value, "://")
+	return ok && backends[scheme] != nil
+}
+
+// Returns the secret a reference names, or the value itself when it isn't one.
+//   - setting: The variable the value came from, for the error messages
+func Resolve(ctx context.Context, setting, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if secret, ok := cache[value]; ok {
+		return secret, nil
+	}
+
+	scheme, rest, _ := strings.Cut(value, "://")
+	name, key, hasKey := strings.Cut(rest, "#")
+	if name == "" {
+		return "", fmt.Errorf("%s: secret reference %q names no secret", setting, value)
+	}
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	secret, err := backends[scheme].Fetch(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to read secret %s: %w", setting, value, err)
+	}
+	if hasKey {
+		if secret, err = jsonField(secret, key); err != nil {
+			return "", fmt.Errorf("%s: secret %s: %w", setting, value, err)
+		}
+	} else if secret, err = onlyField(secret); err != nil {
+		return "", fmt.Errorf("%s: secret %s: %w", setting, value, err)
+	}
+	if secret = strings.TrimSpace(secret); secret == "" {
+		return "", fmt.Errorf("%s: secret %s is empty", setting, value)
+	}
+	cache[value] = secret
+	return secret, nil
+}
+
+// Returns one string field of a JSON object secret.
+func jsonField(secret, key string) (string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("#%s needs a JSON object secret: %w", key, err)
+	}
+	value, ok := fields[key].(string)
+	if !ok {
+		return "", fmt.Errorf("has no string field %q", key)
+	}
+	return value, nil
+}
+
+// Returns the field of a JSON object secret holding a single field; other secrets are
+// returned as they are, and objects with several fields need a #key.
+func onlyField(secret string) (string, error) {
+	var fields map[string]interface{}
+	if json.Unmarshal([]byte(secret), &fields) != nil {
+		return secret, nil
+	}
+	if len(fields) != 1 {
+		return "", fmt.Errorf("holds the fields %s: pick one with #key", strings.Join(sortedFields(fields), ", "))
+	}
+	for key, value := range fields {
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+		return "", fmt.Errorf("field %q isn't a string", key)
+	}
+	return "", nil
+}
+
+func sortedFields(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reads a JSON response body into out, turning an error status into an error that
+// carries the store's message.
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var body struct {
+			Message string   `json:"message"`
+			Errors  []string `json:"errors"`
+			Error   struct {
+				Message string `json:"message"`
+			} `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		message := strings.Join(append([]string{body.Message, body.Error.Message, body.Description}, body.Errors...), " ")
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(message))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,66 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+//   HashiCorp Vault (vault://<API path>[#key]):
+//   - The path is the secret's API path below /v1/, e.g. vault://secret/data/blade#token for
+//     the "token" field of secret "blade" in the KV v2 engine mounted at secret/
+//   - VAULT_ADDR: The server, e.g. https://vault.example.com:8200
+//   - VAULT_TOKEN: The token to read with; VAULT_NAMESPACE (Vault Enterprise) is sent when set
+//   - KV v2 (data.data) and KV v1 (data) responses are both understood; the fields are
+//     handed to Resolve as a JSON object, so #key picks one (or the only one)
+
+func init() {
+	Register(vaultBackend{})
+}
+
+type vaultBackend struct{}
+
+func (vaultBackend) Scheme() string {
+	return "vault"
+}
+
+func (vaultBackend) Fetch(ctx context.Context, path string) (string, error) {
+	addr, token := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required for vault:// references")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := decodeResponse(resp, &body); err != nil {
+		return "", err
+	}
+
+	// - KV v2 nests the fields under data.data, next to data.metadata
+	fields := body.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = nested
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,338 @@
+// Package server implements the ingestion service's REST mode (api/openapi.yaml):
+// other systems queue BLADE ingestions over HTTP and poll their status instead of
+// shelling out to the CLI.
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"databricks-blade-poc/api"
+	"databricks-blade-poc/internal/databricks"
+	"databricks-blade-poc/internal/datasource"
+	"databricks-blade-poc/internal/jobs"
+	"databricks-blade-poc/internal/quota"
+	"databricks-blade-poc/internal/runlog"
+	"databricks-blade-poc/internal/runstore"
+)
+
+// Loads one accepted ingestion; record carries its ID, data type, format and tenant.
+//   - Called on a job worker goroutine; the result is stored on the record either way
+type IngestFunc = jobs.RunFunc
+
+// What the server ingests from and where it keeps track of runs.
+//   - Limiter: Optional per tenant/data type quotas (nil = unlimited)
+//   - DefaultTenant: Tenant of requests that don't name one (BLADE_TENANT)
+//   - Jobs: Worker pool and queue sizes of the job manager (0 = its defaults)
+//   - LogDir: Where the runs write their {runID}.log (BLADE_LOG_DIR), served by
+//     GET /ingestions/{id}/log ("" = logs aren't served)
+type Options struct {
+	Source        datasource.Provider
+	Toggles       datasource.Toggles
+	Store         *runstore.Store
+	Limiter       *quota.Limiter
+	Ingest        IngestFunc
+	DefaultTenant string
+	Jobs          jobs.Options
+	LogDir        string
+}
+
+// HTTP handler of the REST API; ingestions run on the job manager's workers, after
+// the request that queued them has returned.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+	jobs *jobs.Manager
+}
+
+// Creates the server, starts its job workers and registers its routes.
+func New(opts Options) *Server {
+	s := &Server{opts: opts, mux: http.NewServeMux(), jobs: jobs.NewManager(opts.Store, opts.Ingest, opts.Jobs)}
+	s.mux.HandleFunc("GET /healthz", s.health)
+	s.mux.HandleFunc("GET /datatypes", s.listDataTypes)
+	s.mux.HandleFunc("POST /ingest", s.createIngestion)
+	s.mux.HandleFunc("GET /ingestions", s.listIngestions)
+	s.mux.HandleFunc("GET /ingestions/{id}", s.getIngestion)
+	s.mux.HandleFunc("GET /ingestions/{id}/log", s.getIngestionLog)
+	s.mux.HandleFunc("GET /openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(api.Spec)
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Waits for queued and running ingestions to finish, or until ctx is done; then cancels those still running.
+func (s *Server) Close(ctx context.Context) error {
+	return s.jobs.Close(ctx)
+}
+
+// Schema DataType.
+type dataType struct {
+	DataType       string `json:"dataType"`
+	TableName      string `json:"tableName"`
+	Description    string `json:"description,omitempty"`
+	Enabled        bool   `json:"enabled"`
+	DisabledReason string `json:"disabledReason,omitempty"`
+}
+
+// Schema IngestRequest.
+type ingestRequest struct {
+	DataType string `json:"dataType"`
+	Format   string `json:"format"`
+	Tenant   string `json:"tenant"`
+}
+
+// Schema IngestionList.
+type ingestionList struct {
+	Ingestions    []*runstore.Record `json:"ingestions"`
+	NextPageToken string             `json:"nextPageToken,omitempty"`
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) listDataTypes(w http.ResponseWriter, r *http.Request) {
+	dataTypes := []dataType{}
+	for _, name := range s.opts.Source.ListTypes() {
+		schema, err := s.opts.Source.DescribeSchema(name)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		reason, disabled := s.opts.Toggles.Disabled(name)
+		dataTypes = append(dataTypes, dataType{
+			DataType:       name,
+			TableName:      schema.TableName,
+			Description:    schema.Description,
+			Enabled:        !disabled,
+			DisabledReason: reason,
+		})
+	}
+	writeJSON(w, http.StatusOK, dataTypes)
+}
+
+func (s *Server) createIngestion(w http.ResponseWriter, r *http.Request) {
+	var body ingestRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	// Request Checks (400 before anything is queued):
+	// - Format defaults to JSON; NDJSON is streamed by the run like on the CLI
+	// - Tenant defaults to BLADE_TENANT
+	// - Disabled data types are refused with their reason
+	// - The records are prepared up front, so unknown data types and unreadable files
+	//   fail the request instead of the run
+	format := strings.ToUpper(body.Format)
+	if format == "" {
+		format = "JSON"
+	}
+	if format != "JSON" && format != "CSV" && format != "NDJSON" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid format: %s. Use JSON, CSV or NDJSON", body.Format))
+		return
+	}
+	tenant := body.Tenant
+	if tenant == "" {
+		tenant = s.opts.DefaultTenant
+	}
+	if tenant != "" {
+		if err := databricks.ValidateTenant(tenant); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if err := s.opts.Toggles.Check(body.DataType); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req, err := s.opts.Source.FetchRecords(r.Context(), body.DataType, format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to prepare ingestion request: %w", err))
+		return
+	}
+
+	// Quotas (429):
+	// - Checked against the prepared record count; Retry-After says when the run would fit
+	// - Given back when the job can't be queued, so the client's retry isn't refused for it
+	rows := countRecords(req)
+	if s.opts.Limiter != nil {
+		if err := s.opts.Limiter.Acquire(tenant, body.DataType, rows); err != nil {
+			var exceeded *quota.ExceededError
+			if errors.As(err, &exceeded) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(exceeded.RetryAfter.Seconds()+0.5)))
+			}
+			writeError(w, http.StatusTooManyRequests, err)
+			return
+		}
+	}
+
+	// Queueing (503 when the queue is full or the server is shutting down):
+	// - The job is persisted as queued before the response, so its ID can be looked up at once
+	record := &runstore.Record{
+		ID:          runlog.NewRunID(),
+		DataType:    body.DataType,
+		Format:      format,
+		Tenant:      tenant,
+		SubmittedAt: time.Now().UTC(),
+	}
+	if err := s.jobs.Submit(record, req); err != nil {
+		if s.opts.Limiter != nil {
+			s.opts.Limiter.Release(tenant, body.DataType, rows)
+		}
+		switch {
+		case errors.Is(err, jobs.ErrQueueFull):
+			w.Header().Set("Retry-After", "60")
+			writeError(w, http.StatusServiceUnavailable, err)
+		case errors.Is(err, jobs.ErrClosed):
+			writeError(w, http.StatusServiceUnavailable, err)
+		default:
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	writeJSON(w, http.StatusAccepted, record)
+}
+
+func (s *Server) listIngestions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := runstore.Filter{
+		DataType: query.Get("dataType"),
+		Status:   query.Get("status"),
+		Tenant:   query.Get("tenant"),
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > runstore.MaxPageSize {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q (1-%d)", value, runstore.MaxPageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	records, next, err := s.opts.Store.List(filter, query.Get("pageToken"), limit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if records == nil {
+		records = []*runstore.Record{}
+	}
+	writeJSON(w, http.StatusOK, ingestionList{Ingestions: records, NextPageToken: next})
+}
+
+func (s *Server) getIngestion(w http.ResponseWriter, r *http.Request) {
+	record, found, err := s.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no ingestion with ID %s", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
+
+// Serves a run's log file as plain text; a running ingestion's log so far.
+func (s *Server) getIngestionLog(w http.ResponseWriter, r *http.Request) {
+	record, found, err := s.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no ingestion with ID %s", r.PathValue("id")))
+		return
+	}
+	if s.opts.LogDir == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run logs aren't served by this instance"))
+		return
+	}
+
+	// - A queued ingestion hasn't opened its log yet
+	content, err := runlog.ReadLog(s.opts.LogDir, record.ID)
+	if errors.Is(err, fs.ErrNotExist) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("ingestion %s has no log yet (status %s)", record.ID, record.Status))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// Returns the number of records a prepared request loads (0 for file loads, counted by COPY INTO).
+//   - An NDJSON record stream isn't read before the run, so its non-blank lines are counted
+func countRecords(req *databricks.IngestionRequest) int64 {
+	if req.Metadata["mode"] == databricks.ModeRecordStream && req.Records == nil {
+		file, err := os.Open(req.Metadata["source_file"])
+		if err != nil {
+			return 0
+		}
+		defer file.Close()
+		var lines int64
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(nil, 64*1024*1024)
+		for scanner.Scan() {
+			if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+				lines++
+			}
+		}
+		return lines
+	}
+	var records []json.RawMessage
+	if json.Unmarshal([]byte(req.SampleData), &records) != nil {
+		return 0
+	}
+	return int64(len(records))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Writes schema Error, with the remediation of a recognized workspace failure.
+func writeError(w http.ResponseWriter, status int, err error) {
+	body := errorBody{Error: err.Error()}
+	if remedy, ok := databricks.Remediate(err); ok {
+		body.Remediation = &remedy
+	}
+	writeJSON(w, status, body)
+}
+
+type errorBody struct {
+	Error       string                  `json:"error"`
+	Remediation *databricks.Remediation `json:"remediation,omitempty"`
+}
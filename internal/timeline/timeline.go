@@ -0,0 +1,203 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//   Purpose: Records when each statement of a run started and ended so the run's
+//   time can be attributed to the warehouse (statements in flight) or the client
+//   (gaps between statements).
+
+// Behavior:
+// - The Timeline travels in the context like the run log; code without one records nothing
+// - Phases (create_table, insert, verification, ...) label the spans started under them
+// - Export summarizes the finished timeline, WriteGantt renders it as text
+type Timeline struct {
+	mu      sync.Mutex
+	started time.Time
+	spans   []Span
+}
+
+// One statement (or other timed step) of a run.
+type Span struct {
+	Phase       string        `json:"phase"`
+	Kind        string        `json:"kind"` // DDL, DML, QUERY, ...
+	Label       string        `json:"label,omitempty"`
+	StatementID string        `json:"statementId,omitempty"`
+	Start       time.Duration `json:"start"` // offset from the timeline start
+	End         time.Duration `json:"end"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// Aggregate view of a timeline (durations serialize as nanoseconds, like IngestionResult.Duration).
+//   - Busy: time with at least one statement in flight (warehouse side, including queuing)
+//   - Idle: time with none in flight (client-side work and serialization)
+type Summary struct {
+	Wall           time.Duration `json:"wall"`
+	StatementTime  time.Duration `json:"statementTime"` // sum of all span durations
+	Busy           time.Duration `json:"busy"`
+	Idle           time.Duration `json:"idle"`
+	Statements     int           `json:"statements"`
+	MaxConcurrency int           `json:"maxConcurrency"`
+}
+
+// Serialized form written by reporters.
+type Export struct {
+	StartedAt time.Time `json:"startedAt"`
+	Summary   Summary   `json:"summary"`
+	Spans     []Span    `json:"spans"`
+}
+
+type contextKey struct{}
+
+type phaseKey struct{}
+
+// Starts an empty timeline at the current time.
+func New() *Timeline {
+	return &Timeline{started: time.Now()}
+}
+
+// Attaches a timeline to the context so statements further down record into it.
+func WithTimeline(ctx context.Context, t *Timeline) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// Returns the timeline attached to ctx, or nil when none is.
+func FromContext(ctx context.Context) *Timeline {
+	t, _ := ctx.Value(contextKey{}).(*Timeline)
+	return t
+}
+
+// Labels spans started under the returned context with phase.
+func WithPhase(ctx context.Context, phase string) context.Context {
+	return context.WithValue(ctx, phaseKey{}, phase)
+}
+
+// Starts a span in the context's timeline; the returned func ends it. Safe to call without a timeline.
+func Begin(ctx context.Context, kind, label string) func(statementID string, err error) {
+	t := FromContext(ctx)
+	if t == nil {
+		return func(string, error) {}
+	}
+	phase, _ := ctx.Value(phaseKey{}).(string)
+	start := time.Since(t.started)
+	return func(statementID string, err error) {
+		span := Span{Phase: phase, Kind: kind, Label: label, StatementID: statementID, Start: start, End: time.Since(t.started)}
+		if err != nil {
+			span.Error = err.Error()
+		}
+		t.mu.Lock()
+		t.spans = append(t.spans, span)
+		t.mu.Unlock()
+	}
+}
+
+// Returns the recorded spans ordered by start time.
+func (t *Timeline) Spans() []Span {
+	t.mu.Lock()
+	spans := append([]Span(nil), t.spans...)
+	t.mu.Unlock()
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	return spans
+}
+
+// Returns the timeline in its serialized form.
+func (t *Timeline) Export() Export {
+	spans := t.Spans()
+	return Export{StartedAt: t.started.UTC(), Summary: summarize(spans, time.Since(t.started)), Spans: spans}
+}
+
+func summarize(spans []Span, wall time.Duration) Summary {
+	summary := Summary{Wall: wall, Statements: len(spans)}
+
+	// Sweep over span boundaries: ends sort before starts at the same instant
+	type edge struct {
+		at    time.Duration
+		delta int
+	}
+	edges := make([]edge, 0, 2*len(spans))
+	for _, span := range spans {
+		summary.StatementTime += span.End - span.Start
+		edges = append(edges, edge{span.Start, 1}, edge{span.End, -1})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].at != edges[j].at {
+			return edges[i].at < edges[j].at
+		}
+		return edges[i].delta < edges[j].delta
+	})
+	inFlight := 0
+	var busySince time.Duration
+	for _, e := range edges {
+		if inFlight == 0 && e.delta > 0 {
+			busySince = e.at
+		}
+		inFlight += e.delta
+		if inFlight == 0 {
+			summary.Busy += e.at - busySince
+		}
+		if inFlight > summary.MaxConcurrency {
+			summary.MaxConcurrency = inFlight
+		}
+	}
+	if summary.Idle = wall - summary.Busy; summary.Idle < 0 {
+		summary.Idle = 0
+	}
+	return summary
+}
+
+// Writes an ASCII Gantt chart: one row per span, scaled to width columns.
+func WriteGantt(w io.Writer, export Export, width int) error {
+	if width < 10 {
+		width = 10
+	}
+	wall := export.Summary.Wall
+	for _, span := range export.Spans {
+		if span.End > wall {
+			wall = span.End
+		}
+	}
+	if wall <= 0 {
+		wall = time.Millisecond
+	}
+	column := func(d time.Duration) int {
+		return int(int64(d) * int64(width) / int64(wall))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-28s |%s| %s\n", "phase/kind", strings.Repeat("-", width), wall.Round(time.Millisecond))
+	for _, span := range export.Spans {
+		from, to := column(span.Start), column(span.End)
+		if to <= from {
+			to = from + 1
+		}
+		if to > width {
+			to = width
+			from = min(from, width-1)
+		}
+		mark := "#"
+		if span.Error != "" {
+			mark = "x"
+		}
+		name := span.Kind
+		if span.Phase != "" {
+			name = span.Phase + "/" + span.Kind
+		}
+		if len(name) > 28 {
+			name = name[:28]
+		}
+		fmt.Fprintf(&b, "%-28s |%s%s%s| %s\n", name, strings.Repeat(" ", from), strings.Repeat(mark, to-from),
+			strings.Repeat(" ", width-to), (span.End - span.Start).Round(time.Millisecond))
+	}
+	s := export.Summary
+	fmt.Fprintf(&b, "statements: %d, busy: %s, idle: %s, max concurrency: %d\n",
+		s.Statements, s.Busy.Round(time.Millisecond), s.Idle.Round(time.Millisecond), s.MaxConcurrency)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
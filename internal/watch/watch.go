@@ -0,0 +1,271 @@
+// Package watch ingests JSON, CSV and NDJSON files (plain, gzipped or zipped) dropped into the BLADE data path: each
+// {dataPath}/{dataType}/ directory is scanned periodically, and files that are new or
+// changed since their last ingestion are loaded once they have stopped changing. A
+// ledger records every file processed, so restarts don't ingest a file twice.
+package watch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"databricks-blade-poc/blademap"
+	"databricks-blade-poc/internal/runlog"
+)
+
+// Ledger entry states.
+const (
+	StatusIngested = "ingested"
+	StatusFailed   = "failed"
+	StatusBaseline = "baseline" // present when the ledger was created; not ingested
+)
+
+// Defaults used when the Watcher leaves them at 0.
+const (
+	DefaultInterval = 5 * time.Second
+	DefaultDebounce = 2 * time.Second
+)
+
+// The last version of a file the watcher processed.
+//   - Size, ModTime: Cheap change check; SHA256 tells real edits from touches
+//   - RunID: The ingestion run (see status); empty for baseline entries
+type Entry struct {
+	DataType    string    `json:"dataType"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"modTime"`
+	SHA256      string    `json:"sha256"`
+	Status      string    `json:"status"`
+	RunID       string    `json:"runId,omitempty"`
+	Rows        int64     `json:"rows,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	ProcessedAt time.Time `json:"processedAt"`
+}
+
+// Processed files by path, kept in a JSON file.
+type Ledger struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]*Entry
+	created bool
+}
+
+// Opens the ledger at path; a missing file starts an empty (newly created) ledger.
+func OpenLedger(path string) (*Ledger, error) {
+	ledger := &Ledger{path: path, entries: map[string]*Entry{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		ledger.created = true
+		return ledger, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch ledger %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &ledger.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse watch ledger %s: %w", path, err)
+	}
+	return ledger, nil
+}
+
+// Returns the entry of a file.
+func (l *Ledger) Get(path string) (Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, found := l.entries[path]
+	if !found {
+		return Entry{}, false
+	}
+	return *entry, true
+}
+
+// Returns every entry, by path.
+func (l *Ledger) Entries() map[string]Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make(map[string]Entry, len(l.entries))
+	for path, entry := range l.entries {
+		entries[path] = *entry
+	}
+	return entries
+}
+
+// Records a file's entry and writes the ledger.
+func (l *Ledger) Put(path string, entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[path] = &entry
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create watch ledger directory: %w", err)
+	}
+	temp := l.path + ".tmp"
+	if err := os.WriteFile(temp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write watch ledger %s: %w", l.path, err)
+	}
+	if err := os.Rename(temp, l.path); err != nil {
+		return fmt.Errorf("failed to write watch ledger %s: %w", l.path, err)
+	}
+	l.created = false
+	return nil
+}
+
+// Ingests one file; returns the run's ID and the rows loaded.
+type IngestFunc func(ctx context.Context, dataType, path string) (runID string, rows int64, err error)
+
+// What a scan did with a file.
+type Outcome struct {
+	Path     string
+	DataType string
+	Entry    Entry
+}
+
+// Scans {Root}/{dataType}/ for each data type and ingests new or changed files.
+//   - Debounce: A file is only picked up once it hasn't been modified for this long
+//     (DefaultDebounce), so files still being copied in aren't loaded half-written
+//   - Backfill: On a new ledger, files already present are ingested too; otherwise they
+//     are recorded as baseline and only later changes are ingested
+//   - A failed file is retried when it changes again, not on every scan
+type Watcher struct {
+	Root      string
+	DataTypes []string
+	Ledger    *Ledger
+	Ingest    IngestFunc
+	Debounce  time.Duration
+	Backfill  bool
+
+	scanned bool // a scan has completed; later files are never baseline
+}
+
+// Runs one pass over the data type directories and returns the files it processed.
+func (w *Watcher) Scan(ctx context.Context) ([]Outcome, error) {
+	debounce := w.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	baseline := w.Ledger.created && !w.Backfill && !w.scanned
+
+	var outcomes []Outcome
+	for _, dataType := range w.DataTypes {
+		files, err := listFiles(filepath.Join(w.Root, dataType))
+		if err != nil {
+			return outcomes, err
+		}
+		for _, path := range files {
+			if ctx.Err() != nil {
+				return outcomes, ctx.Err()
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			previous, seen := w.Ledger.Get(path)
+			if seen && previous.Size == info.Size() && previous.ModTime.Equal(info.ModTime()) {
+				continue
+			}
+			if !baseline && time.Since(info.ModTime()) < debounce {
+				continue
+			}
+			digest, err := fileDigest(path)
+			if err != nil {
+				runlog.Printf(ctx, "Could not read %s: %v", path, err)
+				continue
+			}
+
+			// - A touched file with unchanged content keeps its outcome; only the
+			//   change check is updated
+			entry := Entry{DataType: dataType, Size: info.Size(), ModTime: info.ModTime(), SHA256: digest, ProcessedAt: time.Now().UTC()}
+			switch {
+			case seen && previous.SHA256 == digest:
+				entry.Status, entry.RunID, entry.Rows, entry.Error = previous.Status, previous.RunID, previous.Rows, previous.Error
+				entry.ProcessedAt = previous.ProcessedAt
+				if err := w.Ledger.Put(path, entry); err != nil {
+					return outcomes, err
+				}
+				continue
+			case baseline:
+				entry.Status = StatusBaseline
+			default:
+				runlog.Printf(ctx, "Ingesting %s (%s)", path, dataType)
+				runID, rows, err := w.Ingest(ctx, dataType, path)
+				entry.RunID, entry.Rows, entry.Status = runID, rows, StatusIngested
+				if err != nil {
+					entry.Status, entry.Error = StatusFailed, err.Error()
+					runlog.Printf(ctx, "Ingesting %s failed: %v", path, err)
+				}
+			}
+			if err := w.Ledger.Put(path, entry); err != nil {
+				return outcomes, err
+			}
+			outcomes = append(outcomes, Outcome{Path: path, DataType: dataType, Entry: entry})
+		}
+	}
+	w.scanned = true
+	return outcomes, nil
+}
+
+// Scans every interval (DefaultInterval) until ctx is done.
+//   - A failing scan is logged and retried at the next interval
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Scan(ctx); err != nil && ctx.Err() == nil {
+			runlog.Printf(ctx, "Watch scan failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the JSON, CSV and NDJSON (.ndjson, .jsonl) files of dir in name order, gzipped
+// or not, and its zip archives; a missing dir has none.
+//   - Hidden files (e.g. ".part" uploads in progress) are skipped
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || strings.HasPrefix(name, ".") || (blademap.FileFormat(name) == "" && !blademap.IsZip(name)) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}